@@ -3,17 +3,26 @@ package main
 //coverage:ignore file
 import (
 	_ "backend/docs"
+	"backend/internal/handler/attachmentsHandler"
 	"backend/internal/handler/bundlesHandler"
+	"backend/internal/handler/conditionsHandler"
+	"backend/internal/handler/locationsHandler"
 	"backend/internal/handler/loginHandler"
 	"backend/internal/handler/logoutHandler"
 	"backend/internal/handler/productsHandler"
 	"backend/internal/handler/rankingsHandler"
+	"backend/internal/handler/ratingsHandler"
+	"backend/internal/handler/recommendationsHandler"
 	"backend/internal/handler/registrationHandler"
+	"backend/internal/handler/runsHandler"
+	"backend/internal/handler/skisHandler"
 	"backend/internal/handler/testsHandler"
+	"backend/internal/handler/tournamentHandler"
 	"backend/internal/handler/userProfileHandler"
 	"backend/internal/handler/usersHandler"
 	"backend/internal/middleware"
 	"backend/internal/services"
+	"backend/internal/services/storage"
 	"github.com/swaggo/http-swagger"
 	"log"
 	"net/http"
@@ -24,6 +33,37 @@ func main() {
 	db := services.InitDB()
 	defer db.Close()
 
+	// Initialize the attachment storage
+	store, err := storage.NewLocalStorageFromEnv()
+	if err != nil {
+		log.Fatalf("Could not initialize the attachment storage: %v", err)
+	}
+
+	// Load the layering rules the bundles are validated with
+	bundlesHandler.Rules, err = bundlesHandler.LayeringRulesFromEnv()
+	if err != nil {
+		log.Fatalf("Could not load the layering rules of the bundles: %v", err)
+	}
+
+	// Keep the product ratings up to date as the tests change, starting with a full fit.
+	testsHandler.OnTestChanged = func(testID int) {
+		if err := ratingsHandler.RecomputeForTest(db, testID); err != nil {
+			log.Printf("Could not update the ratings for test %d: %v", testID, err)
+		}
+	}
+	productsHandler.OnProductsMerged = func(testIDs []int) {
+		for _, testID := range testIDs {
+			if err := ratingsHandler.RecomputeForTest(db, testID); err != nil {
+				log.Printf("Could not update the ratings for test %d: %v", testID, err)
+			}
+		}
+	}
+	go func() {
+		if err := ratingsHandler.RecomputeAll(db); err != nil {
+			log.Printf("Could not fit the ratings: %v", err)
+		}
+	}()
+
 	// Initialize middleware
 	auth := middleware.NewAuthHandler(db)
 	logger := middleware.NewLoggingHandler(db)
@@ -38,6 +78,14 @@ func main() {
 	bundles := bundlesHandler.BundlesHandler(db)
 	users := usersHandler.UsersHandler(db)
 	userProfile := userProfileHandler.UserProfileHandler(db)
+	tournament := tournamentHandler.TournamentHandler(db)
+	skis := skisHandler.SkisHandler(db)
+	runs := runsHandler.RunsHandler(db)
+	conditions := conditionsHandler.ConditionsHandler(db)
+	locations := locationsHandler.LocationsHandler(db)
+	attachments := attachmentsHandler.AttachmentsHandler(db, store)
+	ratings := ratingsHandler.RatingsHandler(db)
+	recommendations := recommendationsHandler.RecommendationsHandler(db)
 	//session := http.HandlerFunc(sessionHandler.IsSessionActive)
 
 	// Create a new ServeMux to handle routes.
@@ -50,12 +98,27 @@ func main() {
 	mux.Handle("/logout", auth.Middleware(logger.LoggingMiddleware(logout)))
 	mux.Handle("/tests", auth.Middleware(logger.LoggingMiddleware(tests)))
 	mux.Handle("/tests/", auth.Middleware(logger.LoggingMiddleware(tests)))
+	mux.Handle("/tests/{id}/tournament", auth.Middleware(logger.LoggingMiddleware(tournament)))
+	mux.Handle("/tests/{id}/tournament/", auth.Middleware(logger.LoggingMiddleware(tournament)))
+	mux.Handle("/tests/{id}/runs", auth.Middleware(logger.LoggingMiddleware(runs)))
+	mux.Handle("/tests/{id}/conditions", auth.Middleware(logger.LoggingMiddleware(conditions)))
+	mux.Handle("/tests/{id}/attachments", auth.Middleware(logger.LoggingMiddleware(attachments)))
+	mux.Handle("/tests/{id}/attachments/", auth.Middleware(logger.LoggingMiddleware(attachments)))
 	mux.Handle("/products", auth.Middleware(logger.LoggingMiddleware(products)))
 	mux.Handle("/products/", auth.Middleware(logger.LoggingMiddleware(products)))
+	mux.Handle("/products/{id}/attachments", auth.Middleware(logger.LoggingMiddleware(attachments)))
+	mux.Handle("/products/{id}/attachments/", auth.Middleware(logger.LoggingMiddleware(attachments)))
+	mux.Handle("/products/{id}/rating", auth.Middleware(logger.LoggingMiddleware(ratings)))
+	mux.Handle("/ratings/leaderboard", auth.Middleware(logger.LoggingMiddleware(ratings)))
+	mux.Handle("/recommendations", auth.Middleware(logger.LoggingMiddleware(recommendations)))
 	mux.Handle("/rankings", auth.Middleware(logger.LoggingMiddleware(rankings)))
 	mux.Handle("/rankings/", auth.Middleware(logger.LoggingMiddleware(rankings)))
 	mux.Handle("/bundles", auth.Middleware(logger.LoggingMiddleware(bundles)))
 	mux.Handle("/bundles/", auth.Middleware(logger.LoggingMiddleware(bundles)))
+	mux.Handle("/locations", auth.Middleware(logger.LoggingMiddleware(locations)))
+	mux.Handle("/locations/", auth.Middleware(logger.LoggingMiddleware(locations)))
+	mux.Handle("/skis", auth.Middleware(logger.LoggingMiddleware(skis)))
+	mux.Handle("/skis/", auth.Middleware(logger.LoggingMiddleware(skis)))
 	mux.Handle("/users/", auth.Middleware(logger.LoggingMiddleware(users)))
 	mux.Handle("/user/profile", auth.Middleware(logger.LoggingMiddleware(userProfile)))
 	//mux.Handle("/is-session-active", auth.Middleware(session))
@@ -24,7 +24,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieves a list of bundles based on query parameters.",
+                "description": "Retrieves the layers of the bundles of the team and of the public bundles, as bundle, product and\nlayer number. With product_id, the bundle of that product row is returned instead, with its layers,\nas from /bundles/{id}.",
                 "consumes": [
                     "application/json"
                 ],
@@ -38,8 +38,8 @@ const docTemplate = `{
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Bundle ID",
-                        "name": "id",
+                        "description": "ID of the product row of a bundle",
+                        "name": "product_id",
                         "in": "query"
                     }
                 ],
@@ -53,6 +53,12 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "404": {
+                        "description": "Could not find the bundle.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "Could not retrieve all bundles.",
                         "schema": {
@@ -67,7 +73,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Creates a new bundle based on the provided JSON request body.",
+                "description": "Creates a new bundle based on the provided JSON request body. The layers are given as products, in\nthe order they are applied, or with the recipe for applying each of them. The layers must follow\nthe layering rules, such as powder and liquid top coats on a solid base, and have temperatures in\ncommon, which become the temperature range of the bundle.",
                 "consumes": [
                     "application/json"
                 ],
@@ -85,15 +91,15 @@ const docTemplate = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/domain.ProductBundle"
+                            "$ref": "#/definitions/bundlesHandler.BundlePOSTRequest"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Bundle created successfully",
+                        "description": "The bundle, without its layers",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/domain.Bundle"
                         }
                     },
                     "400": {
@@ -124,7 +130,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieves a list of bundles based on query parameters.",
+                "description": "Retrieves the layers of the bundles of the team and of the public bundles, as bundle, product and\nlayer number. With product_id, the bundle of that product row is returned instead, with its layers,\nas from /bundles/{id}.",
                 "consumes": [
                     "application/json"
                 ],
@@ -138,8 +144,8 @@ const docTemplate = `{
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Bundle ID",
-                        "name": "id",
+                        "description": "ID of the product row of a bundle",
+                        "name": "product_id",
                         "in": "query"
                     }
                 ],
@@ -153,6 +159,12 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "404": {
+                        "description": "Could not find the bundle.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "Could not retrieve all bundles.",
                         "schema": {
@@ -167,7 +179,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Creates a new bundle based on the provided JSON request body.",
+                "description": "Creates a new bundle based on the provided JSON request body. The layers are given as products, in\nthe order they are applied, or with the recipe for applying each of them. The layers must follow\nthe layering rules, such as powder and liquid top coats on a solid base, and have temperatures in\ncommon, which become the temperature range of the bundle.",
                 "consumes": [
                     "application/json"
                 ],
@@ -185,15 +197,15 @@ const docTemplate = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/domain.ProductBundle"
+                            "$ref": "#/definitions/bundlesHandler.BundlePOSTRequest"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Bundle created successfully",
+                        "description": "The bundle, without its layers",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/domain.Bundle"
                         }
                     },
                     "400": {
@@ -217,67 +229,111 @@ const docTemplate = `{
                 }
             }
         },
-        "/login/": {
-            "post": {
-                "description": "Authenticates the user and creates a session",
-                "consumes": [
-                    "application/json"
+        "/bundles/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Retrieves a bundle of the team, or one with a public product row, with the full details of the\nproduct of each layer and the recipe for applying it, in the order the layers are applied. The\nbundle is found by its ID, or by the ID of its product row, as it appears in the results of a test,\nwith the product_id query parameter of /bundles. Of a layer that is a private product of another\nteam, only the ID of the product is returned.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Login"
+                    "Bundles"
                 ],
-                "summary": "Login user",
+                "summary": "Get a bundle with its layers",
                 "parameters": [
                     {
-                        "description": "User credentials",
-                        "name": "credentials",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/loginHandler.LoginRequest"
-                        }
+                        "type": "integer",
+                        "description": "Bundle ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Session created successfully",
+                        "description": "The bundle with its layers",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Bundle"
+                        }
+                    },
+                    "404": {
+                        "description": "Could not find the bundle.",
                         "schema": {
                             "type": "string"
                         }
                     },
-                    "400": {
-                        "description": "Invalid request body",
+                    "500": {
+                        "description": "Could not retrieve the bundle.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes a bundle with its layers and its product row. A bundle with test results or runs can not\nbe deleted, as the results would be lost with it. A bundle of another team can not be deleted, and\na researcher can not delete a public bundle.",
+                "tags": [
+                    "Bundles"
+                ],
+                "summary": "Delete a bundle",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Bundle ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Bundle deleted successfully",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "401": {
-                        "description": "Invalid email or password",
+                        "description": "User cannot delete this bundle",
                         "schema": {
                             "type": "string"
                         }
                     },
-                    "405": {
-                        "description": "Method not allowed",
+                    "404": {
+                        "description": "Could not find the bundle.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "The bundle has test results and cannot be deleted.",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "500": {
-                        "description": "Could not create session",
+                        "description": "Could not delete the bundle.",
                         "schema": {
                             "type": "string"
                         }
                     }
                 }
-            }
-        },
-        "/logout/": {
-            "post": {
-                "description": "Logs out the user by invalidating the session and CSRF tokens",
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Renames a bundle, or replaces its layers with the products given, in the order they are applied,\nso layers are added, removed and reordered by sending the list as it should be. The layers can be\ngiven with the recipe for applying each of them, which is kept with the layer. The layers must\nfollow the layering rules, such as powder and liquid top coats on a solid base, and have\ntemperatures in common, which become the temperature range of the bundle. The version must\nbe the version of the bundle the changes were made to, or the update is refused as a conflict. A\nbundle of another team can not be updated, and a researcher can not update a public bundle.",
                 "consumes": [
                     "application/json"
                 ],
@@ -285,30 +341,61 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Logout"
+                    "Bundles"
+                ],
+                "summary": "Update a bundle",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Bundle ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "The changes to the bundle",
+                        "name": "bundle",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/bundlesHandler.BundlePATCHRequest"
+                        }
+                    }
                 ],
-                "summary": "Logout user",
                 "responses": {
                     "200": {
-                        "description": "Successfully logged out",
+                        "description": "Bundle updated successfully, with its new version",
                         "schema": {
-                            "type": "string"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "400": {
-                        "description": "Request method not allowed",
+                        "description": "Invalid request body",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "401": {
-                        "description": "Unauthorized",
+                        "description": "User cannot update this bundle",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Could not find the bundle.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Detected a conflict for the current bundle, please refresh.",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "500": {
-                        "description": "Could not log out",
+                        "description": "Could not update the bundle.",
                         "schema": {
                             "type": "string"
                         }
@@ -316,14 +403,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/products": {
+        "/locations": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieves a list of products based on query parameters.",
+                "description": "Retrieves all the test locations, or a single location, with their tracks.",
                 "consumes": [
                     "application/json"
                 ],
@@ -331,41 +418,27 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Products"
-                ],
-                "summary": "Get a list of products",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Product id",
-                        "name": "id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Publicly Available Product",
-                        "name": "public",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Fields to retrieve",
-                        "name": "fields",
-                        "in": "query"
-                    }
+                    "Locations"
                 ],
+                "summary": "Get the test locations",
                 "responses": {
                     "200": {
-                        "description": "Successful response with a list of products",
+                        "description": "Successful response with a list of locations",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/domain.Product"
+                                "$ref": "#/definitions/domain.Location"
                             }
                         }
                     },
+                    "404": {
+                        "description": "Could not find the location.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
-                        "description": "Could not retrieve all products.",
+                        "description": "Could not retrieve the locations.",
                         "schema": {
                             "type": "string"
                         }
@@ -378,7 +451,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Adds a new product to the database.",
+                "description": "Adds a new test location with its tracks. Location names are unique, ignoring case.",
                 "consumes": [
                     "application/json"
                 ],
@@ -386,57 +459,56 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Products"
+                    "Locations"
                 ],
-                "summary": "Create a new product",
+                "summary": "Create a new location",
                 "parameters": [
                     {
-                        "description": "New product information",
-                        "name": "product",
+                        "description": "New location information",
+                        "name": "location",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/productsHandler.ProductPOSTRequest"
+                            "$ref": "#/definitions/locationsHandler.LocationPOSTRequest"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Product created successfully"
-                    },
-                    "400": {
-                        "description": "Invalid request data",
+                        "description": "Location created successfully",
                         "schema": {
                             "type": "string"
                         }
                     },
-                    "403": {
-                        "description": "Researcher cannot create public products",
+                    "400": {
+                        "description": "Invalid POST request body",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "409": {
-                        "description": "Product already exists",
+                        "description": "A location with this name already exists",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "500": {
-                        "description": "Could not create product.",
+                        "description": "Could not create the location.",
                         "schema": {
                             "type": "string"
                         }
                     }
                 }
-            },
-            "patch": {
+            }
+        },
+        "/locations/{location_id}": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Updates fields of an existing product.",
+                "description": "Retrieves all the test locations, or a single location, with their tracks.",
                 "consumes": [
                     "application/json"
                 ],
@@ -444,63 +516,48 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Products"
+                    "Locations"
                 ],
-                "summary": "Update a product",
+                "summary": "Get the test locations",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Product ID",
-                        "name": "id",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "description": "Product update fields",
-                        "name": "product",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/productsHandler.ProductPATCHRequest"
-                        }
+                        "description": "Location ID",
+                        "name": "location_id",
+                        "in": "path"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Product updated successfully",
+                        "description": "Successful response with a list of locations",
                         "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "400": {
-                        "description": "Invalid request data",
-                        "schema": {
-                            "type": "string"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.Location"
+                            }
                         }
                     },
-                    "409": {
-                        "description": "Detected a conflict for the current product, please refresh.",
+                    "404": {
+                        "description": "Could not find the location.",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "500": {
-                        "description": "Could not update product.",
+                        "description": "Could not retrieve the locations.",
                         "schema": {
                             "type": "string"
                         }
                     }
                 }
-            }
-        },
-        "/products/": {
-            "post": {
+            },
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Adds a new product to the database.",
+                "description": "Deletes a location with its tracks, or a single track. Locations and tracks that tests refer to\ncannot be deleted. Only the team that created the location and the official teams may delete it or\nits tracks.",
                 "consumes": [
                     "application/json"
                 ],
@@ -508,59 +565,55 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Products"
+                    "Locations"
                 ],
-                "summary": "Create a new product",
+                "summary": "Delete a location or a track",
                 "parameters": [
                     {
-                        "description": "New product information",
-                        "name": "product",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/productsHandler.ProductPOSTRequest"
-                        }
+                        "type": "integer",
+                        "description": "Location ID",
+                        "name": "location_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Product created successfully"
+                    "204": {
+                        "description": "Deleted successfully"
                     },
-                    "400": {
-                        "description": "Invalid request data",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "string"
                         }
                     },
-                    "403": {
-                        "description": "Researcher cannot create public products",
+                    "404": {
+                        "description": "Could not find the location.",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "409": {
-                        "description": "Product already exists",
+                        "description": "Tests refer to this location.",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "500": {
-                        "description": "Could not create product.",
+                        "description": "Could not delete the location.",
                         "schema": {
                             "type": "string"
                         }
                     }
                 }
-            }
-        },
-        "/products/{products_id}": {
-            "get": {
+            },
+            "patch": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieves a list of products based on query parameters.",
+                "description": "Updates the name, coordinates or altitude of a location. Only the team that created the location\nand the official teams may change it.",
                 "consumes": [
                     "application/json"
                 ],
@@ -568,117 +621,69 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Products"
+                    "Locations"
                 ],
-                "summary": "Get a list of products",
+                "summary": "Update a location",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Product id",
-                        "name": "id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Publicly Available Product",
-                        "name": "public",
-                        "in": "query"
+                        "type": "integer",
+                        "description": "Location ID",
+                        "name": "location_id",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Fields to retrieve",
-                        "name": "fields",
-                        "in": "query"
+                        "description": "Location update fields",
+                        "name": "location",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/locationsHandler.LocationPATCHRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successful response with a list of products",
+                        "description": "Location updated successfully",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/domain.Product"
-                            }
+                            "type": "string"
                         }
                     },
-                    "500": {
-                        "description": "Could not retrieve all products.",
+                    "400": {
+                        "description": "Invalid PATCH request body",
                         "schema": {
                             "type": "string"
                         }
-                    }
-                }
-            }
-        },
-        "/rankings": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Retrieves a list of rankings based on query parameters.",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Rankings"
-                ],
-                "summary": "Get a list of rankings",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Public rankings",
-                        "name": "public",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Test ID",
-                        "name": "test_id",
-                        "in": "query"
                     },
-                    {
-                        "type": "integer",
-                        "description": "Rank",
-                        "name": "rank",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "Successful response with a list of rankings",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/domain.TestRank"
-                            }
+                            "type": "string"
                         }
                     },
-                    "400": {
-                        "description": "Invalid rank parameter",
+                    "404": {
+                        "description": "Could not find the location.",
                         "schema": {
                             "type": "string"
                         }
                     },
-                    "500": {
-                        "description": "Could not retrieve all rankings",
+                    "409": {
+                        "description": "Detected a conflict for the current location, please refresh.",
                         "schema": {
                             "type": "string"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/locations/{location_id}/tracks": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Creates a new ranking",
+                "description": "Adds a new named track to a location. Track names are unique within a location, ignoring case.\nOnly the team that created the location and the official teams may add tracks to it.",
                 "consumes": [
                     "application/json"
                 ],
@@ -686,38 +691,60 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Rankings"
+                    "Locations"
                 ],
-                "summary": "Create a new ranking",
+                "summary": "Create a new track",
                 "parameters": [
                     {
-                        "description": "New ranking information",
-                        "name": "ranking",
+                        "type": "integer",
+                        "description": "Location ID",
+                        "name": "location_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New track information",
+                        "name": "track",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/rankingsHandler.RankingsPOSTRequest"
+                            "$ref": "#/definitions/locationsHandler.TrackPOST"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Ranking created successfully"
+                        "description": "Track created successfully",
+                        "schema": {
+                            "type": "string"
+                        }
                     },
                     "400": {
-                        "description": "Invalid request body",
+                        "description": "Invalid POST request body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Could not find the location.",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "409": {
-                        "description": "Product is already part of the test",
+                        "description": "A track with this name already exists at the location",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "500": {
-                        "description": "Could not create ranking",
+                        "description": "Could not create the track.",
                         "schema": {
                             "type": "string"
                         }
@@ -725,14 +752,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/rankings/": {
-            "post": {
+        "/locations/{location_id}/tracks/{track_id}": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Creates a new ranking",
+                "description": "Deletes a location with its tracks, or a single track. Locations and tracks that tests refer to\ncannot be deleted. Only the team that created the location and the official teams may delete it or\nits tracks.",
                 "consumes": [
                     "application/json"
                 ],
@@ -740,38 +767,48 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Rankings"
+                    "Locations"
                 ],
-                "summary": "Create a new ranking",
+                "summary": "Delete a location or a track",
                 "parameters": [
                     {
-                        "description": "New ranking information",
-                        "name": "ranking",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/rankingsHandler.RankingsPOSTRequest"
-                        }
+                        "type": "integer",
+                        "description": "Location ID",
+                        "name": "location_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Track ID",
+                        "name": "track_id",
+                        "in": "path"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Ranking created successfully"
+                    "204": {
+                        "description": "Deleted successfully"
                     },
-                    "400": {
-                        "description": "Invalid request body",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Could not find the location.",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "409": {
-                        "description": "Product is already part of the test",
+                        "description": "Tests refer to this location.",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "500": {
-                        "description": "Could not create ranking",
+                        "description": "Could not delete the location.",
                         "schema": {
                             "type": "string"
                         }
@@ -779,14 +816,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/rankings/{rank_id}": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Retrieves a list of rankings based on query parameters.",
+        "/login/": {
+            "post": {
+                "description": "Authenticates the user and creates a session",
                 "consumes": [
                     "application/json"
                 ],
@@ -794,47 +826,47 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Rankings"
+                    "Login"
                 ],
-                "summary": "Get a list of rankings",
+                "summary": "Login user",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Public rankings",
-                        "name": "public",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Test ID",
-                        "name": "test_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Rank",
-                        "name": "rank",
-                        "in": "query"
+                        "description": "User credentials",
+                        "name": "credentials",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/loginHandler.LoginRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successful response with a list of rankings",
+                        "description": "Session created successfully",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/domain.TestRank"
-                            }
+                            "type": "string"
                         }
                     },
                     "400": {
-                        "description": "Invalid rank parameter",
+                        "description": "Invalid request body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid email or password",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "405": {
+                        "description": "Method not allowed",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "500": {
-                        "description": "Could not retrieve all rankings",
+                        "description": "Could not create session",
                         "schema": {
                             "type": "string"
                         }
@@ -842,9 +874,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/register/": {
+        "/logout/": {
             "post": {
-                "description": "Registers a new user",
+                "description": "Logs out the user by invalidating the session and CSRF tokens",
                 "consumes": [
                     "application/json"
                 ],
@@ -852,44 +884,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Registration"
-                ],
-                "summary": "Register user",
-                "parameters": [
-                    {
-                        "description": "User credentials",
-                        "name": "credentials",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/registrationHandler.RegistrationPOSTRequest"
-                        }
-                    }
+                    "Logout"
                 ],
+                "summary": "Logout user",
                 "responses": {
-                    "201": {
-                        "description": "User created successfully"
-                    },
-                    "400": {
-                        "description": "Invalid request body",
+                    "200": {
+                        "description": "Successfully logged out",
                         "schema": {
                             "type": "string"
                         }
                     },
-                    "405": {
-                        "description": "Method not allowed",
+                    "400": {
+                        "description": "Request method not allowed",
                         "schema": {
                             "type": "string"
                         }
                     },
-                    "409": {
-                        "description": "User already exists",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "500": {
-                        "description": "Could not create user",
+                        "description": "Could not log out",
                         "schema": {
                             "type": "string"
                         }
@@ -897,14 +915,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/tests": {
+        "/products": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieves a list of tests based on query parameters.",
+                "description": "Retrieves a list of products based on query parameters.",
                 "consumes": [
                     "application/json"
                 ],
@@ -912,47 +930,41 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Tests"
+                    "Products"
                 ],
-                "summary": "Get a list of tests",
+                "summary": "Get a list of products",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "All Public Tests",
-                        "name": "public",
+                        "description": "Product id",
+                        "name": "id",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Start Date in YYYY-MM-DD format",
-                        "name": "start_date",
+                        "description": "Publicly Available Product",
+                        "name": "public",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "End date in YYYY-MM-DD format",
-                        "name": "end_date",
+                        "description": "Fields to retrieve",
+                        "name": "fields",
                         "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successful response with a list of tests",
+                        "description": "Successful response with a list of products",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/domain.Test"
+                                "$ref": "#/definitions/domain.Product"
                             }
                         }
                     },
-                    "400": {
-                        "description": "Invalid start date format.",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
                     "500": {
-                        "description": "Could not retrieve all tests.",
+                        "description": "Could not retrieve all products.",
                         "schema": {
                             "type": "string"
                         }
@@ -965,7 +977,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Adds a new test to the database.",
+                "description": "Adds a new product to the database. An EAN code must be an EAN-8, UPC-A or EAN-13 code with a\nvalid check digit.",
                 "consumes": [
                     "application/json"
                 ],
@@ -973,102 +985,57 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Tests"
+                    "Products"
                 ],
-                "summary": "Create a new test",
+                "summary": "Create a new product",
                 "parameters": [
                     {
-                        "description": "New test information",
-                        "name": "test",
+                        "description": "New product information",
+                        "name": "product",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/testsHandler.TestPOSTRequest"
+                            "$ref": "#/definitions/productsHandler.ProductPOSTRequest"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Test created successfully"
+                        "description": "Product created successfully"
                     },
-                    "500": {
-                        "description": "Could not create test.",
+                    "400": {
+                        "description": "Invalid request data or EAN code",
                         "schema": {
                             "type": "string"
                         }
-                    }
-                }
-            }
-        },
-        "/tests/": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Retrieves a list of tests based on query parameters.",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Tests"
-                ],
-                "summary": "Get a list of tests",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "All Public Tests",
-                        "name": "public",
-                        "in": "query"
                     },
-                    {
-                        "type": "string",
-                        "description": "Start Date in YYYY-MM-DD format",
-                        "name": "start_date",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "End date in YYYY-MM-DD format",
-                        "name": "end_date",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "Successful response with a list of tests",
+                    "403": {
+                        "description": "Researcher cannot create public products",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/domain.Test"
-                            }
+                            "type": "string"
                         }
                     },
-                    "400": {
-                        "description": "Invalid start date format.",
+                    "409": {
+                        "description": "Product already exists",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "500": {
-                        "description": "Could not retrieve all tests.",
+                        "description": "Could not create product.",
                         "schema": {
                             "type": "string"
                         }
                     }
                 }
             },
-            "post": {
+            "patch": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Adds a new test to the database.",
+                "description": "Updates fields of an existing product. The changed fields are recorded in the history of the\nproduct in the same transaction, as a new revision. A change of the status must be allowed from\nthe current status, and is recorded with the reason of the request. A product can not be retired\nwhile it, or a bundle containing it, is in a planned or in progress test of its team. When a\nproduct is discontinued or retired, the bundles of the team containing it follow, and are returned.\nA change of the temperature range updates the flags of the results of the product, and the\ntemperature windows of the bundles containing it.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1076,39 +1043,63 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Tests"
+                    "Products"
                 ],
-                "summary": "Create a new test",
+                "summary": "Update a product",
                 "parameters": [
                     {
-                        "description": "New test information",
-                        "name": "test",
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "description": "Product update fields",
+                        "name": "product",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/testsHandler.TestPOSTRequest"
+                            "$ref": "#/definitions/productsHandler.ProductPATCHRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Test created successfully"
+                    "200": {
+                        "description": "Product updated successfully",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request data or EAN code",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict with a newer version, the status or an open test",
+                        "schema": {
+                            "type": "string"
+                        }
                     },
                     "500": {
-                        "description": "Could not create test.",
+                        "description": "Could not update product.",
                         "schema": {
                             "type": "string"
                         }
                     }
                 }
-            },
-            "patch": {
+            }
+        },
+        "/products/": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Updates an existing test in the database.",
+                "description": "Adds a new product to the database. An EAN code must be an EAN-8, UPC-A or EAN-13 code with a\nvalid check digit.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1116,41 +1107,44 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Tests"
+                    "Products"
                 ],
-                "summary": "Update an existing test's information, ranks, ac, tc and/or sc.",
+                "summary": "Create a new product",
                 "parameters": [
                     {
-                        "description": "Test updates",
-                        "name": "test",
+                        "description": "New product information",
+                        "name": "product",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/testsHandler.TestPATCHRequest"
+                            "$ref": "#/definitions/productsHandler.ProductPOSTRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Test updated successfully",
+                    "201": {
+                        "description": "Product created successfully"
+                    },
+                    "400": {
+                        "description": "Invalid request data or EAN code",
                         "schema": {
                             "type": "string"
                         }
                     },
-                    "400": {
-                        "description": "Invalid patch request.",
+                    "403": {
+                        "description": "Researcher cannot create public products",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "409": {
-                        "description": "Detected a conflict for the current test, please refresh.",
+                        "description": "Product already exists",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "500": {
-                        "description": "Could not JSON encode the response.",
+                        "description": "Could not create product.",
                         "schema": {
                             "type": "string"
                         }
@@ -1158,70 +1152,51 @@ const docTemplate = `{
                 }
             }
         },
-        "/tests/{test_id}/products/{product_id}": {
-            "patch": {
+        "/products/by-ean/{code}": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Updates an existing test in the database.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Retrieves the product with an EAN-8, UPC-A or EAN-13 code, such as a barcode scanned from a box of\nwax. A UPC-A code also finds the product stored with the same EAN-13 code, which has a leading 0,\nand the other way around. The product of the team is returned before a public product. When no\nproduct has the code, the app can create a new product with it.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Tests"
+                    "Products"
                 ],
-                "summary": "Update an existing test's information, ranks, ac, tc and/or sc.",
+                "summary": "Get a product by its EAN code",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Test ID",
-                        "name": "test_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Product ID",
-                        "name": "product_id",
+                        "type": "string",
+                        "description": "EAN code",
+                        "name": "code",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Test updates",
-                        "name": "test",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/testsHandler.TestPATCHRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Test updated successfully",
+                        "description": "The product with the EAN code",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/domain.Product"
                         }
                     },
                     "400": {
-                        "description": "Invalid patch request.",
+                        "description": "Invalid EAN code",
                         "schema": {
                             "type": "string"
                         }
                     },
-                    "409": {
-                        "description": "Detected a conflict for the current test, please refresh.",
+                    "404": {
+                        "description": "No product has this EAN code.",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "500": {
-                        "description": "Could not JSON encode the response.",
+                        "description": "Could not retrieve the product.",
                         "schema": {
                             "type": "string"
                         }
@@ -1229,14 +1204,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/user/profile": {
-            "get": {
+        "/products/merge": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieves the user profile for the authenticated user.",
+                "description": "Merges duplicates of a product into it, in one transaction. The results, bundle layers, tournament\nmatches, runs and attachments of the duplicates are moved to the target, and the duplicates are\ndeleted. When a test has results for more than one of the products, only the best ranked result is\nkept, and the same goes for a bundle with more than one of them as layers. The duplicates must be\nproducts of the team, and the target a product of the team or a public product. Without confirm,\nthe merge is only previewed: the rows it would move and remove are returned, and nothing changes.\nA confirmed merge is recorded in the history of the target, as a revision for each duplicate.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1244,36 +1219,47 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "UserProfile"
+                    "Products"
+                ],
+                "summary": "Merge duplicate products",
+                "parameters": [
+                    {
+                        "description": "The target and its duplicates",
+                        "name": "merge",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/productsHandler.ProductMergeRequest"
+                        }
+                    }
                 ],
-                "summary": "Get user profile",
                 "responses": {
                     "200": {
-                        "description": "Successful response with user profile details",
+                        "description": "The rows moved and removed by the merge, or its preview",
                         "schema": {
-                            "$ref": "#/definitions/userProfileHandler.UserProfileResponse"
+                            "$ref": "#/definitions/productsHandler.ProductMergeResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid request URL",
+                        "description": "Invalid request body",
                         "schema": {
                             "type": "string"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "403": {
+                        "description": "User cannot merge the product",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "404": {
-                        "description": "User not found",
+                        "description": "Could not find the product",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "500": {
-                        "description": "Could not create user profile struct.",
+                        "description": "Could not merge the products.",
                         "schema": {
                             "type": "string"
                         }
@@ -1281,63 +1267,107 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/password": {
-            "patch": {
+        "/products/search": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Updates the password of a user.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Searches the products of the team and the public products. The text is matched against the name,\nbrand and comment of a product, where every word must match the start of a word, so \"swi blu\" finds\n\"Swix Blue Extra\". The products can be filtered by type, status, whether they are public, and a\ntemperature that their rated temperature range covers, where a missing bound leaves that side of the\nrange open. The results are sorted by relevance when searching for text and by name otherwise, or\nby version or rating, the overall rating of the product over the tests of the team and the public\ntests.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Products"
                 ],
-                "summary": "Update a user's password",
+                "summary": "Search the products",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Text to search for in the name, brand and comment",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "liquid",
+                            "solid",
+                            "spray",
+                            "powder",
+                            "gel",
+                            "bundle"
+                        ],
+                        "type": "string",
+                        "description": "Type of the products",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "active",
+                            "discontinued",
+                            "development",
+                            "retired"
+                        ],
+                        "type": "string",
+                        "description": "Status of the products",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only public products, or only private products of the team",
+                        "name": "public",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Temperature in °C covered by the rated temperature range",
+                        "name": "covers",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "relevance",
+                            "name",
+                            "version",
+                            "rating"
+                        ],
+                        "type": "string",
+                        "description": "Order of the products",
+                        "name": "sort",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
-                        "description": "User ID",
-                        "name": "user_id",
-                        "in": "path",
-                        "required": true
+                        "description": "Page, starting at 1",
+                        "name": "page",
+                        "in": "query"
                     },
                     {
-                        "description": "New password",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/usersHandler.ChangePasswordRequest"
-                        }
+                        "type": "integer",
+                        "default": 25,
+                        "description": "Products per page, at most 100",
+                        "name": "page_size",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Password updated successfully",
+                        "description": "The products matching the search",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/productsHandler.ProductSearchResponse"
                         }
                     },
                     "400": {
-                        "description": "New password cannot be the same as the current password.",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
+                        "description": "Invalid query parameter",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "500": {
-                        "description": "Failed to commit the transaction.",
+                        "description": "Could not search the products.",
                         "schema": {
                             "type": "string"
                         }
@@ -1345,128 +1375,94 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/{user_id}": {
+        "/products/{id}/history": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieves the user for a specific user ID.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Retrieves the revisions of a product of the team, oldest first. Each revision is an update of the\nproduct, with the old and new value of every field it changed, the user who made it and when.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Products"
                 ],
-                "summary": "Get a user by ID",
+                "summary": "Get the history of a product",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "User ID",
-                        "name": "user_id",
+                        "description": "Product ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Session status",
-                        "name": "status",
-                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successful response with a list of active sessions",
+                        "description": "The revisions of the product",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/domain.Session"
+                                "$ref": "#/definitions/productsHandler.ProductRevision"
                             }
                         }
                     },
-                    "400": {
-                        "description": "Invalid request URL, no user_id found.",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
                     "404": {
-                        "description": "User not found",
+                        "description": "Could not retrieve the product",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "500": {
-                        "description": "Could not create users struct.",
+                        "description": "Could not retrieve the history of the product.",
                         "schema": {
                             "type": "string"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/products/{id}/performance-window": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Deletes an active session of a user.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Retrieves the temperature window in which a product performs in the top quartile of the completed\nand published tests with at least two ranked products. A result is in the top quartile when its\nplacing, from 1 for the winner to 0 for the last product, is at least 0.75. The windows of the snow\nand air temperatures span the results in the top quartile, and count all the results within them.\nThe results of other teams are only included when the test and the rank are public. When the\nproduct belongs to the team and at least 3 results are in the top quartile, an update of the rated\ntemperature range that covers both windows is suggested, which can be sent as is to PATCH /products/{id}.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Products"
                 ],
-                "summary": "Delete a user's active session",
+                "summary": "Get the temperature window of a product",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "User ID",
-                        "name": "user_id",
+                        "description": "Product ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "Session deleted successfully",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "400": {
-                        "description": "Invalid request URL, no user_id or session_id found.",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
+                    "200": {
+                        "description": "The temperature window of the product",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/productsHandler.PerformanceWindowResponse"
                         }
                     },
                     "404": {
-                        "description": "Session not found",
+                        "description": "Could not retrieve the product",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "500": {
-                        "description": "Failed to commit the transaction.",
+                        "description": "Could not retrieve the results of the product.",
                         "schema": {
                             "type": "string"
                         }
@@ -1474,69 +1470,84 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/{user_id}/sessions": {
+        "/products/{id}/rating": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieves the user for a specific user ID.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Retrieves the ratings of a product over all the tests and for each snow type and temperature\nband it has been tested in, or only the rating of the scope given by snow_type or temperature.\nThe tests are those of the team together with the public tests, or only the public tests with\npublic=true.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Ratings"
                 ],
-                "summary": "Get a user by ID",
+                "summary": "Get the ratings of a product",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "User ID",
-                        "name": "user_id",
+                        "description": "Product ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
+                        "enum": [
+                            "A1",
+                            "A2",
+                            "A3",
+                            "A4",
+                            "A5",
+                            "FS",
+                            "NS",
+                            "IN",
+                            "IT",
+                            "TR"
+                        ],
                         "type": "string",
-                        "description": "Session status",
-                        "name": "status",
+                        "description": "Only the rating for this snow type",
+                        "name": "snow_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Only the rating for the temperature band of this snow temperature",
+                        "name": "temperature",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only public tests",
+                        "name": "public",
                         "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successful response with a list of active sessions",
+                        "description": "The ratings of the product",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/domain.Session"
+                                "$ref": "#/definitions/domain.ProductRating"
                             }
                         }
                     },
                     "400": {
-                        "description": "Invalid request URL, no user_id found.",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
+                        "description": "Invalid ID",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "404": {
-                        "description": "User not found",
+                        "description": "Product not found.",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "500": {
-                        "description": "Could not create users struct.",
+                        "description": "Could not retrieve the ratings.",
                         "schema": {
                             "type": "string"
                         }
@@ -1544,177 +1555,5408 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/{user_id}/sessions/{sessionId}": {
-            "delete": {
+        "/products/{id}/revert/{revision}": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Deletes an active session of a user.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Brings a product back to how it was after a revision, by setting every field changed since the\nrevision to its value at the revision. Revision 0 is the product before any recorded change. The\nrevert is validated like an update of the product, and is recorded in its history as a new revision.\nProducts merged into the product are not split off again.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Products"
                 ],
-                "summary": "Delete a user's active session",
+                "summary": "Revert a product to a revision",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "User ID",
-                        "name": "user_id",
+                        "description": "Product ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "integer",
-                        "description": "Session ID",
-                        "name": "sessionId",
+                        "description": "The revision to revert to",
+                        "name": "revision",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "Session deleted successfully",
+                    "200": {
+                        "description": "Product reverted successfully",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "400": {
-                        "description": "Invalid request URL, no user_id or session_id found.",
+                        "description": "The product is already at this revision.",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "401": {
-                        "description": "Unauthorized",
+                        "description": "Validation error: user cannot update this product",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "404": {
-                        "description": "Session not found",
+                        "description": "Could not find the revision of the product.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Could not revert the product because of a conflict, please refresh.",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "500": {
-                        "description": "Failed to commit the transaction.",
+                        "description": "Could not revert the product.",
                         "schema": {
                             "type": "string"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "domain.Product": {
-            "type": "object",
-            "properties": {
-                "brand": {
-                    "type": "string"
-                },
-                "comment": {
-                    "type": "string"
-                },
-                "ean_code": {
-                    "type": "string"
-                },
-                "high_temperature": {
-                    "type": "number"
-                },
-                "id": {
-                    "type": "integer"
-                },
-                "image_url": {
-                    "type": "string"
-                },
-                "is_public": {
-                    "type": "boolean"
-                },
-                "low_temperature": {
-                    "type": "number"
-                },
-                "name": {
-                    "type": "string"
-                },
-                "status": {
-                    "type": "string"
-                },
-                "testing_team": {
-                    "type": "integer"
-                },
-                "type": {
-                    "type": "string"
-                },
-                "version": {
-                    "type": "string"
-                }
-            }
-        },
-        "domain.ProductBundle": {
-            "type": "object",
-            "properties": {
-                "bundle_id": {
-                    "type": "integer"
-                },
-                "layer_no": {
-                    "type": "integer"
-                },
-                "product_id": {
-                    "type": "integer"
-                }
-            }
         },
-        "domain.Session": {
-            "type": "object",
-            "properties": {
-                "created_at": {
-                    "type": "string"
-                },
-                "expires_at": {
-                    "type": "string"
-                },
-                "session_token": {
-                    "type": "string"
-                },
-                "status": {
-                    "type": "string"
-                },
-                "user_id": {
-                    "type": "integer"
+        "/products/{id}/statuses": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves every change of the status of a product of the team, with the reason for it, who made\nit and when, oldest first. A product moves from development to active or retired, from active to\ndiscontinued or retired, and from discontinued back to active or to retired. A retired product can\nnot change its status. The bundles of the team that contain a discontinued or retired product move\nto the same status.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Get the status changes of a product",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The status changes of the product",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.ProductStatusChange"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Could not retrieve the product",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not retrieve the status changes.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
                 }
             }
         },
-        "domain.Test": {
-            "type": "object",
-            "properties": {
-                "ac_id": {
-                    "type": "integer"
-                },
-                "comment": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "integer"
-                },
-                "is_public": {
-                    "type": "boolean"
-                },
-                "location": {
-                    "type": "string"
-                },
+        "/products/{products_id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves a list of products based on query parameters.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Get a list of products",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product id",
+                        "name": "id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Publicly Available Product",
+                        "name": "public",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Fields to retrieve",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successful response with a list of products",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.Product"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Could not retrieve all products.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/rankings": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves a list of rankings based on query parameters, or the ranking of a product in a test.\nThe rankings of the tests of the team are always visible, while the rankings of other teams are\nonly visible when both the test and the rank are public. Without any filter the rankings of the\nteam are listed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rankings"
+                ],
+                "summary": "Get a list of rankings",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only public rankings",
+                        "name": "public",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Test ID",
+                        "name": "test_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "product_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Team ID",
+                        "name": "team_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successful response with a list of rankings",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.TestRank"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid test_id parameter.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Ranking not found.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not retrieve all testRanks.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Adds the result of a product to a test of the team. The ranking is public if the product is,\nunless is_public is given. A product ranked outside its rated temperature range is flagged and\nreturned as a warning, or refused when the team has a strict temperature range.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rankings"
+                ],
+                "summary": "Create a new ranking",
+                "parameters": [
+                    {
+                        "description": "New ranking information",
+                        "name": "ranking",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rankingsHandler.RankingsPOSTRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Ranking created successfully",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Products were tested outside their rated temperature range",
+                        "schema": {
+                            "$ref": "#/definitions/testsHandler.TestTemperatureResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "User cannot update this test",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Test not found.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Test is completed and cannot be changed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not create ranking.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/rankings/": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Adds the result of a product to a test of the team. The ranking is public if the product is,\nunless is_public is given. A product ranked outside its rated temperature range is flagged and\nreturned as a warning, or refused when the team has a strict temperature range.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rankings"
+                ],
+                "summary": "Create a new ranking",
+                "parameters": [
+                    {
+                        "description": "New ranking information",
+                        "name": "ranking",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rankingsHandler.RankingsPOSTRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Ranking created successfully",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Products were tested outside their rated temperature range",
+                        "schema": {
+                            "$ref": "#/definitions/testsHandler.TestTemperatureResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "User cannot update this test",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Test not found.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Test is completed and cannot be changed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not create ranking.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/rankings/{test_id}/{product_id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves a list of rankings based on query parameters, or the ranking of a product in a test.\nThe rankings of the tests of the team are always visible, while the rankings of other teams are\nonly visible when both the test and the rank are public. Without any filter the rankings of the\nteam are listed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rankings"
+                ],
+                "summary": "Get a list of rankings",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only public rankings",
+                        "name": "public",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Test ID",
+                        "name": "test_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "product_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Team ID",
+                        "name": "team_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successful response with a list of rankings",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.TestRank"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid test_id parameter.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Ranking not found.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not retrieve all testRanks.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes the result of a product from a test of the team.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rankings"
+                ],
+                "summary": "Delete a ranking",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Test ID",
+                        "name": "test_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "product_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Ranking deleted successfully"
+                    },
+                    "401": {
+                        "description": "User cannot update this test",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Ranking not found.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Test is completed and cannot be changed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not delete the ranking.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Updates the rank, distance behind and visibility of a product in a test. The version must be the\nversion of the ranking the update is based on, and the update is rejected with a conflict if the\nranking has been changed since.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rankings"
+                ],
+                "summary": "Update a ranking",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Test ID",
+                        "name": "test_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "product_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Ranking updates",
+                        "name": "ranking",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rankingsHandler.RankingsPATCHRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ranking updated successfully",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid PATCH request body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "User cannot update this test",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Ranking not found.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Test is completed and cannot be changed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not update the ranking.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/ratings/leaderboard": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves the best rated products. The ratings are fitted with a Bradley-Terry model over the\ncompleted and published tests, where 1500 is an average product and a difference of 400 points\nmeans the better product wins 10 of 11 comparisons. The ratings are fitted over all the tests, or\nover the tests of a snow type or a 5 °C snow temperature band. The tests are those of the team\ntogether with the public tests, or only the public tests with public=true.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Ratings"
+                ],
+                "summary": "Get the leaderboard of the products",
+                "parameters": [
+                    {
+                        "enum": [
+                            "A1",
+                            "A2",
+                            "A3",
+                            "A4",
+                            "A5",
+                            "FS",
+                            "NS",
+                            "IN",
+                            "IT",
+                            "TR"
+                        ],
+                        "type": "string",
+                        "description": "Only tests of this snow type",
+                        "name": "snow_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Only tests in the temperature band of this snow temperature",
+                        "name": "temperature",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only public tests",
+                        "name": "public",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only products rated in at least this many tests",
+                        "name": "min_tests",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of products, 50 by default and at most 500",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The best rated products",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/ratingsHandler.LeaderboardEntry"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid snow_type.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not retrieve the leaderboard.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/recommendations": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Ranks the products and bundles by how they placed in the completed and published tests with\nconditions similar to the given ones. Each test is weighted by the distance between its snow, air\nand track conditions and the given conditions, and tests that are too far away are left out. The\nscore is the expected placing, from 0 for last to 1 for first, and the confidence grows with the\nnumber of close tests. The tests are those of the team together with the public tests.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Recommendations"
+                ],
+                "summary": "Recommend products for the conditions",
+                "parameters": [
+                    {
+                        "description": "The conditions",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/recommendationsHandler.RecommendationPOSTRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The recommended products, best first",
+                        "schema": {
+                            "$ref": "#/definitions/recommendationsHandler.RecommendationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid POST request body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not retrieve the tests.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/register/": {
+            "post": {
+                "description": "Registers a new user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Registration"
+                ],
+                "summary": "Register user",
+                "parameters": [
+                    {
+                        "description": "User credentials",
+                        "name": "credentials",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/registrationHandler.RegistrationPOSTRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "User created successfully"
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "405": {
+                        "description": "Method not allowed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "User already exists",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not create user",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/skis": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves all the skis of the user's team, a single ski, or the product results from the tests\nwhere the ski pair was used.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Skis"
+                ],
+                "summary": "Get the test skis",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Only results for this product",
+                        "name": "product_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successful response with a list of skis",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.Ski"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Could not find the ski.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not retrieve the skis.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Adds a new ski pair to the fleet of the user's team.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Skis"
+                ],
+                "summary": "Create a new ski",
+                "parameters": [
+                    {
+                        "description": "New ski information",
+                        "name": "ski",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/skisHandler.SkiPOSTRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Ski created successfully",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid POST request body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "A ski with this ski code already exists",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not create the ski.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/skis/{ski_id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves all the skis of the user's team, a single ski, or the product results from the tests\nwhere the ski pair was used.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Skis"
+                ],
+                "summary": "Get the test skis",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Ski ID",
+                        "name": "ski_id",
+                        "in": "path"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only results for this product",
+                        "name": "product_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successful response with a list of skis",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.Ski"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Could not find the ski.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not retrieve the skis.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes a ski of the user's team. Test ranks that used the ski are kept without the ski reference.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Skis"
+                ],
+                "summary": "Delete a ski",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Ski ID",
+                        "name": "ski_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Ski deleted successfully"
+                    },
+                    "404": {
+                        "description": "Could not find the ski.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not delete the ski.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Updates fields of an existing ski of the user's team.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Skis"
+                ],
+                "summary": "Update a ski",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Ski ID",
+                        "name": "ski_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Ski update fields",
+                        "name": "ski",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/skisHandler.SkiPATCHRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ski updated successfully",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid PATCH request body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Could not find the ski.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Detected a conflict for the current ski, please refresh.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/skis/{ski_id}/results": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves all the skis of the user's team, a single ski, or the product results from the tests\nwhere the ski pair was used.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Skis"
+                ],
+                "summary": "Get the test skis",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Ski ID",
+                        "name": "ski_id",
+                        "in": "path"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only results for this product",
+                        "name": "product_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successful response with a list of skis",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.Ski"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Could not find the ski.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not retrieve the skis.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/tests": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves a list of tests based on query parameters.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tests"
+                ],
+                "summary": "Get a list of tests",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "All Public Tests",
+                        "name": "public",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start Date in YYYY-MM-DD format",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date in YYYY-MM-DD format",
+                        "name": "end_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only tests at this location",
+                        "name": "location_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only tests on this track",
+                        "name": "track_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "planned",
+                            "in_progress",
+                            "completed",
+                            "published"
+                        ],
+                        "type": "string",
+                        "description": "Only tests in this state",
+                        "name": "state",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successful response with a list of tests",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.Test"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid start date format.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not retrieve all tests.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Adds a new test to the database. Every ranked product is checked against the snow and air\ntemperatures of the test, and the results outside the rated temperature range of the product are\nflagged and returned as warnings. A team with a strict temperature range has such tests refused.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tests"
+                ],
+                "summary": "Create a new test",
+                "parameters": [
+                    {
+                        "description": "New test information",
+                        "name": "test",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/testsHandler.TestPOSTRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Test created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/testsHandler.TestTemperatureResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Products tested outside their temperature range",
+                        "schema": {
+                            "$ref": "#/definitions/testsHandler.TestTemperatureResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not create test.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/tests/": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves a list of tests based on query parameters.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tests"
+                ],
+                "summary": "Get a list of tests",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "All Public Tests",
+                        "name": "public",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start Date in YYYY-MM-DD format",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date in YYYY-MM-DD format",
+                        "name": "end_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only tests at this location",
+                        "name": "location_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only tests on this track",
+                        "name": "track_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "planned",
+                            "in_progress",
+                            "completed",
+                            "published"
+                        ],
+                        "type": "string",
+                        "description": "Only tests in this state",
+                        "name": "state",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successful response with a list of tests",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.Test"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid start date format.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not retrieve all tests.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Adds a new test to the database. Every ranked product is checked against the snow and air\ntemperatures of the test, and the results outside the rated temperature range of the product are\nflagged and returned as warnings. A team with a strict temperature range has such tests refused.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tests"
+                ],
+                "summary": "Create a new test",
+                "parameters": [
+                    {
+                        "description": "New test information",
+                        "name": "test",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/testsHandler.TestPOSTRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Test created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/testsHandler.TestTemperatureResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Products tested outside their temperature range",
+                        "schema": {
+                            "$ref": "#/definitions/testsHandler.TestTemperatureResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not create test.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Updates an existing test in the database. The state of a test moves from planned to in_progress,\nbetween in_progress and completed, and from completed to published, which also makes the test\npublic. Completed and published tests are read-only, so only their state can be changed. When the\nsnow or air temperature changes, the ranked products are checked against the new temperatures and\nthe results outside their rated temperature range are flagged and returned as warnings, or the\nupdate is refused for a team with a strict temperature range.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tests"
+                ],
+                "summary": "Update an existing test's information, ranks, ac, tc and/or sc.",
+                "parameters": [
+                    {
+                        "description": "Test updates",
+                        "name": "test",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/testsHandler.TestPATCHRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Test updated successfully",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Products tested outside their temperature range",
+                        "schema": {
+                            "$ref": "#/definitions/testsHandler.TestTemperatureResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Test is completed and cannot be changed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not JSON encode the response.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/tests/drafts": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves a draft, or all the drafts of the user's team with the most recently edited first.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tests"
+                ],
+                "summary": "Get test drafts",
+                "responses": {
+                    "200": {
+                        "description": "Successful response with the drafts",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/testsHandler.TestDraftResponse"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Could not find the draft.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not retrieve the drafts.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Stores a partially filled test for the user's team. All fields are optional.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tests"
+                ],
+                "summary": "Create a new test draft",
+                "parameters": [
+                    {
+                        "description": "Draft information",
+                        "name": "draft",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/testsHandler.TestDraftRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Draft created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/testsHandler.TestDraftResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid POST request body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not create the draft.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/tests/drafts/{draft_id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves a draft, or all the drafts of the user's team with the most recently edited first.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tests"
+                ],
+                "summary": "Get test drafts",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Draft ID",
+                        "name": "draft_id",
+                        "in": "path"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successful response with the drafts",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/testsHandler.TestDraftResponse"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Could not find the draft.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not retrieve the drafts.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes a draft of the user's team.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tests"
+                ],
+                "summary": "Delete a test draft",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Draft ID",
+                        "name": "draft_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Draft deleted successfully"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Could not find the draft.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not delete the draft.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Applies a JSON merge patch to a draft. Fields set to null are removed from the draft,\nnested objects are merged and arrays are replaced.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tests"
+                ],
+                "summary": "Update a test draft",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Draft ID",
+                        "name": "draft_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Draft updates",
+                        "name": "draft",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/testsHandler.TestDraftPATCHRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Draft updated successfully",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid PATCH request body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Could not find the draft.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Detected a conflict for the current draft, please refresh.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not update the draft.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/tests/drafts/{draft_id}/promote": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Validates a draft as a new test, creates the test with its conditions and ranks,\nand deletes the draft, all in one transaction.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tests"
+                ],
+                "summary": "Promote a test draft",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Draft ID",
+                        "name": "draft_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Draft promoted successfully",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Products tested outside their temperature range",
+                        "schema": {
+                            "$ref": "#/definitions/testsHandler.TestTemperatureResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Could not find the draft.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to create test.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/tests/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Exports the tests with one row for each product result, together with the test and its snow, air\nand track conditions. A test without results is exported as a single row without a product. Only\ncompleted and published tests are exported, otherwise the tests are the same as in the list of\ntests: the public tests with public=true, and otherwise the tests of the team. The results of the\ntests of other teams are only included when the rank is public, without their skis. In CSV and\nXLSX, text starting with =, +, - or @ is prefixed with a quote so it is not read as a formula.",
+                "produces": [
+                    "text/csv",
+                    "application/x-ndjson",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "Tests"
+                ],
+                "summary": "Export tests with their conditions and results",
+                "parameters": [
+                    {
+                        "enum": [
+                            "csv",
+                            "jsonl",
+                            "xlsx"
+                        ],
+                        "type": "string",
+                        "description": "Export format, defaults to csv",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "All Public Tests",
+                        "name": "public",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start Date in YYYY-MM-DD format",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date in YYYY-MM-DD format",
+                        "name": "end_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only tests at this location",
+                        "name": "location_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only tests on this track",
+                        "name": "track_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "completed",
+                            "published"
+                        ],
+                        "type": "string",
+                        "description": "Only tests in this state",
+                        "name": "state",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The exported rows",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/testsHandler.TestExportRow"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid format, use csv, jsonl or xlsx.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not export the tests.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/tests/import": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates tests from a CSV file with one row for each product result. The columns are test_key,\ntest_date, location, location_id, track_id, comment, is_public, state, sc_temperature, snow_type,\nsnow_humidity, ac_temperature, air_humidity, wind, cloud, track_hardness, track_type, product_id,\nrank, distance_behind and ski_id, and only product_id is required. Rows with the same test_key, or\nwithout one the same date and venue, make up a test, and the test columns only have to be filled\nin on the first row of a test. The rows are validated with the same rules as a new test, and all\nthe tests are created in one transaction, so nothing is created if a row is invalid. Imported\ntests are completed unless a state is given. Products ranked outside their rated temperature range\nare flagged and listed as warnings, or refused for a team with a strict temperature range. A dry\nrun only validates the file, without the temperature ranges.",
+                "consumes": [
+                    "text/csv",
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tests"
+                ],
+                "summary": "Import tests from a CSV file",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "CSV file, unless the file is the request body",
+                        "name": "file",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only validate the file",
+                        "name": "dry_run",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Validation report of a dry run",
+                        "schema": {
+                            "$ref": "#/definitions/testsHandler.TestImportReport"
+                        }
+                    },
+                    "201": {
+                        "description": "Tests imported successfully",
+                        "schema": {
+                            "$ref": "#/definitions/testsHandler.TestImportReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation report of an invalid file",
+                        "schema": {
+                            "$ref": "#/definitions/testsHandler.TestImportReport"
+                        }
+                    },
+                    "413": {
+                        "description": "The file is larger than 5 MB.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not import the tests.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/tests/similar": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves the completed and published tests whose conditions are closest to the given ones,\nclosest first, with the winning products of each test. The distance is a weighted distance over\nthe snow and air temperatures, the air humidity and the levels of the snow type, snow humidity,\nwind, cloud, track hardness and track type, where each level is placed evenly between the first\nand the last level. Conditions that are left out are not compared. The weights replace the\ndefault weights, which weigh the snow the most, and a weight of 0 ignores a condition. The tests\nare those of the team together with the public tests, or only the public tests.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tests"
+                ],
+                "summary": "Find the tests with the closest conditions",
+                "parameters": [
+                    {
+                        "description": "The conditions",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/testsHandler.SimilarTestsPOSTRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The closest tests",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/testsHandler.SimilarTest"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid POST request body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not retrieve the tests.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/tests/{test_id}/conditions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves the timestamped condition readings of a test with the minimum, maximum and mean of the\ntemperatures and air humidity. With the at parameter, only the reading closest to that moment is\nreturned, which matches a run with the conditions when it was made.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Conditions"
+                ],
+                "summary": "Get the condition readings of a test",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Test ID",
+                        "name": "test_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC 3339 timestamp to find the closest reading for",
+                        "name": "at",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successful response with the readings",
+                        "schema": {
+                            "$ref": "#/definitions/domain.TestConditions"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request URL",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Could not retrieve the test.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not retrieve the condition readings.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Appends timestamped snow and air readings to a test. All the readings are added, or none.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Conditions"
+                ],
+                "summary": "Add condition readings to a test",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Test ID",
+                        "name": "test_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New readings",
+                        "name": "readings",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/conditionsHandler.ConditionReadingsPOSTRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Condition readings added successfully",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid POST request body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Could not retrieve the test.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Test is completed and cannot be changed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not add the condition readings.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/tests/{test_id}/products/{product_id}": {
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Updates an existing test in the database. The state of a test moves from planned to in_progress,\nbetween in_progress and completed, and from completed to published, which also makes the test\npublic. Completed and published tests are read-only, so only their state can be changed. When the\nsnow or air temperature changes, the ranked products are checked against the new temperatures and\nthe results outside their rated temperature range are flagged and returned as warnings, or the\nupdate is refused for a team with a strict temperature range.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tests"
+                ],
+                "summary": "Update an existing test's information, ranks, ac, tc and/or sc.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Test ID",
+                        "name": "test_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "product_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Test updates",
+                        "name": "test",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/testsHandler.TestPATCHRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Test updated successfully",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Products tested outside their temperature range",
+                        "schema": {
+                            "$ref": "#/definitions/testsHandler.TestTemperatureResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Test is completed and cannot be changed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not JSON encode the response.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/tests/{test_id}/runs": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves the raw runs of a test grouped by product, with the mean, standard deviation, rank and\ndistance behind computed from the runs.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Runs"
+                ],
+                "summary": "Get the runs of a test",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Test ID",
+                        "name": "test_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successful response with the runs",
+                        "schema": {
+                            "$ref": "#/definitions/domain.TestRuns"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request URL",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Could not retrieve the test.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not retrieve the runs.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Records raw glide runs for the products in a test, either glide-out distances in meters or gate\ntimes in seconds. All the runs of a test must use the same metric. The rank and distance behind\nof every product are recomputed from all the runs of the test and written to the test ranks.\nThe ski pair of a run must belong to the team.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Runs"
+                ],
+                "summary": "Record runs for a test",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Test ID",
+                        "name": "test_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New runs",
+                        "name": "runs",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/runsHandler.RunsPOSTRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Runs recorded successfully",
+                        "schema": {
+                            "$ref": "#/definitions/domain.TestRuns"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid POST request body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Could not retrieve the test.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "The runs of a test must use the same metric.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not record the runs.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/tests/{test_id}/states": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves every change of the state of a test, with who made it and when, oldest first.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tests"
+                ],
+                "summary": "Get the state changes of a test",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Test ID",
+                        "name": "test_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successful response with the state changes",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.TestStateChange"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Could not retrieve the test.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not retrieve the state changes.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/tests/{test_id}/tournament": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves the head-to-head bracket of a test, grouped by round.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tournaments"
+                ],
+                "summary": "Get the tournament of a test",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Test ID",
+                        "name": "test_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successful response with the tournament",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Tournament"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request URL",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "No tournament found for this test.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not retrieve the tournament.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Generates a single elimination bracket from the products in a test. The products are seeded in\nthe given order, or by their current rank in the test. Only products ranked in the test can be\nseeded. Products without an opponent in the first round get a walkover.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tournaments"
+                ],
+                "summary": "Create a tournament for a test",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Test ID",
+                        "name": "test_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Seeded products",
+                        "name": "tournament",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/tournamentHandler.TournamentPOSTRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Tournament created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Tournament"
+                        }
+                    },
+                    "400": {
+                        "description": "A tournament needs at least two products, or a product is not in the test.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Could not retrieve the test.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "This test already has a tournament.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not create the tournament.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/tests/{test_id}/tournament/matches/{match_id}": {
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Records both battles of a match, decides the winner by the summed battle score and advances it\nto the next round. When the final is decided, the rank and distance behind of every product is\nwritten to the test ranks.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tournaments"
+                ],
+                "summary": "Record the result of a match",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Test ID",
+                        "name": "test_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Match ID",
+                        "name": "match_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Battle results",
+                        "name": "match",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/tournamentHandler.MatchResultPATCHRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Match updated successfully",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid PATCH request body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "No tournament found for this test.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Detected a conflict for the current match, please refresh.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not update the match.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/profile": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves the user profile for the authenticated user.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "UserProfile"
+                ],
+                "summary": "Get user profile",
+                "responses": {
+                    "200": {
+                        "description": "Successful response with user profile details",
+                        "schema": {
+                            "$ref": "#/definitions/userProfileHandler.UserProfileResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request URL",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not create user profile struct.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Updates the settings of the team of the authenticated user, which must be an admin. With a strict\ntemperature range, results of products tested outside their rated temperature range are refused.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "UserProfile"
+                ],
+                "summary": "Update the team settings",
+                "parameters": [
+                    {
+                        "description": "Team settings",
+                        "name": "settings",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/userProfileHandler.UserProfilePATCHRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Team settings updated successfully",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid PATCH request body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not update the team settings.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/password": {
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Updates the password of a user.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Update a user's password",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New password",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/usersHandler.ChangePasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Password updated successfully",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "New password cannot be the same as the current password.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to commit the transaction.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user_id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves the user for a specific user ID.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get a user by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Session status",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successful response with a list of active sessions",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.Session"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request URL, no user_id found.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not create users struct.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes an active session of a user.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Delete a user's active session",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Session deleted successfully",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request URL, no user_id or session_id found.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Session not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to commit the transaction.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user_id}/sessions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves the user for a specific user ID.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get a user by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Session status",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successful response with a list of active sessions",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.Session"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request URL, no user_id found.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not create users struct.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user_id}/sessions/{sessionId}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes an active session of a user.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Delete a user's active session",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Session ID",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Session deleted successfully",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request URL, no user_id or session_id found.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Session not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to commit the transaction.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/{parent}/{parent_id}/attachments": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves the attachments of a test or product. With an attachment ID the file is downloaded,\nand with '/thumbnail' the JPEG thumbnail of an image is downloaded instead. The attachments are\nvisible to the users who can see the test or product.",
+                "produces": [
+                    "application/json",
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "Attachments"
+                ],
+                "summary": "Get attachments",
+                "parameters": [
+                    {
+                        "enum": [
+                            "tests",
+                            "products"
+                        ],
+                        "type": "string",
+                        "description": "Parent entity",
+                        "name": "parent",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Test or product ID",
+                        "name": "parent_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successful response with the attachments",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.Attachment"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request URL",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Could not retrieve the test or product.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not retrieve the attachments.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Uploads a photo or file to a test or product as a multipart form with a 'file' field. The type is\ndetected from the content, and only JPEG, PNG, GIF and WebP images, PDF, plain text and CSV files\nup to 10 MB are accepted. A thumbnail is made for JPEG, PNG and GIF images. Completed and\npublished tests are read-only.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Attachments"
+                ],
+                "summary": "Upload an attachment",
+                "parameters": [
+                    {
+                        "enum": [
+                            "tests",
+                            "products"
+                        ],
+                        "type": "string",
+                        "description": "Parent entity",
+                        "name": "parent",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Test or product ID",
+                        "name": "parent_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "File to attach",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Attachment uploaded successfully",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Attachment"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid upload",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Could not retrieve the test or product.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Test is completed and cannot be changed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "413": {
+                        "description": "The file is larger than 10 MB.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported file type.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not store the file.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/{parent}/{parent_id}/attachments/{attachment_id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves the attachments of a test or product. With an attachment ID the file is downloaded,\nand with '/thumbnail' the JPEG thumbnail of an image is downloaded instead. The attachments are\nvisible to the users who can see the test or product.",
+                "produces": [
+                    "application/json",
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "Attachments"
+                ],
+                "summary": "Get attachments",
+                "parameters": [
+                    {
+                        "enum": [
+                            "tests",
+                            "products"
+                        ],
+                        "type": "string",
+                        "description": "Parent entity",
+                        "name": "parent",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Test or product ID",
+                        "name": "parent_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successful response with the attachments",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.Attachment"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request URL",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Could not retrieve the test or product.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not retrieve the attachments.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes an attachment of a test or product together with its stored files.",
+                "tags": [
+                    "Attachments"
+                ],
+                "summary": "Delete an attachment",
+                "parameters": [
+                    {
+                        "enum": [
+                            "tests",
+                            "products"
+                        ],
+                        "type": "string",
+                        "description": "Parent entity",
+                        "name": "parent",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Test or product ID",
+                        "name": "parent_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Attachment ID",
+                        "name": "attachment_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Attachment deleted successfully",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request URL",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Could not find the attachment.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Test is completed and cannot be changed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not delete the attachment.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/{parent}/{parent_id}/attachments/{attachment_id}/thumbnail": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves the attachments of a test or product. With an attachment ID the file is downloaded,\nand with '/thumbnail' the JPEG thumbnail of an image is downloaded instead. The attachments are\nvisible to the users who can see the test or product.",
+                "produces": [
+                    "application/json",
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "Attachments"
+                ],
+                "summary": "Get attachments",
+                "parameters": [
+                    {
+                        "enum": [
+                            "tests",
+                            "products"
+                        ],
+                        "type": "string",
+                        "description": "Parent entity",
+                        "name": "parent",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Test or product ID",
+                        "name": "parent_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successful response with the attachments",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.Attachment"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request URL",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Could not retrieve the test or product.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not retrieve the attachments.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "bundlesHandler.BundleLayerPOST": {
+            "type": "object",
+            "required": [
+                "product_id"
+            ],
+            "properties": {
+                "product_id": {
+                    "type": "integer"
+                },
+                "recipe": {
+                    "$ref": "#/definitions/bundlesHandler.LayerRecipePOST"
+                }
+            }
+        },
+        "bundlesHandler.BundlePATCHRequest": {
+            "type": "object",
+            "properties": {
+                "layers": {
+                    "type": "array",
+                    "maxItems": 20,
+                    "minItems": 1,
+                    "uniqueItems": true,
+                    "items": {
+                        "$ref": "#/definitions/bundlesHandler.BundleLayerPOST"
+                    }
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 64,
+                    "minLength": 1
+                },
+                "products": {
+                    "description": "Products are the layers of the bundle, in the order they are applied. They replace the current layers, so\nlayers are added, removed and reordered by sending the list as it should be. Layers can be given instead, with\nthe recipe for applying each of them. Without either, the layers are kept.",
+                    "type": "array",
+                    "maxItems": 20,
+                    "minItems": 1,
+                    "uniqueItems": true,
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
+        "bundlesHandler.BundlePOSTRequest": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "type": "string"
+                },
+                "is_public": {
+                    "type": "boolean"
+                },
+                "layers": {
+                    "type": "array",
+                    "maxItems": 20,
+                    "uniqueItems": true,
+                    "items": {
+                        "$ref": "#/definitions/bundlesHandler.BundleLayerPOST"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "products": {
+                    "description": "Products are the layers of the bundle, in the order they are applied, without recipes. Layers can be given\ninstead, with the recipe for applying each of them.",
+                    "type": "array",
+                    "uniqueItems": true,
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "status": {
+                    "type": "string"
+                },
+                "testing_team": {
+                    "type": "integer"
+                }
+            }
+        },
+        "bundlesHandler.LayerRecipePOST": {
+            "type": "object",
+            "required": [
+                "method"
+            ],
+            "properties": {
+                "brushing": {
+                    "type": "array",
+                    "maxItems": 8,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "cooling_minutes": {
+                    "type": "integer",
+                    "maximum": 240,
+                    "minimum": 0
+                },
+                "cork": {
+                    "type": "boolean"
+                },
+                "fleece": {
+                    "type": "boolean"
+                },
+                "iron_temperature": {
+                    "type": "integer",
+                    "maximum": 180,
+                    "minimum": 90
+                },
+                "method": {
+                    "type": "string",
+                    "enum": [
+                        "iron",
+                        "rub",
+                        "spray",
+                        "applicator"
+                    ]
+                },
+                "passes": {
+                    "type": "integer",
+                    "maximum": 20,
+                    "minimum": 1
+                }
+            }
+        },
+        "conditionsHandler.AirReadingPOST": {
+            "type": "object",
+            "properties": {
+                "cloud": {
+                    "type": "string",
+                    "enum": [
+                        "1",
+                        "2",
+                        "3",
+                        "4"
+                    ]
+                },
+                "humidity": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 0
+                },
+                "temperature": {
+                    "type": "number",
+                    "maximum": 100,
+                    "minimum": -100
+                },
+                "wind": {
+                    "type": "string",
+                    "enum": [
+                        "S",
+                        "L",
+                        "M",
+                        "ST"
+                    ]
+                }
+            }
+        },
+        "conditionsHandler.ConditionReadingPOST": {
+            "type": "object",
+            "required": [
+                "ac",
+                "recorded_at",
+                "sc"
+            ],
+            "properties": {
+                "ac": {
+                    "$ref": "#/definitions/conditionsHandler.AirReadingPOST"
+                },
+                "recorded_at": {
+                    "type": "string"
+                },
+                "sc": {
+                    "$ref": "#/definitions/conditionsHandler.SnowReadingPOST"
+                }
+            }
+        },
+        "conditionsHandler.ConditionReadingsPOSTRequest": {
+            "type": "object",
+            "required": [
+                "readings"
+            ],
+            "properties": {
+                "readings": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/conditionsHandler.ConditionReadingPOST"
+                    }
+                }
+            }
+        },
+        "conditionsHandler.SnowReadingPOST": {
+            "type": "object",
+            "properties": {
+                "snow_humidity": {
+                    "type": "string",
+                    "enum": [
+                        "DS",
+                        "W1",
+                        "W2",
+                        "W3",
+                        "W4"
+                    ]
+                },
+                "snow_type": {
+                    "type": "string",
+                    "enum": [
+                        "A1",
+                        "A2",
+                        "A3",
+                        "A4",
+                        "A5",
+                        "FS",
+                        "NS",
+                        "IN",
+                        "IT",
+                        "TR"
+                    ]
+                },
+                "temperature": {
+                    "type": "number",
+                    "maximum": 100,
+                    "minimum": -100
+                }
+            }
+        },
+        "domain.AirConditions": {
+            "type": "object",
+            "properties": {
+                "cloud": {
+                    "description": "'1', '2', '3', '4'",
+                    "type": "string"
+                },
+                "humidity": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "temperature": {
+                    "type": "number"
+                },
+                "wind": {
+                    "description": "'S', 'L', 'M', 'ST'",
+                    "type": "string"
+                }
+            }
+        },
+        "domain.AirReading": {
+            "type": "object",
+            "properties": {
+                "cloud": {
+                    "description": "'1', '2', '3', '4'",
+                    "type": "string"
+                },
+                "humidity": {
+                    "type": "integer"
+                },
+                "temperature": {
+                    "type": "number"
+                },
+                "wind": {
+                    "description": "'S', 'L', 'M', 'ST'",
+                    "type": "string"
+                }
+            }
+        },
+        "domain.Attachment": {
+            "type": "object",
+            "properties": {
+                "content_type": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "file_name": {
+                    "type": "string"
+                },
+                "has_thumbnail": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "size": {
+                    "description": "Size in bytes.",
+                    "type": "integer"
+                },
+                "test_id": {
+                    "type": "integer"
+                },
+                "testing_team": {
+                    "type": "integer"
+                },
+                "uploaded_by": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.Bundle": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "layers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.BundleLayer"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "testing_team": {
+                    "type": "integer"
+                },
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.BundleLayer": {
+            "type": "object",
+            "properties": {
+                "layer_no": {
+                    "type": "integer"
+                },
+                "product": {
+                    "$ref": "#/definitions/domain.Product"
+                },
+                "recipe": {
+                    "$ref": "#/definitions/domain.LayerRecipe"
+                }
+            }
+        },
+        "domain.ConditionReading": {
+            "type": "object",
+            "properties": {
+                "ac": {
+                    "$ref": "#/definitions/domain.AirReading"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "recorded_at": {
+                    "type": "string"
+                },
+                "sc": {
+                    "$ref": "#/definitions/domain.SnowReading"
+                },
+                "test_id": {
+                    "type": "integer"
+                },
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.ConditionSummary": {
+            "type": "object",
+            "properties": {
+                "air_humidity": {
+                    "$ref": "#/definitions/domain.ReadingRange"
+                },
+                "air_temperature": {
+                    "$ref": "#/definitions/domain.ReadingRange"
+                },
+                "count": {
+                    "type": "integer"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "snow_temperature": {
+                    "$ref": "#/definitions/domain.ReadingRange"
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.LayerRecipe": {
+            "type": "object",
+            "properties": {
+                "brushing": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "cooling_minutes": {
+                    "type": "integer"
+                },
+                "cork": {
+                    "type": "boolean"
+                },
+                "fleece": {
+                    "type": "boolean"
+                },
+                "iron_temperature": {
+                    "type": "integer"
+                },
+                "method": {
+                    "type": "string"
+                },
+                "passes": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.Location": {
+            "type": "object",
+            "properties": {
+                "altitude": {
+                    "description": "Altitude in meters above sea level.",
+                    "type": "integer"
+                },
+                "created_by": {
+                    "description": "CreatedBy: The team that created the location and may change it. Empty for locations only official teams may\nchange.",
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "latitude": {
+                    "type": "number"
+                },
+                "longitude": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "tracks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Track"
+                    }
+                },
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.Product": {
+            "type": "object",
+            "properties": {
+                "brand": {
+                    "type": "string"
+                },
+                "comment": {
+                    "type": "string"
+                },
+                "ean_code": {
+                    "type": "string"
+                },
+                "high_temperature": {
+                    "type": "number"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "image_url": {
+                    "type": "string"
+                },
+                "is_public": {
+                    "type": "boolean"
+                },
+                "low_temperature": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "testing_team": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.ProductBundle": {
+            "type": "object",
+            "properties": {
+                "bundle_id": {
+                    "type": "integer"
+                },
+                "layer_no": {
+                    "type": "integer"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "recipe": {
+                    "$ref": "#/definitions/domain.LayerRecipe"
+                }
+            }
+        },
+        "domain.ProductRating": {
+            "type": "object",
+            "properties": {
+                "comparisons": {
+                    "type": "integer"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "rating": {
+                    "type": "number"
+                },
+                "scope_type": {
+                    "description": "all, snow_type or temperature",
+                    "type": "string"
+                },
+                "scope_value": {
+                    "description": "The snow type, or the lower bound of the temperature band.",
+                    "type": "string"
+                },
+                "tests": {
+                    "type": "integer"
+                },
+                "uncertainty": {
+                    "description": "Standard error of the rating.",
+                    "type": "number"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.ProductStatusChange": {
+            "type": "object",
+            "properties": {
+                "changed_at": {
+                    "type": "string"
+                },
+                "changed_by": {
+                    "type": "integer"
+                },
+                "from_status": {
+                    "$ref": "#/definitions/domain.Status"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "to_status": {
+                    "$ref": "#/definitions/domain.Status"
+                }
+            }
+        },
+        "domain.ReadingRange": {
+            "type": "object",
+            "properties": {
+                "max": {
+                    "type": "number"
+                },
+                "mean": {
+                    "type": "number"
+                },
+                "min": {
+                    "type": "number"
+                }
+            }
+        },
+        "domain.RunMetric": {
+            "type": "string",
+            "enum": [
+                "distance",
+                "time"
+            ],
+            "x-enum-comments": {
+                "RunDistance": "Glide-out distance in meters, the longest run is the best",
+                "RunTime": "Gate time in seconds, the shortest run is the best"
+            },
+            "x-enum-varnames": [
+                "RunDistance",
+                "RunTime"
+            ]
+        },
+        "domain.RunSummary": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "distance_behind": {
+                    "type": "integer"
+                },
+                "mean": {
+                    "type": "number"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "rank": {
+                    "type": "integer"
+                },
+                "runs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.TestRun"
+                    }
+                },
+                "std_dev": {
+                    "description": "Sample standard deviation, zero for a single run.",
+                    "type": "number"
+                }
+            }
+        },
+        "domain.Session": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "session_token": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.Ski": {
+            "type": "object",
+            "properties": {
+                "base": {
+                    "type": "string"
+                },
+                "brand": {
+                    "type": "string"
+                },
+                "flex": {
+                    "type": "string"
+                },
+                "grind": {
+                    "description": "The grind or structure of the base.",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "length": {
+                    "description": "Length in centimeters.",
+                    "type": "integer"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "ski_code": {
+                    "description": "The team's own label for the ski pair, e.g. \"C-12\".",
+                    "type": "string"
+                },
+                "testing_team": {
+                    "type": "integer"
+                },
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.SnowConditions": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "snow_humidity": {
+                    "description": "'DS', 'W1', 'W2', 'W3', 'W4'",
+                    "type": "string"
+                },
+                "snow_type": {
+                    "type": "string"
+                },
+                "temperature": {
+                    "type": "number"
+                }
+            }
+        },
+        "domain.SnowReading": {
+            "type": "object",
+            "properties": {
+                "snow_humidity": {
+                    "description": "'DS', 'W1', 'W2', 'W3', 'W4'",
+                    "type": "string"
+                },
+                "snow_type": {
+                    "type": "string"
+                },
+                "temperature": {
+                    "type": "number"
+                }
+            }
+        },
+        "domain.Status": {
+            "type": "string",
+            "enum": [
+                "active",
+                "discontinued",
+                "development",
+                "retired"
+            ],
+            "x-enum-comments": {
+                "Active": "Available for sale, and fully tested",
+                "Development": "Available for sale, but not fully tested yet",
+                "Discontinued": "Not produced anymore, but may exist in stock or replaced by another product.",
+                "Retired": "Removed from sale, but still supported"
+            },
+            "x-enum-varnames": [
+                "Active",
+                "Discontinued",
+                "Development",
+                "Retired"
+            ]
+        },
+        "domain.Test": {
+            "type": "object",
+            "properties": {
+                "ac_id": {
+                    "type": "integer"
+                },
+                "comment": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_public": {
+                    "type": "boolean"
+                },
+                "location": {
+                    "type": "string"
+                },
+                "location_id": {
+                    "type": "integer"
+                },
                 "sc_id": {
                     "type": "integer"
                 },
-                "tc_id": {
-                    "type": "integer"
+                "state": {
+                    "$ref": "#/definitions/domain.TestState"
+                },
+                "tc_id": {
+                    "type": "integer"
+                },
+                "test_date": {
+                    "type": "string"
+                },
+                "testing_team": {
+                    "type": "integer"
+                },
+                "track_id": {
+                    "type": "integer"
+                },
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.TestConditions": {
+            "type": "object",
+            "properties": {
+                "readings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.ConditionReading"
+                    }
+                },
+                "summary": {
+                    "$ref": "#/definitions/domain.ConditionSummary"
+                },
+                "test_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.TestRank": {
+            "type": "object",
+            "properties": {
+                "distance_behind": {
+                    "type": "integer"
+                },
+                "is_public": {
+                    "type": "boolean"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "rank": {
+                    "type": "integer"
+                },
+                "ski_id": {
+                    "description": "The ski pair the product was tested on, if registered.",
+                    "type": "integer"
+                },
+                "test_id": {
+                    "type": "integer"
+                },
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.TestRun": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "metric": {
+                    "$ref": "#/definitions/domain.RunMetric"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "recorded_at": {
+                    "type": "string"
+                },
+                "ski_id": {
+                    "type": "integer"
+                },
+                "test_id": {
+                    "type": "integer"
+                },
+                "value": {
+                    "type": "number"
+                },
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.TestRuns": {
+            "type": "object",
+            "properties": {
+                "metric": {
+                    "$ref": "#/definitions/domain.RunMetric"
+                },
+                "products": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.RunSummary"
+                    }
+                },
+                "test_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.TestState": {
+            "type": "string",
+            "enum": [
+                "planned",
+                "in_progress",
+                "completed",
+                "published"
+            ],
+            "x-enum-comments": {
+                "TestCompleted": "Finished and read-only",
+                "TestInProgress": "Being tested, the results can still change",
+                "TestPlanned": "Scheduled, no results yet",
+                "TestPublished": "Completed and shared with all the teams"
+            },
+            "x-enum-varnames": [
+                "TestPlanned",
+                "TestInProgress",
+                "TestCompleted",
+                "TestPublished"
+            ]
+        },
+        "domain.TestStateChange": {
+            "type": "object",
+            "properties": {
+                "changed_at": {
+                    "type": "string"
+                },
+                "changed_by": {
+                    "type": "integer"
+                },
+                "from_state": {
+                    "$ref": "#/definitions/domain.TestState"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "test_id": {
+                    "type": "integer"
+                },
+                "to_state": {
+                    "$ref": "#/definitions/domain.TestState"
+                }
+            }
+        },
+        "domain.Tournament": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "rounds": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.TournamentRound"
+                    }
+                },
+                "status": {
+                    "type": "string"
+                },
+                "test_id": {
+                    "type": "integer"
+                },
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.TournamentMatch": {
+            "type": "object",
+            "properties": {
+                "battle1": {
+                    "description": "With reference to product 1, positive if product 1 won the battle.",
+                    "type": "integer"
+                },
+                "battle2": {
+                    "description": "With reference to product 1, negative if product 1 lost the battle.",
+                    "type": "integer"
+                },
+                "match_id": {
+                    "type": "integer"
+                },
+                "match_no": {
+                    "type": "integer"
+                },
+                "product1_id": {
+                    "type": "integer"
+                },
+                "product2_id": {
+                    "type": "integer"
+                },
+                "round": {
+                    "type": "integer"
+                },
+                "version": {
+                    "type": "string"
+                },
+                "winner_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.TournamentRound": {
+            "type": "object",
+            "properties": {
+                "matches": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.TournamentMatch"
+                    }
+                },
+                "round": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.Track": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "length": {
+                    "description": "Length in meters.",
+                    "type": "integer"
+                },
+                "location_id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.TrackConditions": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "track_hardness": {
+                    "description": "'H1', 'H2', 'H3', 'H4', 'H5', 'H6'",
+                    "type": "string"
+                },
+                "track_type": {
+                    "description": "'T1', 'T2', 'D1', 'D2'",
+                    "type": "string"
+                }
+            }
+        },
+        "locationsHandler.LocationPATCHRequest": {
+            "type": "object",
+            "required": [
+                "updates"
+            ],
+            "properties": {
+                "updates": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
+        "locationsHandler.LocationPOSTRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "altitude": {
+                    "type": "integer",
+                    "maximum": 9000,
+                    "minimum": -500
+                },
+                "latitude": {
+                    "type": "number",
+                    "maximum": 90,
+                    "minimum": -90
+                },
+                "longitude": {
+                    "type": "number",
+                    "maximum": 180,
+                    "minimum": -180
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "tracks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/locationsHandler.TrackPOST"
+                    }
+                }
+            }
+        },
+        "locationsHandler.TrackPOST": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "length": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 50
+                }
+            }
+        },
+        "loginHandler.LoginRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string",
+                    "maxLength": 64,
+                    "minLength": 8
+                }
+            }
+        },
+        "productsHandler.MergedRows": {
+            "type": "object",
+            "properties": {
+                "moved": {
+                    "type": "integer"
+                },
+                "removed": {
+                    "type": "integer"
+                },
+                "table": {
+                    "type": "string"
+                }
+            }
+        },
+        "productsHandler.PerformanceWindowResponse": {
+            "type": "object",
+            "properties": {
+                "air_temperature": {
+                    "$ref": "#/definitions/productsHandler.TemperatureWindow"
+                },
+                "high_temperature": {
+                    "type": "number"
+                },
+                "low_temperature": {
+                    "type": "number"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "samples": {
+                    "type": "integer"
+                },
+                "snow_temperature": {
+                    "$ref": "#/definitions/productsHandler.TemperatureWindow"
+                },
+                "suggested_update": {
+                    "$ref": "#/definitions/productsHandler.ProductPATCHRequest"
+                },
+                "top_quartile_samples": {
+                    "type": "integer"
+                }
+            }
+        },
+        "productsHandler.ProductFieldChange": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string"
+                },
+                "new_value": {
+                    "type": "object"
+                },
+                "old_value": {
+                    "type": "object"
+                }
+            }
+        },
+        "productsHandler.ProductMergeRequest": {
+            "type": "object",
+            "required": [
+                "source_ids",
+                "target_id"
+            ],
+            "properties": {
+                "confirm": {
+                    "description": "Confirm merges the products. Without it, the merge is only previewed.",
+                    "type": "boolean"
+                },
+                "source_ids": {
+                    "description": "SourceIDs are the duplicates of the target, which are deleted once their references point to the target.",
+                    "type": "array",
+                    "maxItems": 50,
+                    "minItems": 1,
+                    "uniqueItems": true,
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "target_id": {
+                    "description": "TargetID is the product that is kept.",
+                    "type": "integer"
+                }
+            }
+        },
+        "productsHandler.ProductMergeResponse": {
+            "type": "object",
+            "properties": {
+                "merged": {
+                    "description": "False for a preview, which changes nothing.",
+                    "type": "boolean"
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/productsHandler.MergedRows"
+                    }
+                },
+                "source_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "target_id": {
+                    "type": "integer"
+                },
+                "tests": {
+                    "description": "The tests whose results move to the target.",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "productsHandler.ProductPATCHRequest": {
+            "type": "object",
+            "required": [
+                "updates"
+            ],
+            "properties": {
+                "reason": {
+                    "description": "Why the status is changed.",
+                    "type": "string",
+                    "maxLength": 512
+                },
+                "updates": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
+        "productsHandler.ProductPOSTRequest": {
+            "type": "object",
+            "required": [
+                "comment",
+                "high_temperature",
+                "low_temperature",
+                "name",
+                "status",
+                "type"
+            ],
+            "properties": {
+                "brand": {
+                    "description": "Brand: Only ASCII, can be blank, max 64 characters",
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "comment": {
+                    "description": "Comment: max 2040 bytes.",
+                    "type": "string",
+                    "maxLength": 2040
+                },
+                "ean_code": {
+                    "description": "EANCode: can be blank, otherwise an EAN-8, UPC-A or EAN-13 code with a valid check digit, which is checked in\nthe POST handler.",
+                    "type": "string",
+                    "maxLength": 128
+                },
+                "high_temperature": {
+                    "description": "HighTemperature must be between -100 and 100.",
+                    "type": "number",
+                    "maximum": 100,
+                    "minimum": -100
+                },
+                "image_url": {
+                    "description": "ImageURL: Only ASCII, can be blank, and must be a valid URL.",
+                    "type": "string"
+                },
+                "is_public": {
+                    "description": "Gets set to false by default and validated in POST handler.",
+                    "type": "boolean"
+                },
+                "low_temperature": {
+                    "description": "LowTemperature must be between -100 and 100.",
+                    "type": "number",
+                    "maximum": 100,
+                    "minimum": -100
+                },
+                "name": {
+                    "description": "Name: Only ASCII, non-blank, max 64 characters.",
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "status": {
+                    "description": "Status: Only one of the following: active, discontinued, development, retired.",
+                    "type": "string",
+                    "enum": [
+                        "active",
+                        "discontinued",
+                        "development",
+                        "retired"
+                    ]
+                },
+                "testing_team": {
+                    "type": "integer"
+                },
+                "type": {
+                    "description": "Type: Only ASCII, non-blank, max 16 characters.",
+                    "type": "string",
+                    "enum": [
+                        "liquid",
+                        "solid",
+                        "spray",
+                        "powder",
+                        "gel",
+                        "bundle"
+                    ]
+                }
+            }
+        },
+        "productsHandler.ProductRevision": {
+            "type": "object",
+            "properties": {
+                "changed_at": {
+                    "type": "string"
+                },
+                "changed_by": {
+                    "type": "integer"
+                },
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/productsHandler.ProductFieldChange"
+                    }
+                },
+                "revision": {
+                    "type": "integer"
+                }
+            }
+        },
+        "productsHandler.ProductSearchResponse": {
+            "type": "object",
+            "properties": {
+                "page": {
+                    "type": "integer"
+                },
+                "page_size": {
+                    "type": "integer"
+                },
+                "products": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/productsHandler.ProductSearchResult"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "productsHandler.ProductSearchResult": {
+            "type": "object",
+            "properties": {
+                "brand": {
+                    "type": "string"
+                },
+                "comment": {
+                    "type": "string"
+                },
+                "ean_code": {
+                    "type": "string"
+                },
+                "high_temperature": {
+                    "type": "number"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "image_url": {
+                    "type": "string"
+                },
+                "is_public": {
+                    "type": "boolean"
+                },
+                "low_temperature": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "rating": {
+                    "type": "number"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "testing_team": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
+        "productsHandler.TemperatureWindow": {
+            "type": "object",
+            "properties": {
+                "high": {
+                    "type": "number"
+                },
+                "low": {
+                    "type": "number"
+                },
+                "samples": {
+                    "type": "integer"
+                },
+                "top_quartile_samples": {
+                    "type": "integer"
+                }
+            }
+        },
+        "rankingsHandler.RankingsPATCHRequest": {
+            "type": "object",
+            "required": [
+                "version"
+            ],
+            "properties": {
+                "distance_behind": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "is_public": {
+                    "type": "boolean"
+                },
+                "rank": {
+                    "type": "integer"
+                },
+                "version": {
+                    "description": "The version of the ranking the update is based on.",
+                    "type": "string"
+                }
+            }
+        },
+        "rankingsHandler.RankingsPOSTRequest": {
+            "type": "object",
+            "required": [
+                "product_id",
+                "test_id"
+            ],
+            "properties": {
+                "distance_behind": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "is_public": {
+                    "description": "Defaults to the availability of the product.",
+                    "type": "boolean"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "rank": {
+                    "description": "Empty for a product that has not been ranked yet.",
+                    "type": "integer"
+                },
+                "ski_id": {
+                    "type": "integer"
+                },
+                "test_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "ratingsHandler.LeaderboardEntry": {
+            "type": "object",
+            "properties": {
+                "brand": {
+                    "type": "string"
+                },
+                "comparisons": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "position": {
+                    "type": "integer"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "rating": {
+                    "type": "number"
+                },
+                "scope_type": {
+                    "description": "all, snow_type or temperature",
+                    "type": "string"
+                },
+                "scope_value": {
+                    "description": "The snow type, or the lower bound of the temperature band.",
+                    "type": "string"
+                },
+                "tests": {
+                    "type": "integer"
+                },
+                "uncertainty": {
+                    "description": "Standard error of the rating.",
+                    "type": "number"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "recommendationsHandler.Recommendation": {
+            "type": "object",
+            "properties": {
+                "brand": {
+                    "type": "string"
+                },
+                "confidence": {
+                    "description": "From 0 with no similar tests towards 1 with many close tests.",
+                    "type": "number"
+                },
+                "is_bundle": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "position": {
+                    "type": "integer"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "score": {
+                    "description": "Expected placing, from 0 for last to 1 for first, in similar conditions.",
+                    "type": "number"
+                },
+                "tests": {
+                    "description": "Number of similar tests the product was ranked in.",
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "recommendationsHandler.RecommendationPOSTRequest": {
+            "type": "object",
+            "required": [
+                "air_humidity",
+                "snow_humidity",
+                "snow_temperature",
+                "snow_type",
+                "track_hardness"
+            ],
+            "properties": {
+                "air_humidity": {
+                    "type": "number",
+                    "maximum": 100,
+                    "minimum": 0
+                },
+                "limit": {
+                    "description": "Defaults to 10.",
+                    "type": "integer",
+                    "maximum": 100
+                },
+                "snow_humidity": {
+                    "type": "string",
+                    "enum": [
+                        "DS",
+                        "W1",
+                        "W2",
+                        "W3",
+                        "W4"
+                    ]
+                },
+                "snow_temperature": {
+                    "type": "number",
+                    "maximum": 100,
+                    "minimum": -100
+                },
+                "snow_type": {
+                    "type": "string",
+                    "enum": [
+                        "A1",
+                        "A2",
+                        "A3",
+                        "A4",
+                        "A5",
+                        "FS",
+                        "NS",
+                        "IN",
+                        "IT",
+                        "TR"
+                    ]
+                },
+                "track_hardness": {
+                    "type": "string",
+                    "enum": [
+                        "H1",
+                        "H2",
+                        "H3",
+                        "H4",
+                        "H5",
+                        "H6"
+                    ]
+                }
+            }
+        },
+        "recommendationsHandler.RecommendationResponse": {
+            "type": "object",
+            "properties": {
+                "recommendations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/recommendationsHandler.Recommendation"
+                    }
+                },
+                "similar_tests": {
+                    "type": "integer"
+                }
+            }
+        },
+        "registrationHandler.RegistrationPOSTRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password",
+                "team_name",
+                "team_role"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string",
+                    "minLength": 14
+                },
+                "team_name": {
+                    "type": "string"
+                },
+                "team_role": {
+                    "type": "integer",
+                    "enum": [
+                        1,
+                        2
+                    ]
+                }
+            }
+        },
+        "runsHandler.RunPOST": {
+            "type": "object",
+            "required": [
+                "product_id",
+                "value"
+            ],
+            "properties": {
+                "product_id": {
+                    "type": "integer"
+                },
+                "recorded_at": {
+                    "description": "RecordedAt: When the run was made. Defaults to the time of the request.",
+                    "type": "string"
+                },
+                "ski_id": {
+                    "type": "integer"
+                },
+                "value": {
+                    "description": "Value: The measured distance or time of the run.",
+                    "type": "number"
+                }
+            }
+        },
+        "runsHandler.RunsPOSTRequest": {
+            "type": "object",
+            "required": [
+                "metric",
+                "runs"
+            ],
+            "properties": {
+                "metric": {
+                    "description": "Metric: distance for glide-out distances in meters, time for gate times in seconds.",
+                    "enum": [
+                        "distance",
+                        "time"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.RunMetric"
+                        }
+                    ]
+                },
+                "runs": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/runsHandler.RunPOST"
+                    }
+                }
+            }
+        },
+        "similarity.Weights": {
+            "type": "object",
+            "properties": {
+                "air_humidity": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "air_temperature": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "cloud": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "snow_humidity": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "snow_temperature": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "snow_type": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "track_hardness": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "track_type": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "wind": {
+                    "type": "number",
+                    "minimum": 0
+                }
+            }
+        },
+        "skisHandler.SkiPATCHRequest": {
+            "type": "object",
+            "required": [
+                "updates"
+            ],
+            "properties": {
+                "updates": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
+        "skisHandler.SkiPOSTRequest": {
+            "type": "object",
+            "required": [
+                "ski_code"
+            ],
+            "properties": {
+                "base": {
+                    "description": "Base: Only ASCII, can be blank, max 64 characters.",
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "brand": {
+                    "description": "Brand: Only ASCII, can be blank, max 64 characters.",
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "flex": {
+                    "description": "Flex: Only ASCII, can be blank, max 32 characters.",
+                    "type": "string",
+                    "maxLength": 32
+                },
+                "grind": {
+                    "description": "Grind: The grind or structure of the base, only ASCII, can be blank, max 64 characters.",
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "length": {
+                    "description": "Length: In centimeters, between 100 and 250.",
+                    "type": "integer",
+                    "maximum": 250,
+                    "minimum": 100
+                },
+                "notes": {
+                    "description": "Notes: max 2040 bytes.",
+                    "type": "string",
+                    "maxLength": 2040
+                },
+                "ski_code": {
+                    "description": "SkiCode: The team's own label for the ski pair, only ASCII, non-blank, max 32 characters.",
+                    "type": "string",
+                    "maxLength": 32
+                }
+            }
+        },
+        "testsHandler.AirConditionsDraft": {
+            "type": "object",
+            "properties": {
+                "cloud": {
+                    "type": "string",
+                    "enum": [
+                        "1",
+                        "2",
+                        "3",
+                        "4"
+                    ]
+                },
+                "humidity": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 0
+                },
+                "temperature": {
+                    "type": "number",
+                    "maximum": 100,
+                    "minimum": -100
+                },
+                "wind": {
+                    "type": "string",
+                    "enum": [
+                        "S",
+                        "L",
+                        "M",
+                        "ST"
+                    ]
+                }
+            }
+        },
+        "testsHandler.AirConditionsPOST": {
+            "type": "object",
+            "properties": {
+                "cloud": {
+                    "type": "string",
+                    "enum": [
+                        "1",
+                        "2",
+                        "3",
+                        "4"
+                    ]
+                },
+                "humidity": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 0
+                },
+                "temperature": {
+                    "type": "number",
+                    "maximum": 100,
+                    "minimum": -100
+                },
+                "wind": {
+                    "type": "string",
+                    "enum": [
+                        "S",
+                        "L",
+                        "M",
+                        "ST"
+                    ]
+                }
+            }
+        },
+        "testsHandler.ImportRowError": {
+            "type": "object",
+            "properties": {
+                "column": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "row": {
+                    "type": "integer"
+                }
+            }
+        },
+        "testsHandler.SimilarTest": {
+            "type": "object",
+            "properties": {
+                "ac": {
+                    "$ref": "#/definitions/domain.AirConditions"
+                },
+                "distance": {
+                    "description": "0 for the same conditions, about 1 for opposite ones.",
+                    "type": "number"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_public": {
+                    "type": "boolean"
+                },
+                "location": {
+                    "type": "string"
+                },
+                "sc": {
+                    "$ref": "#/definitions/domain.SnowConditions"
+                },
+                "state": {
+                    "$ref": "#/definitions/domain.TestState"
+                },
+                "tc": {
+                    "$ref": "#/definitions/domain.TrackConditions"
                 },
                 "test_date": {
                     "type": "string"
@@ -1722,52 +6964,222 @@ const docTemplate = `{
                 "testing_team": {
                     "type": "integer"
                 },
-                "version": {
-                    "type": "string"
+                "winners": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/testsHandler.SimilarTestWinner"
+                    }
                 }
             }
         },
-        "domain.TestRank": {
+        "testsHandler.SimilarTestWinner": {
             "type": "object",
             "properties": {
-                "distance_behind": {
-                    "type": "integer"
+                "brand": {
+                    "type": "string"
                 },
-                "is_public": {
-                    "type": "boolean"
+                "name": {
+                    "type": "string"
                 },
                 "product_id": {
                     "type": "integer"
+                }
+            }
+        },
+        "testsHandler.SimilarTestsPOSTRequest": {
+            "type": "object",
+            "properties": {
+                "air_humidity": {
+                    "type": "number",
+                    "maximum": 100,
+                    "minimum": 0
                 },
-                "rank": {
-                    "type": "integer"
+                "air_temperature": {
+                    "type": "number",
+                    "maximum": 100,
+                    "minimum": -100
                 },
-                "test_id": {
-                    "type": "integer"
+                "cloud": {
+                    "type": "string",
+                    "enum": [
+                        "1",
+                        "2",
+                        "3",
+                        "4"
+                    ]
                 },
-                "version": {
-                    "type": "string"
+                "limit": {
+                    "description": "Defaults to 20.",
+                    "type": "integer",
+                    "maximum": 100
+                },
+                "public": {
+                    "description": "Only public tests.",
+                    "type": "boolean"
+                },
+                "snow_humidity": {
+                    "type": "string",
+                    "enum": [
+                        "DS",
+                        "W1",
+                        "W2",
+                        "W3",
+                        "W4"
+                    ]
+                },
+                "snow_temperature": {
+                    "type": "number",
+                    "maximum": 100,
+                    "minimum": -100
+                },
+                "snow_type": {
+                    "type": "string",
+                    "enum": [
+                        "A1",
+                        "A2",
+                        "A3",
+                        "A4",
+                        "A5",
+                        "FS",
+                        "NS",
+                        "IN",
+                        "IT",
+                        "TR"
+                    ]
+                },
+                "track_hardness": {
+                    "type": "string",
+                    "enum": [
+                        "H1",
+                        "H2",
+                        "H3",
+                        "H4",
+                        "H5",
+                        "H6"
+                    ]
+                },
+                "track_type": {
+                    "type": "string",
+                    "enum": [
+                        "T1",
+                        "T2",
+                        "D1",
+                        "D2"
+                    ]
+                },
+                "weights": {
+                    "description": "Replaces the default weights.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/similarity.Weights"
+                        }
+                    ]
+                },
+                "wind": {
+                    "type": "string",
+                    "enum": [
+                        "S",
+                        "L",
+                        "M",
+                        "ST"
+                    ]
                 }
             }
         },
-        "loginHandler.LoginRequest": {
+        "testsHandler.SnowConditionsDraft": {
             "type": "object",
-            "required": [
-                "email",
-                "password"
-            ],
             "properties": {
-                "email": {
-                    "type": "string"
+                "snow_humidity": {
+                    "type": "string",
+                    "enum": [
+                        "DS",
+                        "W1",
+                        "W2",
+                        "W3",
+                        "W4"
+                    ]
                 },
-                "password": {
+                "snow_type": {
                     "type": "string",
-                    "maxLength": 64,
-                    "minLength": 8
+                    "enum": [
+                        "A1",
+                        "A2",
+                        "A3",
+                        "A4",
+                        "A5",
+                        "FS",
+                        "NS",
+                        "IN",
+                        "IT",
+                        "TR"
+                    ]
+                },
+                "temperature": {
+                    "type": "number",
+                    "maximum": 100,
+                    "minimum": -100
                 }
             }
         },
-        "productsHandler.ProductPATCHRequest": {
+        "testsHandler.SnowConditionsPOST": {
+            "type": "object",
+            "properties": {
+                "snow_humidity": {
+                    "type": "string",
+                    "enum": [
+                        "DS",
+                        "W1",
+                        "W2",
+                        "W3",
+                        "W4"
+                    ]
+                },
+                "snow_type": {
+                    "type": "string",
+                    "enum": [
+                        "A1",
+                        "A2",
+                        "A3",
+                        "A4",
+                        "A5",
+                        "FS",
+                        "NS",
+                        "IN",
+                        "IT",
+                        "TR"
+                    ]
+                },
+                "temperature": {
+                    "type": "number",
+                    "maximum": 100,
+                    "minimum": -100
+                }
+            }
+        },
+        "testsHandler.TemperatureWarning": {
+            "type": "object",
+            "properties": {
+                "air_temperature": {
+                    "type": "number"
+                },
+                "high_temperature": {
+                    "type": "number"
+                },
+                "low_temperature": {
+                    "type": "number"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "snow_temperature": {
+                    "type": "number"
+                }
+            }
+        },
+        "testsHandler.TestDraftPATCHRequest": {
             "type": "object",
             "required": [
                 "updates"
@@ -1782,203 +7194,188 @@ const docTemplate = `{
                 }
             }
         },
-        "productsHandler.ProductPOSTRequest": {
+        "testsHandler.TestDraftRequest": {
             "type": "object",
-            "required": [
-                "comment",
-                "high_temperature",
-                "low_temperature",
-                "name",
-                "status",
-                "type"
-            ],
             "properties": {
-                "brand": {
-                    "description": "Brand: Only ASCII, can be blank, max 64 characters",
-                    "type": "string",
-                    "maxLength": 64
+                "ac": {
+                    "$ref": "#/definitions/testsHandler.AirConditionsDraft"
                 },
                 "comment": {
-                    "description": "Comment: max 2040 bytes.",
                     "type": "string",
                     "maxLength": 2040
                 },
-                "ean_code": {
-                    "description": "EANCode: Only ASCII, can be blank, max 64 characters,\nOnly capital letters, numbers, and '#' are allowed; non-blank, max 128 characters.",
+                "is_public": {
+                    "type": "boolean"
+                },
+                "location": {
                     "type": "string",
-                    "maxLength": 128
+                    "maxLength": 256
                 },
-                "high_temperature": {
-                    "description": "HighTemperature must be between -100 and 100.",
-                    "type": "number",
-                    "maximum": 100,
-                    "minimum": -100
+                "location_id": {
+                    "type": "integer"
                 },
-                "image_url": {
-                    "description": "ImageURL: Only ASCII, can be blank, and must be a valid URL.",
+                "sc": {
+                    "$ref": "#/definitions/testsHandler.SnowConditionsDraft"
+                },
+                "tc": {
+                    "$ref": "#/definitions/testsHandler.TrackConditionsDraft"
+                },
+                "test_date": {
                     "type": "string"
                 },
-                "is_public": {
-                    "description": "Gets set to false by default and validated in POST handler.",
-                    "type": "boolean"
+                "test_ranks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/testsHandler.TestRanksPOST"
+                    }
                 },
-                "low_temperature": {
-                    "description": "LowTemperature must be between -100 and 100.",
-                    "type": "number",
-                    "maximum": 100,
-                    "minimum": -100
+                "track_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "testsHandler.TestDraftResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
                 },
-                "name": {
-                    "description": "Name: Only ASCII, non-blank, max 64 characters.",
-                    "type": "string",
-                    "maxLength": 64
+                "created_by": {
+                    "type": "integer"
                 },
-                "status": {
-                    "description": "Status: Only one of the following: active, tested, discontinued, development, retired.",
-                    "type": "string",
-                    "enum": [
-                        "active",
-                        "tested",
-                        "discontinued",
-                        "development",
-                        "retired"
-                    ]
+                "draft": {
+                    "$ref": "#/definitions/testsHandler.TestDraftRequest"
+                },
+                "id": {
+                    "type": "integer"
                 },
                 "testing_team": {
                     "type": "integer"
                 },
-                "type": {
-                    "description": "Type: Only ASCII, non-blank, max 16 characters.",
-                    "type": "string",
-                    "enum": [
-                        "liquid",
-                        "solid",
-                        "spray",
-                        "powder",
-                        "gel",
-                        "bundle"
-                    ]
+                "version": {
+                    "type": "string"
                 }
             }
         },
-        "rankingsHandler.RankingsPOSTRequest": {
+        "testsHandler.TestExportRow": {
             "type": "object",
-            "required": [
-                "rank",
-                "test_id",
-                "wins"
-            ],
             "properties": {
+                "ac_temperature": {
+                    "type": "number"
+                },
+                "air_humidity": {
+                    "type": "integer"
+                },
+                "cloud": {
+                    "type": "string"
+                },
+                "comment": {
+                    "type": "string"
+                },
+                "distance_behind": {
+                    "type": "integer"
+                },
                 "is_public": {
                     "type": "boolean"
                 },
-                "rank": {
+                "location": {
+                    "type": "string"
+                },
+                "location_id": {
                     "type": "integer"
                 },
-                "test_id": {
+                "out_of_temperature_range": {
+                    "type": "boolean"
+                },
+                "product_brand": {
+                    "type": "string"
+                },
+                "product_id": {
                     "type": "integer"
                 },
-                "version": {
+                "product_name": {
                     "type": "string"
                 },
-                "wins": {
+                "product_type": {
+                    "type": "string"
+                },
+                "rank": {
                     "type": "integer"
-                }
-            }
-        },
-        "registrationHandler.RegistrationPOSTRequest": {
-            "type": "object",
-            "required": [
-                "email",
-                "password",
-                "team_name",
-                "team_role"
-            ],
-            "properties": {
-                "email": {
+                },
+                "sc_temperature": {
+                    "type": "number"
+                },
+                "ski_code": {
                     "type": "string"
                 },
-                "password": {
-                    "type": "string",
-                    "minLength": 14
+                "ski_id": {
+                    "type": "integer"
                 },
-                "team_name": {
+                "snow_humidity": {
                     "type": "string"
                 },
-                "team_role": {
-                    "type": "integer",
-                    "enum": [
-                        1,
-                        2
-                    ]
-                }
-            }
-        },
-        "testsHandler.AirConditionsPOST": {
-            "type": "object",
-            "properties": {
-                "cloud": {
-                    "type": "string",
-                    "enum": [
-                        "1",
-                        "2",
-                        "3",
-                        "4"
-                    ]
+                "snow_type": {
+                    "type": "string"
                 },
-                "humidity": {
-                    "type": "integer",
-                    "maximum": 100,
-                    "minimum": 0
+                "state": {
+                    "$ref": "#/definitions/domain.TestState"
                 },
-                "temperature": {
-                    "type": "number",
-                    "maximum": 100,
-                    "minimum": -100
+                "test_date": {
+                    "type": "string"
+                },
+                "test_id": {
+                    "type": "integer"
+                },
+                "testing_team": {
+                    "type": "integer"
+                },
+                "track_hardness": {
+                    "type": "string"
+                },
+                "track_id": {
+                    "type": "integer"
+                },
+                "track_type": {
+                    "type": "string"
                 },
                 "wind": {
-                    "type": "string",
-                    "enum": [
-                        "S",
-                        "L",
-                        "M",
-                        "ST"
-                    ]
+                    "type": "string"
                 }
             }
         },
-        "testsHandler.SnowConditionsPOST": {
+        "testsHandler.TestImportReport": {
             "type": "object",
             "properties": {
-                "snow_humidity": {
-                    "type": "string",
-                    "enum": [
-                        "DS",
-                        "W1",
-                        "W2",
-                        "W3",
-                        "W4"
-                    ]
+                "dry_run": {
+                    "type": "boolean"
                 },
-                "snow_type": {
-                    "type": "string",
-                    "enum": [
-                        "A1",
-                        "A2",
-                        "A3",
-                        "A4",
-                        "A5",
-                        "FS",
-                        "NS",
-                        "IN",
-                        "IT",
-                        "TR"
-                    ]
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/testsHandler.ImportRowError"
+                    }
                 },
-                "temperature": {
-                    "type": "number",
-                    "maximum": 100,
-                    "minimum": -100
+                "rows": {
+                    "description": "Number of result rows in the file.",
+                    "type": "integer"
+                },
+                "test_ids": {
+                    "description": "IDs of the created tests, empty for a dry run or a failed import.",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "tests": {
+                    "description": "Number of tests the rows make up.",
+                    "type": "integer"
+                },
+                "warnings": {
+                    "description": "Products ranked outside their rated temperature range, which are errors for a team with a strict range.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/testsHandler.ImportRowError"
+                    }
                 }
             }
         },
@@ -2002,7 +7399,6 @@ const docTemplate = `{
             "required": [
                 "ac",
                 "comment",
-                "location",
                 "sc",
                 "tc",
                 "test_ranks"
@@ -2026,9 +7422,25 @@ const docTemplate = `{
                     "type": "string",
                     "maxLength": 256
                 },
+                "location_id": {
+                    "type": "integer"
+                },
                 "sc": {
                     "$ref": "#/definitions/testsHandler.SnowConditionsPOST"
                 },
+                "state": {
+                    "description": "Defaults to in_progress.",
+                    "enum": [
+                        "planned",
+                        "in_progress",
+                        "completed"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.TestState"
+                        }
+                    ]
+                },
                 "tc": {
                     "$ref": "#/definitions/testsHandler.TrackConditionsPOST"
                 },
@@ -2044,6 +7456,9 @@ const docTemplate = `{
                 },
                 "testing_team": {
                     "type": "integer"
+                },
+                "track_id": {
+                    "type": "integer"
                 }
             }
         },
@@ -2067,6 +7482,52 @@ const docTemplate = `{
                 },
                 "rank": {
                     "type": "integer"
+                },
+                "ski_id": {
+                    "description": "The ski pair the product was tested on.",
+                    "type": "integer"
+                }
+            }
+        },
+        "testsHandler.TestTemperatureResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "test_id": {
+                    "type": "integer"
+                },
+                "warnings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/testsHandler.TemperatureWarning"
+                    }
+                }
+            }
+        },
+        "testsHandler.TrackConditionsDraft": {
+            "type": "object",
+            "properties": {
+                "track_hardness": {
+                    "type": "string",
+                    "enum": [
+                        "H1",
+                        "H2",
+                        "H3",
+                        "H4",
+                        "H5",
+                        "H6"
+                    ]
+                },
+                "track_type": {
+                    "type": "string",
+                    "enum": [
+                        "T1",
+                        "T2",
+                        "D1",
+                        "D2"
+                    ]
                 }
             }
         },
@@ -2095,17 +7556,69 @@ const docTemplate = `{
                 }
             }
         },
+        "tournamentHandler.MatchResultPATCHRequest": {
+            "type": "object",
+            "required": [
+                "battle1",
+                "battle2"
+            ],
+            "properties": {
+                "battle1": {
+                    "description": "Battle1 and Battle2 are measured with reference to product 1, positive if product 1 won the battle.",
+                    "type": "integer",
+                    "maximum": 100000,
+                    "minimum": -100000
+                },
+                "battle2": {
+                    "type": "integer",
+                    "maximum": 100000,
+                    "minimum": -100000
+                },
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
+        "tournamentHandler.TournamentPOSTRequest": {
+            "type": "object",
+            "properties": {
+                "product_ids": {
+                    "description": "ProductIDs: The products in seeding order. Defaults to the products already ranked in the test.",
+                    "type": "array",
+                    "minItems": 2,
+                    "uniqueItems": true,
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
         "userProfileHandler.TeamResponse": {
             "type": "object",
             "properties": {
                 "name": {
                     "type": "string"
                 },
+                "strict_temperature_range": {
+                    "type": "boolean"
+                },
                 "team_role": {
                     "type": "string"
                 }
             }
         },
+        "userProfileHandler.UserProfilePATCHRequest": {
+            "type": "object",
+            "required": [
+                "strict_temperature_range"
+            ],
+            "properties": {
+                "strict_temperature_range": {
+                    "description": "Whether the team refuses results of products tested outside their rated temperature range.",
+                    "type": "boolean"
+                }
+            }
+        },
         "userProfileHandler.UserProfileResponse": {
             "type": "object",
             "properties": {
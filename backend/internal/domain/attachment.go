@@ -0,0 +1,17 @@
+package domain
+
+import "time"
+
+// Attachment is a photo or file attached to either a test or a product.
+type Attachment struct {
+	ID           int       `json:"id"`
+	TestID       *int      `json:"test_id"`
+	ProductID    *int      `json:"product_id"`
+	FileName     string    `json:"file_name"`
+	ContentType  string    `json:"content_type"`
+	Size         int64     `json:"size"` // Size in bytes.
+	HasThumbnail bool      `json:"has_thumbnail"`
+	UploadedBy   *int      `json:"uploaded_by"`
+	TestingTeam  int       `json:"testing_team"`
+	CreatedAt    time.Time `json:"created_at"`
+}
@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"time"
+)
+
+// Bundle is a set of products applied in layers. Its product row, of type bundle, stands for it in tests and rankings.
+type Bundle struct {
+	ID          int           `json:"id"`
+	ProductID   int           `json:"product_id"`
+	Name        string        `json:"name"`
+	TestingTeam int           `json:"testing_team"`
+	Version     time.Time     `json:"version"`
+	Layers      []BundleLayer `json:"layers"`
+}
+
+// BundleLayer is a product of a bundle, with its place in the order the layers are applied, from the first, and the
+// recipe for applying it, if one is recorded.
+type BundleLayer struct {
+	LayerNumber int          `json:"layer_no"`
+	Product     Product      `json:"product"`
+	Recipe      *LayerRecipe `json:"recipe"`
+}
+
+// LayerRecipe is how a layer of a bundle is applied, step by step: the method, the iron temperature in degrees
+// Celsius and the number of passes, the minutes to cool before brushing, the brushes in the order they are used, and
+// whether the layer is finished with cork and fleece.
+type LayerRecipe struct {
+	Method          string   `json:"method"`
+	IronTemperature *int     `json:"iron_temperature"`
+	Passes          *int     `json:"passes"`
+	CoolingMinutes  *int     `json:"cooling_minutes"`
+	Brushing        []string `json:"brushing"`
+	Cork            bool     `json:"cork"`
+	Fleece          bool     `json:"fleece"`
+}
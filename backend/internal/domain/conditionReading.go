@@ -0,0 +1,49 @@
+package domain
+
+import "time"
+
+// ConditionReading is a timestamped reading of the snow and air conditions during a test.
+type ConditionReading struct {
+	ID             int         `json:"id"`
+	TestID         int         `json:"test_id"`
+	RecordedAt     time.Time   `json:"recorded_at"`
+	SnowConditions SnowReading `json:"sc"`
+	AirConditions  AirReading  `json:"ac"`
+	Version        time.Time   `json:"version"`
+}
+
+type SnowReading struct {
+	Temperature  float32 `json:"temperature"`
+	SnowType     string  `json:"snow_type"`
+	SnowHumidity string  `json:"snow_humidity"` // 'DS', 'W1', 'W2', 'W3', 'W4'
+}
+
+type AirReading struct {
+	Temperature float32 `json:"temperature"`
+	Humidity    int     `json:"humidity"`
+	Wind        string  `json:"wind"`  //'S', 'L', 'M', 'ST'
+	Cloud       string  `json:"cloud"` //'1', '2', '3', '4'
+}
+
+// ReadingRange is the minimum, maximum and mean of a measured value over the readings of a test.
+type ReadingRange struct {
+	Min  float64 `json:"min"`
+	Max  float64 `json:"max"`
+	Mean float64 `json:"mean"`
+}
+
+type ConditionSummary struct {
+	Count           int           `json:"count"`
+	From            *time.Time    `json:"from"`
+	To              *time.Time    `json:"to"`
+	SnowTemperature *ReadingRange `json:"snow_temperature"`
+	AirTemperature  *ReadingRange `json:"air_temperature"`
+	AirHumidity     *ReadingRange `json:"air_humidity"`
+}
+
+// TestConditions is the condition series of a test with its summary.
+type TestConditions struct {
+	TestID   int                `json:"test_id"`
+	Summary  ConditionSummary   `json:"summary"`
+	Readings []ConditionReading `json:"readings"`
+}
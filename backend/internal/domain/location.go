@@ -0,0 +1,26 @@
+package domain
+
+import "time"
+
+// Location is a named test venue, shared by all the teams.
+type Location struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	Latitude  *float64  `json:"latitude"`
+	Longitude *float64  `json:"longitude"`
+	Altitude  *int      `json:"altitude"` // Altitude in meters above sea level.
+	Version   time.Time `json:"version"`
+	// CreatedBy: The team that created the location and may change it. Empty for locations only official teams may
+	// change.
+	CreatedBy *int    `json:"created_by"`
+	Tracks    []Track `json:"tracks"`
+}
+
+// Track is a named track at a location.
+type Track struct {
+	ID         int       `json:"id"`
+	LocationID int       `json:"location_id"`
+	Name       string    `json:"name"`
+	Length     *int      `json:"length"` // Length in meters.
+	Version    time.Time `json:"version"`
+}
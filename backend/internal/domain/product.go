@@ -13,8 +13,8 @@ type Product struct {
 	Comment         string    `json:"comment"`
 	IsPublic        bool      `json:"is_public"`
 	Type            string    `json:"type"`
-	HighTemperature float64   `json:"high_temperature"`
-	LowTemperature  float64   `json:"low_temperature"`
+	HighTemperature *float64  `json:"high_temperature"`
+	LowTemperature  *float64  `json:"low_temperature"`
 	TestingTeam     int       `json:"testing_team"`
 	Version         time.Time `json:"version"`
 	Status          string    `json:"status"`
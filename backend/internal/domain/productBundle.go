@@ -1,7 +1,8 @@
 package domain
 
 type ProductBundle struct {
-	BundleID    int `json:"bundle_id"`
-	ProductID   int `json:"product_id"`
-	LayerNumber int `json:"layer_no"`
+	BundleID    int          `json:"bundle_id"`
+	ProductID   int          `json:"product_id"`
+	LayerNumber int          `json:"layer_no"`
+	Recipe      *LayerRecipe `json:"recipe"`
 }
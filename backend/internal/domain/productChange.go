@@ -0,0 +1,19 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ProductChange is the change of a field of a product in a revision, with who made it and when. The values are JSON
+// encoded, as the fields have different types.
+type ProductChange struct {
+	ID        int             `json:"id"`
+	ProductID int             `json:"product_id"`
+	Revision  int             `json:"revision"`
+	Field     string          `json:"field"`
+	OldValue  json.RawMessage `json:"old_value" swaggertype:"object"`
+	NewValue  json.RawMessage `json:"new_value" swaggertype:"object"`
+	ChangedBy *int            `json:"changed_by"`
+	ChangedAt time.Time       `json:"changed_at"`
+}
@@ -0,0 +1,23 @@
+package domain
+
+import "time"
+
+// Scopes of the product ratings. The ratings are fitted for all the tests, and separately for the tests of each
+// snow type and each temperature band.
+const (
+	RatingScopeAll         = "all"
+	RatingScopeSnowType    = "snow_type"
+	RatingScopeTemperature = "temperature"
+)
+
+// ProductRating is the rating of a product fitted over the completed and published tests of a scope.
+type ProductRating struct {
+	ProductID   int       `json:"product_id"`
+	ScopeType   string    `json:"scope_type"`  // all, snow_type or temperature
+	ScopeValue  string    `json:"scope_value"` // The snow type, or the lower bound of the temperature band.
+	Rating      float64   `json:"rating"`
+	Uncertainty float64   `json:"uncertainty"` // Standard error of the rating.
+	Tests       int       `json:"tests"`
+	Comparisons int       `json:"comparisons"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
@@ -0,0 +1,27 @@
+package domain
+
+import "time"
+
+// Ski is a test ski pair in the fleet of a team.
+type Ski struct {
+	ID          int       `json:"id"`
+	SkiCode     string    `json:"ski_code"` // The team's own label for the ski pair, e.g. "C-12".
+	Brand       string    `json:"brand"`
+	Base        string    `json:"base"`
+	Grind       string    `json:"grind"` // The grind or structure of the base.
+	Flex        string    `json:"flex"`
+	Length      int       `json:"length"` // Length in centimeters.
+	Notes       string    `json:"notes"`
+	TestingTeam int       `json:"testing_team"`
+	Version     time.Time `json:"version"`
+}
+
+// SkiResult is a product result from a test where the ski pair was used.
+type SkiResult struct {
+	TestID         int       `json:"test_id"`
+	TestDate       time.Time `json:"test_date"`
+	Location       string    `json:"location"`
+	ProductID      int       `json:"product_id"`
+	Rank           int       `json:"rank"`
+	DistanceBehind int       `json:"distance_behind"`
+}
@@ -1,5 +1,7 @@
 package domain
 
+import "time"
+
 type Status string
 
 const (
@@ -8,3 +10,39 @@ const (
 	Development  Status = "development"  // Available for sale, but not fully tested yet
 	Retired      Status = "retired"      // Removed from sale, but still supported
 )
+
+// statusTransitions lists the statuses a product can move to from each status. A discontinued product can be
+// produced again, while a retired product is final.
+var statusTransitions = map[Status][]Status{
+	Development:  {Active, Retired},
+	Active:       {Discontinued, Retired},
+	Discontinued: {Active, Retired},
+	Retired:      {},
+}
+
+// CanTransitionTo reports whether a product can move from the status to the next status.
+func (s Status) CanTransitionTo(next Status) bool {
+	for _, status := range statusTransitions[s] {
+		if status == next {
+			return true
+		}
+	}
+	return false
+}
+
+// IsWithdrawn reports whether the products with the status are no longer produced, which the bundles containing
+// them follow.
+func (s Status) IsWithdrawn() bool {
+	return s == Discontinued || s == Retired
+}
+
+// ProductStatusChange is a recorded change of the status of a product.
+type ProductStatusChange struct {
+	ID         int       `json:"id"`
+	ProductID  int       `json:"product_id"`
+	FromStatus Status    `json:"from_status"`
+	ToStatus   Status    `json:"to_status"`
+	Reason     string    `json:"reason"`
+	ChangedBy  *int      `json:"changed_by"`
+	ChangedAt  time.Time `json:"changed_at"`
+}
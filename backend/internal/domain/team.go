@@ -6,4 +6,6 @@ type Team struct {
 	ID       int    `json:"id"`
 	Name     string `json:"name"`
 	TeamRole int    `json:"team_role"`
+	// StrictTemperatureRange refuses results of products tested outside their rated temperature range.
+	StrictTemperatureRange bool `json:"strict_temperature_range"`
 }
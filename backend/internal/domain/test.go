@@ -13,4 +13,7 @@ type Test struct {
 	Version         time.Time `json:"version"`
 	IsPublic        bool      `json:"is_public"`
 	TestingTeam     int       `json:"testing_team"`
+	LocationID      *int      `json:"location_id"`
+	TrackID         *int      `json:"track_id"`
+	State           TestState `json:"state"`
 }
@@ -9,4 +9,5 @@ type TestRank struct {
 	DistanceBehind int       `json:"distance_behind"`
 	IsPublic       bool      `json:"is_public"`
 	Version        time.Time `json:"version"`
+	SkiID          *int      `json:"ski_id"` // The ski pair the product was tested on, if registered.
 }
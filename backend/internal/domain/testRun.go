@@ -0,0 +1,41 @@
+package domain
+
+import "time"
+
+type RunMetric string
+
+const (
+	RunDistance RunMetric = "distance" // Glide-out distance in meters, the longest run is the best
+	RunTime     RunMetric = "time"     // Gate time in seconds, the shortest run is the best
+)
+
+// TestRun is a single raw glide run of a product in a test.
+type TestRun struct {
+	ID         int       `json:"id"`
+	TestID     int       `json:"test_id"`
+	ProductID  int       `json:"product_id"`
+	SkiID      *int      `json:"ski_id"`
+	Metric     RunMetric `json:"metric"`
+	Value      float64   `json:"value"`
+	RecordedAt time.Time `json:"recorded_at"`
+	Version    time.Time `json:"version"`
+}
+
+// RunSummary holds the statistics computed from the runs of a product. The distance behind is given
+// in centimeters for distance runs, and in hundredths of a second for time runs.
+type RunSummary struct {
+	ProductID      int       `json:"product_id"`
+	Count          int       `json:"count"`
+	Mean           float64   `json:"mean"`
+	StdDev         float64   `json:"std_dev"` // Sample standard deviation, zero for a single run.
+	Rank           int       `json:"rank"`
+	DistanceBehind int       `json:"distance_behind"`
+	Runs           []TestRun `json:"runs"`
+}
+
+// TestRuns is the raw runs of a test grouped by product, in rank order.
+type TestRuns struct {
+	TestID   int          `json:"test_id"`
+	Metric   RunMetric    `json:"metric"`
+	Products []RunSummary `json:"products"`
+}
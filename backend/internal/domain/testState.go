@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// TestState is the lifecycle state of a test.
+type TestState string
+
+const (
+	TestPlanned    TestState = "planned"     // Scheduled, no results yet
+	TestInProgress TestState = "in_progress" // Being tested, the results can still change
+	TestCompleted  TestState = "completed"   // Finished and read-only
+	TestPublished  TestState = "published"   // Completed and shared with all the teams
+)
+
+// testStates lists the states in the order of the lifecycle.
+var testStates = []TestState{TestPlanned, TestInProgress, TestCompleted, TestPublished}
+
+// testStateTransitions lists the states a test can move to from each state. A completed test can be reopened to
+// correct it, while a published test is final.
+var testStateTransitions = map[TestState][]TestState{
+	TestPlanned:    {TestInProgress},
+	TestInProgress: {TestPlanned, TestCompleted},
+	TestCompleted:  {TestInProgress, TestPublished},
+	TestPublished:  {},
+}
+
+// CanTransitionTo reports whether a test can move from the state to the next state.
+func (s TestState) CanTransitionTo(next TestState) bool {
+	for _, state := range testStateTransitions[s] {
+		if state == next {
+			return true
+		}
+	}
+	return false
+}
+
+// IsReadOnly reports whether the tests in the state can no longer be changed.
+func (s TestState) IsReadOnly() bool {
+	return s == TestCompleted || s == TestPublished
+}
+
+// IsReportable reports whether the tests in the state are included in reports and aggregated statistics.
+func (s TestState) IsReportable() bool {
+	return s == TestCompleted || s == TestPublished
+}
+
+// ReportableStatesSQL is the SQL list of the reportable states, to filter the tests of reports and aggregated
+// statistics with "t.state IN " + ReportableStatesSQL.
+var ReportableStatesSQL = reportableStatesSQL()
+
+func reportableStatesSQL() string {
+	var states []string
+	for _, state := range testStates {
+		if state.IsReportable() {
+			states = append(states, "'"+string(state)+"'")
+		}
+	}
+	return "(" + strings.Join(states, ", ") + ")"
+}
+
+// TestStateChange is a recorded change of the state of a test.
+type TestStateChange struct {
+	ID        int       `json:"id"`
+	TestID    int       `json:"test_id"`
+	FromState TestState `json:"from_state"`
+	ToState   TestState `json:"to_state"`
+	ChangedBy *int      `json:"changed_by"`
+	ChangedAt time.Time `json:"changed_at"`
+}
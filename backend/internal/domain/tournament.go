@@ -0,0 +1,37 @@
+package domain
+
+import "time"
+
+type TournamentStatus string
+
+const (
+	TournamentInProgress TournamentStatus = "in_progress" // Matches are still being run on the track
+	TournamentCompleted  TournamentStatus = "completed"   // The final is decided and the ranks are written to test_ranks
+)
+
+// Tournament is a head-to-head bracket for a test. Every match consists of two battles,
+// and the winner of a match moves on to the next round.
+type Tournament struct {
+	ID      int               `json:"id"`
+	TestID  int               `json:"test_id"`
+	Status  string            `json:"status"`
+	Version time.Time         `json:"version"`
+	Rounds  []TournamentRound `json:"rounds"`
+}
+
+type TournamentRound struct {
+	Round   int               `json:"round"`
+	Matches []TournamentMatch `json:"matches"`
+}
+
+type TournamentMatch struct {
+	ID       int       `json:"match_id"`
+	Round    int       `json:"round"`
+	MatchNo  int       `json:"match_no"`
+	Product1 *int      `json:"product1_id"`
+	Product2 *int      `json:"product2_id"`
+	Battle1  *int      `json:"battle1"` // With reference to product 1, positive if product 1 won the battle.
+	Battle2  *int      `json:"battle2"` // With reference to product 1, negative if product 1 lost the battle.
+	Winner   *int      `json:"winner_id"`
+	Version  time.Time `json:"version"`
+}
@@ -0,0 +1,361 @@
+package attachmentsHandler
+
+import (
+	"backend/internal/domain"
+	"backend/internal/middleware"
+	"backend/internal/resources"
+	"backend/internal/services/access"
+	"backend/internal/services/storage"
+	"backend/internal/services/thumbnail"
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+)
+
+// AttachmentsHandler routes HTTP requests for the attachments of tests and products to the appropriate handler
+// function. The files are kept in the given storage backend.
+//
+// It supports the following methods:
+// - GET: Retrieves the attachments of a test or product, or downloads a single file or its thumbnail.
+// - POST: Uploads a new file.
+// - DELETE: Deletes an attachment and its files.
+//
+// Each method has its own dedicated request handler function, which is documented separately using Swagger annotations.
+func AttachmentsHandler(db *sql.DB, store storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		switch r.Method {
+		case http.MethodGet:
+			AttachmentsRequestGET(w, r, db, store)
+		case http.MethodPost:
+			AttachmentsRequestPOST(w, r, db, store)
+		case http.MethodDelete:
+			AttachmentsRequestDELETE(w, r, db, store)
+		default:
+			http.Error(w, resources.MethodNotAllowed, http.StatusNotImplemented)
+			return
+		}
+	}
+}
+
+// AttachmentsRequestGET retrieves the attachments of a test or product, or downloads one of them.
+//
+//	@Summary		Get attachments
+//	@Description	Retrieves the attachments of a test or product. With an attachment ID the file is downloaded,
+//	@Description	and with '/thumbnail' the JPEG thumbnail of an image is downloaded instead. The attachments are
+//	@Description	visible to the users who can see the test or product.
+//	@Tags			Attachments
+//	@Produce		json
+//	@Produce		octet-stream
+//	@Security		BearerAuth
+//	@Param			parent			path		string				true	"Parent entity"	Enums(tests, products)
+//	@Param			parent_id		path		int					true	"Test or product ID"
+//	@Success		200				{array}		domain.Attachment	"Successful response with the attachments"
+//	@Failure		400				{string}	string				"Invalid request URL"
+//	@Failure		401				{string}	string				"Unauthorized"
+//	@Failure		404				{string}	string				"Could not retrieve the test or product."
+//	@Failure		500				{string}	string				"Could not retrieve the attachments."
+//	@Router			/{parent}/{parent_id}/attachments [get]
+//	@Router			/{parent}/{parent_id}/attachments/{attachment_id} [get]
+//	@Router			/{parent}/{parent_id}/attachments/{attachment_id}/thumbnail [get]
+func AttachmentsRequestGET(w http.ResponseWriter, r *http.Request, db *sql.DB, store storage.Storage) {
+	path, ok := parseAttachmentPath(r.URL.Path)
+	if !ok {
+		http.Error(w, "Invalid request URL, use '/{tests|products}/{id}/attachments'.", http.StatusBadRequest)
+		log.Println("Invalid request URL: " + r.URL.Path)
+		return
+	}
+
+	// Get the user's team membership.
+	team := middleware.GetUserTeamRole(w, r, db)
+
+	// The attachments have the same visibility as the test or product.
+	testingTeam, isPublic, _, err := getParentAccess(db, path)
+	if err != nil {
+		http.Error(w, "Could not retrieve the test or product.", http.StatusNotFound)
+		log.Println("Could not retrieve the test or product: " + err.Error())
+		return
+	}
+	if !isPublic && testingTeam != team {
+		http.Error(w, resources.AuthenticationError, http.StatusUnauthorized)
+		log.Println("User cannot view the attachments of this entity")
+		return
+	}
+
+	if path.AttachmentID == 0 {
+		attachments, err := getAttachments(db, path)
+		if err != nil {
+			http.Error(w, "Could not retrieve the attachments.", http.StatusInternalServerError)
+			log.Println("Could not retrieve the attachments: " + err.Error())
+			return
+		}
+
+		err = json.NewEncoder(w).Encode(attachments)
+		if err != nil {
+			http.Error(w, "Could not encode the attachments.", http.StatusInternalServerError)
+			log.Println("Could not encode the attachments: " + err.Error())
+			return
+		}
+		return
+	}
+
+	record, err := getAttachmentWithID(db, path)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Could not find the attachment.", http.StatusNotFound)
+		log.Println("Could not find the attachment: " + err.Error())
+		return
+	} else if err != nil {
+		http.Error(w, "Could not retrieve the attachment.", http.StatusInternalServerError)
+		log.Println("Could not retrieve the attachment: " + err.Error())
+		return
+	}
+
+	key, contentType, fileName := record.StorageKey, record.ContentType, record.FileName
+	if path.Thumbnail {
+		if !record.ThumbnailKey.Valid {
+			http.Error(w, "The attachment has no thumbnail.", http.StatusNotFound)
+			log.Println("The attachment has no thumbnail: " + strconv.Itoa(record.ID))
+			return
+		}
+		key, contentType, fileName = record.ThumbnailKey.String, "image/jpeg", "thumbnail-"+record.FileName
+	}
+
+	file, err := store.Open(key)
+	if err != nil {
+		http.Error(w, "Could not retrieve the file.", http.StatusInternalServerError)
+		log.Println("Could not open the stored file: " + err.Error())
+		return
+	}
+	defer file.Close()
+
+	w.Header().Set("content-type", contentType)
+	w.Header().Set("content-disposition", mime.FormatMediaType("attachment", map[string]string{"filename": fileName}))
+	w.Header().Set("x-content-type-options", "nosniff")
+	if !path.Thumbnail {
+		w.Header().Set("content-length", strconv.FormatInt(record.Size, 10))
+	}
+	if _, err = io.Copy(w, file); err != nil {
+		log.Println("Could not send the file: " + err.Error())
+		return
+	}
+}
+
+// AttachmentsRequestPOST uploads a file to a test or product.
+//
+//	@Summary		Upload an attachment
+//	@Description	Uploads a photo or file to a test or product as a multipart form with a 'file' field. The type is
+//	@Description	detected from the content, and only JPEG, PNG, GIF and WebP images, PDF, plain text and CSV files
+//	@Description	up to 10 MB are accepted. A thumbnail is made for JPEG, PNG and GIF images. Completed and
+//	@Description	published tests are read-only.
+//	@Tags			Attachments
+//	@Accept			multipart/form-data
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			parent		path		string				true	"Parent entity"	Enums(tests, products)
+//	@Param			parent_id	path		int					true	"Test or product ID"
+//	@Param			file		formData	file				true	"File to attach"
+//	@Success		201			{object}	domain.Attachment	"Attachment uploaded successfully"
+//	@Failure		400			{string}	string				"Invalid upload"
+//	@Failure		401			{string}	string				"Unauthorized"
+//	@Failure		404			{string}	string				"Could not retrieve the test or product."
+//	@Failure		409			{string}	string				"Test is completed and cannot be changed"
+//	@Failure		413			{string}	string				"The file is larger than 10 MB."
+//	@Failure		415			{string}	string				"Unsupported file type."
+//	@Failure		500			{string}	string				"Could not store the file."
+//	@Router			/{parent}/{parent_id}/attachments [post]
+func AttachmentsRequestPOST(w http.ResponseWriter, r *http.Request, db *sql.DB, store storage.Storage) {
+	path, ok := parseAttachmentPath(r.URL.Path)
+	if !ok || path.AttachmentID != 0 {
+		http.Error(w, "Invalid request URL, use '/{tests|products}/{id}/attachments'.", http.StatusBadRequest)
+		log.Println("Invalid request URL: " + r.URL.Path)
+		return
+	}
+
+	// Get the user and the user's team membership.
+	userID := middleware.GetUserID(w, r, db)
+	team := middleware.GetUserTeamRole(w, r, db)
+
+	testingTeam, isPublic, state, err := getParentAccess(db, path)
+	if err != nil {
+		http.Error(w, "Could not retrieve the test or product.", http.StatusNotFound)
+		log.Println("Could not retrieve the test or product: " + err.Error())
+		return
+	}
+
+	err, code := access.ValidateWritePermissions(parentEntities[path.Parent], testingTeam, isPublic, state, team)
+	if err != nil {
+		http.Error(w, "Validation error: "+err.Error(), code)
+		log.Println("Validation error: " + err.Error())
+		return
+	}
+
+	// Leave room for the multipart headers, the file itself is limited while it is stored.
+	r.Body = http.MaxBytesReader(w, r.Body, maxUploadSize+1<<20)
+	reader, err := r.MultipartReader()
+	if err != nil {
+		http.Error(w, "Invalid upload, use a multipart form with a 'file' field.", http.StatusBadRequest)
+		log.Println("Invalid upload: " + err.Error())
+		return
+	}
+
+	part, err := reader.NextPart()
+	for err == nil && part.FormName() != "file" {
+		part, err = reader.NextPart()
+	}
+	if err != nil {
+		http.Error(w, "Invalid upload, use a multipart form with a 'file' field.", http.StatusBadRequest)
+		log.Println("Invalid upload: " + err.Error())
+		return
+	}
+	defer part.Close()
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(part, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		http.Error(w, "Invalid upload, the file could not be read.", http.StatusBadRequest)
+		log.Println("Could not read the upload: " + err.Error())
+		return
+	}
+	if n == 0 {
+		http.Error(w, "The file is empty.", http.StatusBadRequest)
+		log.Println("The uploaded file is empty")
+		return
+	}
+	head = head[:n]
+
+	contentType := detectContentType(head, part.Header.Get("Content-Type"))
+	if !allowedContentTypes[contentType] {
+		http.Error(w, "Unsupported file type.", http.StatusUnsupportedMediaType)
+		log.Println("Unsupported file type: " + contentType)
+		return
+	}
+
+	key, err := newStorageKey()
+	if err != nil {
+		http.Error(w, "Could not store the file.", http.StatusInternalServerError)
+		log.Println("Could not generate a storage key: " + err.Error())
+		return
+	}
+
+	// Read one byte past the limit, so files that are too large can be told apart.
+	content := io.MultiReader(bytes.NewReader(head), io.LimitReader(part, maxUploadSize+1-int64(n)))
+	size, err := store.Save(key, content)
+	var maxBytesError *http.MaxBytesError
+	if errors.As(err, &maxBytesError) || (err == nil && size > maxUploadSize) {
+		deleteFiles(store, attachmentRecord{StorageKey: key})
+		http.Error(w, "The file is larger than 10 MB.", http.StatusRequestEntityTooLarge)
+		log.Println("The uploaded file is too large")
+		return
+	} else if err != nil {
+		http.Error(w, "Could not store the file.", http.StatusInternalServerError)
+		log.Println("Could not store the file: " + err.Error())
+		return
+	}
+
+	record := attachmentRecord{
+		Attachment: domain.Attachment{
+			FileName:    cleanFileName(part.FileName()),
+			ContentType: contentType,
+			Size:        size,
+			TestingTeam: testingTeam,
+		},
+		StorageKey: key,
+	}
+	if userID != 0 {
+		record.UploadedBy = &userID
+	}
+
+	// An image that cannot be decoded, or is too large to decode, is still kept, only without a thumbnail.
+	if thumbnail.Supported(contentType) {
+		record.ThumbnailKey, err = saveThumbnail(store, key)
+		if err != nil {
+			log.Println("Could not make a thumbnail: " + err.Error())
+		}
+	}
+	record.HasThumbnail = record.ThumbnailKey.Valid
+
+	attachment, err := insertAttachment(db, path, record)
+	if err != nil {
+		deleteFiles(store, record)
+		http.Error(w, "Could not store the file.", http.StatusInternalServerError)
+		log.Println("Could not insert the attachment: " + err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	err = json.NewEncoder(w).Encode(attachment)
+	if err != nil {
+		log.Println("Could not encode the attachment: " + err.Error())
+		return
+	}
+}
+
+// AttachmentsRequestDELETE deletes an attachment of a test or product.
+//
+//	@Summary		Delete an attachment
+//	@Description	Deletes an attachment of a test or product together with its stored files.
+//	@Tags			Attachments
+//	@Security		BearerAuth
+//	@Param			parent			path		string	true	"Parent entity"	Enums(tests, products)
+//	@Param			parent_id		path		int		true	"Test or product ID"
+//	@Param			attachment_id	path		int		true	"Attachment ID"
+//	@Success		204				{string}	string	"Attachment deleted successfully"
+//	@Failure		400				{string}	string	"Invalid request URL"
+//	@Failure		401				{string}	string	"Unauthorized"
+//	@Failure		404				{string}	string	"Could not find the attachment."
+//	@Failure		409				{string}	string	"Test is completed and cannot be changed"
+//	@Failure		500				{string}	string	"Could not delete the attachment."
+//	@Router			/{parent}/{parent_id}/attachments/{attachment_id} [delete]
+func AttachmentsRequestDELETE(w http.ResponseWriter, r *http.Request, db *sql.DB, store storage.Storage) {
+	path, ok := parseAttachmentPath(r.URL.Path)
+	if !ok || path.AttachmentID == 0 || path.Thumbnail {
+		http.Error(w, "Invalid request URL, use '/{tests|products}/{id}/attachments/{attachment_id}'.",
+			http.StatusBadRequest)
+		log.Println("Invalid request URL: " + r.URL.Path)
+		return
+	}
+
+	// Get the user's team membership.
+	team := middleware.GetUserTeamRole(w, r, db)
+
+	testingTeam, isPublic, state, err := getParentAccess(db, path)
+	if err != nil {
+		http.Error(w, "Could not retrieve the test or product.", http.StatusNotFound)
+		log.Println("Could not retrieve the test or product: " + err.Error())
+		return
+	}
+
+	err, code := access.ValidateWritePermissions(parentEntities[path.Parent], testingTeam, isPublic, state, team)
+	if err != nil {
+		http.Error(w, "Validation error: "+err.Error(), code)
+		log.Println("Validation error: " + err.Error())
+		return
+	}
+
+	record, err := getAttachmentWithID(db, path)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Could not find the attachment.", http.StatusNotFound)
+		log.Println("Could not find the attachment: " + err.Error())
+		return
+	} else if err != nil {
+		http.Error(w, "Could not delete the attachment.", http.StatusInternalServerError)
+		log.Println("Could not retrieve the attachment: " + err.Error())
+		return
+	}
+
+	_, err = db.Exec("DELETE FROM attachments WHERE id = $1;", record.ID)
+	if err != nil {
+		http.Error(w, "Could not delete the attachment.", http.StatusInternalServerError)
+		log.Println("Could not delete the attachment: " + err.Error())
+		return
+	}
+
+	deleteFiles(store, record)
+	w.WriteHeader(http.StatusNoContent)
+}
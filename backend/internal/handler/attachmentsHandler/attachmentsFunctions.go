@@ -0,0 +1,265 @@
+package attachmentsHandler
+
+import (
+	"backend/internal/domain"
+	"backend/internal/services/storage"
+	"backend/internal/services/thumbnail"
+	"bytes"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"mime"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxUploadSize is the largest file that can be attached, in bytes.
+const maxUploadSize = 10 << 20
+
+const attachmentColumns = "id, test_id, product_id, file_name, content_type, size, storage_key, thumbnail_key, uploaded_by, testing_team, created_at"
+
+var attachmentsPath = regexp.MustCompile(`^/(tests|products)/(\d+)/attachments(?:/(\d+)(/thumbnail)?)?/?$`)
+
+// allowedContentTypes are the content types that can be attached.
+var allowedContentTypes = map[string]bool{
+	"image/jpeg":      true,
+	"image/png":       true,
+	"image/gif":       true,
+	"image/webp":      true,
+	"application/pdf": true,
+	"text/plain":      true,
+	"text/csv":        true,
+}
+
+// parentColumns maps the parent entity in the URL to its column in the attachments table.
+var parentColumns = map[string]string{
+	"tests":    "test_id",
+	"products": "product_id",
+}
+
+// attachmentPath is a parsed attachments URL.
+type attachmentPath struct {
+	Parent       string // Either "tests" or "products".
+	ParentID     int
+	AttachmentID int // Zero for the attachments of the parent.
+	Thumbnail    bool
+}
+
+// attachmentRecord is an attachment with the storage keys of its files, which are never exposed to the clients.
+type attachmentRecord struct {
+	domain.Attachment
+	StorageKey   string
+	ThumbnailKey sql.NullString
+}
+
+// parseAttachmentPath parses the parent and the attachment from the URL.
+func parseAttachmentPath(path string) (attachmentPath, bool) {
+	matches := attachmentsPath.FindStringSubmatch(path)
+	if len(matches) != 5 {
+		return attachmentPath{}, false
+	}
+
+	parsed := attachmentPath{Parent: matches[1], Thumbnail: matches[4] != ""}
+	parsed.ParentID, _ = strconv.Atoi(matches[2])
+	if matches[3] != "" {
+		parsed.AttachmentID, _ = strconv.Atoi(matches[3])
+	}
+	return parsed, true
+}
+
+// parentStateColumns maps the parent entity in the URL to its lifecycle state. Products have no lifecycle.
+var parentStateColumns = map[string]string{
+	"tests":    "state",
+	"products": "NULL",
+}
+
+// parentEntities names the parent entity in the URL in the error messages.
+var parentEntities = map[string]string{
+	"tests":    "test",
+	"products": "product",
+}
+
+// getParentAccess retrieves the owning team, the visibility and the state of the test or product.
+func getParentAccess(db *sql.DB, path attachmentPath) (int, bool, domain.TestState, error) {
+	var testingTeam int
+	var isPublic bool
+	var state sql.NullString
+	err := db.QueryRow(fmt.Sprintf("SELECT testing_team, is_public, %s FROM %s WHERE id = $1;",
+		parentStateColumns[path.Parent], path.Parent), path.ParentID).
+		Scan(&testingTeam, &isPublic, &state)
+	return testingTeam, isPublic, domain.TestState(state.String), err
+}
+
+// scanAttachment scans an attachments row into an attachment record.
+func scanAttachment(row interface{ Scan(...any) error }) (attachmentRecord, error) {
+	var record attachmentRecord
+	var testID, productID, uploadedBy sql.NullInt64
+	err := row.Scan(
+		&record.ID,
+		&testID,
+		&productID,
+		&record.FileName,
+		&record.ContentType,
+		&record.Size,
+		&record.StorageKey,
+		&record.ThumbnailKey,
+		&uploadedBy,
+		&record.TestingTeam,
+		&record.CreatedAt)
+	if err != nil {
+		return attachmentRecord{}, err
+	}
+
+	record.TestID = nullableInt(testID)
+	record.ProductID = nullableInt(productID)
+	record.UploadedBy = nullableInt(uploadedBy)
+	record.HasThumbnail = record.ThumbnailKey.Valid
+	return record, nil
+}
+
+func nullableInt(value sql.NullInt64) *int {
+	if !value.Valid {
+		return nil
+	}
+	i := int(value.Int64)
+	return &i
+}
+
+// getAttachments retrieves the attachments of a test or product, oldest first.
+func getAttachments(db *sql.DB, path attachmentPath) ([]domain.Attachment, error) {
+	rows, err := db.Query(fmt.Sprintf("SELECT %s FROM attachments WHERE %s = $1 ORDER BY created_at, id;",
+		attachmentColumns, parentColumns[path.Parent]), path.ParentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	attachments := []domain.Attachment{}
+	for rows.Next() {
+		record, err := scanAttachment(rows)
+		if err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, record.Attachment)
+	}
+	return attachments, rows.Err()
+}
+
+// getAttachmentWithID retrieves an attachment of a test or product.
+func getAttachmentWithID(db *sql.DB, path attachmentPath) (attachmentRecord, error) {
+	return scanAttachment(db.QueryRow(fmt.Sprintf("SELECT %s FROM attachments WHERE id = $1 AND %s = $2;",
+		attachmentColumns, parentColumns[path.Parent]), path.AttachmentID, path.ParentID))
+}
+
+// insertAttachment inserts a new attachment and returns it with its ID and creation time.
+func insertAttachment(db *sql.DB, path attachmentPath, record attachmentRecord) (domain.Attachment, error) {
+	record.CreatedAt = time.Now()
+	if path.Parent == "tests" {
+		record.TestID = &path.ParentID
+	} else {
+		record.ProductID = &path.ParentID
+	}
+
+	err := db.QueryRow(`INSERT INTO attachments (test_id, product_id, file_name, content_type, size, storage_key,
+									thumbnail_key, uploaded_by, testing_team, created_at)
+								VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+								RETURNING id;`,
+		record.TestID,
+		record.ProductID,
+		record.FileName,
+		record.ContentType,
+		record.Size,
+		record.StorageKey,
+		record.ThumbnailKey,
+		record.UploadedBy,
+		record.TestingTeam,
+		record.CreatedAt).Scan(&record.ID)
+	if err != nil {
+		return domain.Attachment{}, fmt.Errorf("failed to insert attachment: %w", err)
+	}
+	return record.Attachment, nil
+}
+
+// newStorageKey generates a random key for a stored file. The keys never contain user input.
+func newStorageKey() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// detectContentType determines the content type of an upload from its first bytes. The declared content type is
+// only trusted to tell CSV files apart from other plain text.
+func detectContentType(head []byte, declared string) string {
+	detected, _, err := mime.ParseMediaType(http.DetectContentType(head))
+	if err != nil {
+		return "application/octet-stream"
+	}
+
+	declared, _, _ = mime.ParseMediaType(declared)
+	if detected == "text/plain" && declared == "text/csv" {
+		return declared
+	}
+	return detected
+}
+
+// cleanFileName trims the file name of an upload to something safe to store and return in headers.
+func cleanFileName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || r == '/' || r == '\\' {
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(name))
+
+	if name == "" {
+		return "file"
+	}
+	if runes := []rune(name); len(runes) > 255 {
+		name = string(runes[:255])
+	}
+	return name
+}
+
+// saveThumbnail makes a thumbnail of a stored image and stores it under a new key.
+func saveThumbnail(store storage.Storage, key string) (sql.NullString, error) {
+	file, err := store.Open(key)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+	defer file.Close()
+
+	thumb, err := thumbnail.Make(file)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+
+	thumbKey, err := newStorageKey()
+	if err != nil {
+		return sql.NullString{}, err
+	}
+	if _, err = store.Save(thumbKey, bytes.NewReader(thumb)); err != nil {
+		return sql.NullString{}, err
+	}
+	return sql.NullString{String: thumbKey, Valid: true}, nil
+}
+
+// deleteFiles removes the stored files of an attachment. Failures are only logged, as the files can no longer be
+// reached once the attachment is gone.
+func deleteFiles(store storage.Storage, record attachmentRecord) {
+	keys := []string{record.StorageKey}
+	if record.ThumbnailKey.Valid {
+		keys = append(keys, record.ThumbnailKey.String)
+	}
+	for _, key := range keys {
+		if err := store.Delete(key); err != nil {
+			log.Println("Could not delete the stored file " + key + ": " + err.Error())
+		}
+	}
+}
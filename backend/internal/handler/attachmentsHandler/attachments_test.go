@@ -0,0 +1,393 @@
+package attachmentsHandler
+
+import (
+	"backend/internal/services/storage"
+	"backend/internal/utils"
+	"bytes"
+	"database/sql"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"image"
+	"image/png"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+var attachmentColumnNames = []string{"id", "test_id", "product_id", "file_name", "content_type", "size", "storage_key",
+	"thumbnail_key", "uploaded_by", "testing_team", "created_at"}
+
+// memoryStorage is an in-memory storage backend for the tests.
+type memoryStorage struct {
+	mu    sync.Mutex
+	files map[string][]byte
+}
+
+func newMemoryStorage() *memoryStorage {
+	return &memoryStorage{files: map[string][]byte{}}
+}
+
+func (s *memoryStorage) Save(key string, content io.Reader) (int64, error) {
+	b, err := io.ReadAll(content)
+	if err != nil {
+		return 0, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.files[key] = b
+	return int64(len(b)), nil
+}
+
+func (s *memoryStorage) Open(key string) (io.ReadCloser, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b, ok := s.files[key]
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+	return io.NopCloser(bytes.NewReader(b)), nil
+}
+
+func (s *memoryStorage) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.files, key)
+	return nil
+}
+
+func AuthenticationMock(mock sqlmock.Sqlmock) {
+	// Mock the user id query
+	mock.ExpectQuery("SELECT user_id FROM sessions WHERE \\(session_token = \\$1 AND expires_at > NOW\\(\\)\\)").
+		WithArgs("mockToken").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(1))
+
+	// Mock the user team id query
+	mock.ExpectQuery("SELECT team_id FROM users WHERE id = \\$1;").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"team_id"}).AddRow(1))
+
+	// Mock the user team role query
+	mock.ExpectQuery("SELECT team_role FROM team WHERE id = \\$1;").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"team_role"}).AddRow(1))
+}
+
+// multipartBody builds a multipart form with a single file field.
+func multipartBody(t *testing.T, fileName string, content []byte) (string, string) {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	part, err := writer.CreateFormFile("file", fileName)
+	assert.NoError(t, err)
+	_, err = part.Write(content)
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+	return buf.String(), writer.FormDataContentType()
+}
+
+func pngImage(t *testing.T) []byte {
+	var buf bytes.Buffer
+	assert.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 600, 300))))
+	return buf.Bytes()
+}
+
+func Test_parseAttachmentPath(t *testing.T) {
+	path, ok := parseAttachmentPath("/tests/3/attachments")
+	assert.True(t, ok)
+	assert.Equal(t, attachmentPath{Parent: "tests", ParentID: 3}, path)
+
+	path, ok = parseAttachmentPath("/products/7/attachments/12/thumbnail")
+	assert.True(t, ok)
+	assert.Equal(t, attachmentPath{Parent: "products", ParentID: 7, AttachmentID: 12, Thumbnail: true}, path)
+
+	_, ok = parseAttachmentPath("/skis/3/attachments")
+	assert.False(t, ok)
+}
+
+func Test_detectContentType(t *testing.T) {
+	assert.Equal(t, "image/png", detectContentType(pngImage(t), "text/plain"))
+	assert.Equal(t, "text/csv", detectContentType([]byte("id;name\n1;Glider"), "text/csv; charset=utf-8"))
+	assert.Equal(t, "text/plain", detectContentType([]byte("wax notes"), "image/png"))
+	assert.Equal(t, "text/html", detectContentType([]byte("<html><body></body></html>"), "text/plain"))
+}
+
+func Test_cleanFileName(t *testing.T) {
+	assert.Equal(t, "photo.jpg", cleanFileName(" photo.jpg "))
+	assert.Equal(t, "evil.pdf", cleanFileName("evil\r\n.pdf"))
+	assert.Equal(t, "file", cleanFileName(""))
+	assert.Len(t, []rune(cleanFileName(strings.Repeat("å", 300))), 255)
+}
+
+func TestAttachmentsHandler(t *testing.T) {
+	mockDB, mock := utils.InitMockDB(t)
+	store := newMemoryStorage()
+	createdAt := time.Date(2025, 3, 30, 10, 30, 0, 0, time.UTC)
+
+	accessMock := func(table string, id, testingTeam int, isPublic bool) {
+		var state any = "in_progress"
+		if table == "products" {
+			state = nil
+		}
+		mock.ExpectQuery("SELECT testing_team, is_public, .* FROM " + table + " WHERE id = \\$1;").
+			WithArgs(id).
+			WillReturnRows(sqlmock.NewRows([]string{"testing_team", "is_public", "state"}).
+				AddRow(testingTeam, isPublic, state))
+	}
+	attachmentMock := func(thumbnailKey any) {
+		mock.ExpectQuery("SELECT .* FROM attachments WHERE id = \\$1 AND test_id = \\$2;").
+			WithArgs(5, 3).
+			WillReturnRows(sqlmock.NewRows(attachmentColumnNames).
+				AddRow(5, 3, nil, "notes.txt", "text/plain", 9, "a1", thumbnailKey, 1, 1, createdAt))
+	}
+
+	pngBody, pngContentType := multipartBody(t, "glide.png", pngImage(t))
+	htmlBody, htmlContentType := multipartBody(t, "page.html", []byte("<html><script></script></html>"))
+	largeBody, largeContentType := multipartBody(t, "large.txt", bytes.Repeat([]byte("a"), maxUploadSize+1))
+
+	tests := []struct {
+		name          string
+		method        string
+		path          string
+		body          string
+		contentType   string
+		setupMocks    func()
+		expectedCode  int
+		expectedBody  string
+		expectedFiles int
+	}{
+		{
+			name:         "Method = GET (Status OK - attachments of a test)",
+			method:       http.MethodGet,
+			path:         "/tests/3/attachments",
+			expectedCode: http.StatusOK,
+			expectedBody: `"file_name":"glide.jpg","content_type":"image/jpeg","size":2048,"has_thumbnail":true`,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				accessMock("tests", 3, 2, true)
+				mock.ExpectQuery("SELECT .* FROM attachments WHERE test_id = \\$1 ORDER BY created_at, id;").
+					WithArgs(3).
+					WillReturnRows(sqlmock.NewRows(attachmentColumnNames).
+						AddRow(5, 3, nil, "glide.jpg", "image/jpeg", 2048, "a1", "b2", 4, 2, createdAt))
+			},
+		},
+		{
+			name:         "Method = GET (Status unauthorized - private product of another team)",
+			method:       http.MethodGet,
+			path:         "/products/8/attachments",
+			expectedCode: http.StatusUnauthorized,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				accessMock("products", 8, 2, false)
+			},
+		},
+		{
+			name:         "Method = GET (Status not found - missing test)",
+			method:       http.MethodGet,
+			path:         "/tests/9/attachments",
+			expectedCode: http.StatusNotFound,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				mock.ExpectQuery("SELECT testing_team, is_public, state FROM tests WHERE id = \\$1;").
+					WithArgs(9).
+					WillReturnError(sql.ErrNoRows)
+			},
+		},
+		{
+			name:         "Method = GET (Status OK - download)",
+			method:       http.MethodGet,
+			path:         "/tests/3/attachments/5",
+			expectedCode: http.StatusOK,
+			expectedBody: "wax notes",
+			setupMocks: func() {
+				_, _ = store.Save("a1", strings.NewReader("wax notes"))
+				AuthenticationMock(mock)
+				accessMock("tests", 3, 1, false)
+				attachmentMock(nil)
+			},
+			expectedFiles: 1,
+		},
+		{
+			name:         "Method = GET (Status not found - no thumbnail)",
+			method:       http.MethodGet,
+			path:         "/tests/3/attachments/5/thumbnail",
+			expectedCode: http.StatusNotFound,
+			expectedBody: "The attachment has no thumbnail.",
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				accessMock("tests", 3, 1, false)
+				attachmentMock(nil)
+			},
+		},
+		{
+			name:         "Method = DELETE (Status no content)",
+			method:       http.MethodDelete,
+			path:         "/tests/3/attachments/5",
+			expectedCode: http.StatusNoContent,
+			setupMocks: func() {
+				_, _ = store.Save("b2", strings.NewReader("thumbnail"))
+				AuthenticationMock(mock)
+				accessMock("tests", 3, 1, false)
+				attachmentMock("b2")
+				mock.ExpectExec("DELETE FROM attachments WHERE id = \\$1;").
+					WithArgs(5).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name:         "Method = DELETE (Status not found)",
+			method:       http.MethodDelete,
+			path:         "/tests/3/attachments/5",
+			expectedCode: http.StatusNotFound,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				accessMock("tests", 3, 1, false)
+				mock.ExpectQuery("SELECT .* FROM attachments WHERE id = \\$1 AND test_id = \\$2;").
+					WithArgs(5, 3).
+					WillReturnError(sql.ErrNoRows)
+			},
+		},
+		{
+			name:         "Method = DELETE (Status unauthorized - test of another team)",
+			method:       http.MethodDelete,
+			path:         "/tests/3/attachments/5",
+			expectedCode: http.StatusUnauthorized,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				accessMock("tests", 3, 2, true)
+			},
+		},
+		{
+			name:         "Method = DELETE (Status conflict - completed test)",
+			method:       http.MethodDelete,
+			path:         "/tests/3/attachments/5",
+			expectedCode: http.StatusConflict,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				mock.ExpectQuery("SELECT testing_team, is_public, state FROM tests WHERE id = \\$1;").
+					WithArgs(3).
+					WillReturnRows(sqlmock.NewRows([]string{"testing_team", "is_public", "state"}).
+						AddRow(1, false, "completed"))
+			},
+		},
+		{
+			name:         "Method = POST (Status created - image with thumbnail)",
+			method:       http.MethodPost,
+			path:         "/products/8/attachments",
+			body:         pngBody,
+			contentType:  pngContentType,
+			expectedCode: http.StatusCreated,
+			expectedBody: `"product_id":8,"file_name":"glide.png","content_type":"image/png"`,
+			setupMocks: func() {
+				mock.ExpectQuery("SELECT user_id FROM sessions").
+					WithArgs("mockToken").
+					WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(1))
+				AuthenticationMock(mock)
+				accessMock("products", 8, 1, false)
+				mock.ExpectQuery("INSERT INTO attachments").
+					WithArgs(nil, 8, "glide.png", "image/png", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), 1, 1,
+						sqlmock.AnyArg()).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(6))
+			},
+			expectedFiles: 2,
+		},
+		{
+			name:         "Method = POST (Status internal server error - the stored files are deleted)",
+			method:       http.MethodPost,
+			path:         "/products/8/attachments",
+			body:         pngBody,
+			contentType:  pngContentType,
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: "Could not store the file.",
+			setupMocks: func() {
+				mock.ExpectQuery("SELECT user_id FROM sessions").
+					WithArgs("mockToken").
+					WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(1))
+				AuthenticationMock(mock)
+				accessMock("products", 8, 1, false)
+				mock.ExpectQuery("INSERT INTO attachments").
+					WillReturnError(sql.ErrConnDone)
+			},
+			expectedFiles: 0,
+		},
+		{
+			name:         "Method = POST (Status unsupported media type)",
+			method:       http.MethodPost,
+			path:         "/tests/3/attachments",
+			body:         htmlBody,
+			contentType:  htmlContentType,
+			expectedCode: http.StatusUnsupportedMediaType,
+			setupMocks: func() {
+				mock.ExpectQuery("SELECT user_id FROM sessions").
+					WithArgs("mockToken").
+					WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(1))
+				AuthenticationMock(mock)
+				accessMock("tests", 3, 1, false)
+			},
+		},
+		{
+			name:         "Method = POST (Status request entity too large)",
+			method:       http.MethodPost,
+			path:         "/tests/3/attachments",
+			body:         largeBody,
+			contentType:  largeContentType,
+			expectedCode: http.StatusRequestEntityTooLarge,
+			setupMocks: func() {
+				mock.ExpectQuery("SELECT user_id FROM sessions").
+					WithArgs("mockToken").
+					WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(1))
+				AuthenticationMock(mock)
+				accessMock("tests", 3, 1, false)
+			},
+		},
+		{
+			name:         "Method = POST (Status bad request - not a multipart form)",
+			method:       http.MethodPost,
+			path:         "/tests/3/attachments",
+			body:         `{"file":"notes.txt"}`,
+			contentType:  "application/json",
+			expectedCode: http.StatusBadRequest,
+			setupMocks: func() {
+				mock.ExpectQuery("SELECT user_id FROM sessions").
+					WithArgs("mockToken").
+					WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(1))
+				AuthenticationMock(mock)
+				accessMock("tests", 3, 1, false)
+			},
+		},
+		{
+			name:         "Method = PUT (Status not implemented)",
+			method:       http.MethodPut,
+			path:         "/tests/3/attachments",
+			expectedCode: http.StatusNotImplemented,
+			setupMocks:   func() {},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			req.Header.Set("Authorization", "Bearer mockToken")
+			rr := httptest.NewRecorder()
+
+			AttachmentsHandler(mockDB, store).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedCode, rr.Code)
+			if tt.expectedBody != "" {
+				assert.Contains(t, rr.Body.String(), tt.expectedBody)
+			}
+			assert.Len(t, store.files, tt.expectedFiles)
+			store.files = map[string][]byte{}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package bundlesHandler
+
+import (
+	"time"
+)
+
+// BundlePATCHRequest updates the name or the layers of a bundle.
+type BundlePATCHRequest struct {
+	Name *string `json:"name" validate:"omitempty,min=1,max=64"`
+
+	// Products are the layers of the bundle, in the order they are applied. They replace the current layers, so
+	// layers are added, removed and reordered by sending the list as it should be. Layers can be given instead, with
+	// the recipe for applying each of them. Without either, the layers are kept.
+	Products []int             `json:"products" validate:"omitempty,excluded_with=Layers,min=1,max=20,unique,dive,gt=0"`
+	Layers   []BundleLayerPOST `json:"layers" validate:"omitempty,min=1,max=20,unique=ProductID,dive"`
+
+	Version time.Time `json:"version"`
+}
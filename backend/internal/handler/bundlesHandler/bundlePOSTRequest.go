@@ -1,10 +1,31 @@
 package bundlesHandler
 
 type BundlePOSTRequest struct {
-	Products    []int  `json:"products"`
-	ProductName string `json:"name"`
-	Comment     string `json:"comment"`
-	IsPublic    bool   `json:"is_public"`
-	TestingTeam int    `json:"testing_team"`
-	Status      string `json:"status"`
+	// Products are the layers of the bundle, in the order they are applied, without recipes. Layers can be given
+	// instead, with the recipe for applying each of them.
+	Products    []int             `json:"products" validate:"required_without=Layers,excluded_with=Layers,unique,dive,gt=0"`
+	Layers      []BundleLayerPOST `json:"layers" validate:"omitempty,max=20,unique=ProductID,dive"`
+	ProductName string            `json:"name"`
+	Comment     string            `json:"comment"`
+	IsPublic    bool              `json:"is_public"`
+	TestingTeam int               `json:"testing_team"`
+	Status      string            `json:"status"`
+}
+
+// BundleLayerPOST is a layer of a bundle, with the recipe for applying it.
+type BundleLayerPOST struct {
+	ProductID int              `json:"product_id" validate:"required,gt=0"`
+	Recipe    *LayerRecipePOST `json:"recipe"`
+}
+
+// LayerRecipePOST is how a layer is applied. The iron temperature, in degrees Celsius, is only for ironed layers, and
+// the brushing lists the brushes in the order they are used.
+type LayerRecipePOST struct {
+	Method          string   `json:"method" validate:"required,oneof=iron rub spray applicator"`
+	IronTemperature *int     `json:"iron_temperature" validate:"excluded_unless=Method iron,omitempty,gte=90,lte=180"`
+	Passes          *int     `json:"passes" validate:"omitempty,gte=1,lte=20"`
+	CoolingMinutes  *int     `json:"cooling_minutes" validate:"omitempty,gte=0,lte=240"`
+	Brushing        []string `json:"brushing" validate:"max=8,dive,oneof=brass steel bronze nylon horsehair boar"`
+	Cork            bool     `json:"cork"`
+	Fleece          bool     `json:"fleece"`
 }
@@ -10,14 +10,15 @@ import (
 	"encoding/json"
 	"log"
 	"net/http"
-	"regexp"
-	"strings"
 )
 
 // BundlesHandler routes HTTP requests for bundles to the appropriate handler function.
 //
 // It supports the following methods:
-// - GET: Retrieves a list of bundles.
+// - GET: Retrieves a list of bundles, or a bundle with its layers.
+// - POST: Creates a new bundle.
+// - PATCH: Updates the name or the layers of a bundle.
+// - DELETE: Deletes a bundle without test results.
 //
 // Each method has its own dedicated request handler function, which is documented separately using Swagger annotations.
 func BundlesHandler(db *sql.DB) http.HandlerFunc {
@@ -25,9 +26,17 @@ func BundlesHandler(db *sql.DB) http.HandlerFunc {
 		w.Header().Set("content-type", "application/json")
 		switch r.Method {
 		case http.MethodGet:
+			if bundlePath.MatchString(r.URL.Path) || r.URL.Query().Has("product_id") {
+				BundleRequestGET(w, r, db)
+				return
+			}
 			BundlesRequestGET(w, r, db)
 		case http.MethodPost:
 			BundlesRequestPOST(w, r, db)
+		case http.MethodPatch:
+			BundleRequestPATCH(w, r, db)
+		case http.MethodDelete:
+			BundleRequestDELETE(w, r, db)
 		default:
 			http.Error(w, resources.MethodNotAllowed, http.StatusNotImplemented)
 			return
@@ -38,34 +47,26 @@ func BundlesHandler(db *sql.DB) http.HandlerFunc {
 // BundlesRequestGET handles GET requests for bundles.
 //
 //	@Summary		Get a list of bundles
-//	@Description	Retrieves a list of bundles based on query parameters.
+//	@Description	Retrieves the layers of the bundles of the team and of the public bundles, as bundle, product and
+//	@Description	layer number. With product_id, the bundle of that product row is returned instead, with its layers,
+//	@Description	as from /bundles/{id}.
 //	@Tags			Bundles
 //	@Accept			json
 //	@Produce		json
 //	@Security		BearerAuth
-//	@Param			id	query		int						false	"Bundle ID"
-//	@Success		200	{array}		domain.ProductBundle	"Successful response with a list of bundles"
-//	@Failure		500	{string}	string					"Could not retrieve all bundles."
+//	@Param			product_id	query		int						false	"ID of the product row of a bundle"
+//	@Success		200			{array}		domain.ProductBundle	"Successful response with a list of bundles"
+//	@Failure		404			{string}	string					"Could not find the bundle."
+//	@Failure		500			{string}	string					"Could not retrieve all bundles."
 //	@Router			/bundles [get]
 //	@Router			/bundles/ [get]
 func BundlesRequestGET(w http.ResponseWriter, r *http.Request, db *sql.DB) {
-	// Retrieve the part of the URL that contains the bundle id.
-	idParam := strings.TrimPrefix(r.URL.Path, "/bundles/")
-	idStr := regexp.MustCompile(`\d+`).FindString(idParam)
-
-	var id int
-	// Get the product ID from the URL query parameter.
-	if idStr != "" {
-		id, _ = utils.GetIDFromURLQuery(w, idStr)
-	}
-
-	var bundles []domain.ProductBundle
+	// Get the user's team membership.
+	team := middleware.GetUserTeamRole(w, r, db)
 
-	// Check if the query does not contain an id.
-	if id == 0 {
-		bundles = getAllBundles(w, db, bundles)
-	} else {
-		bundles = getBundlesByBundleID(w, db, bundles, id, idStr)
+	bundles := getAllBundles(w, db, []domain.ProductBundle{}, team)
+	if bundles == nil {
+		return
 	}
 
 	// Check if no bundles were found.
@@ -85,13 +86,16 @@ func BundlesRequestGET(w http.ResponseWriter, r *http.Request, db *sql.DB) {
 // BundlesRequestPOST handles POST requests for creating new bundles.
 //
 //	@Summary		Create a new bundle
-//	@Description	Creates a new bundle based on the provided JSON request body.
+//	@Description	Creates a new bundle based on the provided JSON request body. The layers are given as products, in
+//	@Description	the order they are applied, or with the recipe for applying each of them. The layers must follow
+//	@Description	the layering rules, such as powder and liquid top coats on a solid base, and have temperatures in
+//	@Description	common, which become the temperature range of the bundle.
 //	@Tags			Bundles
 //	@Accept			json
 //	@Produce		json
 //	@Security		BearerAuth
-//	@Param			bundle	body		domain.ProductBundle	true	"Bundle details"
-//	@Success		201		{string}	string					"Bundle created successfully"
+//	@Param			bundle	body		BundlePOSTRequest	true	"Bundle details"
+//	@Success		201		{object}	domain.Bundle		"The bundle, without its layers"
 //	@Failure		400		{string}	string					"Invalid request body"
 //	@Failure		401		{string}	string					"Unauthorized"
 //	@Failure		500		{string}	string					"Failed to commit the transaction."
@@ -101,12 +105,6 @@ func BundlesRequestPOST(w http.ResponseWriter, r *http.Request, db *sql.DB) {
 	team := middleware.GetUserTeamRole(w, r, db)
 
 	bundle, err := utils.ParseAndValidateRequest[BundlePOSTRequest](r)
-	bundleJSON, err := json.MarshalIndent(bundle, "", "  ")
-	if err != nil {
-		log.Println("Error marshaling test:", err)
-	} else {
-		log.Println("Test JSON:", string(bundleJSON))
-	}
 	if err != nil {
 		http.Error(w, resources.InvalidPOSTRequest, http.StatusBadRequest)
 		log.Println(resources.InvalidPOSTRequest + ": " + err.Error())
@@ -120,6 +118,13 @@ func BundlesRequestPOST(w http.ResponseWriter, r *http.Request, db *sql.DB) {
 		return
 	}
 
+	// Validate the layers, and get the temperature window of the bundle from them.
+	window, err, code := validateLayers(db, layerProductIDs(requestLayers(bundle.Products, bundle.Layers)), team)
+	if err != nil {
+		http.Error(w, "Validation error: "+err.Error(), code)
+		return
+	}
+
 	tx, err := db.Begin()
 	if err != nil {
 		http.Error(w, resources.TransactionStartFailed, http.StatusInternalServerError)
@@ -134,10 +139,11 @@ func BundlesRequestPOST(w http.ResponseWriter, r *http.Request, db *sql.DB) {
 		}
 	}()
 
-	err = createBundles(tx, bundle, team)
+	bundleID, productID, err := createBundles(tx, bundle, window, team)
 	if err != nil {
 		http.Error(w, resources.InvalidPOSTRequest, http.StatusBadRequest)
 		log.Println(resources.InvalidPOSTRequest + ": " + err.Error())
+		return
 	}
 
 	//Commit transaction
@@ -149,4 +155,9 @@ func BundlesRequestPOST(w http.ResponseWriter, r *http.Request, db *sql.DB) {
 	}
 
 	w.WriteHeader(http.StatusCreated)
+	err = json.NewEncoder(w).Encode(domain.Bundle{ID: bundleID, ProductID: productID, Name: bundle.ProductName,
+		TestingTeam: team})
+	if err != nil {
+		log.Println("Could not encode the bundle: " + err.Error())
+	}
 }
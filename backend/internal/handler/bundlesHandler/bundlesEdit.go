@@ -0,0 +1,165 @@
+package bundlesHandler
+
+import (
+	"backend/internal/middleware"
+	"backend/internal/utils"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+)
+
+// BundleRequestPATCH is the request handler for updating a bundle.
+//
+//	@Summary		Update a bundle
+//	@Description	Renames a bundle, or replaces its layers with the products given, in the order they are applied,
+//	@Description	so layers are added, removed and reordered by sending the list as it should be. The layers can be
+//	@Description	given with the recipe for applying each of them, which is kept with the layer. The layers must
+//	@Description	follow the layering rules, such as powder and liquid top coats on a solid base, and have
+//	@Description	temperatures in common, which become the temperature range of the bundle. The version must
+//	@Description	be the version of the bundle the changes were made to, or the update is refused as a conflict. A
+//	@Description	bundle of another team can not be updated, and a researcher can not update a public bundle.
+//	@Tags			Bundles
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		int						true	"Bundle ID"
+//	@Param			bundle	body		BundlePATCHRequest		true	"The changes to the bundle"
+//	@Success		200		{object}	map[string]interface{}	"Bundle updated successfully, with its new version"
+//	@Failure		400		{string}	string					"Invalid request body"
+//	@Failure		401		{string}	string					"User cannot update this bundle"
+//	@Failure		404		{string}	string					"Could not find the bundle."
+//	@Failure		409		{string}	string					"Detected a conflict for the current bundle, please refresh."
+//	@Failure		500		{string}	string					"Could not update the bundle."
+//	@Router			/bundles/{id} [patch]
+func BundleRequestPATCH(w http.ResponseWriter, r *http.Request, db *sql.DB) {
+	matches := bundlePath.FindStringSubmatch(r.URL.Path)
+	if len(matches) != 2 {
+		http.Error(w, "Invalid request URL, use '/bundles/{bundle_id}'.", http.StatusBadRequest)
+		log.Println("Invalid request URL: " + r.URL.Path)
+		return
+	}
+	bundleID, _ := strconv.Atoi(matches[1])
+
+	// Get the user's team membership.
+	team := middleware.GetUserTeamRole(w, r, db)
+
+	request, err := utils.ParseAndValidateRequest[BundlePATCHRequest](r)
+	if err != nil {
+		http.Error(w, "Invalid PATCH request", http.StatusBadRequest)
+		log.Println("Invalid PATCH request: " + err.Error())
+		return
+	}
+
+	existingBundle, isPublic, err := getBundleWithID(db, bundleID)
+	if err != nil {
+		http.Error(w, "Could not find the bundle.", http.StatusNotFound)
+		log.Println("Could not find the bundle: " + err.Error())
+		return
+	}
+
+	var code int
+	var window temperatureWindow
+	err, code = validateBundleAccess(existingBundle, isPublic, team, "update")
+	if layers := requestLayers(request.Products, request.Layers); err == nil && len(layers) > 0 {
+		window, err, code = validateLayers(db, layerProductIDs(layers), team)
+	}
+	if err != nil {
+		http.Error(w, "Validation error: "+err.Error(), code)
+		return
+	}
+
+	// Solve the concurrency challenge by checking if the bundle has been updated since the last sync.
+	if existingBundle.Version.After(request.Version) {
+		http.Error(w, "Detected a conflict for the current bundle, please refresh.", http.StatusConflict)
+		log.Println("Detected a conflict for the current bundle, please refresh.")
+		return
+	}
+
+	userID := middleware.GetUserID(w, r, db)
+	newVersion, err := updateBundle(db, existingBundle, request, window, userID)
+	if errors.Is(err, errBundleConflict) {
+		http.Error(w, "Detected a conflict for the current bundle, please refresh.", http.StatusConflict)
+		log.Println("Detected a conflict for the current bundle, please refresh.")
+		return
+	} else if err != nil {
+		http.Error(w, "Could not update the bundle.", http.StatusInternalServerError)
+		log.Println("Could not update the bundle: " + err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Bundle updated successfully",
+		"version": newVersion,
+	})
+	if err != nil {
+		log.Println("Could not JSON encode the response: " + err.Error())
+	}
+}
+
+// BundleRequestDELETE is the request handler for deleting a bundle.
+//
+//	@Summary		Delete a bundle
+//	@Description	Deletes a bundle with its layers and its product row. A bundle with test results or runs can not
+//	@Description	be deleted, as the results would be lost with it. A bundle of another team can not be deleted, and
+//	@Description	a researcher can not delete a public bundle.
+//	@Tags			Bundles
+//	@Security		BearerAuth
+//	@Param			id	path		int		true	"Bundle ID"
+//	@Success		204	{string}	string	"Bundle deleted successfully"
+//	@Failure		401	{string}	string	"User cannot delete this bundle"
+//	@Failure		404	{string}	string	"Could not find the bundle."
+//	@Failure		409	{string}	string	"The bundle has test results and cannot be deleted."
+//	@Failure		500	{string}	string	"Could not delete the bundle."
+//	@Router			/bundles/{id} [delete]
+func BundleRequestDELETE(w http.ResponseWriter, r *http.Request, db *sql.DB) {
+	matches := bundlePath.FindStringSubmatch(r.URL.Path)
+	if len(matches) != 2 {
+		http.Error(w, "Invalid request URL, use '/bundles/{bundle_id}'.", http.StatusBadRequest)
+		log.Println("Invalid request URL: " + r.URL.Path)
+		return
+	}
+	bundleID, _ := strconv.Atoi(matches[1])
+
+	// Get the user's team membership.
+	team := middleware.GetUserTeamRole(w, r, db)
+
+	bundle, isPublic, err := getBundleWithID(db, bundleID)
+	if err != nil {
+		http.Error(w, "Could not find the bundle.", http.StatusNotFound)
+		log.Println("Could not find the bundle: " + err.Error())
+		return
+	}
+
+	var code int
+	err, code = validateBundleAccess(bundle, isPublic, team, "delete")
+	if err != nil {
+		http.Error(w, "Validation error: "+err.Error(), code)
+		return
+	}
+
+	count, err := countBundleResults(db, bundle.ProductID)
+	if err != nil {
+		http.Error(w, "Could not delete the bundle.", http.StatusInternalServerError)
+		log.Println("Could not count the results of the bundle: " + err.Error())
+		return
+	}
+	if count > 0 {
+		http.Error(w, "The bundle has test results and cannot be deleted.", http.StatusConflict)
+		log.Printf("The bundle %d has %d test results and cannot be deleted", bundleID, count)
+		return
+	}
+
+	// The bundle and its layers are deleted with its product row.
+	_, err = db.Exec("DELETE FROM products WHERE id = $1 AND type = 'bundle';", bundle.ProductID)
+	if err != nil {
+		http.Error(w, "Could not delete the bundle.", http.StatusInternalServerError)
+		log.Println("Could not delete the bundle: " + err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
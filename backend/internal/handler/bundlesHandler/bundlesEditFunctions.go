@@ -0,0 +1,116 @@
+package bundlesHandler
+
+import (
+	"backend/internal/domain"
+	"backend/internal/handler/productsHandler"
+	"backend/internal/resources"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+)
+
+// errBundleConflict is returned when a bundle was changed since it was read.
+var errBundleConflict = errors.New("the bundle was changed since it was read")
+
+// getBundleWithID retrieves a bundle, without its layers, and whether its product row is public.
+func getBundleWithID(db *sql.DB, id int) (domain.Bundle, bool, error) {
+	var bundle domain.Bundle
+	var isPublic bool
+	err := db.QueryRow(`SELECT `+bundleColumns+`, p.is_public FROM bundles b JOIN products p ON p.id = b.product_id
+							WHERE b.id = $1;`, id).
+		Scan(&bundle.ID, &bundle.ProductID, &bundle.Name, &bundle.TestingTeam, &bundle.Version, &isPublic)
+	return bundle, isPublic, err
+}
+
+// validateBundleAccess checks that the team can change the bundle, by the same rules as creating it: a bundle of
+// another team can not be changed, and a researcher can not change a public bundle. A bundle the team can not see
+// is not found.
+func validateBundleAccess(bundle domain.Bundle, isPublic bool, team int, action string) (error, int) {
+	switch {
+	case bundle.TestingTeam != team && !isPublic:
+		log.Printf("Could not find the bundle %d", bundle.ID)
+		return fmt.Errorf("could not find the bundle %d, %d", bundle.ID, http.StatusNotFound), http.StatusNotFound
+	case isPublic && domain.TeamRole(team) == domain.Researcher:
+		log.Printf("Researcher cannot %s public bundles", action)
+		return fmt.Errorf("researcher cannot %s public bundles, %d", action, http.StatusUnauthorized),
+			http.StatusUnauthorized
+	case bundle.TestingTeam != team:
+		log.Printf("User cannot %s this bundle", action)
+		return fmt.Errorf("user cannot %s this bundle, %d", action, http.StatusUnauthorized), http.StatusUnauthorized
+	}
+	return nil, 0
+}
+
+// updateBundle renames a bundle and its product row, and replaces its layers with their recipes and the temperature
+// window of the product row with theirs, in one transaction. The changes of the product row are recorded in its
+// history by the user. The bundle must still have the version it was read with, or errBundleConflict is returned. It
+// returns the new version.
+func updateBundle(db *sql.DB, bundle domain.Bundle, request BundlePATCHRequest, window temperatureWindow, userID int,
+) (newVersion time.Time, err error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer func() {
+		if err != nil {
+			rollbackErr := tx.Rollback()
+			if rollbackErr != nil {
+				log.Println(resources.RollbackFailed + rollbackErr.Error())
+			}
+		}
+	}()
+
+	name := bundle.Name
+	if request.Name != nil {
+		name = *request.Name
+	}
+	err = tx.QueryRow(`UPDATE bundles SET name = $1, version = $2 WHERE id = $3 AND version = $4 RETURNING version;`,
+		name, time.Now(), bundle.ID, bundle.Version).Scan(&newVersion)
+	if errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, errBundleConflict
+	} else if err != nil {
+		return time.Time{}, err
+	}
+
+	productUpdates := map[string]interface{}{}
+	if name != bundle.Name {
+		productUpdates["name"] = name
+	}
+
+	if layers := requestLayers(request.Products, request.Layers); len(layers) > 0 {
+		_, err = tx.Exec("DELETE FROM product_bundles WHERE bundle_id = $1;", bundle.ID)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("could not remove the layers: %v", err)
+		}
+		for layerNo, layer := range layers {
+			err = insertBundles(tx, bundle.ID, layer, layerNo+1)
+			if err != nil {
+				return time.Time{}, fmt.Errorf("could not insert the layer of product %d: %v", layer.ProductID, err)
+			}
+		}
+		productUpdates["low_temperature"] = window.Low
+		productUpdates["high_temperature"] = window.High
+	}
+
+	// The product row of the bundle follows its name and temperature window, with the changes in its history.
+	if len(productUpdates) > 0 {
+		err = productsHandler.UpdateProductFields(tx, bundle.ProductID, productUpdates, userID)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("could not update the product of the bundle: %v", err)
+		}
+	}
+
+	err = tx.Commit()
+	return newVersion, err
+}
+
+// countBundleResults returns the number of test results and runs of the product row of a bundle.
+func countBundleResults(db *sql.DB, productID int) (int, error) {
+	var count int
+	err := db.QueryRow(`SELECT (SELECT COUNT(*) FROM test_ranks WHERE product_id = $1)
+								+ (SELECT COUNT(*) FROM test_runs WHERE product_id = $1);`, productID).Scan(&count)
+	return count, err
+}
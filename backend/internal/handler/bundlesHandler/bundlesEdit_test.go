@@ -0,0 +1,338 @@
+package bundlesHandler
+
+import (
+	"backend/internal/utils"
+	"database/sql"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBundleRequestPATCH(t *testing.T) {
+	mockDB, mock := utils.InitMockDB(t)
+	version := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	bundleMock := func(team int, isPublic bool) {
+		mock.ExpectQuery("SELECT b.id, b.product_id, b.name, b.testing_team, b.version, p.is_public FROM bundles b").
+			WithArgs(2).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "name", "testing_team", "version",
+				"is_public"}).AddRow(2, 7, "SuperGo Bundle", team, version, isPublic))
+	}
+	layersMock := func() *sqlmock.ExpectedQuery {
+		return mock.ExpectQuery("SELECT id, type, low_temperature, high_temperature FROM products "+
+			"WHERE id = ANY\\(\\$1\\)").
+			WithArgs("{6,4}", 1)
+	}
+
+	layerRows := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "type", "low_temperature", "high_temperature"})
+	}
+	userMock := func() {
+		mock.ExpectQuery("SELECT user_id FROM sessions WHERE \\(session_token = \\$1 AND expires_at > NOW\\(\\)\\)").
+			WithArgs("mockToken").
+			WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(1))
+	}
+	// The product row of the bundle is read, and its changes are recorded as revision 2.
+	productMock := func() {
+		mock.ExpectQuery("SELECT \\* FROM products WHERE id = \\$1 FOR UPDATE;").
+			WithArgs(7).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "brand", "ean_code", "image_url", "comment",
+				"is_public", "type", "high_temperature", "low_temperature", "testing_team", "version", "status"}).
+				AddRow(7, "SuperGo Bundle", "", "", "", "", false, "bundle", 0.0, -12.0, 1, version, "active"))
+	}
+	revisionMock := func() {
+		mock.ExpectQuery("SELECT COALESCE\\(MAX\\(revision\\), 0\\) \\+ 1 FROM product_history WHERE product_id = \\$1;").
+			WithArgs(7).
+			WillReturnRows(sqlmock.NewRows([]string{"revision"}).AddRow(2))
+	}
+	temperaturesMock := func() {
+		productMock()
+		mock.ExpectExec("UPDATE products SET high_temperature = \\$1, low_temperature = \\$2, version = \\$3 "+
+			"WHERE id = \\$4;").
+			WithArgs(-2.0, -8.0, sqlmock.AnyArg(), 7).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		revisionMock()
+		mock.ExpectExec("INSERT INTO product_history").
+			WithArgs(7, 2, "high_temperature", "0", "-2", 1, sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("INSERT INTO product_history").
+			WithArgs(7, 2, "low_temperature", "-12", "-8", 1, sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectQuery("SELECT DISTINCT test_id FROM test_ranks WHERE product_id = \\$1").
+			WithArgs(7).
+			WillReturnRows(sqlmock.NewRows([]string{"test_id"}))
+	}
+
+	tests := []struct {
+		name         string
+		body         string
+		setupMocks   func()
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:         "Status OK - layers reordered and one removed",
+			body:         `{"products":[6,4],"version":"2025-01-02T00:00:00Z"}`,
+			expectedCode: http.StatusOK,
+			expectedBody: `"message":"Bundle updated successfully"`,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				bundleMock(1, false)
+				layersMock().WillReturnRows(layerRows().
+					AddRow(4, "liquid", -12.0, -2.0).AddRow(6, "solid", -8.0, 0.0))
+				userMock()
+				mock.ExpectBegin()
+				mock.ExpectQuery("UPDATE bundles SET name = \\$1, version = \\$2 WHERE id = \\$3 AND version = \\$4").
+					WithArgs("SuperGo Bundle", sqlmock.AnyArg(), 2, version).
+					WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(time.Now()))
+				mock.ExpectExec("DELETE FROM product_bundles WHERE bundle_id = \\$1;").
+					WithArgs(2).
+					WillReturnResult(sqlmock.NewResult(0, 3))
+				mock.ExpectExec("INSERT INTO product_bundles").
+					WithArgs(2, 6, 1, nil, nil, nil, nil, "{}", false, false).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO product_bundles").
+					WithArgs(2, 4, 2, nil, nil, nil, nil, "{}", false, false).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				temperaturesMock()
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "Status OK - layers with recipes",
+			body: `{"layers":[{"product_id":6,"recipe":{"method":"iron","iron_temperature":130,"passes":3,` +
+				`"cooling_minutes":20,"brushing":["steel","nylon"],"fleece":true}},{"product_id":4,` +
+				`"recipe":{"method":"rub","cork":true}}],"version":"2025-01-02T00:00:00Z"}`,
+			expectedCode: http.StatusOK,
+			expectedBody: `"message":"Bundle updated successfully"`,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				bundleMock(1, false)
+				layersMock().WillReturnRows(layerRows().
+					AddRow(4, "liquid", -12.0, -2.0).AddRow(6, "solid", -8.0, 0.0))
+				userMock()
+				mock.ExpectBegin()
+				mock.ExpectQuery("UPDATE bundles SET name = \\$1").
+					WithArgs("SuperGo Bundle", sqlmock.AnyArg(), 2, version).
+					WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(time.Now()))
+				mock.ExpectExec("DELETE FROM product_bundles WHERE bundle_id = \\$1;").
+					WithArgs(2).
+					WillReturnResult(sqlmock.NewResult(0, 3))
+				mock.ExpectExec("INSERT INTO product_bundles").
+					WithArgs(2, 6, 1, "iron", 130, 3, 20, "{\"steel\",\"nylon\"}", false, true).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO product_bundles").
+					WithArgs(2, 4, 2, "rub", nil, nil, nil, "{}", true, false).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				temperaturesMock()
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "Status bad request - iron temperature of a rubbed layer",
+			body: `{"layers":[{"product_id":6,"recipe":{"method":"rub","iron_temperature":130}}],` +
+				`"version":"2025-01-02T00:00:00Z"}`,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Invalid PATCH request",
+			setupMocks: func() {
+				AuthenticationMock(mock)
+			},
+		},
+		{
+			name:         "Status OK - renamed",
+			body:         `{"name":"SuperGo Cold","version":"2025-01-02T00:00:00Z"}`,
+			expectedCode: http.StatusOK,
+			expectedBody: `"message":"Bundle updated successfully"`,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				bundleMock(1, false)
+				userMock()
+				mock.ExpectBegin()
+				mock.ExpectQuery("UPDATE bundles SET name = \\$1").
+					WithArgs("SuperGo Cold", sqlmock.AnyArg(), 2, version).
+					WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(time.Now()))
+				productMock()
+				mock.ExpectExec("UPDATE products SET name = \\$1, version = \\$2 WHERE id = \\$3;").
+					WithArgs("SuperGo Cold", sqlmock.AnyArg(), 7).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				revisionMock()
+				mock.ExpectExec("INSERT INTO product_history").
+					WithArgs(7, 2, "name", `"SuperGo Bundle"`, `"SuperGo Cold"`, 1, sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:         "Status conflict - outdated version",
+			body:         `{"products":[6,4],"version":"2025-01-01T00:00:00Z"}`,
+			expectedCode: http.StatusConflict,
+			expectedBody: "Detected a conflict for the current bundle, please refresh.",
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				bundleMock(1, false)
+				layersMock().WillReturnRows(layerRows().
+					AddRow(4, "liquid", -12.0, -2.0).AddRow(6, "solid", -8.0, 0.0))
+			},
+		},
+		{
+			name:         "Status conflict - changed since it was read",
+			body:         `{"name":"SuperGo Cold","version":"2025-01-02T00:00:00Z"}`,
+			expectedCode: http.StatusConflict,
+			expectedBody: "Detected a conflict for the current bundle, please refresh.",
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				bundleMock(1, false)
+				userMock()
+				mock.ExpectBegin()
+				mock.ExpectQuery("UPDATE bundles SET name = \\$1").
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+			},
+		},
+		{
+			name:         "Status bad request - bundle as a layer",
+			body:         `{"products":[6,4],"version":"2025-01-02T00:00:00Z"}`,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Validation error: layer 1, product 6, is a bundle, which cannot be a layer of a bundle",
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				bundleMock(1, false)
+				layersMock().WillReturnRows(layerRows().
+					AddRow(4, "liquid", -12.0, -2.0).AddRow(6, "bundle", -8.0, 0.0))
+			},
+		},
+		{
+			name:         "Status bad request - top coat without a base",
+			body:         `{"products":[4,6],"version":"2025-01-02T00:00:00Z"}`,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Validation error: layer 1, product 4, is a liquid, which must be applied on a solid layer " +
+				"below it",
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				bundleMock(1, false)
+				mock.ExpectQuery("SELECT id, type, low_temperature, high_temperature FROM products").
+					WithArgs("{4,6}", 1).
+					WillReturnRows(layerRows().AddRow(4, "liquid", -12.0, -2.0).AddRow(6, "solid", -8.0, 0.0))
+			},
+		},
+		{
+			name:         "Status unauthorized - public bundle of another team",
+			body:         `{"name":"SuperGo Cold","version":"2025-01-02T00:00:00Z"}`,
+			expectedCode: http.StatusUnauthorized,
+			expectedBody: "Validation error: user cannot update this bundle",
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				bundleMock(2, true)
+			},
+		},
+		{
+			name:         "Status not found - private bundle of another team",
+			body:         `{"name":"SuperGo Cold","version":"2025-01-02T00:00:00Z"}`,
+			expectedCode: http.StatusNotFound,
+			expectedBody: "Validation error: could not find the bundle 2",
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				bundleMock(2, false)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+
+			req := httptest.NewRequest(http.MethodPatch, "/bundles/2", strings.NewReader(tt.body))
+			req.Header.Set("Authorization", "Bearer mockToken")
+			rr := httptest.NewRecorder()
+
+			BundlesHandler(mockDB).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedCode, rr.Code)
+			assert.Contains(t, rr.Body.String(), tt.expectedBody)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestBundleRequestDELETE(t *testing.T) {
+	mockDB, mock := utils.InitMockDB(t)
+
+	bundleMock := func() {
+		mock.ExpectQuery("SELECT b.id, b.product_id, b.name, b.testing_team, b.version, p.is_public FROM bundles b").
+			WithArgs(2).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "name", "testing_team", "version",
+				"is_public"}).AddRow(2, 7, "SuperGo Bundle", 1, time.Now(), false))
+	}
+	resultsMock := func(count int) {
+		mock.ExpectQuery("SELECT \\(SELECT COUNT\\(\\*\\) FROM test_ranks WHERE product_id = \\$1\\)").
+			WithArgs(7).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(count))
+	}
+
+	tests := []struct {
+		name         string
+		setupMocks   func()
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:         "Status no content",
+			expectedCode: http.StatusNoContent,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				bundleMock()
+				resultsMock(0)
+				mock.ExpectExec("DELETE FROM products WHERE id = \\$1 AND type = 'bundle';").
+					WithArgs(7).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name:         "Status conflict - bundle with test results",
+			expectedCode: http.StatusConflict,
+			expectedBody: "The bundle has test results and cannot be deleted.",
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				bundleMock()
+				resultsMock(3)
+			},
+		},
+		{
+			name:         "Status not found",
+			expectedCode: http.StatusNotFound,
+			expectedBody: "Could not find the bundle.",
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				mock.ExpectQuery("SELECT b.id, b.product_id").
+					WithArgs(2).
+					WillReturnError(sql.ErrNoRows)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+
+			req := httptest.NewRequest(http.MethodDelete, "/bundles/2", nil)
+			req.Header.Set("Authorization", "Bearer mockToken")
+			rr := httptest.NewRecorder()
+
+			BundlesHandler(mockDB).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedCode, rr.Code)
+			assert.Contains(t, rr.Body.String(), tt.expectedBody)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
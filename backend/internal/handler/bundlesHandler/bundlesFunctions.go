@@ -6,6 +6,7 @@ import (
 	"backend/internal/resources"
 	"database/sql"
 	"fmt"
+	"github.com/lib/pq"
 	"log"
 	"net/http"
 	"time"
@@ -16,14 +17,16 @@ func FetchBundles(w http.ResponseWriter, bundles *[]domain.ProductBundle, rows *
 	// Iterate over the rows and scan the data into the bundle struct.
 	for rows.Next() {
 		var bundle domain.ProductBundle
+		var recipe scannedRecipe
 
-		if err := rows.Scan(
+		if err := rows.Scan(append([]interface{}{
 			&bundle.BundleID,
 			&bundle.ProductID,
 			&bundle.LayerNumber,
-		); err != nil {
+		}, recipe.dest()...)...); err != nil {
 			return fmt.Errorf("could not scan bundle: %v", err)
 		}
+		bundle.Recipe = recipe.result()
 
 		// Append the bundle to the bundles slice.
 		*bundles = append(*bundles, bundle)
@@ -140,9 +143,15 @@ func getTestIDs(w http.ResponseWriter, db *sql.DB, team int) []int {
 
 */
 
-func getAllBundles(w http.ResponseWriter, db *sql.DB, bundles []domain.ProductBundle) []domain.ProductBundle {
-	// Fetch all the bundles from the database for the different authenticated user's role and team memberships.
-	rows, err := db.Query("SELECT * FROM product_bundles;")
+func getAllBundles(w http.ResponseWriter, db *sql.DB, bundles []domain.ProductBundle, team int,
+) []domain.ProductBundle {
+	// Fetch the layers of the bundles of the team and of the public bundles, with their recipes.
+	rows, err := db.Query(`SELECT l.bundle_id, l.product_id, l.layer_no, `+recipeColumns+`
+							FROM product_bundles l
+							JOIN bundles b ON b.id = l.bundle_id
+							JOIN products p ON p.id = b.product_id
+							WHERE b.testing_team = $1 OR p.is_public = true
+							ORDER BY l.bundle_id, l.layer_no;`, team)
 	if err != nil {
 		http.Error(w, resources.CouldNotRetrieveBundles, http.StatusInternalServerError)
 		log.Println(resources.CouldNotRetrieveBundles, err.Error())
@@ -157,58 +166,56 @@ func getAllBundles(w http.ResponseWriter, db *sql.DB, bundles []domain.ProductBu
 	return bundles
 }
 
-func getBundlesByBundleID(w http.ResponseWriter, db *sql.DB, bundles []domain.ProductBundle,
-	id int, idStr string) []domain.ProductBundle {
-	// Fetch the product with the given id from the database based on the user's role and team membership.
-	rows, err := db.Query("SELECT * FROM product_bundles WHERE bundle_id = $1;", id)
-	if err != nil {
-		http.Error(w, "Could not retrieve bundle with id: "+idStr, http.StatusInternalServerError)
-		log.Println(resources.CouldNotRetrieveProduct, err.Error())
-		return nil
+// insertBundles inserts a layer of a bundle, with its recipe if it has one.
+func insertBundles(tx *sql.Tx, bundleID int, layer BundleLayerPOST, layerNo int) error {
+	var recipe LayerRecipePOST
+	var method *string
+	if layer.Recipe != nil {
+		recipe = *layer.Recipe
+		method = &recipe.Method
 	}
-	if err = FetchBundles(w, &bundles, rows); err != nil {
-		http.Error(w, resources.CouldNotRetrieveBundles, http.StatusInternalServerError)
-		log.Println(resources.CouldNotRetrieveBundles, err.Error())
-		return nil
+	if recipe.Brushing == nil {
+		recipe.Brushing = []string{}
 	}
 
-	return bundles
-}
-
-func insertBundles(tx *sql.Tx, bundleID int, productID int, layerNo int) error {
 	_, err := tx.Exec(`INSERT INTO product_bundles (
-                             bundle_id, product_id, layer_no) 
-							 VALUES ($1, $2, $3)`,
-		bundleID, productID, layerNo)
+                             bundle_id, product_id, layer_no, method, iron_temperature, passes, cooling_minutes,
+                             brushing, cork, fleece)
+							 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+		bundleID, layer.ProductID, layerNo, method, recipe.IronTemperature, recipe.Passes, recipe.CoolingMinutes,
+		pq.Array(recipe.Brushing), recipe.Cork, recipe.Fleece)
 	return err
 }
 
-func createBundles(tx *sql.Tx, bundle BundlePOSTRequest, team int) error {
-	var bundleID int
+// createBundles creates the product row of a bundle, with the temperature window of its layers, the bundle linked to
+// it, and its layers in the order of the request. It returns the ID of the bundle and of its product row.
+func createBundles(tx *sql.Tx, bundle BundlePOSTRequest, window temperatureWindow, team int) (int, int, error) {
+	productID, err := insertNewProduct(tx, bundle, window, team)
+	if err != nil {
+		return 0, 0, fmt.Errorf("could not insert bundles for team %d: %v", team, err)
+	}
 
-	// Generate a new bundle_id using a sequence
-	err := tx.QueryRow("SELECT nextval('bundle_id_seq')").Scan(&bundleID)
+	var bundleID int
+	err = tx.QueryRow(`INSERT INTO bundles (product_id, name, testing_team, version)
+							VALUES ($1, $2, $3, $4) RETURNING id;`,
+		productID, bundle.ProductName, team, time.Now()).Scan(&bundleID)
 	if err != nil {
-		return fmt.Errorf("could not get next bundle id: %v", err)
+		return 0, 0, fmt.Errorf("could not insert the bundle of product %d: %v", productID, err)
 	}
 
-	for layerNo, productID := range bundle.Products {
-		err := insertBundles(tx, bundleID, productID, layerNo+1)
+	for layerNo, layer := range requestLayers(bundle.Products, bundle.Layers) {
+		err = insertBundles(tx, bundleID, layer, layerNo+1)
 		if err != nil {
-			return fmt.Errorf("could not insert bundles for product %d: %v", productID, err)
+			return 0, 0, fmt.Errorf("could not insert bundles for product %d: %v", layer.ProductID, err)
 		}
 	}
 
-	err = insertNewProduct(tx, bundle, team)
-	if err != nil {
-		return fmt.Errorf("could not insert bundles for team %d: %v", team, err)
-	}
-
-	return nil
+	return bundleID, productID, nil
 }
 
-func insertNewProduct(tx *sql.Tx, bundle BundlePOSTRequest, team int) error {
-	_, err := tx.Exec(`INSERT INTO products (
+func insertNewProduct(tx *sql.Tx, bundle BundlePOSTRequest, window temperatureWindow, team int) (int, error) {
+	var productID int
+	err := tx.QueryRow(`INSERT INTO products (
                 	name,
 					ean_code,
                     comment,
@@ -219,19 +226,19 @@ func insertNewProduct(tx *sql.Tx, bundle BundlePOSTRequest, team int) error {
 					testing_team, 
 					version,
 					status) 
-        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10);`,
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id;`,
 		bundle.ProductName,
 		"",
 		bundle.Comment,
 		bundle.IsPublic,
 		"bundle",
-		0,
-		0,
+		window.High,
+		window.Low,
 		team,
 		time.Now(),
-		bundle.Status)
+		bundle.Status).Scan(&productID)
 
-	return err
+	return productID, err
 }
 
 func validatePermissions(bundle BundlePOSTRequest, team int) error {
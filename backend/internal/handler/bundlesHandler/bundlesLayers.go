@@ -0,0 +1,61 @@
+package bundlesHandler
+
+import (
+	"backend/internal/middleware"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"regexp"
+	"strconv"
+)
+
+var bundlePath = regexp.MustCompile(`^/bundles/(\d+)/?$`)
+
+// BundleRequestGET is the request handler for a bundle with its layers.
+//
+//	@Summary		Get a bundle with its layers
+//	@Description	Retrieves a bundle of the team, or one with a public product row, with the full details of the
+//	@Description	product of each layer and the recipe for applying it, in the order the layers are applied. The
+//	@Description	bundle is found by its ID, or by the ID of its product row, as it appears in the results of a test,
+//	@Description	with the product_id query parameter of /bundles. Of a layer that is a private product of another
+//	@Description	team, only the ID of the product is returned.
+//	@Tags			Bundles
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int				true	"Bundle ID"
+//	@Success		200	{object}	domain.Bundle	"The bundle with its layers"
+//	@Failure		404	{string}	string			"Could not find the bundle."
+//	@Failure		500	{string}	string			"Could not retrieve the bundle."
+//	@Router			/bundles/{id} [get]
+func BundleRequestGET(w http.ResponseWriter, r *http.Request, db *sql.DB) {
+	byProduct := !bundlePath.MatchString(r.URL.Path)
+	var id int
+	if byProduct {
+		id, _ = strconv.Atoi(r.URL.Query().Get("product_id"))
+	} else {
+		id, _ = strconv.Atoi(bundlePath.FindStringSubmatch(r.URL.Path)[1])
+	}
+
+	// Get the user's team membership.
+	team := middleware.GetUserTeamRole(w, r, db)
+
+	bundle, err := getBundle(db, id, byProduct, team)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Could not find the bundle.", http.StatusNotFound)
+		log.Printf("Could not find the bundle %d for team %d", id, team)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Could not retrieve the bundle.", http.StatusInternalServerError)
+		log.Println("Could not retrieve the bundle: " + err.Error())
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(bundle)
+	if err != nil {
+		http.Error(w, "Could not JSON encode the response.", http.StatusInternalServerError)
+		log.Println("Could not JSON encode the response: " + err.Error())
+	}
+}
@@ -0,0 +1,73 @@
+package bundlesHandler
+
+import (
+	"backend/internal/domain"
+	"database/sql"
+)
+
+// bundleColumns are the columns of a bundle, with b as the bundles table and p as its product row.
+const bundleColumns = "b.id, b.product_id, b.name, b.testing_team, b.version"
+
+// getBundle retrieves the bundle with the given ID, or the bundle of the product with the given ID when byProduct
+// is set, with its layers. The bundle must belong to the team or have a public product row.
+func getBundle(db *sql.DB, id int, byProduct bool, team int) (domain.Bundle, error) {
+	column := "b.id"
+	if byProduct {
+		column = "b.product_id"
+	}
+
+	var bundle domain.Bundle
+	err := db.QueryRow(`SELECT `+bundleColumns+` FROM bundles b JOIN products p ON p.id = b.product_id
+							WHERE `+column+` = $1 AND (b.testing_team = $2 OR p.is_public = true);`, id, team).
+		Scan(&bundle.ID, &bundle.ProductID, &bundle.Name, &bundle.TestingTeam, &bundle.Version)
+	if err != nil {
+		return bundle, err
+	}
+
+	bundle.Layers, err = getBundleLayers(db, bundle.ID, team)
+	return bundle, err
+}
+
+// getBundleLayers retrieves the layers of a bundle, with the full details of their products, in the order they are
+// applied. Only the ID is kept of the private products of other teams, as a public bundle can have them as layers.
+func getBundleLayers(db *sql.DB, bundleID int, team int) ([]domain.BundleLayer, error) {
+	rows, err := db.Query(`SELECT l.layer_no, p.id, p.name, COALESCE(p.brand, ''), COALESCE(p.ean_code, ''),
+								COALESCE(p.image_url, ''), COALESCE(p.comment, ''), p.is_public, p.type,
+								p.high_temperature, p.low_temperature, p.testing_team, p.version, p.status, `+recipeColumns+`
+							FROM product_bundles l JOIN products p ON p.id = l.product_id
+							WHERE l.bundle_id = $1 ORDER BY l.layer_no;`, bundleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	layers := []domain.BundleLayer{}
+	for rows.Next() {
+		var layer domain.BundleLayer
+		var recipe scannedRecipe
+		if err = rows.Scan(append([]interface{}{
+			&layer.LayerNumber,
+			&layer.Product.ID,
+			&layer.Product.Name,
+			&layer.Product.Brand,
+			&layer.Product.EANCode,
+			&layer.Product.ImageURL,
+			&layer.Product.Comment,
+			&layer.Product.IsPublic,
+			&layer.Product.Type,
+			&layer.Product.HighTemperature,
+			&layer.Product.LowTemperature,
+			&layer.Product.TestingTeam,
+			&layer.Product.Version,
+			&layer.Product.Status,
+		}, recipe.dest()...)...); err != nil {
+			return nil, err
+		}
+		if !layer.Product.IsPublic && layer.Product.TestingTeam != team {
+			layer.Product = domain.Product{ID: layer.Product.ID}
+		}
+		layer.Recipe = recipe.result()
+		layers = append(layers, layer)
+	}
+	return layers, rows.Err()
+}
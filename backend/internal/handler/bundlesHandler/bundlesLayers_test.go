@@ -0,0 +1,154 @@
+package bundlesHandler
+
+import (
+	"backend/internal/utils"
+	"database/sql"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func AuthenticationMock(mock sqlmock.Sqlmock) {
+	// Mock the user id query
+	mock.ExpectQuery("SELECT user_id FROM sessions WHERE \\(session_token = \\$1 AND expires_at > NOW\\(\\)\\)").
+		WithArgs("mockToken").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(1))
+
+	// Mock the user team id query
+	mock.ExpectQuery("SELECT team_id FROM users WHERE id = \\$1;").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"team_id"}).AddRow(1))
+
+	// Mock the user team role query
+	mock.ExpectQuery("SELECT team_role FROM team WHERE id = \\$1;").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"team_role"}).AddRow(1))
+}
+
+func TestBundleRequestGET(t *testing.T) {
+	mockDB, mock := utils.InitMockDB(t)
+	version := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	bundleMock := func(column string, id int) *sqlmock.ExpectedQuery {
+		return mock.ExpectQuery("SELECT b.id, b.product_id, b.name, b.testing_team, b.version FROM bundles b "+
+			"JOIN products p ON p.id = b.product_id WHERE "+column+" = \\$1").
+			WithArgs(id, 1)
+	}
+	bundleRows := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "product_id", "name", "testing_team", "version"}).
+			AddRow(2, 7, "SuperGo Bundle", 1, version)
+	}
+	layersMock := func() {
+		mock.ExpectQuery("SELECT l.layer_no, p.id, p.name").
+			WithArgs(2).
+			WillReturnRows(sqlmock.NewRows([]string{"layer_no", "id", "name", "brand", "ean_code", "image_url",
+				"comment", "is_public", "type", "high_temperature", "low_temperature", "testing_team", "version",
+				"status", "method", "iron_temperature", "passes", "cooling_minutes", "brushing", "cork", "fleece"}).
+				AddRow(1, 4, "Base glider", "Swix", "", "", "", true, "liquid", 0.0, -4.0, 1, version, "active",
+					"iron", 130, 3, 20, "{steel,nylon}", false, true).
+				AddRow(2, 5, "Top coat", "Rode", "", "", "", false, "powder", -2.0, -8.0, 1, version, "active",
+					nil, nil, nil, nil, "{}", false, false))
+	}
+
+	tests := []struct {
+		name         string
+		url          string
+		setupMocks   func()
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:         "Status OK - by bundle ID",
+			url:          "/bundles/2",
+			expectedCode: http.StatusOK,
+			expectedBody: `{"id":2,"product_id":7,"name":"SuperGo Bundle","testing_team":1,` +
+				`"version":"2025-01-02T00:00:00Z","layers":[{"layer_no":1,"product":{"id":4,"name":"Base glider",`,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				bundleMock("b.id", 2).WillReturnRows(bundleRows())
+				layersMock()
+			},
+		},
+		{
+			name:         "Status OK - by product ID",
+			url:          "/bundles?product_id=7",
+			expectedCode: http.StatusOK,
+			expectedBody: `"recipe":{"method":"iron","iron_temperature":130,"passes":3,"cooling_minutes":20,` +
+				`"brushing":["steel","nylon"],"cork":false,"fleece":true}},{"layer_no":2,"product":{"id":5,`,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				bundleMock("b.product_id", 7).WillReturnRows(bundleRows())
+				layersMock()
+			},
+		},
+		{
+			name:         "Status OK - layers of the bundles of the team and the public bundles",
+			url:          "/bundles",
+			expectedCode: http.StatusOK,
+			expectedBody: `[{"bundle_id":2,"product_id":4,"layer_no":1,"recipe":{"method":"iron",`,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				mock.ExpectQuery("SELECT l.bundle_id, l.product_id, l.layer_no, .* FROM product_bundles l " +
+					"JOIN bundles b ON b.id = l.bundle_id JOIN products p ON p.id = b.product_id " +
+					"WHERE b.testing_team = \\$1 OR p.is_public = true").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"bundle_id", "product_id", "layer_no", "method",
+						"iron_temperature", "passes", "cooling_minutes", "brushing", "cork", "fleece"}).
+						AddRow(2, 4, 1, "iron", 130, 3, 20, "{steel,nylon}", false, true))
+			},
+		},
+		{
+			name:         "Status OK - private layer of another team",
+			url:          "/bundles/3",
+			expectedCode: http.StatusOK,
+			expectedBody: `{"layer_no":1,"product":{"id":6,"name":"","brand":"","ean_code":"","image_url":"",` +
+				`"comment":"","is_public":false,"type":"","high_temperature":null,"low_temperature":null,` +
+				`"testing_team":0,`,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				bundleMock("b.id", 3).WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "name",
+					"testing_team", "version"}).
+					AddRow(3, 8, "Public Bundle", 2, version))
+				mock.ExpectQuery("SELECT l.layer_no, p.id, p.name").
+					WithArgs(3).
+					WillReturnRows(sqlmock.NewRows([]string{"layer_no", "id", "name", "brand", "ean_code", "image_url",
+						"comment", "is_public", "type", "high_temperature", "low_temperature", "testing_team", "version",
+						"status", "method", "iron_temperature", "passes", "cooling_minutes", "brushing", "cork", "fleece"}).
+						AddRow(1, 6, "Secret glider", "Swix", "", "", "Prototype", false, "liquid", 0.0, -4.0, 2, version,
+							"development", nil, nil, nil, nil, "{}", false, false))
+			},
+		},
+		{
+			name:         "Status not found - bundle of another team",
+			url:          "/bundles/3",
+			expectedCode: http.StatusNotFound,
+			expectedBody: "Could not find the bundle.",
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				bundleMock("b.id", 3).WillReturnError(sql.ErrNoRows)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			req.Header.Set("Authorization", "Bearer mockToken")
+			rr := httptest.NewRecorder()
+
+			BundlesHandler(mockDB).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedCode, rr.Code)
+			assert.Contains(t, rr.Body.String(), tt.expectedBody)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package bundlesHandler
+
+import (
+	"backend/internal/domain"
+	"github.com/lib/pq"
+)
+
+// recipeColumns are the columns of the recipe of a layer, with l as the product_bundles table.
+const recipeColumns = "l.method, l.iron_temperature, l.passes, l.cooling_minutes, l.brushing, l.cork, l.fleece"
+
+// scannedRecipe holds the recipe columns of a layer as they are scanned.
+type scannedRecipe struct {
+	method *string
+	recipe domain.LayerRecipe
+}
+
+// dest returns the scan destinations of the recipe columns, in the order of recipeColumns.
+func (s *scannedRecipe) dest() []interface{} {
+	return []interface{}{&s.method, &s.recipe.IronTemperature, &s.recipe.Passes, &s.recipe.CoolingMinutes,
+		pq.Array(&s.recipe.Brushing), &s.recipe.Cork, &s.recipe.Fleece}
+}
+
+// result returns the scanned recipe, or nil when the layer has no recipe recorded.
+func (s *scannedRecipe) result() *domain.LayerRecipe {
+	if s.method == nil {
+		return nil
+	}
+	recipe := s.recipe
+	recipe.Method = *s.method
+	if recipe.Brushing == nil {
+		recipe.Brushing = []string{}
+	}
+	return &recipe
+}
+
+// requestLayers returns the layers of a request, given either as products without recipes or as layers.
+func requestLayers(products []int, layers []BundleLayerPOST) []BundleLayerPOST {
+	if len(layers) > 0 {
+		return layers
+	}
+	for _, productID := range products {
+		layers = append(layers, BundleLayerPOST{ProductID: productID})
+	}
+	return layers
+}
+
+// layerProductIDs returns the products of the layers, in order.
+func layerProductIDs(layers []BundleLayerPOST) []int {
+	ids := make([]int, len(layers))
+	for i, layer := range layers {
+		ids[i] = layer.ProductID
+	}
+	return ids
+}
@@ -0,0 +1,50 @@
+package bundlesHandler
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+)
+
+// LayeringRules are the rules for which products can be layered in a bundle, and in which order.
+type LayeringRules struct {
+	// Excluded are the product types that can not be a layer of a bundle. Bundles are always excluded.
+	Excluded []string `json:"excluded"`
+
+	// Bases maps a product type to the types of which a layer must be applied somewhere below it.
+	Bases map[string][]string `json:"bases"`
+}
+
+// DefaultLayeringRules keep bundles out of bundles, and put powder and liquid top coats on a solid base.
+var DefaultLayeringRules = LayeringRules{
+	Excluded: []string{"bundle"},
+	Bases: map[string][]string{
+		"powder": {"solid"},
+		"liquid": {"solid"},
+	},
+}
+
+// Rules are the layering rules the bundles are validated with when they are created or their layers change. They
+// are set by the server.
+var Rules = DefaultLayeringRules
+
+// LayeringRulesFromEnv returns the layering rules in the BUNDLE_LAYERING_RULES environment variable, as JSON, or the
+// default rules when it is not set. The rules replace the default rules, and must still exclude bundles, as a bundle
+// can not contain another bundle.
+func LayeringRulesFromEnv() (LayeringRules, error) {
+	value, exists := os.LookupEnv("BUNDLE_LAYERING_RULES")
+	if !exists || value == "" {
+		return DefaultLayeringRules, nil
+	}
+
+	var rules LayeringRules
+	if err := json.Unmarshal([]byte(value), &rules); err != nil {
+		return LayeringRules{}, fmt.Errorf("invalid layering rules: %w", err)
+	}
+	if !slices.Contains(rules.Excluded, "bundle") {
+		return LayeringRules{}, fmt.Errorf("the layering rules must exclude bundles, as a bundle can not contain " +
+			"another bundle")
+	}
+	return rules, nil
+}
@@ -0,0 +1,124 @@
+package bundlesHandler
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/lib/pq"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// layerProduct is what the layering rules and the temperature window of a bundle need to know about a layer.
+type layerProduct struct {
+	ID   int
+	Type string
+	Low  *float64
+	High *float64
+}
+
+// temperatureWindow is the temperature range of a bundle: the range all of its layers are rated for. A missing bound
+// leaves that side open.
+type temperatureWindow struct {
+	Low  *float64
+	High *float64
+}
+
+// validateLayers checks that the products of the layers exist, are visible to the team and follow the layering
+// rules, and that they have temperatures in common. It returns the temperature window of the layers.
+func validateLayers(db *sql.DB, productIDs []int, team int) (temperatureWindow, error, int) {
+	products, err := getLayerProducts(db, productIDs, team)
+	if err != nil {
+		log.Println("Could not retrieve the products of the layers: " + err.Error())
+		return temperatureWindow{}, fmt.Errorf("could not retrieve the products of the layers, %d",
+			http.StatusInternalServerError), http.StatusInternalServerError
+	}
+
+	layers := make([]layerProduct, len(productIDs))
+	for i, id := range productIDs {
+		product, ok := products[id]
+		if !ok {
+			log.Printf("Could not find the product %d", id)
+			return temperatureWindow{}, fmt.Errorf("could not find the product %d, %d", id, http.StatusBadRequest),
+				http.StatusBadRequest
+		}
+		layers[i] = product
+	}
+
+	if err = checkLayeringRules(layers, Rules); err != nil {
+		log.Println("Invalid layers: " + err.Error())
+		return temperatureWindow{}, fmt.Errorf("%v, %d", err, http.StatusBadRequest), http.StatusBadRequest
+	}
+
+	window, err := bundleTemperatureWindow(layers)
+	if err != nil {
+		log.Println("Invalid layers: " + err.Error())
+		return temperatureWindow{}, fmt.Errorf("%v, %d", err, http.StatusBadRequest), http.StatusBadRequest
+	}
+	return window, nil, 0
+}
+
+// getLayerProducts retrieves the products with the given IDs that are visible to the team, by their IDs.
+func getLayerProducts(db *sql.DB, productIDs []int, team int) (map[int]layerProduct, error) {
+	rows, err := db.Query(`SELECT id, type, low_temperature, high_temperature FROM products
+							WHERE id = ANY($1) AND (testing_team = $2 OR is_public = true);`, pq.Array(productIDs), team)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	products := map[int]layerProduct{}
+	for rows.Next() {
+		var product layerProduct
+		if err = rows.Scan(&product.ID, &product.Type, &product.Low, &product.High); err != nil {
+			return nil, err
+		}
+		products[product.ID] = product
+	}
+	return products, rows.Err()
+}
+
+// checkLayeringRules returns an error describing the first layer, from the bottom, that breaks the rules.
+func checkLayeringRules(layers []layerProduct, rules LayeringRules) error {
+	for i, layer := range layers {
+		if slices.Contains(rules.Excluded, layer.Type) {
+			return fmt.Errorf("layer %d, product %d, is a %s, which cannot be a layer of a bundle", i+1, layer.ID,
+				layer.Type)
+		}
+
+		bases, ok := rules.Bases[layer.Type]
+		if !ok {
+			continue
+		}
+		onBase := slices.ContainsFunc(layers[:i], func(below layerProduct) bool {
+			return slices.Contains(bases, below.Type)
+		})
+		if !onBase {
+			return fmt.Errorf("layer %d, product %d, is a %s, which must be applied on a %s layer below it", i+1,
+				layer.ID, layer.Type, strings.Join(bases, " or "))
+		}
+	}
+	return nil
+}
+
+// bundleTemperatureWindow returns the range of temperatures all layers are rated for, or an error naming two layers
+// that have no temperature in common.
+func bundleTemperatureWindow(layers []layerProduct) (temperatureWindow, error) {
+	var window temperatureWindow
+	var lowID, highID int
+	for _, layer := range layers {
+		if layer.Low != nil && (window.Low == nil || *layer.Low > *window.Low) {
+			window.Low, lowID = layer.Low, layer.ID
+		}
+		if layer.High != nil && (window.High == nil || *layer.High < *window.High) {
+			window.High, highID = layer.High, layer.ID
+		}
+	}
+
+	if window.Low != nil && window.High != nil && *window.Low > *window.High {
+		return temperatureWindow{}, fmt.Errorf("the layers have no temperature in common, as product %d is rated "+
+			"from %g °C and product %d up to %g °C", lowID, *window.Low, highID, *window.High)
+	}
+	return window, nil
+}
@@ -0,0 +1,111 @@
+package bundlesHandler
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_checkLayeringRules(t *testing.T) {
+	solid := layerProduct{ID: 1, Type: "solid"}
+	gel := layerProduct{ID: 2, Type: "gel"}
+	powder := layerProduct{ID: 3, Type: "powder"}
+	bundle := layerProduct{ID: 4, Type: "bundle"}
+
+	tests := []struct {
+		name    string
+		layers  []layerProduct
+		wantErr string
+	}{
+		{name: "Top coat on a solid base", layers: []layerProduct{solid, gel, powder}},
+		{name: "Without top coats", layers: []layerProduct{gel}},
+		{name: "Top coat without a base", layers: []layerProduct{powder, solid},
+			wantErr: "layer 1, product 3, is a powder, which must be applied on a solid layer below it"},
+		{name: "Bundle in a bundle", layers: []layerProduct{solid, bundle},
+			wantErr: "layer 2, product 4, is a bundle, which cannot be a layer of a bundle"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkLayeringRules(tt.layers, DefaultLayeringRules)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_bundleTemperatureWindow(t *testing.T) {
+	temperature := func(value float64) *float64 {
+		return &value
+	}
+
+	tests := []struct {
+		name     string
+		layers   []layerProduct
+		wantLow  *float64
+		wantHigh *float64
+		wantErr  string
+	}{
+		{
+			name: "Overlapping ranges",
+			layers: []layerProduct{{ID: 1, Low: temperature(-12), High: temperature(-2)},
+				{ID: 2, Low: temperature(-8), High: temperature(0)}},
+			wantLow:  temperature(-8),
+			wantHigh: temperature(-2),
+		},
+		{
+			name:     "Open range",
+			layers:   []layerProduct{{ID: 1, High: temperature(-4)}, {ID: 2}},
+			wantHigh: temperature(-4),
+		},
+		{
+			name: "No temperature in common",
+			layers: []layerProduct{{ID: 1, Low: temperature(-20), High: temperature(-10)},
+				{ID: 2, Low: temperature(-4), High: temperature(2)}},
+			wantErr: "the layers have no temperature in common, as product 2 is rated from -4 °C and product 1 up " +
+				"to -10 °C",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			window, err := bundleTemperatureWindow(tt.layers)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantLow, window.Low)
+			assert.Equal(t, tt.wantHigh, window.High)
+		})
+	}
+}
+
+func TestLayeringRulesFromEnv(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		wantRules LayeringRules
+		wantErr   bool
+	}{
+		{name: "Not set", wantRules: DefaultLayeringRules},
+		{
+			name:  "Override",
+			value: `{"excluded":["bundle","spray"],"bases":{"powder":["solid","gel"]}}`,
+			wantRules: LayeringRules{Excluded: []string{"bundle", "spray"},
+				Bases: map[string][]string{"powder": {"solid", "gel"}}},
+		},
+		{name: "Bundles not excluded", value: `{"excluded":[]}`, wantErr: true},
+		{name: "Invalid JSON", value: `{"excluded":`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("BUNDLE_LAYERING_RULES", tt.value)
+			rules, err := LayeringRulesFromEnv()
+			assert.Equal(t, tt.wantErr, err != nil)
+			if !tt.wantErr {
+				assert.Equal(t, tt.wantRules, rules)
+			}
+		})
+	}
+}
@@ -0,0 +1,213 @@
+package conditionsHandler
+
+import (
+	"backend/internal/domain"
+	"backend/internal/middleware"
+	"backend/internal/resources"
+	"backend/internal/services/access"
+	"backend/internal/utils"
+	"database/sql"
+	"encoding/json"
+	"log"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+var conditionsPath = regexp.MustCompile(`^/tests/(\d+)/conditions/?$`)
+
+// ConditionsHandler routes HTTP requests for the condition readings of a test to the appropriate handler function.
+//
+// It supports the following methods:
+// - GET: Retrieves the condition readings of a test with a summary, or the reading closest to a point in time.
+// - POST: Appends new condition readings to a test.
+//
+// Each method has its own dedicated request handler function, which is documented separately using Swagger annotations.
+func ConditionsHandler(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		switch r.Method {
+		case http.MethodGet:
+			ConditionsRequestGET(w, r, db)
+		case http.MethodPost:
+			ConditionsRequestPOST(w, r, db)
+		default:
+			http.Error(w, resources.MethodNotAllowed, http.StatusNotImplemented)
+			return
+		}
+	}
+}
+
+// ConditionsRequestGET retrieves the condition readings of a test.
+//
+//	@Summary		Get the condition readings of a test
+//	@Description	Retrieves the timestamped condition readings of a test with the minimum, maximum and mean of the
+//	@Description	temperatures and air humidity. With the at parameter, only the reading closest to that moment is
+//	@Description	returned, which matches a run with the conditions when it was made.
+//	@Tags			Conditions
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			test_id	path		int						true	"Test ID"
+//	@Param			at		query		string					false	"RFC 3339 timestamp to find the closest reading for"
+//	@Success		200		{object}	domain.TestConditions	"Successful response with the readings"
+//	@Failure		400		{string}	string					"Invalid request URL"
+//	@Failure		401		{string}	string					"Unauthorized"
+//	@Failure		404		{string}	string					"Could not retrieve the test."
+//	@Failure		500		{string}	string					"Could not retrieve the condition readings."
+//	@Router			/tests/{test_id}/conditions [get]
+func ConditionsRequestGET(w http.ResponseWriter, r *http.Request, db *sql.DB) {
+	matches := conditionsPath.FindStringSubmatch(r.URL.Path)
+	if len(matches) != 2 {
+		http.Error(w, "Invalid request URL, use '/tests/{test_id}/conditions'.", http.StatusBadRequest)
+		log.Println("Invalid request URL: " + r.URL.Path)
+		return
+	}
+	testID, _ := strconv.Atoi(matches[1])
+
+	// Get the user's team membership.
+	team := middleware.GetUserTeamRole(w, r, db)
+
+	var at time.Time
+	var err error
+	if param := r.URL.Query().Get("at"); param != "" {
+		at, err = time.Parse(time.RFC3339, param)
+		if err != nil {
+			http.Error(w, "Invalid at parameter, use an RFC 3339 timestamp.", http.StatusBadRequest)
+			log.Println("Invalid at parameter: " + param)
+			return
+		}
+	}
+
+	// Check that the test is visible for the user's team.
+	testingTeam, isPublic, _, err := access.GetTestAccess(db, testID)
+	if err != nil {
+		http.Error(w, "Could not retrieve the test.", http.StatusNotFound)
+		log.Println("Could not retrieve the test: " + err.Error())
+		return
+	}
+	if !isPublic && testingTeam != team {
+		http.Error(w, resources.AuthenticationError, http.StatusUnauthorized)
+		log.Println("User cannot view the conditions of this test")
+		return
+	}
+
+	readings, err := getReadingsForTest(db, testID)
+	if err != nil {
+		http.Error(w, "Could not retrieve the condition readings.", http.StatusInternalServerError)
+		log.Println("Could not retrieve the condition readings: " + err.Error())
+		return
+	}
+
+	var response interface{} = domain.TestConditions{
+		TestID:   testID,
+		Summary:  summarizeReadings(readings),
+		Readings: readings,
+	}
+	if !at.IsZero() {
+		reading, found := nearestReading(readings, at)
+		if !found {
+			http.Error(w, "This test has no condition readings.", http.StatusNotFound)
+			log.Println("This test has no condition readings: " + matches[1])
+			return
+		}
+		response = reading
+	}
+
+	err = json.NewEncoder(w).Encode(response)
+	if err != nil {
+		http.Error(w, "Could not encode the condition readings.", http.StatusInternalServerError)
+		log.Println("Could not encode the condition readings: " + err.Error())
+		return
+	}
+}
+
+// ConditionsRequestPOST appends condition readings to a test.
+//
+//	@Summary		Add condition readings to a test
+//	@Description	Appends timestamped snow and air readings to a test. All the readings are added, or none.
+//	@Tags			Conditions
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			test_id		path		int								true	"Test ID"
+//	@Param			readings	body		ConditionReadingsPOSTRequest	true	"New readings"
+//	@Success		201			{string}	string							"Condition readings added successfully"
+//	@Failure		400			{string}	string							"Invalid POST request body"
+//	@Failure		401			{string}	string							"Unauthorized"
+//	@Failure		404			{string}	string							"Could not retrieve the test."
+//	@Failure		409			{string}	string							"Test is completed and cannot be changed"
+//	@Failure		500			{string}	string							"Could not add the condition readings."
+//	@Router			/tests/{test_id}/conditions [post]
+func ConditionsRequestPOST(w http.ResponseWriter, r *http.Request, db *sql.DB) {
+	matches := conditionsPath.FindStringSubmatch(r.URL.Path)
+	if len(matches) != 2 {
+		http.Error(w, "Invalid request URL, use '/tests/{test_id}/conditions'.", http.StatusBadRequest)
+		log.Println("Invalid request URL: " + r.URL.Path)
+		return
+	}
+	testID, _ := strconv.Atoi(matches[1])
+
+	// Get the user's team membership.
+	team := middleware.GetUserTeamRole(w, r, db)
+
+	request, err := utils.ParseAndValidateRequest[ConditionReadingsPOSTRequest](r)
+	if err != nil {
+		http.Error(w, resources.InvalidPOSTRequest, http.StatusBadRequest)
+		log.Println(resources.InvalidPOSTRequest + ": " + err.Error())
+		return
+	}
+
+	testingTeam, isPublic, state, err := access.GetTestAccess(db, testID)
+	if err != nil {
+		http.Error(w, "Could not retrieve the test.", http.StatusNotFound)
+		log.Println("Could not retrieve the test: " + err.Error())
+		return
+	}
+
+	var code int
+	if err, code = access.ValidateWritePermissions("test", testingTeam, isPublic, state, team); err != nil {
+		http.Error(w, "Validation error: "+err.Error(), code)
+		log.Println("Validation error: " + err.Error())
+		return
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		http.Error(w, resources.TransactionStartFailed, http.StatusInternalServerError)
+		return
+	}
+	defer func() {
+		if err != nil {
+			rollbackErr := tx.Rollback()
+			if rollbackErr != nil {
+				log.Println(resources.RollbackFailed + rollbackErr.Error())
+			}
+		}
+	}()
+
+	err = insertReadings(tx, testID, request.Readings)
+	if err != nil {
+		http.Error(w, "Could not add the condition readings.", http.StatusInternalServerError)
+		log.Println("Could not add the condition readings: " + err.Error())
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		http.Error(w, resources.TransactionCommitFailed, http.StatusInternalServerError)
+		log.Println(resources.TransactionCommitFailed + ": " + err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	err = json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Condition readings added successfully",
+		"count":   len(request.Readings),
+	})
+	if err != nil {
+		log.Println("Could not JSON encode the response: " + err.Error())
+		return
+	}
+}
@@ -0,0 +1,137 @@
+package conditionsHandler
+
+import (
+	"backend/internal/domain"
+	"database/sql"
+	"fmt"
+	"math"
+	"time"
+)
+
+// getReadingsForTest retrieves the condition readings of a test in chronological order.
+func getReadingsForTest(db *sql.DB, testID int) ([]domain.ConditionReading, error) {
+	rows, err := db.Query(`SELECT id, test_id, recorded_at, snow_temperature, snow_type, snow_humidity,
+                                  air_temperature, air_humidity, wind, cloud, version
+								FROM condition_readings WHERE test_id = $1 ORDER BY recorded_at, id;`, testID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	readings := []domain.ConditionReading{}
+	for rows.Next() {
+		var reading domain.ConditionReading
+		var snowType, snowHumidity, wind, cloud sql.NullString
+		if err = rows.Scan(
+			&reading.ID,
+			&reading.TestID,
+			&reading.RecordedAt,
+			&reading.SnowConditions.Temperature,
+			&snowType,
+			&snowHumidity,
+			&reading.AirConditions.Temperature,
+			&reading.AirConditions.Humidity,
+			&wind,
+			&cloud,
+			&reading.Version); err != nil {
+			return nil, err
+		}
+		reading.SnowConditions.SnowType = snowType.String
+		reading.SnowConditions.SnowHumidity = snowHumidity.String
+		reading.AirConditions.Wind = wind.String
+		reading.AirConditions.Cloud = cloud.String
+		readings = append(readings, reading)
+	}
+	return readings, rows.Err()
+}
+
+// nullIfEmpty stores the optional condition levels as NULL, since an empty string is not a valid enum value.
+func nullIfEmpty(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
+
+// insertReadings appends the new readings to a test.
+func insertReadings(tx *sql.Tx, testID int, readings []ConditionReadingPOST) error {
+	now := time.Now()
+	for _, reading := range readings {
+		_, err := tx.Exec(`INSERT INTO condition_readings (
+                                test_id, recorded_at, snow_temperature, snow_type, snow_humidity,
+                                air_temperature, air_humidity, wind, cloud, version)
+                                VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10);`,
+			testID,
+			reading.RecordedAt,
+			reading.SnowConditions.Temperature,
+			nullIfEmpty(reading.SnowConditions.SnowType),
+			nullIfEmpty(reading.SnowConditions.SnowHumidity),
+			reading.AirConditions.Temperature,
+			reading.AirConditions.Humidity,
+			nullIfEmpty(reading.AirConditions.Wind),
+			nullIfEmpty(reading.AirConditions.Cloud),
+			now)
+		if err != nil {
+			return fmt.Errorf("failed to insert reading at %s: %w", reading.RecordedAt.Format(time.RFC3339), err)
+		}
+	}
+	return nil
+}
+
+// summarizeReadings computes the time span of the readings and the minimum, maximum and mean of the measured values.
+func summarizeReadings(readings []domain.ConditionReading) domain.ConditionSummary {
+	summary := domain.ConditionSummary{Count: len(readings)}
+	if len(readings) == 0 {
+		return summary
+	}
+
+	from, to := readings[0].RecordedAt, readings[0].RecordedAt
+	snowTemperatures := make([]float64, len(readings))
+	airTemperatures := make([]float64, len(readings))
+	airHumidities := make([]float64, len(readings))
+	for i, reading := range readings {
+		if reading.RecordedAt.Before(from) {
+			from = reading.RecordedAt
+		}
+		if reading.RecordedAt.After(to) {
+			to = reading.RecordedAt
+		}
+		snowTemperatures[i] = float64(reading.SnowConditions.Temperature)
+		airTemperatures[i] = float64(reading.AirConditions.Temperature)
+		airHumidities[i] = float64(reading.AirConditions.Humidity)
+	}
+
+	summary.From, summary.To = &from, &to
+	summary.SnowTemperature = readingRange(snowTemperatures)
+	summary.AirTemperature = readingRange(airTemperatures)
+	summary.AirHumidity = readingRange(airHumidities)
+	return summary
+}
+
+// readingRange computes the minimum, maximum and mean of a non-empty list of values.
+func readingRange(values []float64) *domain.ReadingRange {
+	result := domain.ReadingRange{Min: values[0], Max: values[0]}
+	var sum float64
+	for _, value := range values {
+		result.Min = math.Min(result.Min, value)
+		result.Max = math.Max(result.Max, value)
+		sum += value
+	}
+	result.Mean = sum / float64(len(values))
+	return &result
+}
+
+// nearestReading returns the reading taken closest to the given time, so a run can be matched with the
+// conditions at that moment. The earlier reading wins a tie.
+func nearestReading(readings []domain.ConditionReading, at time.Time) (domain.ConditionReading, bool) {
+	var nearest domain.ConditionReading
+	var smallest time.Duration = -1
+	for _, reading := range readings {
+		difference := reading.RecordedAt.Sub(at)
+		if difference < 0 {
+			difference = -difference
+		}
+		if smallest < 0 || difference < smallest ||
+			(difference == smallest && reading.RecordedAt.Before(nearest.RecordedAt)) {
+			nearest, smallest = reading, difference
+		}
+	}
+	return nearest, smallest >= 0
+}
@@ -0,0 +1,28 @@
+package conditionsHandler
+
+import (
+	"time"
+)
+
+type ConditionReadingsPOSTRequest struct {
+	Readings []ConditionReadingPOST `json:"readings" validate:"required,min=1,dive"`
+}
+
+type ConditionReadingPOST struct {
+	RecordedAt     time.Time       `json:"recorded_at" validate:"required"`
+	SnowConditions SnowReadingPOST `json:"sc" validate:"required"`
+	AirConditions  AirReadingPOST  `json:"ac" validate:"required"`
+}
+
+type SnowReadingPOST struct {
+	Temperature  float32 `json:"temperature" validate:"omitempty,lte=100,gte=-100"`
+	SnowType     string  `json:"snow_type" validate:"omitempty,oneof=A1 A2 A3 A4 A5 FS NS IN IT TR"`
+	SnowHumidity string  `json:"snow_humidity" validate:"omitempty,oneof=DS W1 W2 W3 W4"`
+}
+
+type AirReadingPOST struct {
+	Temperature float32 `json:"temperature" validate:"omitempty,lte=100,gte=-100"`
+	Humidity    int     `json:"humidity" validate:"omitempty,lte=100,gte=0"`
+	Wind        string  `json:"wind" validate:"omitempty,oneof=S L M ST"`
+	Cloud       string  `json:"cloud" validate:"omitempty,oneof=1 2 3 4"`
+}
@@ -0,0 +1,251 @@
+package conditionsHandler
+
+import (
+	"backend/internal/domain"
+	"backend/internal/utils"
+	"database/sql"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func AuthenticationMock(mock sqlmock.Sqlmock) {
+	// Mock the user id query
+	mock.ExpectQuery("SELECT user_id FROM sessions WHERE \\(session_token = \\$1 AND expires_at > NOW\\(\\)\\)").
+		WithArgs("mockToken").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(1))
+
+	// Mock the user team id query
+	mock.ExpectQuery("SELECT team_id FROM users WHERE id = \\$1;").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"team_id"}).AddRow(1))
+
+	// Mock the user team role query
+	mock.ExpectQuery("SELECT team_role FROM team WHERE id = \\$1;").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"team_role"}).AddRow(1))
+}
+
+var readingColumns = []string{
+	"id", "test_id", "recorded_at", "snow_temperature", "snow_type", "snow_humidity",
+	"air_temperature", "air_humidity", "wind", "cloud", "version",
+}
+
+var start = time.Date(2025, 1, 12, 10, 0, 0, 0, time.UTC)
+
+func reading(minutes int, snowTemperature float32, airTemperature float32, humidity int) domain.ConditionReading {
+	return domain.ConditionReading{
+		RecordedAt:     start.Add(time.Duration(minutes) * time.Minute),
+		SnowConditions: domain.SnowReading{Temperature: snowTemperature},
+		AirConditions:  domain.AirReading{Temperature: airTemperature, Humidity: humidity},
+	}
+}
+
+func Test_summarizeReadings(t *testing.T) {
+	summary := summarizeReadings([]domain.ConditionReading{
+		reading(0, -8, -5, 80),
+		reading(60, -6, -2, 70),
+		reading(120, -4, 1, 60),
+	})
+
+	assert.Equal(t, 3, summary.Count)
+	assert.Equal(t, start, *summary.From)
+	assert.Equal(t, start.Add(2*time.Hour), *summary.To)
+	assert.Equal(t, domain.ReadingRange{Min: -8, Max: -4, Mean: -6}, *summary.SnowTemperature)
+	assert.Equal(t, domain.ReadingRange{Min: -5, Max: 1, Mean: -2}, *summary.AirTemperature)
+	assert.Equal(t, domain.ReadingRange{Min: 60, Max: 80, Mean: 70}, *summary.AirHumidity)
+
+	empty := summarizeReadings(nil)
+	assert.Equal(t, 0, empty.Count)
+	assert.Nil(t, empty.SnowTemperature)
+}
+
+func Test_nearestReading(t *testing.T) {
+	readings := []domain.ConditionReading{reading(0, -8, -5, 80), reading(30, -7, -4, 75), reading(60, -6, -2, 70)}
+
+	nearest, found := nearestReading(readings, start.Add(40*time.Minute))
+	assert.True(t, found)
+	assert.Equal(t, readings[1], nearest)
+
+	// The earlier reading wins a tie.
+	nearest, _ = nearestReading(readings, start.Add(45*time.Minute))
+	assert.Equal(t, readings[1], nearest)
+
+	nearest, _ = nearestReading(readings, start.Add(-time.Hour))
+	assert.Equal(t, readings[0], nearest)
+
+	_, found = nearestReading(nil, start)
+	assert.False(t, found)
+}
+
+func TestConditionsHandler(t *testing.T) {
+	mockDB, mock := utils.InitMockDB(t)
+
+	accessMock := func(testingTeam int, isPublic bool) {
+		mock.ExpectQuery("SELECT testing_team, is_public, state FROM tests WHERE id = \\$1;").
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"testing_team", "is_public", "state"}).
+				AddRow(testingTeam, isPublic, "in_progress"))
+	}
+	readingsMock := func() {
+		mock.ExpectQuery("SELECT id, test_id, recorded_at, snow_temperature").
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows(readingColumns).
+				AddRow(1, 1, start, -8.0, "A2", "DS", -5.0, 80, "S", "1", start).
+				AddRow(2, 1, start.Add(time.Hour), -4.0, nil, nil, 1.0, 60, nil, nil, start))
+	}
+
+	tests := []struct {
+		name         string
+		method       string
+		path         string
+		body         string
+		setupMocks   func()
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:         "Method = GET (Status OK - series and summary)",
+			method:       http.MethodGet,
+			path:         "/tests/1/conditions",
+			expectedCode: http.StatusOK,
+			expectedBody: `"snow_temperature":{"min":-8,"max":-4,"mean":-6}`,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				accessMock(2, true)
+				readingsMock()
+			},
+		},
+		{
+			name:         "Method = GET (Status OK - closest reading)",
+			method:       http.MethodGet,
+			path:         "/tests/1/conditions?at=2025-01-12T10:50:00Z",
+			expectedCode: http.StatusOK,
+			expectedBody: `{"id":2,"test_id":1,"recorded_at":"2025-01-12T11:00:00Z"`,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				accessMock(1, false)
+				readingsMock()
+			},
+		},
+		{
+			name:         "Method = GET (Status bad request - invalid at)",
+			method:       http.MethodGet,
+			path:         "/tests/1/conditions?at=yesterday",
+			expectedCode: http.StatusBadRequest,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+			},
+		},
+		{
+			name:         "Method = GET (Status unauthorized - private test of another team)",
+			method:       http.MethodGet,
+			path:         "/tests/1/conditions",
+			expectedCode: http.StatusUnauthorized,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				accessMock(2, false)
+			},
+		},
+		{
+			name:         "Method = GET (Status not found - no test)",
+			method:       http.MethodGet,
+			path:         "/tests/1/conditions",
+			expectedCode: http.StatusNotFound,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				mock.ExpectQuery("SELECT testing_team, is_public, state FROM tests WHERE id = \\$1;").
+					WithArgs(1).
+					WillReturnError(sql.ErrNoRows)
+			},
+		},
+		{
+			name:   "Method = POST (Status created)",
+			method: http.MethodPost,
+			path:   "/tests/1/conditions",
+			body: `{"readings":[{"recorded_at":"2025-01-12T10:00:00Z","sc":{"temperature":-8,"snow_type":"A2"},` +
+				`"ac":{"temperature":-5,"humidity":80,"wind":"S"}}]}`,
+			expectedCode: http.StatusCreated,
+			expectedBody: "Condition readings added successfully",
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				accessMock(1, false)
+
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO condition_readings").
+					WithArgs(1, start, float32(-8), "A2", nil, float32(-5), 80, "S", nil, sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:         "Method = POST (Status bad request - invalid snow type)",
+			method:       http.MethodPost,
+			path:         "/tests/1/conditions",
+			body:         `{"readings":[{"recorded_at":"2025-01-12T10:00:00Z","sc":{"snow_type":"B9"},"ac":{}}]}`,
+			expectedCode: http.StatusBadRequest,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+			},
+		},
+		{
+			name:         "Method = POST (Status unauthorized - test of another team)",
+			method:       http.MethodPost,
+			path:         "/tests/1/conditions",
+			body:         `{"readings":[{"recorded_at":"2025-01-12T10:00:00Z","sc":{},"ac":{}}]}`,
+			expectedCode: http.StatusUnauthorized,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				accessMock(2, true)
+			},
+		},
+		{
+			name:         "Method = POST (Status conflict - completed test)",
+			method:       http.MethodPost,
+			path:         "/tests/1/conditions",
+			body:         `{"readings":[{"recorded_at":"2025-01-12T10:00:00Z","sc":{},"ac":{}}]}`,
+			expectedCode: http.StatusConflict,
+			expectedBody: "test is completed and cannot be changed",
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				mock.ExpectQuery("SELECT testing_team, is_public, state FROM tests WHERE id = \\$1;").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"testing_team", "is_public", "state"}).
+						AddRow(1, false, "completed"))
+			},
+		},
+		{
+			name:         "Method = PATCH (Status not implemented)",
+			method:       http.MethodPatch,
+			path:         "/tests/1/conditions",
+			expectedCode: http.StatusNotImplemented,
+			setupMocks:   func() {},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer mockToken")
+			rr := httptest.NewRecorder()
+
+			ConditionsHandler(mockDB).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedCode, rr.Code)
+			if tt.expectedBody != "" {
+				assert.Contains(t, rr.Body.String(), tt.expectedBody)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,457 @@
+package locationsHandler
+
+import (
+	"backend/internal/middleware"
+	"backend/internal/resources"
+	"backend/internal/utils"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var locationPath = regexp.MustCompile(`^/locations/(\d+)$`)
+var tracksPath = regexp.MustCompile(`^/locations/(\d+)/tracks$`)
+var trackPath = regexp.MustCompile(`^/locations/(\d+)/tracks/(\d+)$`)
+
+// LocationsHandler routes HTTP requests for the test locations and their tracks to the appropriate handler function.
+//
+// It supports the following methods:
+// - GET: Retrieves all the locations, or a single location, with their tracks.
+// - POST: Creates a new location, or a new track at a location.
+// - PATCH: Updates an existing location.
+// - DELETE: Deletes a location or a track that no test refers to.
+//
+// Each method has its own dedicated request handler function, which is documented separately using Swagger annotations.
+func LocationsHandler(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		switch r.Method {
+		case http.MethodGet:
+			LocationsRequestGET(w, r, db)
+		case http.MethodPost:
+			if tracksPath.MatchString(r.URL.Path) {
+				TracksRequestPOST(w, r, db)
+				return
+			}
+			LocationsRequestPOST(w, r, db)
+		case http.MethodPatch:
+			LocationsRequestPATCH(w, r, db)
+		case http.MethodDelete:
+			LocationsRequestDELETE(w, r, db)
+		default:
+			http.Error(w, resources.MethodNotAllowed, http.StatusNotImplemented)
+			return
+		}
+	}
+}
+
+// LocationsRequestGET retrieves the test locations.
+//
+//	@Summary		Get the test locations
+//	@Description	Retrieves all the test locations, or a single location, with their tracks.
+//	@Tags			Locations
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			location_id	path		int					false	"Location ID"
+//	@Success		200			{array}		domain.Location		"Successful response with a list of locations"
+//	@Failure		404			{string}	string				"Could not find the location."
+//	@Failure		500			{string}	string				"Could not retrieve the locations."
+//	@Router			/locations [get]
+//	@Router			/locations/{location_id} [get]
+func LocationsRequestGET(w http.ResponseWriter, r *http.Request, db *sql.DB) {
+	idParam := strings.Trim(strings.TrimPrefix(r.URL.Path, "/locations"), "/")
+	if idParam == "" {
+		locations, err := getLocations(db)
+		if err != nil {
+			http.Error(w, "Could not retrieve the locations.", http.StatusInternalServerError)
+			log.Println("Could not retrieve the locations: " + err.Error())
+			return
+		}
+
+		err = json.NewEncoder(w).Encode(locations)
+		if err != nil {
+			http.Error(w, "Could not encode the locations.", http.StatusInternalServerError)
+			log.Println("Could not encode the locations: " + err.Error())
+		}
+		return
+	}
+
+	locationID, err := utils.GetIDFromURLQuery(w, idParam)
+	if err != nil {
+		return
+	}
+
+	location, err := getLocationWithID(db, locationID)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Could not find the location.", http.StatusNotFound)
+		log.Println("Could not find the location: " + idParam)
+		return
+	} else if err != nil {
+		http.Error(w, "Could not retrieve the location.", http.StatusInternalServerError)
+		log.Println("Could not retrieve the location: " + err.Error())
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(location)
+	if err != nil {
+		http.Error(w, "Could not encode the location.", http.StatusInternalServerError)
+		log.Println("Could not encode the location: " + err.Error())
+		return
+	}
+}
+
+// LocationsRequestPOST creates a new test location.
+//
+//	@Summary		Create a new location
+//	@Description	Adds a new test location with its tracks. Location names are unique, ignoring case.
+//	@Tags			Locations
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			location	body		LocationPOSTRequest	true	"New location information"
+//	@Success		201			{string}	string				"Location created successfully"
+//	@Failure		400			{string}	string				"Invalid POST request body"
+//	@Failure		409			{string}	string				"A location with this name already exists"
+//	@Failure		500			{string}	string				"Could not create the location."
+//	@Router			/locations [post]
+func LocationsRequestPOST(w http.ResponseWriter, r *http.Request, db *sql.DB) {
+	location, err := utils.ParseAndValidateRequest[LocationPOSTRequest](r)
+	if err == nil {
+		err = normalizeLocation(&location)
+	}
+	if err != nil {
+		http.Error(w, resources.InvalidPOSTRequest, http.StatusBadRequest)
+		log.Println(resources.InvalidPOSTRequest + ": " + err.Error())
+		return
+	}
+
+	// Get the user's team membership.
+	team := middleware.GetUserTeamRole(w, r, db)
+
+	taken, err := isLocationNameTaken(db, location.Name, 0)
+	if err != nil {
+		http.Error(w, "Could not create the location.", http.StatusInternalServerError)
+		log.Println("Could not check the location name: " + err.Error())
+		return
+	}
+	if taken {
+		http.Error(w, "A location with this name already exists", http.StatusConflict)
+		log.Println("A location with this name already exists: " + location.Name)
+		return
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		http.Error(w, resources.TransactionStartFailed, http.StatusInternalServerError)
+		return
+	}
+	defer func() {
+		if err != nil {
+			rollbackErr := tx.Rollback()
+			if rollbackErr != nil {
+				log.Println(resources.RollbackFailed + rollbackErr.Error())
+			}
+		}
+	}()
+
+	locationID, err := insertLocation(tx, location, team)
+	if err != nil {
+		http.Error(w, "Could not create the location.", http.StatusInternalServerError)
+		log.Println("Could not create the location: " + err.Error())
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		http.Error(w, resources.TransactionCommitFailed, http.StatusInternalServerError)
+		log.Println(resources.TransactionCommitFailed + ": " + err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	err = json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Location created successfully",
+		"id":      locationID,
+	})
+	if err != nil {
+		log.Println("Could not JSON encode the response: " + err.Error())
+		return
+	}
+}
+
+// TracksRequestPOST creates a new track at a location.
+//
+//	@Summary		Create a new track
+//	@Description	Adds a new named track to a location. Track names are unique within a location, ignoring case.
+//	@Description	Only the team that created the location and the official teams may add tracks to it.
+//	@Tags			Locations
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			location_id	path		int			true	"Location ID"
+//	@Param			track		body		TrackPOST	true	"New track information"
+//	@Success		201			{string}	string		"Track created successfully"
+//	@Failure		400			{string}	string		"Invalid POST request body"
+//	@Failure		401			{string}	string		"Unauthorized"
+//	@Failure		404			{string}	string		"Could not find the location."
+//	@Failure		409			{string}	string		"A track with this name already exists at the location"
+//	@Failure		500			{string}	string		"Could not create the track."
+//	@Router			/locations/{location_id}/tracks [post]
+func TracksRequestPOST(w http.ResponseWriter, r *http.Request, db *sql.DB) {
+	matches := tracksPath.FindStringSubmatch(r.URL.Path)
+	locationID, _ := strconv.Atoi(matches[1])
+
+	track, err := utils.ParseAndValidateRequest[TrackPOST](r)
+	track.Name = strings.TrimSpace(track.Name)
+	if err == nil && track.Name == "" {
+		err = errors.New("track name cannot be blank")
+	}
+	if err != nil {
+		http.Error(w, resources.InvalidPOSTRequest, http.StatusBadRequest)
+		log.Println(resources.InvalidPOSTRequest + ": " + err.Error())
+		return
+	}
+
+	// Get the user's team membership.
+	team := middleware.GetUserTeamRole(w, r, db)
+
+	createdBy, err := getLocationOwner(db, locationID)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Could not find the location.", http.StatusNotFound)
+		log.Println("Could not find the location: " + matches[1])
+		return
+	} else if err != nil {
+		http.Error(w, "Could not create the track.", http.StatusInternalServerError)
+		log.Println("Could not retrieve the location: " + err.Error())
+		return
+	}
+	if err, code := validateLocationOwner(createdBy, team); err != nil {
+		http.Error(w, "Validation error: "+err.Error(), code)
+		log.Println("Validation error: " + err.Error())
+		return
+	}
+
+	var count int
+	err = db.QueryRow("SELECT COUNT(*) FROM tracks WHERE location_id = $1 AND lower(name) = lower($2);",
+		locationID, track.Name).Scan(&count)
+	if err != nil {
+		http.Error(w, "Could not create the track.", http.StatusInternalServerError)
+		log.Println("Could not check the track name: " + err.Error())
+		return
+	}
+	if count != 0 {
+		http.Error(w, "A track with this name already exists at the location", http.StatusConflict)
+		log.Println("A track with this name already exists at the location: " + track.Name)
+		return
+	}
+
+	trackID, err := insertTrack(db, locationID, track)
+	if err != nil {
+		http.Error(w, "Could not create the track.", http.StatusInternalServerError)
+		log.Println("Could not create the track: " + err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	err = json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Track created successfully",
+		"id":      trackID,
+	})
+	if err != nil {
+		log.Println("Could not JSON encode the response: " + err.Error())
+		return
+	}
+}
+
+// LocationsRequestPATCH updates an existing location.
+//
+//	@Summary		Update a location
+//	@Description	Updates the name, coordinates or altitude of a location. Only the team that created the location
+//	@Description	and the official teams may change it.
+//	@Tags			Locations
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			location_id	path		int						true	"Location ID"
+//	@Param			location	body		LocationPATCHRequest	true	"Location update fields"
+//	@Success		200			{string}	string					"Location updated successfully"
+//	@Failure		400			{string}	string					"Invalid PATCH request body"
+//	@Failure		401			{string}	string					"Unauthorized"
+//	@Failure		404			{string}	string					"Could not find the location."
+//	@Failure		409			{string}	string					"Detected a conflict for the current location, please refresh."
+//	@Router			/locations/{location_id} [patch]
+func LocationsRequestPATCH(w http.ResponseWriter, r *http.Request, db *sql.DB) {
+	matches := locationPath.FindStringSubmatch(r.URL.Path)
+	if len(matches) != 2 {
+		http.Error(w, "Invalid request URL, use '/locations/{location_id}'.", http.StatusBadRequest)
+		log.Println("Invalid request URL: " + r.URL.Path)
+		return
+	}
+	locationID, _ := strconv.Atoi(matches[1])
+
+	var locationUpdateRequest LocationPATCHRequest
+	err := utils.DecodeRequestBody(w, r, &locationUpdateRequest)
+	if err != nil {
+		return
+	}
+
+	// Get the user's team membership.
+	team := middleware.GetUserTeamRole(w, r, db)
+
+	existingLocation, err := getLocationWithID(db, locationID)
+	if err != nil {
+		http.Error(w, "Could not find the location.", http.StatusNotFound)
+		log.Println("Could not find the location: " + err.Error())
+		return
+	}
+
+	var code int
+	if err, code = validateLocationOwner(existingLocation.CreatedBy, team); err != nil {
+		http.Error(w, "Validation error: "+err.Error(), code)
+		log.Println("Validation error: " + err.Error())
+		return
+	}
+
+	err, code = ValidateLocationPATCHRequestBody(db, locationUpdateRequest, existingLocation)
+	if err != nil {
+		http.Error(w, "Validation error: "+err.Error(), code)
+		return
+	}
+
+	// Solve the concurrency challenge by checking if the location has been updated since the last sync.
+	if existingLocation.Version.After(locationUpdateRequest.Version) {
+		http.Error(w, "Detected a conflict for the current location, please refresh.", http.StatusConflict)
+		log.Println("Detected a conflict for the current location, please refresh.")
+		return
+	}
+
+	var updatedFields []string
+	var newValues []interface{}
+	i := 1 // Index for the newValues array.
+	updatedFields, newValues, i = utils.CreateUpdateQuery(w, locationUpdateRequest.Updates, updatedFields, newValues, i)
+	if len(updatedFields) == 0 {
+		return
+	}
+
+	query := fmt.Sprintf("UPDATE locations SET %s WHERE id = $%d AND version = $%d RETURNING version",
+		strings.Join(updatedFields, ", "), i, i+1)
+	newValues = append(newValues, locationID, existingLocation.Version)
+
+	var newVersion time.Time
+	err = db.QueryRow(query, newValues...).Scan(&newVersion)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Detected a conflict for the current location, please refresh.", http.StatusConflict)
+		log.Println("Detected a conflict for the current location, please refresh.")
+		return
+	} else if err != nil {
+		http.Error(w, "Could not update the location.", http.StatusInternalServerError)
+		log.Println("Could not update the location: " + err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Location updated successfully",
+		"version": newVersion,
+	})
+	if err != nil {
+		log.Println("Could not JSON encode the response: " + err.Error())
+		return
+	}
+}
+
+// LocationsRequestDELETE deletes a location or a track.
+//
+//	@Summary		Delete a location or a track
+//	@Description	Deletes a location with its tracks, or a single track. Locations and tracks that tests refer to
+//	@Description	cannot be deleted. Only the team that created the location and the official teams may delete it or
+//	@Description	its tracks.
+//	@Tags			Locations
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			location_id	path	int	true	"Location ID"
+//	@Param			track_id	path	int	false	"Track ID"
+//	@Success		204			"Deleted successfully"
+//	@Failure		401			{string}	string	"Unauthorized"
+//	@Failure		404			{string}	string	"Could not find the location."
+//	@Failure		409			{string}	string	"Tests refer to this location."
+//	@Failure		500			{string}	string	"Could not delete the location."
+//	@Router			/locations/{location_id} [delete]
+//	@Router			/locations/{location_id}/tracks/{track_id} [delete]
+func LocationsRequestDELETE(w http.ResponseWriter, r *http.Request, db *sql.DB) {
+	entity, column := "location", "location_id"
+	query := "DELETE FROM locations WHERE id = $1;"
+	args := []interface{}{}
+
+	var locationID int
+	if matches := trackPath.FindStringSubmatch(r.URL.Path); len(matches) == 3 {
+		locationID, _ = strconv.Atoi(matches[1])
+		trackID, _ := strconv.Atoi(matches[2])
+		entity, column = "track", "track_id"
+		query = "DELETE FROM tracks WHERE id = $1 AND location_id = $2;"
+		args = append(args, trackID, locationID)
+	} else if matches = locationPath.FindStringSubmatch(r.URL.Path); len(matches) == 2 {
+		locationID, _ = strconv.Atoi(matches[1])
+		args = append(args, locationID)
+	} else {
+		http.Error(w, "Invalid request URL, use '/locations/{location_id}'.", http.StatusBadRequest)
+		log.Println("Invalid request URL: " + r.URL.Path)
+		return
+	}
+
+	// Get the user's team membership.
+	team := middleware.GetUserTeamRole(w, r, db)
+
+	createdBy, err := getLocationOwner(db, locationID)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Could not find the location.", http.StatusNotFound)
+		log.Println("Could not find the location: " + r.URL.Path)
+		return
+	} else if err != nil {
+		http.Error(w, "Could not delete the "+entity+".", http.StatusInternalServerError)
+		log.Println("Could not retrieve the location: " + err.Error())
+		return
+	}
+	if err, code := validateLocationOwner(createdBy, team); err != nil {
+		http.Error(w, "Validation error: "+err.Error(), code)
+		log.Println("Validation error: " + err.Error())
+		return
+	}
+
+	// Deleting would lose the venue of the tests.
+	count, err := countTestsAt(db, column, args[0].(int))
+	if err != nil {
+		http.Error(w, "Could not delete the "+entity+".", http.StatusInternalServerError)
+		log.Println("Could not count the tests at the " + entity + ": " + err.Error())
+		return
+	}
+	if count != 0 {
+		http.Error(w, "Tests refer to this "+entity+".", http.StatusConflict)
+		log.Println("Tests refer to this " + entity + ".")
+		return
+	}
+
+	result, err := db.Exec(query, args...)
+	if err != nil {
+		http.Error(w, "Could not delete the "+entity+".", http.StatusInternalServerError)
+		log.Println("Could not delete the " + entity + ": " + err.Error())
+		return
+	}
+
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		http.Error(w, "Could not find the "+entity+".", http.StatusNotFound)
+		log.Println("Could not find the " + entity + ": " + r.URL.Path)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,248 @@
+package locationsHandler
+
+import (
+	"backend/internal/domain"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const locationColumns = "id, name, latitude, longitude, altitude, version, created_by"
+const trackColumns = "id, location_id, name, length, version"
+
+// scanLocation scans a locations row into a Location struct.
+func scanLocation(row interface{ Scan(...any) error }) (domain.Location, error) {
+	location := domain.Location{Tracks: []domain.Track{}}
+	err := row.Scan(
+		&location.ID,
+		&location.Name,
+		&location.Latitude,
+		&location.Longitude,
+		&location.Altitude,
+		&location.Version,
+		&location.CreatedBy)
+	return location, err
+}
+
+// getTracks retrieves the tracks of the given locations, or of all the locations when no ID is given.
+func getTracks(db *sql.DB, locationID int) ([]domain.Track, error) {
+	query := "SELECT " + trackColumns + " FROM tracks"
+	var args []interface{}
+	if locationID != 0 {
+		query += " WHERE location_id = $1"
+		args = append(args, locationID)
+	}
+
+	rows, err := db.Query(query+" ORDER BY name;", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tracks []domain.Track
+	for rows.Next() {
+		var track domain.Track
+		if err = rows.Scan(&track.ID, &track.LocationID, &track.Name, &track.Length, &track.Version); err != nil {
+			return nil, err
+		}
+		tracks = append(tracks, track)
+	}
+	return tracks, rows.Err()
+}
+
+// getLocations retrieves all the locations with their tracks, ordered by name.
+func getLocations(db *sql.DB) ([]domain.Location, error) {
+	rows, err := db.Query("SELECT " + locationColumns + " FROM locations ORDER BY name;")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	locations := []domain.Location{}
+	index := map[int]int{}
+	for rows.Next() {
+		location, err := scanLocation(rows)
+		if err != nil {
+			return nil, err
+		}
+		index[location.ID] = len(locations)
+		locations = append(locations, location)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	tracks, err := getTracks(db, 0)
+	if err != nil {
+		return nil, err
+	}
+	for _, track := range tracks {
+		if i, ok := index[track.LocationID]; ok {
+			locations[i].Tracks = append(locations[i].Tracks, track)
+		}
+	}
+	return locations, nil
+}
+
+// getLocationWithID retrieves a location with its tracks.
+func getLocationWithID(db *sql.DB, locationID int) (domain.Location, error) {
+	location, err := scanLocation(db.QueryRow("SELECT "+locationColumns+" FROM locations WHERE id = $1;", locationID))
+	if err != nil {
+		return domain.Location{}, err
+	}
+
+	tracks, err := getTracks(db, locationID)
+	if err != nil {
+		return domain.Location{}, err
+	}
+	location.Tracks = append(location.Tracks, tracks...)
+	return location, nil
+}
+
+// isLocationNameTaken checks if another location already has the name, ignoring case.
+func isLocationNameTaken(db *sql.DB, name string, locationID int) (bool, error) {
+	var count int
+	err := db.QueryRow("SELECT COUNT(*) FROM locations WHERE lower(name) = lower($1) AND id <> $2;",
+		name, locationID).Scan(&count)
+	return count > 0, err
+}
+
+// insertLocation inserts a new location of the team with its tracks and returns its ID.
+func insertLocation(tx *sql.Tx, location LocationPOSTRequest, team int) (int, error) {
+	var locationID int
+	err := tx.QueryRow(`INSERT INTO locations (name, latitude, longitude, altitude, version, created_by)
+								VALUES ($1, $2, $3, $4, $5, $6)
+								RETURNING id;`,
+		location.Name,
+		location.Latitude,
+		location.Longitude,
+		location.Altitude,
+		time.Now(),
+		team).Scan(&locationID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert location: %w", err)
+	}
+
+	for _, track := range location.Tracks {
+		if _, err = insertTrack(tx, locationID, track); err != nil {
+			return 0, err
+		}
+	}
+	return locationID, nil
+}
+
+// getLocationOwner retrieves the team that created a location.
+func getLocationOwner(db *sql.DB, locationID int) (*int, error) {
+	var createdBy *int
+	err := db.QueryRow("SELECT created_by FROM locations WHERE id = $1;", locationID).Scan(&createdBy)
+	return createdBy, err
+}
+
+// validateLocationOwner checks that the team is allowed to change or delete a location, which only the team that
+// created it and the official teams are.
+func validateLocationOwner(createdBy *int, team int) (error, int) {
+	if domain.TeamRole(team) == domain.Official || (createdBy != nil && *createdBy == team) {
+		return nil, 0
+	}
+	return fmt.Errorf("user cannot change this location, %d", http.StatusUnauthorized), http.StatusUnauthorized
+}
+
+// insertTrack inserts a new track at a location and returns its ID.
+func insertTrack(q interface {
+	QueryRow(query string, args ...any) *sql.Row
+}, locationID int, track TrackPOST) (int, error) {
+	var trackID int
+	err := q.QueryRow(`INSERT INTO tracks (location_id, name, length, version)
+								VALUES ($1, $2, $3, $4)
+								RETURNING id;`,
+		locationID,
+		track.Name,
+		track.Length,
+		time.Now()).Scan(&trackID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert track %s: %w", track.Name, err)
+	}
+	return trackID, nil
+}
+
+// normalizeLocation trims the names of a new location and its tracks, and checks that the track names are unique.
+func normalizeLocation(location *LocationPOSTRequest) error {
+	location.Name = strings.TrimSpace(location.Name)
+	if location.Name == "" {
+		return fmt.Errorf("location name cannot be blank")
+	}
+
+	seen := map[string]bool{}
+	for i := range location.Tracks {
+		location.Tracks[i].Name = strings.TrimSpace(location.Tracks[i].Name)
+		key := strings.ToLower(location.Tracks[i].Name)
+		if key == "" {
+			return fmt.Errorf("track name cannot be blank")
+		}
+		if seen[key] {
+			return fmt.Errorf("duplicate track name %s", location.Tracks[i].Name)
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
+// ValidateLocationPATCHRequestBody validates the keys and values of a location update.
+func ValidateLocationPATCHRequestBody(db *sql.DB, locationUpdateRequest LocationPATCHRequest,
+	existingLocation domain.Location) (error, int) {
+	var update LocationUpdateFields
+	b, _ := json.Marshal(locationUpdateRequest.Updates)
+	err := json.Unmarshal(b, &update)
+	if err != nil {
+		log.Println("Could not decode request body: " + err.Error())
+		return fmt.Errorf("could not decode request body, %d", http.StatusBadRequest), http.StatusBadRequest
+	}
+
+	validate := validator.New()
+
+	// Validate keys
+	err = validate.Struct(locationUpdateRequest)
+	if err != nil {
+		log.Println("Invalid PATCH request body keys: " + err.Error())
+		return fmt.Errorf("invalid PATCH request body keys, %d", http.StatusBadRequest), http.StatusBadRequest
+	}
+
+	// Validate values
+	err = validate.Struct(update)
+	if err != nil {
+		log.Println("Invalid PATCH request body values: " + err.Error())
+		return fmt.Errorf("invalid PATCH request body values, %d", http.StatusBadRequest), http.StatusBadRequest
+	}
+
+	if update.Name != nil {
+		name := strings.TrimSpace(*update.Name)
+		if name == "" {
+			return fmt.Errorf("location name cannot be blank, %d", http.StatusBadRequest), http.StatusBadRequest
+		}
+		locationUpdateRequest.Updates["name"] = name
+
+		taken, err := isLocationNameTaken(db, name, existingLocation.ID)
+		if err != nil {
+			log.Println("Could not check the location name: " + err.Error())
+			return fmt.Errorf("could not check the location name, %d", http.StatusInternalServerError),
+				http.StatusInternalServerError
+		}
+		if taken {
+			log.Println("Update not allowed: a location with this name already exists")
+			return fmt.Errorf("a location with this name already exists, %d", http.StatusConflict), http.StatusConflict
+		}
+	}
+	return nil, 0
+}
+
+// countTestsAt counts the tests that reference a location or a track.
+func countTestsAt(db *sql.DB, column string, id int) (int, error) {
+	var count int
+	err := db.QueryRow(fmt.Sprintf("SELECT COUNT(*) FROM tests WHERE %s = $1;", column), id).Scan(&count)
+	return count, err
+}
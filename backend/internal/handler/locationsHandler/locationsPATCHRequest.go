@@ -0,0 +1,15 @@
+package locationsHandler
+
+import "time"
+
+type LocationPATCHRequest struct {
+	Updates map[string]interface{} `json:"updates" validate:"required,dive,keys,oneof=name latitude longitude altitude,endkeys"`
+	Version time.Time              `json:"version"`
+}
+
+type LocationUpdateFields struct {
+	Name      *string  `json:"name" validate:"omitempty,lte=50"`
+	Latitude  *float64 `json:"latitude" validate:"omitempty,gte=-90,lte=90"`
+	Longitude *float64 `json:"longitude" validate:"omitempty,gte=-180,lte=180"`
+	Altitude  *int     `json:"altitude" validate:"omitempty,gte=-500,lte=9000"`
+}
@@ -0,0 +1,14 @@
+package locationsHandler
+
+type LocationPOSTRequest struct {
+	Name      string      `json:"name" validate:"required,lte=50"`
+	Latitude  *float64    `json:"latitude" validate:"omitempty,gte=-90,lte=90"`
+	Longitude *float64    `json:"longitude" validate:"omitempty,gte=-180,lte=180"`
+	Altitude  *int        `json:"altitude" validate:"omitempty,gte=-500,lte=9000"`
+	Tracks    []TrackPOST `json:"tracks" validate:"omitempty,dive"`
+}
+
+type TrackPOST struct {
+	Name   string `json:"name" validate:"required,lte=50"`
+	Length *int   `json:"length" validate:"omitempty,gt=0"`
+}
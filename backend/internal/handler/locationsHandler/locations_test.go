@@ -0,0 +1,352 @@
+package locationsHandler
+
+import (
+	"backend/internal/utils"
+	"database/sql"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+var locationColumnNames = []string{"id", "name", "latitude", "longitude", "altitude", "version", "created_by"}
+var trackColumnNames = []string{"id", "location_id", "name", "length", "version"}
+
+func AuthenticationMock(mock sqlmock.Sqlmock, teamRole int) {
+	// Mock the user id query
+	mock.ExpectQuery("SELECT user_id FROM sessions WHERE \\(session_token = \\$1 AND expires_at > NOW\\(\\)\\)").
+		WithArgs("mockToken").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(1))
+
+	// Mock the user team id query
+	mock.ExpectQuery("SELECT team_id FROM users WHERE id = \\$1;").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"team_id"}).AddRow(teamRole))
+
+	// Mock the user team role query
+	mock.ExpectQuery("SELECT team_role FROM team WHERE id = \\$1;").
+		WithArgs(teamRole).
+		WillReturnRows(sqlmock.NewRows([]string{"team_role"}).AddRow(teamRole))
+}
+
+func Test_validateLocationOwner(t *testing.T) {
+	team := 2
+	err, code := validateLocationOwner(&team, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, code)
+
+	err, code = validateLocationOwner(nil, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, code)
+
+	err, code = validateLocationOwner(nil, 2)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusUnauthorized, code)
+}
+
+func Test_normalizeLocation(t *testing.T) {
+	location := LocationPOSTRequest{Name: " Beitostølen ", Tracks: []TrackPOST{{Name: "Stadion "}, {Name: "Lysløype"}}}
+	assert.NoError(t, normalizeLocation(&location))
+	assert.Equal(t, "Beitostølen", location.Name)
+	assert.Equal(t, "Stadion", location.Tracks[0].Name)
+
+	assert.Error(t, normalizeLocation(&LocationPOSTRequest{Name: "   "}))
+	assert.Error(t, normalizeLocation(&LocationPOSTRequest{Name: "Beito",
+		Tracks: []TrackPOST{{Name: "Stadion"}, {Name: "stadion"}}}))
+}
+
+func TestLocationsHandler(t *testing.T) {
+	mockDB, mock := utils.InitMockDB(t)
+	version := time.Date(2025, 3, 30, 10, 30, 0, 0, time.UTC)
+
+	locationMock := func(id int) {
+		mock.ExpectQuery("SELECT id, name, latitude, longitude, altitude, version, created_by FROM locations WHERE id = \\$1;").
+			WithArgs(id).
+			WillReturnRows(sqlmock.NewRows(locationColumnNames).AddRow(id, "Beitostølen", 61.25, 8.91, 900, version, 2))
+		mock.ExpectQuery("SELECT id, location_id, name, length, version FROM tracks WHERE location_id = \\$1").
+			WithArgs(id).
+			WillReturnRows(sqlmock.NewRows(trackColumnNames).AddRow(4, id, "Stadion", 1200, version))
+	}
+
+	tests := []struct {
+		name         string
+		method       string
+		path         string
+		body         string
+		setupMocks   func()
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:         "Method = GET (Status OK - all locations)",
+			method:       http.MethodGet,
+			path:         "/locations",
+			expectedCode: http.StatusOK,
+			expectedBody: `"name":"Beitostølen","latitude":61.25,"longitude":8.91,"altitude":900`,
+			setupMocks: func() {
+				mock.ExpectQuery("SELECT id, name, latitude, longitude, altitude, version, created_by FROM locations ORDER BY name;").
+					WillReturnRows(sqlmock.NewRows(locationColumnNames).
+						AddRow(3, "Beitostølen", 61.25, 8.91, 900, version, 2).
+						AddRow(5, "Holmenkollen", nil, nil, nil, version, nil))
+				mock.ExpectQuery("SELECT id, location_id, name, length, version FROM tracks ORDER BY name;").
+					WillReturnRows(sqlmock.NewRows(trackColumnNames).AddRow(4, 3, "Stadion", 1200, version))
+			},
+		},
+		{
+			name:         "Method = GET (Status OK - single location)",
+			method:       http.MethodGet,
+			path:         "/locations/3",
+			expectedCode: http.StatusOK,
+			expectedBody: `"tracks":[{"id":4,"location_id":3,"name":"Stadion","length":1200`,
+			setupMocks: func() {
+				locationMock(3)
+			},
+		},
+		{
+			name:         "Method = GET (Status not found)",
+			method:       http.MethodGet,
+			path:         "/locations/9",
+			expectedCode: http.StatusNotFound,
+			setupMocks: func() {
+				mock.ExpectQuery("SELECT .* FROM locations WHERE id = \\$1;").
+					WithArgs(9).
+					WillReturnError(sql.ErrNoRows)
+			},
+		},
+		{
+			name:         "Method = POST (Status created)",
+			method:       http.MethodPost,
+			path:         "/locations",
+			body:         `{"name":" Beitostølen","latitude":61.25,"longitude":8.91,"altitude":900,"tracks":[{"name":"Stadion","length":1200}]}`,
+			expectedCode: http.StatusCreated,
+			expectedBody: "Location created successfully",
+			setupMocks: func() {
+				AuthenticationMock(mock, 2)
+				mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM locations WHERE lower\\(name\\) = lower\\(\\$1\\) AND id <> \\$2;").
+					WithArgs("Beitostølen", 0).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+				mock.ExpectBegin()
+				mock.ExpectQuery("INSERT INTO locations").
+					WithArgs("Beitostølen", 61.25, 8.91, 900, sqlmock.AnyArg(), 2).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+				mock.ExpectQuery("INSERT INTO tracks").
+					WithArgs(3, "Stadion", 1200, sqlmock.AnyArg()).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:         "Method = POST (Status conflict - name in use)",
+			method:       http.MethodPost,
+			path:         "/locations",
+			body:         `{"name":"beitostølen"}`,
+			expectedCode: http.StatusConflict,
+			setupMocks: func() {
+				AuthenticationMock(mock, 2)
+				mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM locations").
+					WithArgs("beitostølen", 0).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+			},
+		},
+		{
+			name:         "Method = POST (Status bad request - invalid latitude)",
+			method:       http.MethodPost,
+			path:         "/locations",
+			body:         `{"name":"Beito","latitude":123}`,
+			expectedCode: http.StatusBadRequest,
+			setupMocks:   func() {},
+		},
+		{
+			name:         "Method = POST track (Status created)",
+			method:       http.MethodPost,
+			path:         "/locations/3/tracks",
+			body:         `{"name":"Lysløype","length":2500}`,
+			expectedCode: http.StatusCreated,
+			expectedBody: "Track created successfully",
+			setupMocks: func() {
+				AuthenticationMock(mock, 2)
+				mock.ExpectQuery("SELECT created_by FROM locations WHERE id = \\$1;").
+					WithArgs(3).
+					WillReturnRows(sqlmock.NewRows([]string{"created_by"}).AddRow(2))
+				mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM tracks").
+					WithArgs(3, "Lysløype").
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+				mock.ExpectQuery("INSERT INTO tracks").
+					WithArgs(3, "Lysløype", 2500, sqlmock.AnyArg()).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(6))
+			},
+		},
+		{
+			name:         "Method = POST track (Status conflict - name in use)",
+			method:       http.MethodPost,
+			path:         "/locations/3/tracks",
+			body:         `{"name":"stadion"}`,
+			expectedCode: http.StatusConflict,
+			setupMocks: func() {
+				AuthenticationMock(mock, 2)
+				mock.ExpectQuery("SELECT created_by FROM locations WHERE id = \\$1;").
+					WithArgs(3).
+					WillReturnRows(sqlmock.NewRows([]string{"created_by"}).AddRow(2))
+				mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM tracks").
+					WithArgs(3, "stadion").
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+			},
+		},
+		{
+			name:         "Method = POST track (Status unauthorized - location of another team)",
+			method:       http.MethodPost,
+			path:         "/locations/3/tracks",
+			body:         `{"name":"Lysløype"}`,
+			expectedCode: http.StatusUnauthorized,
+			expectedBody: "user cannot change this location",
+			setupMocks: func() {
+				AuthenticationMock(mock, 3)
+				mock.ExpectQuery("SELECT created_by FROM locations WHERE id = \\$1;").
+					WithArgs(3).
+					WillReturnRows(sqlmock.NewRows([]string{"created_by"}).AddRow(2))
+			},
+		},
+		{
+			name:         "Method = PATCH (Status OK)",
+			method:       http.MethodPatch,
+			path:         "/locations/3",
+			body:         `{"updates":{"altitude":880},"version":"2025-03-30T10:30:00Z"}`,
+			expectedCode: http.StatusOK,
+			expectedBody: "Location updated successfully",
+			setupMocks: func() {
+				AuthenticationMock(mock, 2)
+				locationMock(3)
+				mock.ExpectQuery("UPDATE locations SET altitude = \\$1, version = \\$2 WHERE id = \\$3 AND version = \\$4 RETURNING version").
+					WithArgs(880.0, sqlmock.AnyArg(), 3, version).
+					WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(time.Now()))
+			},
+		},
+		{
+			name:         "Method = PATCH (Status OK - official team)",
+			method:       http.MethodPatch,
+			path:         "/locations/3",
+			body:         `{"updates":{"altitude":880},"version":"2025-03-30T10:30:00Z"}`,
+			expectedCode: http.StatusOK,
+			setupMocks: func() {
+				AuthenticationMock(mock, 1)
+				locationMock(3)
+				mock.ExpectQuery("UPDATE locations SET altitude = \\$1, version = \\$2 WHERE id = \\$3 AND version = \\$4 RETURNING version").
+					WithArgs(880.0, sqlmock.AnyArg(), 3, version).
+					WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(time.Now()))
+			},
+		},
+		{
+			name:         "Method = PATCH (Status conflict - outdated version)",
+			method:       http.MethodPatch,
+			path:         "/locations/3",
+			body:         `{"updates":{"altitude":880},"version":"2025-03-29T10:30:00Z"}`,
+			expectedCode: http.StatusConflict,
+			setupMocks: func() {
+				AuthenticationMock(mock, 2)
+				locationMock(3)
+			},
+		},
+		{
+			name:         "Method = PATCH (Status bad request - invalid key)",
+			method:       http.MethodPatch,
+			path:         "/locations/3",
+			body:         `{"updates":{"tracks":[]},"version":"2025-03-30T10:30:00Z"}`,
+			expectedCode: http.StatusBadRequest,
+			setupMocks: func() {
+				AuthenticationMock(mock, 2)
+				locationMock(3)
+			},
+		},
+		{
+			name:         "Method = DELETE track (Status no content)",
+			method:       http.MethodDelete,
+			path:         "/locations/3/tracks/4",
+			expectedCode: http.StatusNoContent,
+			setupMocks: func() {
+				AuthenticationMock(mock, 2)
+				mock.ExpectQuery("SELECT created_by FROM locations WHERE id = \\$1;").
+					WithArgs(3).
+					WillReturnRows(sqlmock.NewRows([]string{"created_by"}).AddRow(2))
+				mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM tests WHERE track_id = \\$1;").
+					WithArgs(4).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+				mock.ExpectExec("DELETE FROM tracks WHERE id = \\$1 AND location_id = \\$2;").
+					WithArgs(4, 3).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name:         "Method = DELETE (Status conflict - tests at the location)",
+			method:       http.MethodDelete,
+			path:         "/locations/3",
+			expectedCode: http.StatusConflict,
+			setupMocks: func() {
+				AuthenticationMock(mock, 2)
+				mock.ExpectQuery("SELECT created_by FROM locations WHERE id = \\$1;").
+					WithArgs(3).
+					WillReturnRows(sqlmock.NewRows([]string{"created_by"}).AddRow(2))
+				mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM tests WHERE location_id = \\$1;").
+					WithArgs(3).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+			},
+		},
+		{
+			name:         "Method = DELETE (Status not found)",
+			method:       http.MethodDelete,
+			path:         "/locations/9",
+			expectedCode: http.StatusNotFound,
+			setupMocks: func() {
+				AuthenticationMock(mock, 2)
+				mock.ExpectQuery("SELECT created_by FROM locations WHERE id = \\$1;").
+					WithArgs(9).
+					WillReturnError(sql.ErrNoRows)
+			},
+		},
+		{
+			name:         "Method = DELETE (Status unauthorized - location of another team)",
+			method:       http.MethodDelete,
+			path:         "/locations/5",
+			expectedCode: http.StatusUnauthorized,
+			expectedBody: "user cannot change this location",
+			setupMocks: func() {
+				AuthenticationMock(mock, 2)
+				mock.ExpectQuery("SELECT created_by FROM locations WHERE id = \\$1;").
+					WithArgs(5).
+					WillReturnRows(sqlmock.NewRows([]string{"created_by"}).AddRow(nil))
+			},
+		},
+		{
+			name:         "Method = PUT (Status not implemented)",
+			method:       http.MethodPut,
+			path:         "/locations/3",
+			expectedCode: http.StatusNotImplemented,
+			setupMocks:   func() {},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer mockToken")
+			rr := httptest.NewRecorder()
+
+			LocationsHandler(mockDB).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedCode, rr.Code)
+			if tt.expectedBody != "" {
+				assert.Contains(t, rr.Body.String(), tt.expectedBody)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package productsHandler
 
 import (
 	"backend/internal/domain"
+	"backend/internal/services/access"
 	"database/sql"
 	"time"
 )
@@ -64,10 +65,10 @@ func ScanProducts(rows *sql.Rows) ([]domain.Product, error) {
 	return products, nil
 }
 
-// queryAndScanProduct executes a query and scans the result into a Product struct.
-func queryAndScanProduct(db *sql.DB, query string, args ...interface{}) (domain.Product, error) {
+// queryAndScanProduct executes a query, in the database or a transaction, and scans the result into a Product struct.
+func queryAndScanProduct(q access.Querier, query string, args ...interface{}) (domain.Product, error) {
 	var product domain.Product
-	err := db.QueryRow(query, args...).Scan(
+	err := q.QueryRow(query, args...).Scan(
 		&product.ID,
 		&product.Name,
 		&product.Brand,
@@ -4,9 +4,11 @@ import (
 	"backend/internal/domain"
 	"backend/internal/middleware"
 	"backend/internal/resources"
+	"backend/internal/services/ean"
 	"backend/internal/utils"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -18,8 +20,9 @@ import (
 // ProductsHandler routes HTTP requests for products to the appropriate handler function.
 //
 // It supports the following methods:
-// - GET: Retrieves a list of products.
-// - POST: Creates a new product.
+// - GET: Retrieves a list of products, the products matching a search, a product by its EAN code, the temperature
+// window of a product, or the history or status changes of a product.
+// - POST: Creates a new product, reverts a product to an earlier revision, or merges duplicate products.
 // - PATCH: Updates an existing product.
 //
 // Each method has its own dedicated request handler function, which is documented separately using Swagger annotations.
@@ -28,8 +31,36 @@ func ProductsHandler(db *sql.DB) http.HandlerFunc {
 		w.Header().Set("content-type", "application/json")
 		switch r.Method {
 		case http.MethodGet:
+			if searchPath.MatchString(r.URL.Path) {
+				ProductsSearchRequestGET(w, r, db)
+				return
+			}
+			if performanceWindowPath.MatchString(r.URL.Path) {
+				ProductPerformanceWindowRequestGET(w, r, db)
+				return
+			}
+			if historyPath.MatchString(r.URL.Path) {
+				ProductHistoryRequestGET(w, r, db)
+				return
+			}
+			if statusChangesPath.MatchString(r.URL.Path) {
+				ProductStatusChangesRequestGET(w, r, db)
+				return
+			}
+			if byEANPath.MatchString(r.URL.Path) {
+				ProductByEANRequestGET(w, r, db)
+				return
+			}
 			ProductsRequestGET(w, r, db)
 		case http.MethodPost:
+			if mergePath.MatchString(r.URL.Path) {
+				ProductsMergeRequestPOST(w, r, db)
+				return
+			}
+			if revertPath.MatchString(r.URL.Path) {
+				ProductRevertRequestPOST(w, r, db)
+				return
+			}
 			ProductsRequestPOST(w, r, db)
 		case http.MethodPatch:
 			ProductsRequestPATCH(w, r, db)
@@ -114,14 +145,15 @@ func ProductsRequestGET(w http.ResponseWriter, r *http.Request, db *sql.DB) {
 // ProductsRequestPOST creates a new product in the database and sends a response to the client.
 //
 //	@Summary		Create a new product
-//	@Description	Adds a new product to the database.
+//	@Description	Adds a new product to the database. An EAN code must be an EAN-8, UPC-A or EAN-13 code with a
+//	@Description	valid check digit.
 //	@Tags			Products
 //	@Accept			json
 //	@Produce		json
 //	@Security		BearerAuth
 //	@Param			product	body	ProductPOSTRequest	true	"New product information"
 //	@Success		201		"Product created successfully"
-//	@Failure		400		{string}	string	"Invalid request data"
+//	@Failure		400		{string}	string	"Invalid request data or EAN code"
 //	@Failure		403		{string}	string	"Researcher cannot create public products"
 //	@Failure		409		{string}	string	"Product already exists"
 //	@Failure		500		{string}	string	"Could not create product."
@@ -139,6 +171,16 @@ func ProductsRequestPOST(w http.ResponseWriter, r *http.Request, db *sql.DB) {
 		return
 	}
 
+	// Check the check digit of the EAN code, so a scanned barcode finds the product.
+	if product.EANCode != "" {
+		err = ean.Validate(product.EANCode)
+		if err != nil {
+			http.Error(w, "Invalid EAN code: "+err.Error(), http.StatusBadRequest)
+			log.Println("Invalid EAN code: " + err.Error())
+			return
+		}
+	}
+
 	// Get the total product count.
 	var id int
 	err = db.QueryRow("SELECT COUNT(*) FROM products;").Scan(&id)
@@ -188,7 +230,13 @@ func ProductsRequestPOST(w http.ResponseWriter, r *http.Request, db *sql.DB) {
 // ProductsRequestPATCH updates an existing product in the database and sends a response to the client.
 //
 //	@Summary		Update a product
-//	@Description	Updates fields of an existing product.
+//	@Description	Updates fields of an existing product. The changed fields are recorded in the history of the
+//	@Description	product in the same transaction, as a new revision. A change of the status must be allowed from
+//	@Description	the current status, and is recorded with the reason of the request. A product can not be retired
+//	@Description	while it, or a bundle containing it, is in a planned or in progress test of its team. When a
+//	@Description	product is discontinued or retired, the bundles of the team containing it follow, and are returned.
+//	@Description	A change of the temperature range updates the flags of the results of the product, and the
+//	@Description	temperature windows of the bundles containing it.
 //	@Tags			Products
 //	@Accept			json
 //	@Produce		json
@@ -196,9 +244,9 @@ func ProductsRequestPOST(w http.ResponseWriter, r *http.Request, db *sql.DB) {
 //	@Param			id		query		int					true	"Product ID"
 //	@Param			product	body		ProductPATCHRequest	true	"Product update fields"
 //	@Success		200		{string}	string				"Product updated successfully"
-//	@Failure		400		{string}	string				"Invalid request data"
+//	@Failure		400		{string}	string				"Invalid request data or EAN code"
 //	@Failure		500		{string}	string				"Could not update product."
-//	@Failure		409		{string}	string				"Detected a conflict for the current product, please refresh."
+//	@Failure		409		{string}	string				"Conflict with a newer version, the status or an open test"
 //	@Router			/products [patch]
 func ProductsRequestPATCH(w http.ResponseWriter, r *http.Request, db *sql.DB) {
 	// Get the product ID from the URL parameter.
@@ -246,6 +294,7 @@ func ProductsRequestPATCH(w http.ResponseWriter, r *http.Request, db *sql.DB) {
 	updatedFields, newValues, i = utils.CreateUpdateQuery(w, productUpdateRequest.Updates, updatedFields, newValues, i)
 
 	var newVersion time.Time
+	var bundles []int // The bundles whose status changed with the product.
 
 	// Check if the product is being updated to a public product and if the product is unique,
 	// which will not trigger the DirectReferenceUpdateProductAppearances function.
@@ -255,7 +304,10 @@ func ProductsRequestPATCH(w http.ResponseWriter, r *http.Request, db *sql.DB) {
 	if !uniqueProduct && ((productUpdateRequest.Updates["is_public"] != existingProduct.IsPublic) &&
 		productUpdateRequest.Updates["is_public"] != nil) {
 		// Direct reference update for products, tests, and rankings that an updated product is part of.
-		DirectReferenceUpdateProductAppearances(w, r, db, existingProduct.EANCode, productID)
+		err = DirectReferenceUpdateProductAppearances(w, r, db, existingProduct.EANCode, productID)
+		if err != nil {
+			return
+		}
 		newVersion = productUpdateVersion
 	} else {
 		// Create the query to update the product in the database.
@@ -267,11 +319,25 @@ func ProductsRequestPATCH(w http.ResponseWriter, r *http.Request, db *sql.DB) {
 		)
 		newValues = append(newValues, productID, existingProductVersion)
 
-		// Execute the query and get the new version of the product.
-		err = db.QueryRow(query, newValues...).Scan(&newVersion)
+		// Get the changed fields and status, so they can be recorded in the history of the product.
+		userID := middleware.GetUserID(w, r, db)
+		changes, err := productChanges(existingProduct, productUpdateRequest.Updates, userID)
 		if err != nil {
+			http.Error(w, "Could not record the history of the product.", http.StatusInternalServerError)
+			log.Println("Could not record the history of the product: " + err.Error())
+			return
+		}
+		statusChange := newStatusChange(productUpdateRequest.Updates, existingProduct, productUpdateRequest.Reason,
+			userID)
+
+		// Execute the query and record the history in the same transaction, and get the new version of the product.
+		newVersion, _, bundles, err = updateProduct(db, query, newValues, changes, statusChange)
+		if errors.Is(err, errProductConflict) {
 			http.Error(w, "Could not update the product because of a conflict, please refresh.", http.StatusConflict)
 			log.Println("Could not update the product because of a conflict, please refresh: " + err.Error())
+		} else if err != nil {
+			http.Error(w, "Could not update the product.", http.StatusInternalServerError)
+			log.Println("Could not update the product: " + err.Error())
 		}
 	}
 
@@ -281,6 +347,7 @@ func ProductsRequestPATCH(w http.ResponseWriter, r *http.Request, db *sql.DB) {
 		err = json.NewEncoder(w).Encode(map[string]interface{}{
 			"message": "Product updated successfully",
 			"version": newVersion,
+			"bundles": bundles,
 		})
 		if err != nil {
 			http.Error(w, "Could not JSON encode the response.", http.StatusInternalServerError)
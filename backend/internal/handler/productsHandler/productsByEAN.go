@@ -0,0 +1,69 @@
+package productsHandler
+
+import (
+	"backend/internal/middleware"
+	"backend/internal/services/ean"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"regexp"
+)
+
+var byEANPath = regexp.MustCompile(`^/products/by-ean/([^/]+)/?$`)
+
+// ProductByEANRequestGET is the request handler for looking up a product by its barcode.
+//
+//	@Summary		Get a product by its EAN code
+//	@Description	Retrieves the product with an EAN-8, UPC-A or EAN-13 code, such as a barcode scanned from a box of
+//	@Description	wax. A UPC-A code also finds the product stored with the same EAN-13 code, which has a leading 0,
+//	@Description	and the other way around. The product of the team is returned before a public product. When no
+//	@Description	product has the code, the app can create a new product with it.
+//	@Tags			Products
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			code	path		string			true	"EAN code"
+//	@Success		200		{object}	domain.Product	"The product with the EAN code"
+//	@Failure		400		{string}	string			"Invalid EAN code"
+//	@Failure		404		{string}	string			"No product has this EAN code."
+//	@Failure		500		{string}	string			"Could not retrieve the product."
+//	@Router			/products/by-ean/{code} [get]
+func ProductByEANRequestGET(w http.ResponseWriter, r *http.Request, db *sql.DB) {
+	code := byEANPath.FindStringSubmatch(r.URL.Path)[1]
+
+	// Get the user's team membership.
+	team := middleware.GetUserTeamRole(w, r, db)
+
+	err := ean.Validate(code)
+	if err != nil {
+		http.Error(w, "Invalid EAN code: "+err.Error(), http.StatusBadRequest)
+		log.Println("Invalid EAN code: " + err.Error())
+		return
+	}
+
+	// Look up the code in both its UPC-A and EAN-13 form.
+	codes := ean.Equivalent(code)
+	if len(codes) == 1 {
+		codes = append(codes, code)
+	}
+	product, err := queryAndScanProduct(db, `SELECT * FROM products
+							WHERE ean_code IN ($1, $2) AND (testing_team = $3 OR is_public)
+							ORDER BY testing_team = $3 DESC, id
+							LIMIT 1;`, codes[0], codes[1], team)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "No product has this EAN code.", http.StatusNotFound)
+		log.Println("No product has the EAN code " + code)
+		return
+	} else if err != nil {
+		http.Error(w, "Could not retrieve the product.", http.StatusInternalServerError)
+		log.Println("Could not retrieve the product: " + err.Error())
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(product)
+	if err != nil {
+		http.Error(w, "Could not JSON encode the response.", http.StatusInternalServerError)
+		log.Println("Could not JSON encode the response: " + err.Error())
+	}
+}
@@ -0,0 +1,103 @@
+package productsHandler
+
+import (
+	"backend/internal/utils"
+	"database/sql"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestProductByEANRequestGET(t *testing.T) {
+	mockDB, mock := utils.InitMockDB(t)
+
+	version := time.Date(2025, 1, 12, 0, 0, 0, 0, time.UTC)
+	lookupMock := func(code string, equivalent string) *sqlmock.ExpectedQuery {
+		return mock.ExpectQuery("SELECT \\* FROM products WHERE ean_code IN \\(\\$1, \\$2\\) "+
+			"AND \\(testing_team = \\$3 OR is_public\\)").
+			WithArgs(code, equivalent, 1)
+	}
+
+	tests := []struct {
+		name         string
+		path         string
+		setupMocks   func()
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:         "Status OK - EAN-13",
+			path:         "/products/by-ean/7038010055720",
+			expectedCode: http.StatusOK,
+			expectedBody: `{"id":3,"name":"Blue Wax","brand":"Swix","ean_code":"7038010055720"`,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				lookupMock("7038010055720", "7038010055720").WillReturnRows(sqlmock.NewRows(productColumns).
+					AddRow(3, "Blue Wax", "Swix", "7038010055720", "", "", true, "solid", -2.0, -8.0, 2, version,
+						"active"))
+			},
+		},
+		{
+			name:         "Status OK - UPC-A finds the EAN-13 code",
+			path:         "/products/by-ean/036000291452",
+			expectedCode: http.StatusOK,
+			expectedBody: `"ean_code":"0036000291452"`,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				lookupMock("036000291452", "0036000291452").WillReturnRows(sqlmock.NewRows(productColumns).
+					AddRow(4, "Klister", "Rode", "0036000291452", "", "", false, "gel", 5.0, -1.0, 1, version,
+						"active"))
+			},
+		},
+		{
+			name:         "Status not found",
+			path:         "/products/by-ean/96385074",
+			expectedCode: http.StatusNotFound,
+			expectedBody: "No product has this EAN code.",
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				lookupMock("96385074", "96385074").WillReturnError(sql.ErrNoRows)
+			},
+		},
+		{
+			name:         "Status bad request - check digit",
+			path:         "/products/by-ean/7038010055721",
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Invalid EAN code: the check digit of 7038010055721 should be 0",
+			setupMocks: func() {
+				AuthenticationMock(mock)
+			},
+		},
+		{
+			name:         "Status bad request - not a barcode",
+			path:         "/products/by-ean/WAX-42",
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Invalid EAN code",
+			setupMocks: func() {
+				AuthenticationMock(mock)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.Header.Set("Authorization", "Bearer mockToken")
+			rr := httptest.NewRecorder()
+
+			ProductsHandler(mockDB).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedCode, rr.Code)
+			assert.Contains(t, rr.Body.String(), tt.expectedBody)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"backend/internal/domain"
 	"backend/internal/middleware"
 	"backend/internal/resources"
+	"backend/internal/services/ean"
 	"database/sql"
 	"encoding/json"
 	"fmt"
@@ -166,6 +167,15 @@ func ValidateProductPATCHRequestBody(db *sql.DB,
 		return fmt.Errorf("invalid PATCH request body values, %d", http.StatusBadRequest), http.StatusBadRequest
 	}
 
+	// Check the check digit of a new EAN code.
+	if update.EANCode != nil && *update.EANCode != "" {
+		err = ean.Validate(*update.EANCode)
+		if err != nil {
+			log.Println("Invalid EAN code: " + err.Error())
+			return fmt.Errorf("invalid EAN code, %w", err), http.StatusBadRequest
+		}
+	}
+
 	// Check if the public product exists.
 	if existingProduct.IsPublic == true && productUpdateRequest.Updates["is_public"] == false {
 		log.Println("Product is public and cannot be made private.")
@@ -184,6 +194,12 @@ func ValidateProductPATCHRequestBody(db *sql.DB,
 		return fmt.Errorf("user cannot update this product, %d", http.StatusUnauthorized), http.StatusUnauthorized
 	}
 
+	// Check that the product can move to its new status.
+	err, code := validateStatusUpdate(db, update, existingProduct)
+	if err != nil {
+		return err, code
+	}
+
 	eanCode, _ := productUpdateRequest.Updates["ean_code"].(string)
 	name, _ := productUpdateRequest.Updates["name"].(string)
 
@@ -217,13 +233,16 @@ func isProductUnique(db *sql.DB, productUpdateRequest ProductPATCHRequest, team
 	return true
 }
 
-// DirectReferenceUpdateProductAppearances updates the appearances of the updated product in the rankings table and
-// deletes the private product from the products table.
-func DirectReferenceUpdateProductAppearances(w http.ResponseWriter, r *http.Request, db *sql.DB, eanCode string, id int) {
+// DirectReferenceUpdateProductAppearances merges a private product into the public product with the same EAN code,
+// so its results, bundle layers and other references move to the public product and the private product is deleted.
+// The merge is recorded in the history of the public product.
+func DirectReferenceUpdateProductAppearances(w http.ResponseWriter, r *http.Request, db *sql.DB, eanCode string,
+	id int) error {
 	var publicProduct domain.Product
 
 	// Get the user's role and team.
 	team := middleware.GetUserTeamRole(w, r, db)
+	userID := middleware.GetUserID(w, r, db)
 
 	privateQuery := "SELECT * FROM products WHERE id = $1 AND ean_code = $2 AND is_public = $3 AND testing_team = $4;"
 	// Get the private product from the database.
@@ -231,7 +250,7 @@ func DirectReferenceUpdateProductAppearances(w http.ResponseWriter, r *http.Requ
 	if err != nil {
 		http.Error(w, resources.CouldNotRetrieveProduct, http.StatusNotFound)
 		log.Println("Could not retrieve the private product: " + err.Error())
-		return
+		return err
 	}
 
 	publicQuery := "SELECT * FROM products WHERE ean_code = $1 AND is_public = $2;"
@@ -240,14 +259,46 @@ func DirectReferenceUpdateProductAppearances(w http.ResponseWriter, r *http.Requ
 	if err != nil {
 		http.Error(w, resources.CouldNotRetrieveProduct, http.StatusNotFound)
 		log.Println("Could not retrieve the public product: " + err.Error())
-		return
+		return err
 	}
 
-	// Update the appearances of the updated product in the rankings table.
-	_, err = db.Exec("UPDATE test_ranks SET product_id = $1 WHERE product_id = $2;",
-		publicProduct.ID, privateProduct.ID)
+	tx, err := db.Begin()
+	if err != nil {
+		http.Error(w, resources.TransactionStartFailed, http.StatusInternalServerError)
+		log.Println(resources.TransactionStartFailed + ": " + err.Error())
+		return err
+	}
+	defer func() {
+		if err != nil {
+			rollbackErr := tx.Rollback()
+			if rollbackErr != nil {
+				log.Println(resources.RollbackFailed + rollbackErr.Error())
+			}
+		}
+	}()
+
+	// Move the appearances of the private product to the public product, and delete the private product.
+	merged, err := mergeProducts(tx, publicProduct.ID, []int{privateProduct.ID})
+	if err != nil {
+		http.Error(w, "Could not merge the product into the public product.", http.StatusInternalServerError)
+		log.Println("Could not merge the product into the public product: " + err.Error())
+		return err
+	}
 
-	// Delete the private product from the database.
-	_, err = db.Exec("DELETE FROM products WHERE id = $1 AND testing_team = $2;",
-		privateProduct.ID, team)
+	// The history of the private product is deleted with it, so the merge is recorded in the public product's.
+	err = recordMergedProduct(tx, publicProduct.ID, privateProduct.ID, userID)
+	if err != nil {
+		http.Error(w, "Could not record the history of the product.", http.StatusInternalServerError)
+		log.Println("Could not record the history of the product: " + err.Error())
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		http.Error(w, resources.TransactionCommitFailed, http.StatusInternalServerError)
+		log.Println(resources.TransactionCommitFailed + ": " + err.Error())
+		return err
+	}
+	productsMerged(merged.Tests)
+	return nil
 }
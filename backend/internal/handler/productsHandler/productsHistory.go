@@ -0,0 +1,171 @@
+package productsHandler
+
+import (
+	"backend/internal/middleware"
+	"backend/internal/resources"
+	"backend/internal/utils"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	historyPath = regexp.MustCompile(`^/products/(\d+)/history/?$`)
+	revertPath  = regexp.MustCompile(`^/products/(\d+)/revert/(\d+)/?$`)
+)
+
+// ProductHistoryRequestGET is the request handler for the history of a product.
+//
+//	@Summary		Get the history of a product
+//	@Description	Retrieves the revisions of a product of the team, oldest first. Each revision is an update of the
+//	@Description	product, with the old and new value of every field it changed, the user who made it and when.
+//	@Tags			Products
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int				true	"Product ID"
+//	@Success		200	{array}		ProductRevision	"The revisions of the product"
+//	@Failure		404	{string}	string			"Could not retrieve the product"
+//	@Failure		500	{string}	string			"Could not retrieve the history of the product."
+//	@Router			/products/{id}/history [get]
+func ProductHistoryRequestGET(w http.ResponseWriter, r *http.Request, db *sql.DB) {
+	productID, _ := strconv.Atoi(historyPath.FindStringSubmatch(r.URL.Path)[1])
+
+	// Get the user's team membership.
+	team := middleware.GetUserTeamRole(w, r, db)
+
+	// Only the team of the product can see who changed it.
+	var count int
+	err := db.QueryRow("SELECT COUNT(*) FROM products WHERE id = $1 AND testing_team = $2;", productID, team).
+		Scan(&count)
+	if err != nil || count == 0 {
+		http.Error(w, resources.CouldNotRetrieveProduct, http.StatusNotFound)
+		log.Printf("Could not retrieve the product %d of team %d", productID, team)
+		return
+	}
+
+	revisions, err := getProductHistory(db, productID)
+	if err != nil {
+		http.Error(w, "Could not retrieve the history of the product.", http.StatusInternalServerError)
+		log.Println("Could not retrieve the history of the product: " + err.Error())
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(revisions)
+	if err != nil {
+		http.Error(w, "Could not JSON encode the response.", http.StatusInternalServerError)
+		log.Println("Could not JSON encode the response: " + err.Error())
+	}
+}
+
+// ProductRevertRequestPOST is the request handler for reverting a product to an earlier revision.
+//
+//	@Summary		Revert a product to a revision
+//	@Description	Brings a product back to how it was after a revision, by setting every field changed since the
+//	@Description	revision to its value at the revision. Revision 0 is the product before any recorded change. The
+//	@Description	revert is validated like an update of the product, and is recorded in its history as a new revision.
+//	@Description	Products merged into the product are not split off again.
+//	@Tags			Products
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id			path		int		true	"Product ID"
+//	@Param			revision	path		int		true	"The revision to revert to"
+//	@Success		200			{string}	string	"Product reverted successfully"
+//	@Failure		400			{string}	string	"The product is already at this revision."
+//	@Failure		401			{string}	string	"Validation error: user cannot update this product"
+//	@Failure		404			{string}	string	"Could not find the revision of the product."
+//	@Failure		409			{string}	string	"Could not revert the product because of a conflict, please refresh."
+//	@Failure		500			{string}	string	"Could not revert the product."
+//	@Router			/products/{id}/revert/{revision} [post]
+func ProductRevertRequestPOST(w http.ResponseWriter, r *http.Request, db *sql.DB) {
+	match := revertPath.FindStringSubmatch(r.URL.Path)
+	productID, _ := strconv.Atoi(match[1])
+	revision, _ := strconv.Atoi(match[2])
+
+	// Get the team role of the user from the session token.
+	team := middleware.GetUserTeamRole(w, r, db)
+
+	// Get the existing product from the database.
+	existingProduct := GetProductWithID(w, db, productID)
+	if existingProduct.ID == 0 {
+		return
+	}
+
+	latestRevision, err := getLatestRevision(db, productID)
+	if err != nil {
+		http.Error(w, "Could not retrieve the history of the product.", http.StatusInternalServerError)
+		log.Println("Could not retrieve the history of the product: " + err.Error())
+		return
+	}
+	if revision > latestRevision {
+		http.Error(w, "Could not find the revision of the product.", http.StatusNotFound)
+		log.Printf("Could not find revision %d of product %d", revision, productID)
+		return
+	}
+
+	updates, err := getRevertUpdates(db, productID, revision)
+	if err != nil {
+		http.Error(w, "Could not retrieve the history of the product.", http.StatusInternalServerError)
+		log.Println("Could not retrieve the history of the product: " + err.Error())
+		return
+	}
+	if len(updates) == 0 {
+		http.Error(w, "The product is already at this revision.", http.StatusBadRequest)
+		log.Println("The product is already at this revision")
+		return
+	}
+
+	// Validate the revert like any other update of the product.
+	revertRequest := ProductPATCHRequest{Updates: updates, Version: existingProduct.Version}
+	var code int
+	err, code = ValidateProductPATCHRequestBody(db, revertRequest, existingProduct, team)
+	if err != nil {
+		http.Error(w, "Validation error: "+err.Error(), code)
+		return
+	}
+
+	// Create the query to update the product in the database.
+	var updatedFields []string
+	var newValues []interface{}
+	i := 1 // Index for the newValues array.
+	updatedFields, newValues, i = utils.CreateUpdateQuery(w, updates, updatedFields, newValues, i)
+	query := fmt.Sprintf("UPDATE products SET %s WHERE id = $%d AND version = $%d RETURNING version",
+		strings.Join(updatedFields, ", "), i, i+1)
+	newValues = append(newValues, productID, existingProduct.Version)
+
+	userID := middleware.GetUserID(w, r, db)
+	changes, err := productChanges(existingProduct, updates, userID)
+	if err != nil {
+		http.Error(w, "Could not revert the product.", http.StatusInternalServerError)
+		log.Println("Could not record the history of the product: " + err.Error())
+		return
+	}
+	statusChange := newStatusChange(updates, existingProduct, fmt.Sprintf("Reverted to revision %d.", revision), userID)
+
+	newVersion, newRevision, bundles, err := updateProduct(db, query, newValues, changes, statusChange)
+	if errors.Is(err, errProductConflict) {
+		http.Error(w, "Could not revert the product because of a conflict, please refresh.", http.StatusConflict)
+		log.Println("Could not revert the product because of a conflict, please refresh: " + err.Error())
+		return
+	} else if err != nil {
+		http.Error(w, "Could not revert the product.", http.StatusInternalServerError)
+		log.Println("Could not revert the product: " + err.Error())
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":  "Product reverted successfully",
+		"revision": newRevision,
+		"version":  newVersion,
+		"bundles":  bundles,
+	})
+	if err != nil {
+		http.Error(w, "Could not JSON encode the response.", http.StatusInternalServerError)
+		log.Println("Could not JSON encode the response: " + err.Error())
+	}
+}
@@ -0,0 +1,391 @@
+package productsHandler
+
+import (
+	"backend/internal/domain"
+	"backend/internal/handler/testsHandler"
+	"backend/internal/resources"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+)
+
+// errProductConflict is returned when a product was changed since it was read.
+var errProductConflict = errors.New("the product was changed since it was read")
+
+// mergedProductField is the field of the history of a product recording a product that was merged into it.
+const mergedProductField = "merged_product"
+
+// productFieldValues returns the values of the fields of a product that can be updated, by their column names.
+func productFieldValues(product domain.Product) map[string]interface{} {
+	return map[string]interface{}{
+		"name":             product.Name,
+		"brand":            product.Brand,
+		"ean_code":         product.EANCode,
+		"image_url":        product.ImageURL,
+		"type":             product.Type,
+		"high_temperature": product.HighTemperature,
+		"low_temperature":  product.LowTemperature,
+		"comment":          product.Comment,
+		"is_public":        product.IsPublic,
+		"status":           product.Status,
+	}
+}
+
+// productChanges returns the changes of the fields of a product made by the updates, sorted by field. Fields that
+// are updated to the value they already have are left out.
+func productChanges(existingProduct domain.Product, updates map[string]interface{}, userID int,
+) ([]domain.ProductChange, error) {
+	values := productFieldValues(existingProduct)
+	changedAt := time.Now()
+
+	var changes []domain.ProductChange
+	for field, value := range updates {
+		oldValue, err := json.Marshal(values[field])
+		if err != nil {
+			return nil, err
+		}
+		newValue, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		if string(oldValue) == string(newValue) {
+			continue
+		}
+
+		change := domain.ProductChange{
+			ProductID: existingProduct.ID,
+			Field:     field,
+			OldValue:  oldValue,
+			NewValue:  newValue,
+			ChangedAt: changedAt,
+		}
+		if userID != 0 {
+			change.ChangedBy = &userID
+		}
+		changes = append(changes, change)
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Field < changes[j].Field
+	})
+	return changes, nil
+}
+
+// updateProduct runs the update query of a product and records the changes in its history as the next revision, in
+// the same transaction. A change of the status is recorded with its reason, and carried over to the bundles
+// containing the product, and a change of the temperature range refreshes the flags of the results of the product and
+// the temperature windows of the bundles containing it. It returns the new version of the product, the revision,
+// which is 0 when no field changed, and the bundles whose status changed with it.
+func updateProduct(db *sql.DB, query string, values []interface{}, changes []domain.ProductChange,
+	statusChange *domain.ProductStatusChange) (newVersion time.Time, revision int, bundles []int, err error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return time.Time{}, 0, nil, fmt.Errorf("%s: %w", resources.TransactionStartFailed, err)
+	}
+	defer func() {
+		if err != nil {
+			rollbackErr := tx.Rollback()
+			if rollbackErr != nil {
+				log.Println(resources.RollbackFailed + rollbackErr.Error())
+			}
+		}
+	}()
+
+	err = tx.QueryRow(query, values...).Scan(&newVersion)
+	if errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, 0, nil, errProductConflict
+	} else if err != nil {
+		return time.Time{}, 0, nil, err
+	}
+
+	if len(changes) > 0 {
+		revision, err = insertProductChanges(tx, changes)
+		if err != nil {
+			return time.Time{}, 0, nil, fmt.Errorf("failed to record the history of the product: %w", err)
+		}
+	}
+
+	if temperatureRangeChanged(changes) {
+		err = refreshTemperatureRangeFlags(tx, changes[0].ProductID)
+		if err != nil {
+			return time.Time{}, 0, nil, fmt.Errorf("failed to update the temperature range flags: %w", err)
+		}
+		err = refreshBundleTemperatureWindows(tx, changes[0].ProductID, changes[0].ChangedBy)
+		if err != nil {
+			return time.Time{}, 0, nil, fmt.Errorf("failed to update the temperature windows of the bundles: %w", err)
+		}
+	}
+
+	if statusChange != nil {
+		err = insertStatusChange(tx, *statusChange)
+		if err != nil {
+			return time.Time{}, 0, nil, fmt.Errorf("failed to record the status change of the product: %w", err)
+		}
+		bundles, err = cascadeStatusToBundles(tx, *statusChange)
+		if err != nil {
+			return time.Time{}, 0, nil, fmt.Errorf("failed to change the status of the bundles: %w", err)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return time.Time{}, 0, nil, fmt.Errorf("%s: %w", resources.TransactionCommitFailed, err)
+	}
+	return newVersion, revision, bundles, nil
+}
+
+// UpdateProductFields updates fields of a product on behalf of another entity, such as the name and the temperature
+// range of the product row of a bundle, in the transaction of that change. The changed fields are recorded in the
+// history of the product as the next revision, and the product gets a new version.
+func UpdateProductFields(tx *sql.Tx, productID int, updates map[string]interface{}, userID int) error {
+	existingProduct, err := queryAndScanProduct(tx, "SELECT * FROM products WHERE id = $1 FOR UPDATE;", productID)
+	if err != nil {
+		return err
+	}
+	changes, err := productChanges(existingProduct, updates, userID)
+	if err != nil || len(changes) == 0 {
+		return err
+	}
+
+	var fields []string
+	var values []interface{}
+	for _, change := range changes {
+		values = append(values, updates[change.Field])
+		fields = append(fields, fmt.Sprintf("%s = $%d", change.Field, len(values)))
+	}
+	values = append(values, time.Now(), productID)
+	fields = append(fields, fmt.Sprintf("version = $%d", len(values)-1))
+	_, err = tx.Exec(fmt.Sprintf("UPDATE products SET %s WHERE id = $%d;", strings.Join(fields, ", "), len(values)),
+		values...)
+	if err != nil {
+		return err
+	}
+
+	if _, err = insertProductChanges(tx, changes); err != nil {
+		return fmt.Errorf("failed to record the history of the product: %w", err)
+	}
+	if temperatureRangeChanged(changes) {
+		if err = refreshTemperatureRangeFlags(tx, productID); err != nil {
+			return fmt.Errorf("failed to update the temperature range flags: %w", err)
+		}
+	}
+	return nil
+}
+
+// recordMergedProduct records a product merged into another as a revision of the product it was merged into, which
+// gets a new version. A merge can not be reverted.
+func recordMergedProduct(tx *sql.Tx, targetID int, sourceID int, userID int) error {
+	newValue, err := json.Marshal(sourceID)
+	if err != nil {
+		return err
+	}
+	change := domain.ProductChange{
+		ProductID: targetID,
+		Field:     mergedProductField,
+		OldValue:  json.RawMessage("null"),
+		NewValue:  newValue,
+		ChangedAt: time.Now(),
+	}
+	if userID != 0 {
+		change.ChangedBy = &userID
+	}
+
+	_, err = tx.Exec("UPDATE products SET version = $1 WHERE id = $2;", change.ChangedAt, targetID)
+	if err != nil {
+		return err
+	}
+	_, err = insertProductChanges(tx, []domain.ProductChange{change})
+	return err
+}
+
+// temperatureRangeChanged reports whether the changes of a product include its rated temperature range.
+func temperatureRangeChanged(changes []domain.ProductChange) bool {
+	for _, change := range changes {
+		if change.Field == "low_temperature" || change.Field == "high_temperature" {
+			return true
+		}
+	}
+	return false
+}
+
+// refreshTemperatureRangeFlags checks the results of the tests a product is ranked in against its new temperature
+// range, and updates their flags.
+func refreshTemperatureRangeFlags(tx *sql.Tx, productID int) error {
+	rows, err := tx.Query("SELECT DISTINCT test_id FROM test_ranks WHERE product_id = $1 ORDER BY test_id;",
+		productID)
+	if err != nil {
+		return err
+	}
+
+	var testIDs []int
+	for rows.Next() {
+		var testID int
+		if err = rows.Scan(&testID); err != nil {
+			_ = rows.Close()
+			return err
+		}
+		testIDs = append(testIDs, testID)
+	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+	_ = rows.Close()
+
+	for _, testID := range testIDs {
+		if _, err = testsHandler.UpdateTemperatureRangeFlags(tx, testID); err != nil {
+			return fmt.Errorf("failed to update the flags of test %d: %w", testID, err)
+		}
+	}
+	return nil
+}
+
+// refreshBundleTemperatureWindows recomputes the temperature windows of the bundles containing a product as a layer,
+// from the temperature ranges of their layers, and updates the product rows of the bundles whose window changed. A
+// missing bound leaves that side open, and bundles whose layers no longer have a temperature in common have no window.
+func refreshBundleTemperatureWindows(tx *sql.Tx, productID int, changedBy *int) error {
+	rows, err := tx.Query(`SELECT b.product_id, MAX(p.low_temperature), MIN(p.high_temperature)
+							FROM bundles b
+							JOIN product_bundles l ON l.bundle_id = b.id
+							JOIN products p ON p.id = l.product_id
+							WHERE b.id IN (SELECT bundle_id FROM product_bundles WHERE product_id = $1)
+							GROUP BY b.product_id ORDER BY b.product_id;`, productID)
+	if err != nil {
+		return err
+	}
+
+	windows := map[int]map[string]interface{}{}
+	var bundleProducts []int
+	for rows.Next() {
+		var (
+			bundleProduct int
+			low, high     *float64
+		)
+		if err = rows.Scan(&bundleProduct, &low, &high); err != nil {
+			_ = rows.Close()
+			return err
+		}
+		if low != nil && high != nil && *low > *high {
+			low, high = nil, nil
+		}
+		windows[bundleProduct] = map[string]interface{}{"low_temperature": low, "high_temperature": high}
+		bundleProducts = append(bundleProducts, bundleProduct)
+	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+	_ = rows.Close()
+
+	userID := 0
+	if changedBy != nil {
+		userID = *changedBy
+	}
+	for _, bundleProduct := range bundleProducts {
+		if err = UpdateProductFields(tx, bundleProduct, windows[bundleProduct], userID); err != nil {
+			return fmt.Errorf("failed to update the bundle product %d: %w", bundleProduct, err)
+		}
+	}
+	return nil
+}
+
+// insertProductChanges records the changes of a product as its next revision, and returns the revision.
+func insertProductChanges(tx *sql.Tx, changes []domain.ProductChange) (int, error) {
+	var revision int
+	err := tx.QueryRow("SELECT COALESCE(MAX(revision), 0) + 1 FROM product_history WHERE product_id = $1;",
+		changes[0].ProductID).Scan(&revision)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, change := range changes {
+		_, err = tx.Exec(`INSERT INTO product_history (product_id, revision, field, old_value, new_value, changed_by,
+                             changed_at)
+							VALUES ($1, $2, $3, $4, $5, $6, $7);`,
+			change.ProductID,
+			revision,
+			change.Field,
+			string(change.OldValue),
+			string(change.NewValue),
+			change.ChangedBy,
+			change.ChangedAt)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return revision, nil
+}
+
+// getProductHistory retrieves the revisions of a product, oldest first.
+func getProductHistory(db *sql.DB, productID int) ([]ProductRevision, error) {
+	rows, err := db.Query(`SELECT revision, field, old_value, new_value, changed_by, changed_at
+							FROM product_history WHERE product_id = $1 ORDER BY revision, field;`, productID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	revisions := []ProductRevision{}
+	for rows.Next() {
+		var (
+			revision           ProductRevision
+			change             ProductFieldChange
+			oldValue, newValue []byte
+		)
+		if err = rows.Scan(&revision.Revision, &change.Field, &oldValue, &newValue, &revision.ChangedBy,
+			&revision.ChangedAt); err != nil {
+			return nil, err
+		}
+		change.OldValue, change.NewValue = oldValue, newValue
+
+		last := len(revisions) - 1
+		if last < 0 || revisions[last].Revision != revision.Revision {
+			revisions = append(revisions, revision)
+			last++
+		}
+		revisions[last].Changes = append(revisions[last].Changes, change)
+	}
+	return revisions, rows.Err()
+}
+
+// getLatestRevision retrieves the latest revision of a product, or 0 when it has never been changed.
+func getLatestRevision(db *sql.DB, productID int) (int, error) {
+	var revision int
+	err := db.QueryRow("SELECT COALESCE(MAX(revision), 0) FROM product_history WHERE product_id = $1;", productID).
+		Scan(&revision)
+	return revision, err
+}
+
+// getRevertUpdates returns the updates that bring a product back to a revision: every field changed after the
+// revision is set to the value it had before its first change after the revision. Revision 0 is the product before
+// any recorded change. Merges of other products into the product are kept.
+func getRevertUpdates(db *sql.DB, productID int, revision int) (map[string]interface{}, error) {
+	rows, err := db.Query(`SELECT DISTINCT ON (field) field, old_value
+							FROM product_history WHERE product_id = $1 AND revision > $2
+							  AND field <> '`+mergedProductField+`'
+							ORDER BY field, revision;`, productID, revision)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	updates := map[string]interface{}{}
+	for rows.Next() {
+		var (
+			field    string
+			oldValue []byte
+			value    interface{}
+		)
+		if err = rows.Scan(&field, &oldValue); err != nil {
+			return nil, err
+		}
+		if err = json.Unmarshal(oldValue, &value); err != nil {
+			return nil, err
+		}
+		updates[field] = value
+	}
+	return updates, rows.Err()
+}
@@ -0,0 +1,21 @@
+package productsHandler
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ProductRevision is an update of a product, with the fields it changed.
+type ProductRevision struct {
+	Revision  int                  `json:"revision"`
+	ChangedBy *int                 `json:"changed_by"`
+	ChangedAt time.Time            `json:"changed_at"`
+	Changes   []ProductFieldChange `json:"changes"`
+}
+
+// ProductFieldChange is the old and new value of a field changed in a revision.
+type ProductFieldChange struct {
+	Field    string          `json:"field"`
+	OldValue json.RawMessage `json:"old_value" swaggertype:"object"`
+	NewValue json.RawMessage `json:"new_value" swaggertype:"object"`
+}
@@ -0,0 +1,296 @@
+package productsHandler
+
+import (
+	"backend/internal/utils"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func Test_productChanges(t *testing.T) {
+	changes, err := productChanges(testProduct, map[string]interface{}{
+		"status":           "retired",
+		"high_temperature": 1.0,
+		"low_temperature":  -4.5,
+		"name":             "Product1",
+	}, 1)
+	assert.NoError(t, err)
+	if assert.Len(t, changes, 2) {
+		assert.Equal(t, "low_temperature", changes[0].Field)
+		assert.Equal(t, "-1", string(changes[0].OldValue))
+		assert.Equal(t, "-4.5", string(changes[0].NewValue))
+		assert.Equal(t, "status", changes[1].Field)
+		assert.Equal(t, `"Status1"`, string(changes[1].OldValue))
+		assert.Equal(t, `"retired"`, string(changes[1].NewValue))
+		assert.Equal(t, 1, *changes[1].ChangedBy)
+	}
+
+	changes, err = productChanges(testProduct, map[string]interface{}{"comment": "Comment1"}, 0)
+	assert.NoError(t, err)
+	assert.Empty(t, changes)
+}
+
+func TestProductHistoryRequestGET(t *testing.T) {
+	mockDB, mock := utils.InitMockDB(t)
+
+	changedAt := time.Date(2025, 1, 12, 10, 0, 0, 0, time.UTC)
+	ownerMock := func(count int) {
+		mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM products WHERE id = \\$1 AND testing_team = \\$2;").
+			WithArgs(1, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(count))
+	}
+
+	tests := []struct {
+		name         string
+		setupMocks   func()
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:         "Status OK",
+			expectedCode: http.StatusOK,
+			expectedBody: `[{"revision":1,"changed_by":1,"changed_at":"2025-01-12T10:00:00Z","changes":[` +
+				`{"field":"high_temperature","old_value":0,"new_value":-2},` +
+				`{"field":"low_temperature","old_value":-20,"new_value":-8}]},` +
+				`{"revision":2,"changed_by":null,"changed_at":"2025-01-12T10:00:00Z","changes":[` +
+				`{"field":"status","old_value":"active","new_value":"retired"}]}]`,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				ownerMock(1)
+				mock.ExpectQuery("SELECT revision, field, old_value, new_value, changed_by, changed_at FROM product_history").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"revision", "field", "old_value", "new_value", "changed_by",
+						"changed_at"}).
+						AddRow(1, "high_temperature", []byte("0"), []byte("-2"), 1, changedAt).
+						AddRow(1, "low_temperature", []byte("-20"), []byte("-8"), 1, changedAt).
+						AddRow(2, "status", []byte(`"active"`), []byte(`"retired"`), nil, changedAt))
+			},
+		},
+		{
+			name:         "Status not found - product of another team",
+			expectedCode: http.StatusNotFound,
+			expectedBody: "Could not retrieve the product",
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				ownerMock(0)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+
+			req := httptest.NewRequest(http.MethodGet, "/products/1/history", nil)
+			req.Header.Set("Authorization", "Bearer mockToken")
+			rr := httptest.NewRecorder()
+
+			ProductsHandler(mockDB).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedCode, rr.Code)
+			assert.Contains(t, rr.Body.String(), tt.expectedBody)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestProductRevertRequestPOST(t *testing.T) {
+	mockDB, mock := utils.InitMockDB(t)
+
+	version := time.Date(2025, 1, 12, 10, 0, 0, 0, time.UTC)
+	productMock := func(testingTeam int, status string) {
+		mock.ExpectQuery("SELECT \\* FROM products WHERE id = \\$1;").
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows(productColumns).
+				AddRow(1, "Blue Wax", "Swix", "", "", "", false, "solid", -2.0, -8.0, testingTeam, version, status))
+	}
+	latestRevisionMock := func(revision int) {
+		mock.ExpectQuery("SELECT COALESCE\\(MAX\\(revision\\), 0\\) FROM product_history WHERE product_id = \\$1;").
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"revision"}).AddRow(revision))
+	}
+	revertMock := func(revision int) *sqlmock.ExpectedQuery {
+		return mock.ExpectQuery("SELECT DISTINCT ON \\(field\\) field, old_value FROM product_history "+
+			"WHERE product_id = \\$1 AND revision > \\$2").
+			WithArgs(1, revision)
+	}
+
+	tests := []struct {
+		name         string
+		path         string
+		setupMocks   func()
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:         "Status OK",
+			path:         "/products/1/revert/1",
+			expectedCode: http.StatusOK,
+			expectedBody: `"message":"Product reverted successfully","revision":3`,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				productMock(1, "discontinued")
+				latestRevisionMock(2)
+				revertMock(1).WillReturnRows(sqlmock.NewRows([]string{"field", "old_value"}).
+					AddRow("status", []byte(`"active"`)))
+
+				mock.ExpectQuery("SELECT user_id FROM sessions WHERE \\(session_token = \\$1 AND expires_at > NOW\\(\\)\\)").
+					WithArgs("mockToken").
+					WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(1))
+
+				mock.ExpectBegin()
+				mock.ExpectQuery("UPDATE products SET status = \\$1, version = \\$2 WHERE id = \\$3 AND version = \\$4 RETURNING version").
+					WithArgs("active", sqlmock.AnyArg(), 1, version).
+					WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(version.Add(time.Hour)))
+				mock.ExpectQuery("SELECT COALESCE\\(MAX\\(revision\\), 0\\) \\+ 1 FROM product_history WHERE product_id = \\$1;").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"revision"}).AddRow(3))
+				mock.ExpectExec("INSERT INTO product_history").
+					WithArgs(1, 3, "status", `"discontinued"`, `"active"`, 1, sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("INSERT INTO product_status_changes").
+					WithArgs(1, "discontinued", "active", "Reverted to revision 1.", 1, sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:         "Status OK - the temperature range refreshes the flags of the results and the windows of the bundles",
+			path:         "/products/1/revert/1",
+			expectedCode: http.StatusOK,
+			expectedBody: `"message":"Product reverted successfully","revision":3`,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				productMock(1, "active")
+				latestRevisionMock(2)
+				revertMock(1).WillReturnRows(sqlmock.NewRows([]string{"field", "old_value"}).
+					AddRow("high_temperature", []byte(`-6`)))
+
+				mock.ExpectQuery("SELECT user_id FROM sessions WHERE \\(session_token = \\$1 AND expires_at > NOW\\(\\)\\)").
+					WithArgs("mockToken").
+					WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(1))
+
+				mock.ExpectBegin()
+				mock.ExpectQuery("UPDATE products SET high_temperature = \\$1, version = \\$2 WHERE id = \\$3 AND version = \\$4 RETURNING version").
+					WithArgs(-6.0, sqlmock.AnyArg(), 1, version).
+					WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(version.Add(time.Hour)))
+				mock.ExpectQuery("SELECT COALESCE\\(MAX\\(revision\\), 0\\) \\+ 1 FROM product_history WHERE product_id = \\$1;").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"revision"}).AddRow(3))
+				mock.ExpectExec("INSERT INTO product_history").
+					WithArgs(1, 3, "high_temperature", "-2", "-6", 1, sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+
+				// The product is no longer rated for the -5 °C snow of test 7.
+				mock.ExpectQuery("SELECT DISTINCT test_id FROM test_ranks WHERE product_id = \\$1").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"test_id"}).AddRow(7))
+				mock.ExpectQuery("SELECT r.product_id, r.out_of_temperature_range, .* FROM test_ranks r").
+					WithArgs(7).
+					WillReturnRows(sqlmock.NewRows([]string{"product_id", "out_of_temperature_range", "type",
+						"low_temperature", "high_temperature", "temperature", "temperature"}).
+						AddRow(1, false, "solid", -8.0, -6.0, -5.0, -7.0))
+				mock.ExpectExec("UPDATE test_ranks SET out_of_temperature_range = \\$1").
+					WithArgs(true, 7, 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				// The product is a layer of the bundle product 9, whose window narrows from -8 °C to -2 °C to the new
+				// high temperature.
+				mock.ExpectQuery("SELECT b.product_id, MAX\\(p.low_temperature\\), MIN\\(p.high_temperature\\) FROM bundles b").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"product_id", "max", "min"}).AddRow(9, -8.0, -6.0))
+				mock.ExpectQuery("SELECT \\* FROM products WHERE id = \\$1 FOR UPDATE;").
+					WithArgs(9).
+					WillReturnRows(sqlmock.NewRows(productColumns).
+						AddRow(9, "Bundle", "Brand1", "", "", "", false, "bundle", -2.0, -8.0, 1, version, "active"))
+				mock.ExpectExec("UPDATE products SET high_temperature = \\$1, version = \\$2 WHERE id = \\$3;").
+					WithArgs(-6.0, sqlmock.AnyArg(), 9).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("SELECT COALESCE\\(MAX\\(revision\\), 0\\) \\+ 1 FROM product_history WHERE product_id = \\$1;").
+					WithArgs(9).
+					WillReturnRows(sqlmock.NewRows([]string{"revision"}).AddRow(1))
+				mock.ExpectExec("INSERT INTO product_history").
+					WithArgs(9, 1, "high_temperature", "-2", "-6", 1, sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectQuery("SELECT DISTINCT test_id FROM test_ranks WHERE product_id = \\$1").
+					WithArgs(9).
+					WillReturnRows(sqlmock.NewRows([]string{"test_id"}))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:         "Status bad request - already at the revision",
+			path:         "/products/1/revert/2",
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "The product is already at this revision.",
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				productMock(1, "discontinued")
+				latestRevisionMock(2)
+				revertMock(2).WillReturnRows(sqlmock.NewRows([]string{"field", "old_value"}))
+			},
+		},
+		{
+			name:         "Status unauthorized - product of another team",
+			path:         "/products/1/revert/0",
+			expectedCode: http.StatusUnauthorized,
+			expectedBody: "Validation error: user cannot update this product",
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				productMock(2, "discontinued")
+				latestRevisionMock(2)
+				revertMock(0).WillReturnRows(sqlmock.NewRows([]string{"field", "old_value"}).
+					AddRow("status", []byte(`"active"`)))
+			},
+		},
+		{
+			name:         "Status conflict - a retired product is final",
+			path:         "/products/1/revert/1",
+			expectedCode: http.StatusConflict,
+			expectedBody: "Validation error: product cannot move from retired to active",
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				productMock(1, "retired")
+				latestRevisionMock(2)
+				revertMock(1).WillReturnRows(sqlmock.NewRows([]string{"field", "old_value"}).
+					AddRow("status", []byte(`"active"`)))
+			},
+		},
+		{
+			name:         "Status not found - revision",
+			path:         "/products/1/revert/5",
+			expectedCode: http.StatusNotFound,
+			expectedBody: "Could not find the revision of the product.",
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				productMock(1, "discontinued")
+				latestRevisionMock(2)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+
+			req := httptest.NewRequest(http.MethodPost, tt.path, nil)
+			req.Header.Set("Authorization", "Bearer mockToken")
+			rr := httptest.NewRecorder()
+
+			ProductsHandler(mockDB).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedCode, rr.Code)
+			assert.Contains(t, rr.Body.String(), tt.expectedBody)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
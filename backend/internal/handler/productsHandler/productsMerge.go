@@ -0,0 +1,128 @@
+package productsHandler
+
+import (
+	"backend/internal/middleware"
+	"backend/internal/resources"
+	"backend/internal/utils"
+	"database/sql"
+	"encoding/json"
+	"log"
+	"net/http"
+	"regexp"
+)
+
+var mergePath = regexp.MustCompile(`^/products/merge/?$`)
+
+// OnProductsMerged is called in the background after products have been merged, with the tests whose results moved
+// to the target, so data derived from the tests, such as the product ratings, can be brought up to date. It is set
+// by the server.
+var OnProductsMerged func(testIDs []int)
+
+// productsMerged runs OnProductsMerged for the tests of merged products, if it is set.
+func productsMerged(testIDs []int) {
+	if OnProductsMerged != nil && len(testIDs) > 0 {
+		go OnProductsMerged(testIDs)
+	}
+}
+
+// ProductsMergeRequestPOST is the request handler for merging duplicate products.
+//
+//	@Summary		Merge duplicate products
+//	@Description	Merges duplicates of a product into it, in one transaction. The results, bundle layers, tournament
+//	@Description	matches, runs and attachments of the duplicates are moved to the target, and the duplicates are
+//	@Description	deleted. When a test has results for more than one of the products, only the best ranked result is
+//	@Description	kept, and the same goes for a bundle with more than one of them as layers. The duplicates must be
+//	@Description	products of the team, and the target a product of the team or a public product. Without confirm,
+//	@Description	the merge is only previewed: the rows it would move and remove are returned, and nothing changes.
+//	@Description	A confirmed merge is recorded in the history of the target, as a revision for each duplicate.
+//	@Tags			Products
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			merge	body		ProductMergeRequest		true	"The target and its duplicates"
+//	@Success		200		{object}	ProductMergeResponse	"The rows moved and removed by the merge, or its preview"
+//	@Failure		400		{string}	string					"Invalid request body"
+//	@Failure		403		{string}	string					"User cannot merge the product"
+//	@Failure		404		{string}	string					"Could not find the product"
+//	@Failure		500		{string}	string					"Could not merge the products."
+//	@Router			/products/merge [post]
+func ProductsMergeRequestPOST(w http.ResponseWriter, r *http.Request, db *sql.DB) {
+	// Get the user and the user's team membership.
+	team := middleware.GetUserTeamRole(w, r, db)
+	userID := middleware.GetUserID(w, r, db)
+
+	request, err := utils.ParseAndValidateRequest[ProductMergeRequest](r)
+	if err != nil {
+		http.Error(w, resources.InvalidPOSTRequest, http.StatusBadRequest)
+		log.Println(resources.InvalidPOSTRequest + ": " + err.Error())
+		return
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		http.Error(w, resources.TransactionStartFailed, http.StatusInternalServerError)
+		log.Println(resources.TransactionStartFailed + ": " + err.Error())
+		return
+	}
+	defer func() {
+		if err != nil {
+			rollbackErr := tx.Rollback()
+			if rollbackErr != nil {
+				log.Println(resources.RollbackFailed + rollbackErr.Error())
+			}
+		}
+	}()
+
+	products, err := lockMergeProducts(tx, append([]int{request.TargetID}, request.SourceIDs...))
+	if err != nil {
+		http.Error(w, "Could not merge the products.", http.StatusInternalServerError)
+		log.Println("Could not retrieve the products to merge: " + err.Error())
+		return
+	}
+	var code int
+	err, code = validateMerge(request, products, team)
+	if err != nil {
+		http.Error(w, "Validation error: "+err.Error(), code)
+		return
+	}
+
+	response, err := mergeProducts(tx, request.TargetID, request.SourceIDs)
+	if err != nil {
+		http.Error(w, "Could not merge the products.", http.StatusInternalServerError)
+		log.Println("Could not merge the products: " + err.Error())
+		return
+	}
+
+	// A preview undoes the merge, so it shows exactly the rows a confirmed merge changes.
+	if !request.Confirm {
+		err = tx.Rollback()
+		if err != nil {
+			http.Error(w, "Could not preview the merge.", http.StatusInternalServerError)
+			log.Println(resources.RollbackFailed + err.Error())
+			return
+		}
+	} else {
+		for _, sourceID := range request.SourceIDs {
+			err = recordMergedProduct(tx, request.TargetID, sourceID, userID)
+			if err != nil {
+				http.Error(w, "Could not merge the products.", http.StatusInternalServerError)
+				log.Println("Could not record the merge in the history of the product: " + err.Error())
+				return
+			}
+		}
+		err = tx.Commit()
+		if err != nil {
+			http.Error(w, resources.TransactionCommitFailed, http.StatusInternalServerError)
+			log.Println(resources.TransactionCommitFailed + ": " + err.Error())
+			return
+		}
+		response.Merged = true
+		productsMerged(response.Tests)
+	}
+
+	err = json.NewEncoder(w).Encode(response)
+	if err != nil {
+		http.Error(w, "Could not JSON encode the response.", http.StatusInternalServerError)
+		log.Println("Could not JSON encode the response: " + err.Error())
+	}
+}
@@ -0,0 +1,176 @@
+package productsHandler
+
+import (
+	"backend/internal/domain"
+	"database/sql"
+	"fmt"
+	"github.com/lib/pq"
+	"log"
+	"net/http"
+)
+
+// mergeProduct is what a merge needs to know about a product.
+type mergeProduct struct {
+	ID          int
+	Type        string
+	IsPublic    bool
+	TestingTeam int
+}
+
+// lockMergeProducts retrieves the products of a merge, by their IDs, and locks them until the merge is done.
+func lockMergeProducts(tx *sql.Tx, ids []int) (map[int]mergeProduct, error) {
+	rows, err := tx.Query(`SELECT id, type, is_public, testing_team FROM products
+							WHERE id = ANY($1) ORDER BY id FOR UPDATE;`, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	products := map[int]mergeProduct{}
+	for rows.Next() {
+		var product mergeProduct
+		if err = rows.Scan(&product.ID, &product.Type, &product.IsPublic, &product.TestingTeam); err != nil {
+			return nil, err
+		}
+		products[product.ID] = product
+	}
+	return products, rows.Err()
+}
+
+// validateMerge checks that the team can merge the source products into the target. The target must be visible to
+// the team, while the sources are deleted, so they must belong to the team, and a researcher can not delete public
+// products. A bundle can only be merged with another bundle.
+func validateMerge(request ProductMergeRequest, products map[int]mergeProduct, team int) (error, int) {
+	target, ok := products[request.TargetID]
+	if !ok || (!target.IsPublic && target.TestingTeam != team) {
+		log.Printf("Could not find the target product %d", request.TargetID)
+		return fmt.Errorf("could not find the target product %d, %d", request.TargetID, http.StatusNotFound),
+			http.StatusNotFound
+	}
+
+	for _, id := range request.SourceIDs {
+		source, ok := products[id]
+		switch {
+		case id == request.TargetID:
+			log.Println("Product cannot be merged into itself")
+			return fmt.Errorf("product %d cannot be merged into itself, %d", id, http.StatusBadRequest),
+				http.StatusBadRequest
+		case !ok:
+			log.Printf("Could not find the product %d", id)
+			return fmt.Errorf("could not find the product %d, %d", id, http.StatusNotFound), http.StatusNotFound
+		case source.TestingTeam != team:
+			log.Printf("User cannot merge the product %d of another team", id)
+			return fmt.Errorf("user cannot merge the product %d of another team, %d", id, http.StatusForbidden),
+				http.StatusForbidden
+		case source.IsPublic && domain.TeamRole(team) == domain.Researcher:
+			log.Println("Researcher cannot merge public products")
+			return fmt.Errorf("researcher cannot merge public products, %d", http.StatusForbidden),
+				http.StatusForbidden
+		case (source.Type == "bundle") != (target.Type == "bundle"):
+			log.Println("A bundle can only be merged with another bundle")
+			return fmt.Errorf("a bundle can only be merged with another bundle, %d", http.StatusBadRequest),
+				http.StatusBadRequest
+		}
+	}
+	return nil, 0
+}
+
+// execCount runs a statement and returns the number of rows it affected.
+func execCount(tx *sql.Tx, query string, args ...interface{}) (int, error) {
+	result, err := tx.Exec(query, args...)
+	if err != nil {
+		return 0, err
+	}
+	count, err := result.RowsAffected()
+	return int(count), err
+}
+
+// mergeProducts points every reference to the source products at the target, and deletes the sources. When a test
+// has results for more than one of the products, only the best ranked result is kept, preferring the target on a
+// tie, and the same goes for a bundle with more than one of them as layers. The layers of a source bundle are
+// removed, as the target bundle has its own.
+func mergeProducts(tx *sql.Tx, targetID int, sourceIDs []int) (ProductMergeResponse, error) {
+	response := ProductMergeResponse{TargetID: targetID, SourceIDs: sourceIDs, Tests: []int{}}
+	sources := pq.Array(sourceIDs)
+
+	rows, err := tx.Query("SELECT DISTINCT test_id FROM test_ranks WHERE product_id = ANY($1) ORDER BY test_id;",
+		sources)
+	if err != nil {
+		return response, fmt.Errorf("failed to retrieve the tests of the products: %w", err)
+	}
+	for rows.Next() {
+		var testID int
+		if err = rows.Scan(&testID); err != nil {
+			rows.Close()
+			return response, err
+		}
+		response.Tests = append(response.Tests, testID)
+	}
+	rows.Close()
+
+	ranks := MergedRows{Table: "test_ranks"}
+	ranks.Removed, err = execCount(tx, `DELETE FROM test_ranks r USING (
+							SELECT test_id, product_id, row_number() OVER (PARTITION BY test_id
+								ORDER BY rank NULLS LAST, product_id = $1 DESC, product_id) AS n
+							FROM test_ranks WHERE product_id = $1 OR product_id = ANY($2)) d
+							WHERE r.test_id = d.test_id AND r.product_id = d.product_id AND d.n > 1;`,
+		targetID, sources)
+	if err != nil {
+		return response, fmt.Errorf("failed to remove the duplicate results: %w", err)
+	}
+	ranks.Moved, err = execCount(tx, "UPDATE test_ranks SET product_id = $1 WHERE product_id = ANY($2);",
+		targetID, sources)
+	if err != nil {
+		return response, fmt.Errorf("failed to move the results: %w", err)
+	}
+
+	bundles := MergedRows{Table: "product_bundles"}
+	bundles.Removed, err = execCount(tx, `DELETE FROM product_bundles b USING (
+							SELECT bundle_id, product_id, row_number() OVER (PARTITION BY bundle_id
+								ORDER BY product_id = $1 DESC, layer_no, product_id) AS n
+							FROM product_bundles WHERE product_id = $1 OR product_id = ANY($2)) d
+							WHERE b.bundle_id = d.bundle_id AND b.product_id = d.product_id AND d.n > 1;`,
+		targetID, sources)
+	if err != nil {
+		return response, fmt.Errorf("failed to remove the duplicate layers: %w", err)
+	}
+	bundles.Moved, err = execCount(tx, "UPDATE product_bundles SET product_id = $1 WHERE product_id = ANY($2);",
+		targetID, sources)
+	if err != nil {
+		return response, fmt.Errorf("failed to move the layers: %w", err)
+	}
+	layers, err := execCount(tx, `DELETE FROM product_bundles l USING bundles b
+								WHERE l.bundle_id = b.id AND b.product_id = ANY($1);`, sources)
+	if err != nil {
+		return response, fmt.Errorf("failed to remove the layers of the bundles: %w", err)
+	}
+	bundles.Removed += layers
+
+	matches := MergedRows{Table: "tournament_matches"}
+	for _, column := range []string{"product1_id", "product2_id", "winner_id"} {
+		moved, err := execCount(tx, fmt.Sprintf("UPDATE tournament_matches SET %s = $1 WHERE %s = ANY($2);",
+			column, column), targetID, sources)
+		if err != nil {
+			return response, fmt.Errorf("failed to move the tournament matches: %w", err)
+		}
+		matches.Moved += moved
+	}
+
+	response.Rows = []MergedRows{ranks, bundles, matches}
+	for _, table := range []string{"test_runs", "attachments"} {
+		moved, err := execCount(tx, fmt.Sprintf("UPDATE %s SET product_id = $1 WHERE product_id = ANY($2);", table),
+			targetID, sources)
+		if err != nil {
+			return response, fmt.Errorf("failed to move the %s: %w", table, err)
+		}
+		response.Rows = append(response.Rows, MergedRows{Table: table, Moved: moved})
+	}
+
+	products := MergedRows{Table: "products"}
+	products.Removed, err = execCount(tx, "DELETE FROM products WHERE id = ANY($1);", sources)
+	if err != nil {
+		return response, fmt.Errorf("failed to delete the products: %w", err)
+	}
+	response.Rows = append(response.Rows, products)
+	return response, nil
+}
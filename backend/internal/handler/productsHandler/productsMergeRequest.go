@@ -0,0 +1,13 @@
+package productsHandler
+
+// ProductMergeRequest merges duplicate products into a target product.
+type ProductMergeRequest struct {
+	// TargetID is the product that is kept.
+	TargetID int `json:"target_id" validate:"required,gt=0"`
+
+	// SourceIDs are the duplicates of the target, which are deleted once their references point to the target.
+	SourceIDs []int `json:"source_ids" validate:"required,min=1,max=50,unique,dive,gt=0"`
+
+	// Confirm merges the products. Without it, the merge is only previewed.
+	Confirm bool `json:"confirm"`
+}
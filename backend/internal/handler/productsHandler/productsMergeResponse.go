@@ -0,0 +1,17 @@
+package productsHandler
+
+// ProductMergeResponse is the result of a merge of products, or of its preview.
+type ProductMergeResponse struct {
+	TargetID  int          `json:"target_id"`
+	SourceIDs []int        `json:"source_ids"`
+	Merged    bool         `json:"merged"` // False for a preview, which changes nothing.
+	Rows      []MergedRows `json:"rows"`
+	Tests     []int        `json:"tests"` // The tests whose results move to the target.
+}
+
+// MergedRows are the rows of a table that a merge points to the target, and the rows it removes.
+type MergedRows struct {
+	Table   string `json:"table"`
+	Moved   int    `json:"moved"`
+	Removed int    `json:"removed"`
+}
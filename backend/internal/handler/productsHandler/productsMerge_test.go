@@ -0,0 +1,191 @@
+package productsHandler
+
+import (
+	"backend/internal/utils"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// mergeMock mocks the statements of a merge of the sources, as a Postgres array, into the target. The tests are those
+// of the sources, and the rows affected are those of the statements, in order.
+func mergeMock(mock sqlmock.Sqlmock, targetID int, sources string, tests []int, rowsAffected [11]int64) {
+	testRows := sqlmock.NewRows([]string{"test_id"})
+	for _, id := range tests {
+		testRows.AddRow(id)
+	}
+	mock.ExpectQuery("SELECT DISTINCT test_id FROM test_ranks WHERE product_id = ANY\\(\\$1\\)").
+		WithArgs(sources).
+		WillReturnRows(testRows)
+
+	statements := []string{
+		"DELETE FROM test_ranks r USING",
+		"UPDATE test_ranks SET product_id = \\$1 WHERE product_id = ANY\\(\\$2\\);",
+		"DELETE FROM product_bundles b USING",
+		"UPDATE product_bundles SET product_id = \\$1 WHERE product_id = ANY\\(\\$2\\);",
+		"DELETE FROM product_bundles l USING bundles b WHERE l.bundle_id = b.id AND b.product_id = ANY\\(\\$1\\);",
+		"UPDATE tournament_matches SET product1_id = \\$1",
+		"UPDATE tournament_matches SET product2_id = \\$1",
+		"UPDATE tournament_matches SET winner_id = \\$1",
+		"UPDATE test_runs SET product_id = \\$1",
+		"UPDATE attachments SET product_id = \\$1",
+		"DELETE FROM products WHERE id = ANY\\(\\$1\\);",
+	}
+	for i, statement := range statements {
+		exec := mock.ExpectExec(statement)
+		if strings.Contains(statement, "ANY\\(\\$1\\)") {
+			exec.WithArgs(sources)
+		} else {
+			exec.WithArgs(targetID, sources)
+		}
+		exec.WillReturnResult(sqlmock.NewResult(0, rowsAffected[i]))
+	}
+}
+
+func Test_validateMerge(t *testing.T) {
+	products := map[int]mergeProduct{
+		1: {ID: 1, Type: "solid", IsPublic: true, TestingTeam: 2},
+		2: {ID: 2, Type: "solid", TestingTeam: 1},
+		3: {ID: 3, Type: "bundle", TestingTeam: 1},
+		4: {ID: 4, Type: "solid", TestingTeam: 2},
+		5: {ID: 5, Type: "solid", TestingTeam: 2},
+	}
+
+	tests := []struct {
+		name     string
+		request  ProductMergeRequest
+		wantCode int
+	}{
+		{name: "Into a public product", request: ProductMergeRequest{TargetID: 1, SourceIDs: []int{2}}},
+		{name: "Target of another team", request: ProductMergeRequest{TargetID: 4, SourceIDs: []int{2}},
+			wantCode: http.StatusNotFound},
+		{name: "Source not found", request: ProductMergeRequest{TargetID: 1, SourceIDs: []int{2, 9}},
+			wantCode: http.StatusNotFound},
+		{name: "Source of another team", request: ProductMergeRequest{TargetID: 2, SourceIDs: []int{5}},
+			wantCode: http.StatusForbidden},
+		{name: "Into itself", request: ProductMergeRequest{TargetID: 2, SourceIDs: []int{2}},
+			wantCode: http.StatusBadRequest},
+		{name: "Bundle into a product", request: ProductMergeRequest{TargetID: 2, SourceIDs: []int{3}},
+			wantCode: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err, code := validateMerge(tt.request, products, 1)
+			assert.Equal(t, tt.wantCode, code)
+			assert.Equal(t, tt.wantCode != 0, err != nil)
+		})
+	}
+}
+
+func TestProductsMergeRequestPOST(t *testing.T) {
+	mockDB, mock := utils.InitMockDB(t)
+
+	lockMock := func(ids string) *sqlmock.ExpectedQuery {
+		return mock.ExpectQuery("SELECT id, type, is_public, testing_team FROM products WHERE id = ANY\\(\\$1\\) " +
+			"ORDER BY id FOR UPDATE;").
+			WithArgs(ids)
+	}
+	productRows := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "type", "is_public", "testing_team"}).
+			AddRow(1, "solid", false, 1).
+			AddRow(2, "solid", false, 1).
+			AddRow(3, "solid", false, 1)
+	}
+
+	tests := []struct {
+		name         string
+		body         string
+		setupMocks   func()
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:         "Status OK - preview",
+			body:         `{"target_id":1,"source_ids":[2,3]}`,
+			expectedCode: http.StatusOK,
+			expectedBody: `{"target_id":1,"source_ids":[2,3],"merged":false,"rows":[` +
+				`{"table":"test_ranks","moved":3,"removed":1},{"table":"product_bundles","moved":1,"removed":0},` +
+				`{"table":"tournament_matches","moved":2,"removed":0},{"table":"test_runs","moved":6,"removed":0},` +
+				`{"table":"attachments","moved":1,"removed":0},{"table":"products","moved":0,"removed":2}],` +
+				`"tests":[4,5,6]}`,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				UserIDMock(mock)
+				mock.ExpectBegin()
+				lockMock("{1,2,3}").WillReturnRows(productRows())
+				mergeMock(mock, 1, "{2,3}", []int{4, 5, 6}, [11]int64{1, 3, 0, 1, 0, 1, 1, 0, 6, 1, 2})
+				mock.ExpectRollback()
+			},
+		},
+		{
+			name:         "Status OK - merged",
+			body:         `{"target_id":1,"source_ids":[2],"confirm":true}`,
+			expectedCode: http.StatusOK,
+			expectedBody: `{"target_id":1,"source_ids":[2],"merged":true,`,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				UserIDMock(mock)
+				mock.ExpectBegin()
+				lockMock("{1,2}").WillReturnRows(productRows())
+				mergeMock(mock, 1, "{2}", []int{4}, [11]int64{0, 1, 0, 0, 0, 0, 0, 0, 0, 0, 1})
+				mock.ExpectExec("UPDATE products SET version = \\$1 WHERE id = \\$2;").
+					WithArgs(sqlmock.AnyArg(), 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("SELECT COALESCE\\(MAX\\(revision\\), 0\\) \\+ 1 FROM product_history WHERE product_id = \\$1;").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"revision"}).AddRow(4))
+				mock.ExpectExec("INSERT INTO product_history").
+					WithArgs(1, 4, "merged_product", "null", "2", 1, sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:         "Status forbidden - product of another team",
+			body:         `{"target_id":1,"source_ids":[2],"confirm":true}`,
+			expectedCode: http.StatusForbidden,
+			expectedBody: "Validation error: user cannot merge the product 2 of another team",
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				UserIDMock(mock)
+				mock.ExpectBegin()
+				lockMock("{1,2}").WillReturnRows(sqlmock.NewRows([]string{"id", "type", "is_public", "testing_team"}).
+					AddRow(1, "solid", true, 2).
+					AddRow(2, "solid", false, 2))
+				mock.ExpectRollback()
+			},
+		},
+		{
+			name:         "Status bad request - no sources",
+			body:         `{"target_id":1,"source_ids":[]}`,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Invalid POST request",
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				UserIDMock(mock)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+
+			req := httptest.NewRequest(http.MethodPost, "/products/merge", strings.NewReader(tt.body))
+			req.Header.Set("Authorization", "Bearer mockToken")
+			rr := httptest.NewRecorder()
+
+			ProductsHandler(mockDB).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedCode, rr.Code)
+			assert.Contains(t, rr.Body.String(), tt.expectedBody)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 type ProductPATCHRequest struct {
 	Updates map[string]interface{} `json:"updates" validate:"required,dive,keys,oneof=name brand ean_code image_url type high_temperature low_temperature comment is_public status,endkeys"`
 	Version time.Time              `json:"version"`
+	Reason  string                 `json:"reason,omitempty" validate:"omitempty,max=512"` // Why the status is changed.
 }
 
 type ProductUpdateFields struct {
@@ -19,5 +20,5 @@ type ProductUpdateFields struct {
 	Type            *string  `json:"type" validate:"omitempty,oneof=liquid solid spray powder gel bundle"`
 	HighTemperature *float64 `json:"high_temperature" validate:"omitempty,lte=100,gte=-100"`
 	LowTemperature  *float64 `json:"low_temperature" validate:"omitempty,lte=100,gte=-100,ltfield=HighTemperature"`
-	Status          *string  `json:"status" validate:"omitempty,oneof=active tested discontinued development retired"`
+	Status          *string  `json:"status" validate:"omitempty,oneof=active discontinued development retired"`
 }
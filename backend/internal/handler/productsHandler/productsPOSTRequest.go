@@ -10,8 +10,8 @@ type ProductPOSTRequest struct {
 	// ImageURL: Only ASCII, can be blank, and must be a valid URL.
 	ImageURL string `json:"image_url" validate:"omitempty,url"`
 
-	// EANCode: Only ASCII, can be blank, max 64 characters,
-	// Only capital letters, numbers, and '#' are allowed; non-blank, max 128 characters.
+	// EANCode: can be blank, otherwise an EAN-8, UPC-A or EAN-13 code with a valid check digit, which is checked in
+	// the POST handler.
 	EANCode string `json:"ean_code" validate:"omitempty,max=128,ascii"`
 
 	// Comment: max 2040 bytes.
@@ -30,6 +30,6 @@ type ProductPOSTRequest struct {
 
 	TestingTeam int `json:"testing_team"`
 
-	// Status: Only one of the following: active, tested, discontinued, development, retired.
-	Status string `json:"status" validate:"required,oneof=active tested discontinued development retired"`
+	// Status: Only one of the following: active, discontinued, development, retired.
+	Status string `json:"status" validate:"required,oneof=active discontinued development retired"`
 }
@@ -0,0 +1,60 @@
+package productsHandler
+
+import (
+	"backend/internal/middleware"
+	"backend/internal/resources"
+	"database/sql"
+	"encoding/json"
+	"log"
+	"net/http"
+	"regexp"
+	"strconv"
+)
+
+var performanceWindowPath = regexp.MustCompile(`^/products/(\d+)/performance-window/?$`)
+
+// ProductPerformanceWindowRequestGET is the request handler for the temperature window of a product.
+//
+//	@Summary		Get the temperature window of a product
+//	@Description	Retrieves the temperature window in which a product performs in the top quartile of the completed
+//	@Description	and published tests with at least two ranked products. A result is in the top quartile when its
+//	@Description	placing, from 1 for the winner to 0 for the last product, is at least 0.75. The windows of the snow
+//	@Description	and air temperatures span the results in the top quartile, and count all the results within them.
+//	@Description	The results of other teams are only included when the test and the rank are public. When the
+//	@Description	product belongs to the team and at least 3 results are in the top quartile, an update of the rated
+//	@Description	temperature range that covers both windows is suggested, which can be sent as is to PATCH /products/{id}.
+//	@Tags			Products
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int							true	"Product ID"
+//	@Success		200	{object}	PerformanceWindowResponse	"The temperature window of the product"
+//	@Failure		404	{string}	string						"Could not retrieve the product"
+//	@Failure		500	{string}	string						"Could not retrieve the results of the product."
+//	@Router			/products/{id}/performance-window [get]
+func ProductPerformanceWindowRequestGET(w http.ResponseWriter, r *http.Request, db *sql.DB) {
+	productID, _ := strconv.Atoi(performanceWindowPath.FindStringSubmatch(r.URL.Path)[1])
+
+	// Get the user's team membership.
+	team := middleware.GetUserTeamRole(w, r, db)
+
+	product, err := queryAndScanProduct(db, "SELECT * FROM products WHERE id = $1 AND (is_public OR testing_team = $2);",
+		productID, team)
+	if err != nil {
+		http.Error(w, resources.CouldNotRetrieveProduct, http.StatusNotFound)
+		log.Println("Could not retrieve the product: " + err.Error())
+		return
+	}
+
+	results, err := getProductResults(db, productID, team)
+	if err != nil {
+		http.Error(w, "Could not retrieve the results of the product.", http.StatusInternalServerError)
+		log.Println("Could not retrieve the results of the product: " + err.Error())
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(performanceWindow(product, results, team))
+	if err != nil {
+		http.Error(w, "Could not JSON encode the response.", http.StatusInternalServerError)
+		log.Println("Could not JSON encode the response: " + err.Error())
+	}
+}
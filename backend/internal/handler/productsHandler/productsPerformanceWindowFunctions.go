@@ -0,0 +1,135 @@
+package productsHandler
+
+import (
+	"backend/internal/domain"
+	"database/sql"
+	"math"
+)
+
+const (
+	// topQuartilePlacing is the lowest placing in the top quartile, where the winner of a test is placed at 1 and the
+	// last product at 0.
+	topQuartilePlacing = 0.75
+	// minPerformanceWindowSamples is the number of results in the top quartile needed to suggest a temperature range.
+	minPerformanceWindowSamples = 3
+)
+
+// productResult is a result of a product in a test, with the temperatures of the test.
+type productResult struct {
+	SnowTemperature float64
+	AirTemperature  float64
+	Rank            int
+	Ranked          int
+}
+
+// placing returns the placing of the result between 0 for the last product and 1 for the winner.
+func (result productResult) placing() float64 {
+	return float64(result.Ranked-result.Rank) / float64(result.Ranked-1)
+}
+
+// getProductResults retrieves the ranked results of a product in the completed and published tests with at least two
+// ranked products. Products without a rank are left out of both the results and the count of ranked products. The
+// results of other teams are only included when the test and the rank are public.
+func getProductResults(db *sql.DB, productID int, team int) ([]productResult, error) {
+	rows, err := db.Query(`SELECT sc.temperature, ac.temperature, r.rank, n.ranked
+							FROM test_ranks r
+							JOIN tests t ON t.id = r.test_id
+							JOIN snow_conditions sc ON sc.id = t.sc_id
+							JOIN air_conditions ac ON ac.id = t.ac_id
+							JOIN (SELECT test_id, COUNT(*) AS ranked FROM test_ranks WHERE rank > 0 GROUP BY test_id) n
+							  ON n.test_id = r.test_id
+							WHERE r.product_id = $1 AND r.rank > 0
+							  AND t.state IN `+domain.ReportableStatesSQL+`
+							  AND (t.testing_team = $2 OR (t.is_public AND r.is_rank_public))
+							  AND n.ranked > 1
+							ORDER BY t.test_date, t.id;`, productID, team)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []productResult
+	for rows.Next() {
+		var result productResult
+		if err = rows.Scan(&result.SnowTemperature, &result.AirTemperature, &result.Rank, &result.Ranked); err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, rows.Err()
+}
+
+// temperatureWindow returns the interval of the temperatures of the results in the top quartile, or nil when no
+// result is in the top quartile.
+func temperatureWindow(results []productResult, temperature func(productResult) float64) *TemperatureWindow {
+	var window *TemperatureWindow
+	for _, result := range results {
+		if result.placing() < topQuartilePlacing {
+			continue
+		}
+		t := temperature(result)
+		if window == nil {
+			window = &TemperatureWindow{Low: t, High: t}
+		}
+		window.Low = math.Min(window.Low, t)
+		window.High = math.Max(window.High, t)
+	}
+	if window == nil {
+		return nil
+	}
+
+	for _, result := range results {
+		t := temperature(result)
+		if t < window.Low || t > window.High {
+			continue
+		}
+		window.Samples++
+		if result.placing() >= topQuartilePlacing {
+			window.TopQuartileSamples++
+		}
+	}
+	return window
+}
+
+// isBound reports whether a bound of a rated temperature range is set to the temperature. A missing bound is open.
+func isBound(bound *float64, temperature float64) bool {
+	return bound != nil && *bound == temperature
+}
+
+// performanceWindow computes the temperature window of a product from its results. The window covers both the snow
+// and the air temperatures of the results in the top quartile, as both are compared with the rated temperature range
+// of a product. An update of the range is suggested to the team of the product when there are enough results in the
+// top quartile and the window differs from the rated range.
+func performanceWindow(product domain.Product, results []productResult, team int) PerformanceWindowResponse {
+	response := PerformanceWindowResponse{
+		ProductID:       product.ID,
+		LowTemperature:  product.LowTemperature,
+		HighTemperature: product.HighTemperature,
+		Samples:         len(results),
+		SnowTemperature: temperatureWindow(results, func(r productResult) float64 { return r.SnowTemperature }),
+		AirTemperature:  temperatureWindow(results, func(r productResult) float64 { return r.AirTemperature }),
+	}
+	for _, result := range results {
+		if result.placing() >= topQuartilePlacing {
+			response.TopQuartileSamples++
+		}
+	}
+
+	if response.SnowTemperature == nil || response.TopQuartileSamples < minPerformanceWindowSamples ||
+		product.TestingTeam != team {
+		return response
+	}
+	low := math.Min(response.SnowTemperature.Low, response.AirTemperature.Low)
+	high := math.Max(response.SnowTemperature.High, response.AirTemperature.High)
+	if low >= high || (isBound(product.LowTemperature, low) && isBound(product.HighTemperature, high)) {
+		return response
+	}
+	response.SuggestedUpdate = &ProductPATCHRequest{
+		Updates: map[string]interface{}{
+			"low_temperature":  low,
+			"high_temperature": high,
+		},
+		Version: product.Version,
+	}
+	return response
+}
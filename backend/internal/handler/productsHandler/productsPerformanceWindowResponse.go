@@ -0,0 +1,22 @@
+package productsHandler
+
+// PerformanceWindowResponse is the temperature window in which a product performs in the top quartile of its tests.
+type PerformanceWindowResponse struct {
+	ProductID          int                  `json:"product_id"`
+	LowTemperature     *float64             `json:"low_temperature"`
+	HighTemperature    *float64             `json:"high_temperature"`
+	Samples            int                  `json:"samples"`
+	TopQuartileSamples int                  `json:"top_quartile_samples"`
+	SnowTemperature    *TemperatureWindow   `json:"snow_temperature"`
+	AirTemperature     *TemperatureWindow   `json:"air_temperature"`
+	SuggestedUpdate    *ProductPATCHRequest `json:"suggested_update"`
+}
+
+// TemperatureWindow is the interval of the temperatures of the results in the top quartile. Samples counts all the
+// results within the interval, of which TopQuartileSamples are in the top quartile.
+type TemperatureWindow struct {
+	Low                float64 `json:"low"`
+	High               float64 `json:"high"`
+	Samples            int     `json:"samples"`
+	TopQuartileSamples int     `json:"top_quartile_samples"`
+}
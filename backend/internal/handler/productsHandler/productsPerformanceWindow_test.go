@@ -0,0 +1,156 @@
+package productsHandler
+
+import (
+	"backend/internal/domain"
+	"backend/internal/utils"
+	"database/sql"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+var productResults = []productResult{
+	{SnowTemperature: -8, AirTemperature: -6, Rank: 1, Ranked: 4},
+	{SnowTemperature: -4, AirTemperature: -3, Rank: 2, Ranked: 5},
+	{SnowTemperature: -6, AirTemperature: -5, Rank: 3, Ranked: 4},
+	{SnowTemperature: -12, AirTemperature: -10, Rank: 2, Ranked: 2},
+	{SnowTemperature: -5, AirTemperature: -2, Rank: 1, Ranked: 3},
+}
+
+func Test_performanceWindow(t *testing.T) {
+	version := time.Date(2025, 1, 12, 0, 0, 0, 0, time.UTC)
+	product := domain.Product{ID: 1, LowTemperature: float(-20), HighTemperature: float(0), TestingTeam: 1, Version: version}
+
+	window := performanceWindow(product, productResults, 1)
+	assert.Equal(t, 5, window.Samples)
+	assert.Equal(t, 3, window.TopQuartileSamples)
+	assert.Equal(t, &TemperatureWindow{Low: -8, High: -4, Samples: 4, TopQuartileSamples: 3}, window.SnowTemperature)
+	assert.Equal(t, &TemperatureWindow{Low: -6, High: -2, Samples: 4, TopQuartileSamples: 3}, window.AirTemperature)
+	assert.Equal(t, &ProductPATCHRequest{
+		Updates: map[string]interface{}{"low_temperature": -8.0, "high_temperature": -2.0},
+		Version: version,
+	}, window.SuggestedUpdate)
+
+	// Only the team of the product is suggested an update.
+	window = performanceWindow(product, productResults, 2)
+	assert.Nil(t, window.SuggestedUpdate)
+
+	// No update is suggested when the rated range already matches.
+	product.LowTemperature, product.HighTemperature = float(-8), float(-2)
+	window = performanceWindow(product, productResults, 1)
+	assert.Nil(t, window.SuggestedUpdate)
+
+	// Too few results in the top quartile.
+	window = performanceWindow(product, productResults[:2], 1)
+	assert.Equal(t, 2, window.TopQuartileSamples)
+	assert.Nil(t, window.SuggestedUpdate)
+
+	// No results in the top quartile.
+	window = performanceWindow(product, productResults[2:4], 1)
+	assert.Nil(t, window.SnowTemperature)
+	assert.Nil(t, window.AirTemperature)
+	assert.Nil(t, window.SuggestedUpdate)
+}
+
+func TestProductPerformanceWindowRequestGET(t *testing.T) {
+	mockDB, mock := utils.InitMockDB(t)
+
+	version := time.Date(2025, 1, 12, 0, 0, 0, 0, time.UTC)
+	productMock := func() *sqlmock.ExpectedQuery {
+		return mock.ExpectQuery("SELECT \\* FROM products WHERE id = \\$1 AND \\(is_public OR testing_team = \\$2\\);").
+			WithArgs(1, 1)
+	}
+	resultsMock := func() *sqlmock.ExpectedQuery {
+		return mock.ExpectQuery("SELECT sc.temperature, ac.temperature, r.rank, n.ranked FROM test_ranks r .* "+
+			"FROM test_ranks WHERE rank > 0 GROUP BY test_id\\) n .* "+
+			"WHERE r.product_id = \\$1 AND r.rank > 0 AND t.state IN \\('completed', 'published'\\)").
+			WithArgs(1, 1)
+	}
+
+	tests := []struct {
+		name         string
+		path         string
+		setupMocks   func()
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:         "Status OK",
+			path:         "/products/1/performance-window",
+			expectedCode: http.StatusOK,
+			expectedBody: `{"product_id":1,"low_temperature":-20,"high_temperature":0,"samples":5,` +
+				`"top_quartile_samples":3,"snow_temperature":{"low":-8,"high":-4,"samples":4,"top_quartile_samples":3},` +
+				`"air_temperature":{"low":-6,"high":-2,"samples":4,"top_quartile_samples":3},` +
+				`"suggested_update":{"updates":{"high_temperature":-2,"low_temperature":-8},` +
+				`"version":"2025-01-12T00:00:00Z"}}`,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				productMock().WillReturnRows(sqlmock.NewRows(productColumns).
+					AddRow(1, "Blue Wax", "Swix", "", "", "", false, "solid", 0, -20, 1, version, "active"))
+				rows := sqlmock.NewRows([]string{"temperature", "temperature", "rank", "ranked"})
+				for _, result := range productResults {
+					rows.AddRow(result.SnowTemperature, result.AirTemperature, result.Rank, result.Ranked)
+				}
+				resultsMock().WillReturnRows(rows)
+			},
+		},
+		{
+			name:         "Status OK - no results",
+			path:         "/products/1/performance-window/",
+			expectedCode: http.StatusOK,
+			expectedBody: `"samples":0,"top_quartile_samples":0,"snow_temperature":null,"air_temperature":null,` +
+				`"suggested_update":null}`,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				productMock().WillReturnRows(sqlmock.NewRows(productColumns).
+					AddRow(1, "Blue Wax", "Swix", "", "", "", true, "solid", 0, -20, 2, version, "active"))
+				resultsMock().WillReturnRows(sqlmock.NewRows([]string{"temperature", "temperature", "rank", "ranked"}))
+			},
+		},
+		{
+			name:         "Status not found",
+			path:         "/products/1/performance-window",
+			expectedCode: http.StatusNotFound,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				productMock().WillReturnError(sql.ErrNoRows)
+			},
+		},
+		{
+			name:         "Status internal server error",
+			path:         "/products/1/performance-window",
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: "Could not retrieve the results of the product.",
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				productMock().WillReturnRows(sqlmock.NewRows(productColumns).
+					AddRow(1, "Blue Wax", "Swix", "", "", "", false, "solid", 0, -20, 1, version, "active"))
+				resultsMock().WillReturnError(sql.ErrConnDone)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.Header.Set("Authorization", "Bearer mockToken")
+			rr := httptest.NewRecorder()
+
+			ProductsHandler(mockDB).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedCode, rr.Code)
+			if tt.expectedBody != "" {
+				assert.Contains(t, rr.Body.String(), tt.expectedBody)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package productsHandler
+
+import (
+	"backend/internal/middleware"
+	"database/sql"
+	"encoding/json"
+	"log"
+	"net/http"
+	"regexp"
+)
+
+var searchPath = regexp.MustCompile(`^/products/search/?$`)
+
+// ProductsSearchRequestGET is the request handler for searching the products.
+//
+//	@Summary		Search the products
+//	@Description	Searches the products of the team and the public products. The text is matched against the name,
+//	@Description	brand and comment of a product, where every word must match the start of a word, so "swi blu" finds
+//	@Description	"Swix Blue Extra". The products can be filtered by type, status, whether they are public, and a
+//	@Description	temperature that their rated temperature range covers, where a missing bound leaves that side of the
+//	@Description	range open. The results are sorted by relevance when searching for text and by name otherwise, or
+//	@Description	by version or rating, the overall rating of the product over the tests of the team and the public
+//	@Description	tests.
+//	@Tags			Products
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			q			query		string					false	"Text to search for in the name, brand and comment"
+//	@Param			type		query		string					false	"Type of the products"	Enums(liquid, solid, spray, powder, gel, bundle)
+//	@Param			status		query		string					false	"Status of the products"	Enums(active, discontinued, development, retired)
+//	@Param			public		query		bool					false	"Only public products, or only private products of the team"
+//	@Param			covers		query		number					false	"Temperature in °C covered by the rated temperature range"
+//	@Param			sort		query		string					false	"Order of the products"	Enums(relevance, name, version, rating)
+//	@Param			page		query		int						false	"Page, starting at 1"
+//	@Param			page_size	query		int						false	"Products per page, at most 100"	default(25)
+//	@Success		200			{object}	ProductSearchResponse	"The products matching the search"
+//	@Failure		400			{string}	string					"Invalid query parameter"
+//	@Failure		500			{string}	string					"Could not search the products."
+//	@Router			/products/search [get]
+func ProductsSearchRequestGET(w http.ResponseWriter, r *http.Request, db *sql.DB) {
+	// Get the user's team membership.
+	team := middleware.GetUserTeamRole(w, r, db)
+
+	conditions, args, err := searchFilter(w, r, team)
+	if err != nil {
+		return
+	}
+	order, err := searchOrder(w, r)
+	if err != nil {
+		return
+	}
+	page, pageSize, err := searchPage(w, r)
+	if err != nil {
+		return
+	}
+
+	products, total, err := searchProducts(db, conditions, args, order, page, pageSize)
+	if err != nil {
+		http.Error(w, "Could not search the products.", http.StatusInternalServerError)
+		log.Println("Could not search the products: " + err.Error())
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(ProductSearchResponse{
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+		Products: products,
+	})
+	if err != nil {
+		http.Error(w, "Could not JSON encode the response.", http.StatusInternalServerError)
+		log.Println("Could not JSON encode the response: " + err.Error())
+	}
+}
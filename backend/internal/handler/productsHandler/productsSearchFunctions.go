@@ -0,0 +1,211 @@
+package productsHandler
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const (
+	defaultSearchPageSize = 25
+	maxSearchPageSize     = 100
+
+	// searchVector is the text of a product that is searched, which is the expression of the products_search_idx index.
+	searchVector = `to_tsvector('simple'::regconfig, COALESCE(p.name, '') || ' ' || COALESCE(p.brand, '') || ' ' ||
+       COALESCE(p.comment, ''))`
+
+	// searchColumns are the columns of a product matching a search, in the order of ProductSearchResult.
+	searchColumns = `p.id, p.name, COALESCE(p.brand, ''), COALESCE(p.ean_code, ''), COALESCE(p.image_url, ''),
+       COALESCE(p.comment, ''), p.is_public, p.type, p.high_temperature,
+       p.low_temperature, p.testing_team, p.version, p.status, pr.rating`
+)
+
+var (
+	productTypes    = []string{"liquid", "solid", "spray", "powder", "gel", "bundle"}
+	productStatuses = []string{"active", "discontinued", "development", "retired"}
+
+	// searchOrders are the orders of the search results by the sort parameter. The relevance is only known when
+	// searching for text.
+	searchOrders = map[string]string{
+		"relevance": "ts_rank(" + searchVector + ", to_tsquery('simple', $2)) DESC, lower(p.name), p.id",
+		"name":      "lower(p.name), p.id",
+		"version":   "p.version DESC, p.id",
+		"rating":    "pr.rating DESC NULLS LAST, lower(p.name), p.id",
+	}
+)
+
+// searchQuery turns the text of a search into a full-text query, where every word must match the start of a word of
+// the product. Only letters and digits are kept, so the text can not change the query.
+func searchQuery(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(c rune) bool {
+		return !unicode.IsLetter(c) && !unicode.IsDigit(c)
+	})
+	for i, word := range words {
+		words[i] = word + ":*"
+	}
+	return strings.Join(words, " & ")
+}
+
+// isOneOf reports whether a value is one of the values.
+func isOneOf(value string, values []string) bool {
+	for _, v := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
+
+// searchFilter builds the conditions of the products matching the search, from the query parameters. The products
+// are those of the team together with the public products. The team is the first argument, and the full-text query
+// the second, if there is one.
+func searchFilter(w http.ResponseWriter, r *http.Request, team int) (string, []interface{}, error) {
+	query := r.URL.Query()
+	conditions := "(p.testing_team = $1 OR p.is_public)"
+	args := []interface{}{team}
+
+	if text := searchQuery(query.Get("q")); text != "" {
+		args = append(args, text)
+		conditions += " AND " + searchVector + " @@ to_tsquery('simple', $2)"
+	}
+
+	for _, param := range []struct {
+		name   string
+		values []string
+	}{{"type", productTypes}, {"status", productStatuses}} {
+		value := query.Get(param.name)
+		if value == "" {
+			continue
+		}
+		if !isOneOf(value, param.values) {
+			http.Error(w, fmt.Sprintf("Invalid %s, use %s.", param.name, strings.Join(param.values, ", ")),
+				http.StatusBadRequest)
+			log.Printf("Invalid %s: %s", param.name, value)
+			return "", nil, fmt.Errorf("invalid %s %s", param.name, value)
+		}
+		args = append(args, value)
+		conditions += fmt.Sprintf(" AND p.%s = $%d", param.name, len(args))
+	}
+
+	switch public := query.Get("public"); public {
+	case "":
+	case "true":
+		conditions += " AND p.is_public"
+	case "false":
+		conditions += " AND NOT p.is_public AND p.testing_team = $1"
+	default:
+		http.Error(w, "Invalid public, use true or false.", http.StatusBadRequest)
+		log.Println("Invalid public: " + public)
+		return "", nil, fmt.Errorf("invalid public %s", public)
+	}
+
+	if covers := query.Get("covers"); covers != "" {
+		temperature, err := strconv.ParseFloat(covers, 64)
+		if err != nil {
+			http.Error(w, "Invalid covers, use a temperature such as -8.", http.StatusBadRequest)
+			log.Println("Invalid covers: " + covers)
+			return "", nil, err
+		}
+		// A missing bound leaves that side of the range open.
+		args = append(args, temperature)
+		conditions += fmt.Sprintf(" AND (p.low_temperature IS NULL OR p.low_temperature <= $%d)"+
+			" AND (p.high_temperature IS NULL OR p.high_temperature >= $%d)", len(args), len(args))
+	}
+	return conditions, args, nil
+}
+
+// searchOrder gets the order of the search results from the sort parameter. The results are sorted by relevance
+// when searching for text, and by name otherwise.
+func searchOrder(w http.ResponseWriter, r *http.Request) (string, error) {
+	sort := r.URL.Query().Get("sort")
+	hasText := searchQuery(r.URL.Query().Get("q")) != ""
+	if sort == "" && hasText {
+		sort = "relevance"
+	} else if sort == "" {
+		sort = "name"
+	}
+
+	order, ok := searchOrders[sort]
+	if !ok || (sort == "relevance" && !hasText) {
+		http.Error(w, "Invalid sort, use relevance, name, version or rating. Relevance needs q.",
+			http.StatusBadRequest)
+		log.Println("Invalid sort: " + sort)
+		return "", fmt.Errorf("invalid sort %s", sort)
+	}
+	return order, nil
+}
+
+// searchPage gets the page and the page size from the query parameters.
+func searchPage(w http.ResponseWriter, r *http.Request) (int, int, error) {
+	page, pageSize := 1, defaultSearchPageSize
+	for _, param := range []struct {
+		name  string
+		value *int
+		max   int
+	}{{"page", &page, 0}, {"page_size", &pageSize, maxSearchPageSize}} {
+		value := r.URL.Query().Get(param.name)
+		if value == "" {
+			continue
+		}
+		number, err := strconv.Atoi(value)
+		if err != nil || number < 1 || (param.max > 0 && number > param.max) {
+			http.Error(w, "Invalid "+param.name+".", http.StatusBadRequest)
+			log.Println("Invalid " + param.name + ": " + value)
+			return 0, 0, fmt.Errorf("invalid %s %s", param.name, value)
+		}
+		*param.value = number
+	}
+	return page, pageSize, nil
+}
+
+// searchProducts retrieves a page of the products matching the conditions, and the number of matching products. The
+// rating of a product is its rating over all the tests of the team and the public tests.
+func searchProducts(db *sql.DB, conditions string, args []interface{}, order string, page int, pageSize int,
+) ([]ProductSearchResult, int, error) {
+	var total int
+	err := db.QueryRow("SELECT COUNT(*) FROM products p WHERE "+conditions+";", args...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	args = append(args, pageSize, (page-1)*pageSize)
+	rows, err := db.Query(fmt.Sprintf(`SELECT `+searchColumns+`
+			FROM products p
+			LEFT JOIN product_ratings pr ON pr.product_id = p.id AND pr.pool = $1
+			                            AND pr.scope_type = 'all' AND pr.scope_value = ''
+			WHERE %s
+			ORDER BY %s
+			LIMIT $%d OFFSET $%d;`, conditions, order, len(args)-1, len(args)), args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	results := []ProductSearchResult{}
+	for rows.Next() {
+		var result ProductSearchResult
+		if err = rows.Scan(
+			&result.ID,
+			&result.Name,
+			&result.Brand,
+			&result.EANCode,
+			&result.ImageURL,
+			&result.Comment,
+			&result.IsPublic,
+			&result.Type,
+			&result.HighTemperature,
+			&result.LowTemperature,
+			&result.TestingTeam,
+			&result.Version,
+			&result.Status,
+			&result.Rating); err != nil {
+			return nil, 0, err
+		}
+		results = append(results, result)
+	}
+	return results, total, rows.Err()
+}
@@ -0,0 +1,18 @@
+package productsHandler
+
+import "backend/internal/domain"
+
+// ProductSearchResponse is a page of the products matching a search.
+type ProductSearchResponse struct {
+	Total    int                   `json:"total"`
+	Page     int                   `json:"page"`
+	PageSize int                   `json:"page_size"`
+	Products []ProductSearchResult `json:"products"`
+}
+
+// ProductSearchResult is a product matching a search, with its rating over all the tests of the team and the public
+// tests, if it has been rated.
+type ProductSearchResult struct {
+	domain.Product
+	Rating *float64 `json:"rating"`
+}
@@ -0,0 +1,155 @@
+package productsHandler
+
+import (
+	"backend/internal/utils"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func Test_searchQuery(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{name: "Words", text: "Swix Blue", want: "swix:* & blue:*"},
+		{name: "Letters and digits", text: "V40 blå", want: "v40:* & blå:*"},
+		{name: "Operators are dropped", text: "red | !klister & (gel):*", want: "red:* & klister:* & gel:*"},
+		{name: "Empty", text: "  ", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, searchQuery(tt.text))
+		})
+	}
+}
+
+func TestProductsSearchRequestGET(t *testing.T) {
+	mockDB, mock := utils.InitMockDB(t)
+
+	version := time.Date(2025, 1, 12, 0, 0, 0, 0, time.UTC)
+	searchColumnNames := append(append([]string{}, productColumns...), "rating")
+
+	tests := []struct {
+		name         string
+		path         string
+		setupMocks   func()
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:         "Status OK - text and filters",
+			path:         "/products/search?q=swix+blu&type=solid&status=active&public=true&covers=-8&page=2&page_size=1",
+			expectedCode: http.StatusOK,
+			expectedBody: `{"total":2,"page":2,"page_size":1,"products":[{"id":3,"name":"Blue Extra","brand":"Swix"`,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM products p WHERE \\(p.testing_team = \\$1 OR p.is_public\\) "+
+					"AND to_tsvector\\(.*\\) @@ to_tsquery\\('simple', \\$2\\) AND p.type = \\$3 AND p.status = \\$4 "+
+					"AND p.is_public AND \\(p.low_temperature IS NULL OR p.low_temperature <= \\$5\\) "+
+					"AND \\(p.high_temperature IS NULL OR p.high_temperature >= \\$5\\)").
+					WithArgs(1, "swix:* & blu:*", "solid", "active", -8.0).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+				mock.ExpectQuery("SELECT p.id, .* FROM products p LEFT JOIN product_ratings pr .* "+
+					"ORDER BY ts_rank\\(.*\\) DESC, lower\\(p.name\\), p.id LIMIT \\$6 OFFSET \\$7").
+					WithArgs(1, "swix:* & blu:*", "solid", "active", -8.0, 1, 1).
+					WillReturnRows(sqlmock.NewRows(searchColumnNames).
+						AddRow(3, "Blue Extra", "Swix", "", "", "", true, "solid", -2.0, -10.0, 2, version, "active",
+							4.5))
+			},
+		},
+		{
+			name:         "Status OK - private products by rating",
+			path:         "/products/search?public=false&sort=rating",
+			expectedCode: http.StatusOK,
+			expectedBody: `"products":[{"id":5,"name":"Team Klister"`,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM products p WHERE \\(p.testing_team = \\$1 OR p.is_public\\) " +
+					"AND NOT p.is_public AND p.testing_team = \\$1;").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+				mock.ExpectQuery("SELECT p.id, .* ORDER BY pr.rating DESC NULLS LAST, lower\\(p.name\\), p.id "+
+					"LIMIT \\$2 OFFSET \\$3").
+					WithArgs(1, 25, 0).
+					WillReturnRows(sqlmock.NewRows(searchColumnNames).
+						AddRow(5, "Team Klister", "", "", "", "", false, "gel", 3.0, -2.0, 1, version, "development",
+							nil))
+			},
+		},
+		{
+			name:         "Status OK - no products",
+			path:         "/products/search?q=zzz",
+			expectedCode: http.StatusOK,
+			expectedBody: `{"total":0,"page":1,"page_size":25,"products":[]}`,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM products p").
+					WithArgs(1, "zzz:*").
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+				mock.ExpectQuery("SELECT p.id, .* FROM products p").
+					WithArgs(1, "zzz:*", 25, 0).
+					WillReturnRows(sqlmock.NewRows(searchColumnNames))
+			},
+		},
+		{
+			name:         "Status bad request - type",
+			path:         "/products/search?type=paste",
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Invalid type",
+			setupMocks: func() {
+				AuthenticationMock(mock)
+			},
+		},
+		{
+			name:         "Status bad request - covers",
+			path:         "/products/search?covers=cold",
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Invalid covers",
+			setupMocks: func() {
+				AuthenticationMock(mock)
+			},
+		},
+		{
+			name:         "Status bad request - relevance without text",
+			path:         "/products/search?sort=relevance",
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Invalid sort",
+			setupMocks: func() {
+				AuthenticationMock(mock)
+			},
+		},
+		{
+			name:         "Status bad request - page size",
+			path:         "/products/search?page_size=500",
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Invalid page_size.",
+			setupMocks: func() {
+				AuthenticationMock(mock)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.Header.Set("Authorization", "Bearer mockToken")
+			rr := httptest.NewRecorder()
+
+			ProductsHandler(mockDB).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedCode, rr.Code)
+			assert.Contains(t, rr.Body.String(), tt.expectedBody)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package productsHandler
+
+import (
+	"backend/internal/middleware"
+	"backend/internal/resources"
+	"database/sql"
+	"encoding/json"
+	"log"
+	"net/http"
+	"regexp"
+	"strconv"
+)
+
+var statusChangesPath = regexp.MustCompile(`^/products/(\d+)/statuses/?$`)
+
+// ProductStatusChangesRequestGET is the request handler for the status changes of a product.
+//
+//	@Summary		Get the status changes of a product
+//	@Description	Retrieves every change of the status of a product of the team, with the reason for it, who made
+//	@Description	it and when, oldest first. A product moves from development to active or retired, from active to
+//	@Description	discontinued or retired, and from discontinued back to active or to retired. A retired product can
+//	@Description	not change its status. The bundles of the team that contain a discontinued or retired product move
+//	@Description	to the same status.
+//	@Tags			Products
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int							true	"Product ID"
+//	@Success		200	{array}		domain.ProductStatusChange	"The status changes of the product"
+//	@Failure		404	{string}	string						"Could not retrieve the product"
+//	@Failure		500	{string}	string						"Could not retrieve the status changes."
+//	@Router			/products/{id}/statuses [get]
+func ProductStatusChangesRequestGET(w http.ResponseWriter, r *http.Request, db *sql.DB) {
+	productID, _ := strconv.Atoi(statusChangesPath.FindStringSubmatch(r.URL.Path)[1])
+
+	// Get the user's team membership.
+	team := middleware.GetUserTeamRole(w, r, db)
+
+	// Only the team of the product can see who changed it and why.
+	var count int
+	err := db.QueryRow("SELECT COUNT(*) FROM products WHERE id = $1 AND testing_team = $2;", productID, team).
+		Scan(&count)
+	if err != nil || count == 0 {
+		http.Error(w, resources.CouldNotRetrieveProduct, http.StatusNotFound)
+		log.Printf("Could not retrieve the product %d of team %d", productID, team)
+		return
+	}
+
+	changes, err := getStatusChanges(db, productID)
+	if err != nil {
+		http.Error(w, "Could not retrieve the status changes.", http.StatusInternalServerError)
+		log.Println("Could not retrieve the status changes: " + err.Error())
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(changes)
+	if err != nil {
+		http.Error(w, "Could not JSON encode the response.", http.StatusInternalServerError)
+		log.Println("Could not JSON encode the response: " + err.Error())
+	}
+}
@@ -0,0 +1,207 @@
+package productsHandler
+
+import (
+	"backend/internal/domain"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// validateStatusUpdate checks that a product can move to its new status. A product can not be retired while it, or
+// a bundle containing it, is in a planned or in progress test of its team, as the test could not be completed with
+// it. A discontinued product may still be in stock, so it can be discontinued during a test.
+func validateStatusUpdate(db *sql.DB, update ProductUpdateFields, existingProduct domain.Product) (error, int) {
+	if update.Status == nil || domain.Status(*update.Status) == domain.Status(existingProduct.Status) {
+		return nil, 0
+	}
+
+	current, next := domain.Status(existingProduct.Status), domain.Status(*update.Status)
+	if !current.CanTransitionTo(next) {
+		log.Println("Product cannot move from " + string(current) + " to " + string(next))
+		return fmt.Errorf("product cannot move from %s to %s, %d", current, next, http.StatusConflict),
+			http.StatusConflict
+	}
+
+	if next != domain.Retired {
+		return nil, 0
+	}
+	tests, err := getOpenTests(db, existingProduct.ID, existingProduct.TestingTeam)
+	if err != nil {
+		log.Println("Could not retrieve the open tests of the product: " + err.Error())
+		return fmt.Errorf("could not retrieve the open tests of the product, %d", http.StatusInternalServerError),
+			http.StatusInternalServerError
+	}
+	if len(tests) > 0 {
+		ids := make([]string, len(tests))
+		for i, id := range tests {
+			ids[i] = strconv.Itoa(id)
+		}
+		log.Println("Product is in the open tests " + strings.Join(ids, ", "))
+		return fmt.Errorf("product cannot be retired while it is in the open tests %s, %d",
+			strings.Join(ids, ", "), http.StatusConflict), http.StatusConflict
+	}
+	return nil, 0
+}
+
+// getOpenTests retrieves the planned and in progress tests of the team that include the product, or a bundle
+// containing it.
+func getOpenTests(db *sql.DB, productID int, team int) ([]int, error) {
+	rows, err := db.Query(`SELECT DISTINCT t.id FROM tests t
+							JOIN test_ranks r ON r.test_id = t.id
+							WHERE t.testing_team = $2 AND t.state IN ('planned', 'in_progress')
+							  AND (r.product_id = $1 OR r.product_id IN (
+							      SELECT b.product_id FROM bundles b
+							      JOIN product_bundles l ON l.bundle_id = b.id WHERE l.product_id = $1))
+							ORDER BY t.id;`, productID, team)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tests []int
+	for rows.Next() {
+		var id int
+		if err = rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		tests = append(tests, id)
+	}
+	return tests, rows.Err()
+}
+
+// newStatusChange returns the status change made by a product update, or nil when the status is unchanged.
+func newStatusChange(updates map[string]interface{}, existingProduct domain.Product, reason string, userID int,
+) *domain.ProductStatusChange {
+	next, ok := updates["status"].(string)
+	if !ok || next == existingProduct.Status {
+		return nil
+	}
+
+	change := &domain.ProductStatusChange{
+		ProductID:  existingProduct.ID,
+		FromStatus: domain.Status(existingProduct.Status),
+		ToStatus:   domain.Status(next),
+		Reason:     reason,
+		ChangedAt:  time.Now(),
+	}
+	if userID != 0 {
+		change.ChangedBy = &userID
+	}
+	return change
+}
+
+// insertStatusChange records a change of the status of a product.
+func insertStatusChange(tx *sql.Tx, change domain.ProductStatusChange) error {
+	_, err := tx.Exec(`INSERT INTO product_status_changes (product_id, from_status, to_status, reason, changed_by,
+                                    changed_at)
+							VALUES ($1, $2, $3, $4, $5, $6);`,
+		change.ProductID,
+		change.FromStatus,
+		change.ToStatus,
+		change.Reason,
+		change.ChangedBy,
+		change.ChangedAt)
+	return err
+}
+
+// cascadeStatusToBundles moves the bundles containing a discontinued or retired product to the same status, as they
+// can no longer be made. Only the bundles of the team of the product are changed, and only when they can make the
+// transition. Each change is recorded in the history and status changes of the bundle. It returns the IDs of the
+// changed bundles.
+func cascadeStatusToBundles(tx *sql.Tx, change domain.ProductStatusChange) ([]int, error) {
+	if !change.ToStatus.IsWithdrawn() {
+		return nil, nil
+	}
+
+	rows, err := tx.Query(`SELECT p.id, p.status FROM products p
+							JOIN bundles b ON b.product_id = p.id
+							JOIN product_bundles l ON l.bundle_id = b.id
+							WHERE l.product_id = $1
+							  AND p.testing_team = (SELECT testing_team FROM products WHERE id = $1)
+							ORDER BY p.id
+							FOR UPDATE OF p;`, change.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	var bundles []domain.ProductStatusChange
+	for rows.Next() {
+		bundle := domain.ProductStatusChange{
+			ToStatus:  change.ToStatus,
+			Reason:    fmt.Sprintf("Product %d in the bundle was %s.", change.ProductID, change.ToStatus),
+			ChangedBy: change.ChangedBy,
+			ChangedAt: change.ChangedAt,
+		}
+		if err = rows.Scan(&bundle.ProductID, &bundle.FromStatus); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		if bundle.FromStatus.CanTransitionTo(bundle.ToStatus) {
+			bundles = append(bundles, bundle)
+		}
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var ids []int
+	for _, bundle := range bundles {
+		_, err = tx.Exec("UPDATE products SET status = $1, version = $2 WHERE id = $3;",
+			bundle.ToStatus, bundle.ChangedAt, bundle.ProductID)
+		if err != nil {
+			return nil, err
+		}
+
+		oldValue, _ := json.Marshal(bundle.FromStatus)
+		newValue, _ := json.Marshal(bundle.ToStatus)
+		_, err = insertProductChanges(tx, []domain.ProductChange{{
+			ProductID: bundle.ProductID,
+			Field:     "status",
+			OldValue:  oldValue,
+			NewValue:  newValue,
+			ChangedBy: bundle.ChangedBy,
+			ChangedAt: bundle.ChangedAt,
+		}})
+		if err != nil {
+			return nil, err
+		}
+
+		if err = insertStatusChange(tx, bundle); err != nil {
+			return nil, err
+		}
+		ids = append(ids, bundle.ProductID)
+	}
+	return ids, nil
+}
+
+// getStatusChanges retrieves the status changes of a product, oldest first.
+func getStatusChanges(db *sql.DB, productID int) ([]domain.ProductStatusChange, error) {
+	rows, err := db.Query(`SELECT id, product_id, from_status, to_status, reason, changed_by, changed_at
+								FROM product_status_changes WHERE product_id = $1 ORDER BY changed_at, id;`, productID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	changes := []domain.ProductStatusChange{}
+	for rows.Next() {
+		var change domain.ProductStatusChange
+		if err = rows.Scan(
+			&change.ID,
+			&change.ProductID,
+			&change.FromStatus,
+			&change.ToStatus,
+			&change.Reason,
+			&change.ChangedBy,
+			&change.ChangedAt); err != nil {
+			return nil, err
+		}
+		changes = append(changes, change)
+	}
+	return changes, rows.Err()
+}
@@ -0,0 +1,199 @@
+package productsHandler
+
+import (
+	"backend/internal/utils"
+	"database/sql"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProductsRequestPATCH_status(t *testing.T) {
+	mockDB, mock := utils.InitMockDB(t)
+
+	version := time.Date(2025, 1, 12, 10, 0, 0, 0, time.UTC)
+	productMock := func(status string) {
+		mock.ExpectQuery("SELECT \\* FROM products WHERE id = \\$1;").
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows(productColumns).
+				AddRow(1, "Blue Wax", "Swix", "", "", "", false, "solid", -2.0, -8.0, 1, version, status))
+	}
+	openTestsMock := func() *sqlmock.ExpectedQuery {
+		return mock.ExpectQuery("SELECT DISTINCT t.id FROM tests t JOIN test_ranks r ON r.test_id = t.id "+
+			"WHERE t.testing_team = \\$2 AND t.state IN \\('planned', 'in_progress'\\)").
+			WithArgs(1, 1)
+	}
+	historyMock := func(productID int, revision int, from string, to string) {
+		mock.ExpectQuery("SELECT COALESCE\\(MAX\\(revision\\), 0\\) \\+ 1 FROM product_history WHERE product_id = \\$1;").
+			WithArgs(productID).
+			WillReturnRows(sqlmock.NewRows([]string{"revision"}).AddRow(revision))
+		mock.ExpectExec("INSERT INTO product_history").
+			WithArgs(productID, revision, "status", `"`+from+`"`, `"`+to+`"`, 1, sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+	}
+
+	tests := []struct {
+		name         string
+		body         string
+		setupMocks   func()
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:         "Status OK - retired with its bundles",
+			body:         `{"updates":{"status":"retired"},"version":"2025-01-12T10:00:00Z","reason":"Out of production."}`,
+			expectedCode: http.StatusOK,
+			expectedBody: `"bundles":[7]`,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				productMock("active")
+				openTestsMock().WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				mock.ExpectQuery("SELECT \\* FROM products WHERE testing_team = \\$1 AND name = \\$2;").
+					WithArgs(1, "").
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectQuery("SELECT user_id FROM sessions WHERE \\(session_token = \\$1 AND expires_at > NOW\\(\\)\\)").
+					WithArgs("mockToken").
+					WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(1))
+
+				mock.ExpectBegin()
+				mock.ExpectQuery("UPDATE products SET status = \\$1, version = \\$2 WHERE id = \\$3 AND version = \\$4 RETURNING version").
+					WithArgs("retired", sqlmock.AnyArg(), 1, version).
+					WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(version.Add(time.Hour)))
+				historyMock(1, 3, "active", "retired")
+				mock.ExpectExec("INSERT INTO product_status_changes").
+					WithArgs(1, "active", "retired", "Out of production.", 1, sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+
+				// Bundle 7 follows the product, while bundle 8 is already retired.
+				mock.ExpectQuery("SELECT p.id, p.status FROM products p JOIN bundles b ON b.product_id = p.id").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).AddRow(7, "active").AddRow(8, "retired"))
+				mock.ExpectExec("UPDATE products SET status = \\$1, version = \\$2 WHERE id = \\$3;").
+					WithArgs("retired", sqlmock.AnyArg(), 7).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				historyMock(7, 1, "active", "retired")
+				mock.ExpectExec("INSERT INTO product_status_changes").
+					WithArgs(7, "active", "retired", "Product 1 in the bundle was retired.", 1, sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:         "Status conflict - in open tests",
+			body:         `{"updates":{"status":"retired"},"version":"2025-01-12T10:00:00Z"}`,
+			expectedCode: http.StatusConflict,
+			expectedBody: "Validation error: product cannot be retired while it is in the open tests 3, 5",
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				productMock("active")
+				openTestsMock().WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3).AddRow(5))
+			},
+		},
+		{
+			name:         "Status conflict - transition not allowed",
+			body:         `{"updates":{"status":"development"},"version":"2025-01-12T10:00:00Z"}`,
+			expectedCode: http.StatusConflict,
+			expectedBody: "Validation error: product cannot move from active to development",
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				productMock("active")
+			},
+		},
+		{
+			name:         "Status bad request - tested is not a status",
+			body:         `{"updates":{"status":"tested"},"version":"2025-01-12T10:00:00Z"}`,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "invalid PATCH request body values",
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				productMock("development")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+
+			req := httptest.NewRequest(http.MethodPatch, "/products/1", strings.NewReader(tt.body))
+			req.Header.Set("Authorization", "Bearer mockToken")
+			rr := httptest.NewRecorder()
+
+			ProductsHandler(mockDB).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedCode, rr.Code)
+			assert.Contains(t, rr.Body.String(), tt.expectedBody)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestProductStatusChangesRequestGET(t *testing.T) {
+	mockDB, mock := utils.InitMockDB(t)
+
+	changedAt := time.Date(2025, 1, 12, 10, 0, 0, 0, time.UTC)
+	ownerMock := func(count int) {
+		mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM products WHERE id = \\$1 AND testing_team = \\$2;").
+			WithArgs(1, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(count))
+	}
+
+	tests := []struct {
+		name         string
+		setupMocks   func()
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:         "Status OK",
+			expectedCode: http.StatusOK,
+			expectedBody: `[{"id":4,"product_id":1,"from_status":"development","to_status":"active",` +
+				`"reason":"Passed the tests.","changed_by":1,"changed_at":"2025-01-12T10:00:00Z"}]`,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				ownerMock(1)
+				mock.ExpectQuery("SELECT id, product_id, from_status, to_status, reason, changed_by, changed_at " +
+					"FROM product_status_changes WHERE product_id = \\$1").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "from_status", "to_status", "reason",
+						"changed_by", "changed_at"}).
+						AddRow(4, 1, "development", "active", "Passed the tests.", 1, changedAt))
+			},
+		},
+		{
+			name:         "Status not found - product of another team",
+			expectedCode: http.StatusNotFound,
+			expectedBody: "Could not retrieve the product",
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				ownerMock(0)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+
+			req := httptest.NewRequest(http.MethodGet, "/products/1/statuses", nil)
+			req.Header.Set("Authorization", "Bearer mockToken")
+			rr := httptest.NewRecorder()
+
+			ProductsHandler(mockDB).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedCode, rr.Code)
+			assert.Contains(t, rr.Body.String(), tt.expectedBody)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
@@ -18,6 +18,10 @@ import (
 	"time"
 )
 
+func float(value float64) *float64 {
+	return &value
+}
+
 func resetTime(products []domain.Product) []domain.Product {
 	for i := range products {
 		products[i].Version = time.Time{}
@@ -42,15 +46,22 @@ func AuthenticationMock(mock sqlmock.Sqlmock) {
 		WillReturnRows(sqlmock.NewRows([]string{"team_role"}).AddRow(1))
 }
 
+// UserIDMock mocks the lookup of the authenticated user.
+func UserIDMock(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery("SELECT user_id FROM sessions WHERE \\(session_token = \\$1 AND expires_at > NOW\\(\\)\\)").
+		WithArgs("mockToken").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(1))
+}
+
 var productColumns = []string{"id", "name", "brand", "ean_code", "image_url", "comment", "is_public",
 	"type", "high_temperature", "low_temperature", "testing_team", "version", "status"}
 
 var productsArray = []domain.Product{
 	{ID: 1, Name: "Product1", Brand: "Brand1", EANCode: "1234567890123", ImageURL: "", Type: "Type1",
-		HighTemperature: 1.0, LowTemperature: -1.0, Comment: "Comment1", TestingTeam: 1, IsPublic: false,
+		HighTemperature: float(1.0), LowTemperature: float(-1.0), Comment: "Comment1", TestingTeam: 1, IsPublic: false,
 		Version: time.Time{}, Status: "Status1"},
 	{ID: 2, Name: "Product2", Brand: "Brand2", EANCode: "1234567890124", ImageURL: "", Type: "Type2",
-		HighTemperature: 2.0, LowTemperature: -2.0, Comment: "Comment2", TestingTeam: 2, IsPublic: true,
+		HighTemperature: float(2.0), LowTemperature: float(-2.0), Comment: "Comment2", TestingTeam: 2, IsPublic: true,
 		Version: time.Time{}, Status: "Status2"},
 }
 
@@ -63,8 +74,8 @@ var testProduct = domain.Product{
 	Comment:         "Comment1",
 	IsPublic:        false,
 	Type:            "Type1",
-	HighTemperature: 1.0,
-	LowTemperature:  -1.0,
+	HighTemperature: float(1.0),
+	LowTemperature:  float(-1.0),
 	TestingTeam:     1,
 	Version:         time.Time{},
 	Status:          "Status1",
@@ -101,6 +112,7 @@ func TestDirectReferenceUpdateProductAppearances(t *testing.T) {
 			eanCode: "1234567890123",
 			setupMocks: func() {
 				AuthenticationMock(mock)
+				UserIDMock(mock)
 				// Mock the private product query
 				mock.ExpectQuery("SELECT \\* FROM products WHERE id = \\$1 AND ean_code = \\$2 AND is_public = \\$3 AND testing_team = \\$4;").
 					WithArgs(1, "1234567890123", false, 1).
@@ -112,18 +124,24 @@ func TestDirectReferenceUpdateProductAppearances(t *testing.T) {
 				mock.ExpectQuery("SELECT \\* FROM products WHERE ean_code = \\$1 AND is_public = \\$2;").
 					WithArgs("1234567890123", true).
 					WillReturnRows(sqlmock.NewRows(productColumns).
-						AddRow(1, "Product1", "Brand1", "1234567890123", "", "Comment1", false,
-							"Type1", 1.0, 1.0, 1, time.Time{}, "Status1"))
-
-				// Mock the update rankings query
-				mock.ExpectExec("UPDATE test_ranks SET product_id = \\$1 WHERE product_id = \\$2;").
-					WithArgs(1, 1).
-					WillReturnResult(sqlmock.NewResult(1, 1))
-
-				// Mock the delete private product query
-				mock.ExpectExec("DELETE FROM products WHERE id = \\$1 AND testing_team = \\$2;").
-					WithArgs(1, 1).
+						AddRow(2, "Product1", "Brand1", "1234567890123", "", "Comment1", true,
+							"Type1", 1.0, 1.0, 2, time.Time{}, "Status1"))
+
+				// Mock the merge of the private product into the public product
+				mock.ExpectBegin()
+				mergeMock(mock, 2, "{1}", []int{3}, [11]int64{0, 1, 0, 0, 0, 0, 0, 0, 0, 0, 1})
+
+				// Mock the record of the merge in the history of the public product
+				mock.ExpectExec("UPDATE products SET version = \\$1 WHERE id = \\$2;").
+					WithArgs(sqlmock.AnyArg(), 2).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("SELECT COALESCE\\(MAX\\(revision\\), 0\\) \\+ 1 FROM product_history WHERE product_id = \\$1;").
+					WithArgs(2).
+					WillReturnRows(sqlmock.NewRows([]string{"revision"}).AddRow(1))
+				mock.ExpectExec("INSERT INTO product_history").
+					WithArgs(2, 1, "merged_product", "null", "1", 1, sqlmock.AnyArg()).
 					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
 			},
 			wantStatus: http.StatusOK,
 			wantBody:   "",
@@ -134,6 +152,7 @@ func TestDirectReferenceUpdateProductAppearances(t *testing.T) {
 			eanCode: "1234567890123",
 			setupMocks: func() {
 				AuthenticationMock(mock)
+				UserIDMock(mock)
 
 				// Mock the private product query
 				mock.ExpectQuery("SELECT \\* FROM products WHERE id = \\$1 AND ean_code = \\$2 AND is_public = \\$3 AND testing_team = \\$4;").
@@ -149,6 +168,7 @@ func TestDirectReferenceUpdateProductAppearances(t *testing.T) {
 			eanCode: "1234567890123",
 			setupMocks: func() {
 				AuthenticationMock(mock)
+				UserIDMock(mock)
 
 				// Mock the private product query
 				mock.ExpectQuery("SELECT \\* FROM products WHERE id = \\$1 AND ean_code = \\$2 AND is_public = \\$3 AND testing_team = \\$4;").
@@ -363,13 +383,24 @@ func TestGetProductWithID(t *testing.T) {
 				Comment:         "Comment1",
 				IsPublic:        false,
 				Type:            "Type1",
-				HighTemperature: 1.0,
-				LowTemperature:  1.0,
+				HighTemperature: float(1.0),
+				LowTemperature:  float(1.0),
 				TestingTeam:     1,
 				Version:         time.Time{},
 				Status:          "Status1",
 			},
 		},
+		{
+			name:      "Successful retrieval of a bundle without a temperature window",
+			productID: 2,
+			setupMocks: func() {
+				mock.ExpectQuery("SELECT \\* FROM products WHERE id = \\$1;").
+					WithArgs(2).
+					WillReturnRows(sqlmock.NewRows(productColumns).
+						AddRow(2, "Bundle", "Brand1", "", "", "", false, "bundle", nil, nil, 1, time.Time{}, "active"))
+			},
+			want: domain.Product{ID: 2, Name: "Bundle", Brand: "Brand1", Type: "bundle", TestingTeam: 1, Status: "active"},
+		},
 		{
 			name:      "Could not retrieve the product",
 			productID: 1,
@@ -620,7 +651,7 @@ func TestProductsHandler(t *testing.T) {
 			name:         "Method = POST (Status OK)",
 			method:       http.MethodPost,
 			path:         "/products",
-			body:         `{"name":"Product1","brand":"Brand1","ean_code":"1234567890123","image_url":"","comment":"Comment1","is_public":false,"type":"bundle","high_temperature":1.0,"low_temperature":-1.0,"testing_team":1,"status":"active"}`,
+			body:         `{"name":"Product1","brand":"Brand1","ean_code":"1234567890128","image_url":"","comment":"Comment1","is_public":false,"type":"bundle","high_temperature":1.0,"low_temperature":-1.0,"testing_team":1,"status":"active"}`,
 			expectedCode: http.StatusCreated,
 			setupMocks: func() {
 				AuthenticationMock(mock)
@@ -630,11 +661,11 @@ func TestProductsHandler(t *testing.T) {
 
 				// Mock the product retrieval query to return no rows
 				mock.ExpectQuery("SELECT \\* FROM products WHERE testing_team = \\$1 AND ean_code = \\$2;").
-					WithArgs(1, "1234567890123").
+					WithArgs(1, "1234567890128").
 					WillReturnError(sql.ErrNoRows)
 
 				mock.ExpectExec("INSERT INTO products").
-					WithArgs("Product1", "Brand1", "1234567890123", "", "Comment1", false,
+					WithArgs("Product1", "Brand1", "1234567890128", "", "Comment1", false,
 						"bundle", 1.0, -1.0, 1, sqlmock.AnyArg(), "active").
 					WillReturnResult(sqlmock.NewResult(1, 1))
 			},
@@ -658,9 +689,24 @@ func TestProductsHandler(t *testing.T) {
 					WithArgs(1, "Updated product").
 					WillReturnRows(sqlmock.NewRows(productColumns))
 
+				// Mock the user making the change
+				mock.ExpectQuery("SELECT user_id FROM sessions WHERE \\(session_token = \\$1 AND expires_at > NOW\\(\\)\\)").
+					WithArgs("mockToken").
+					WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(1))
+
+				mock.ExpectBegin()
 				mock.ExpectQuery("UPDATE products SET name = \\$1, version = \\$2 WHERE id = \\$3 AND version = \\$4 RETURNING version").
 					WithArgs("Updated product", sqlmock.AnyArg(), 1, time.Time{}).
 					WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(time.Now()))
+
+				// Record the changed name in the history of the product
+				mock.ExpectQuery("SELECT COALESCE\\(MAX\\(revision\\), 0\\) \\+ 1 FROM product_history WHERE product_id = \\$1;").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"revision"}).AddRow(1))
+				mock.ExpectExec("INSERT INTO product_history").
+					WithArgs(1, 1, "name", `"Product1"`, `"Updated product"`, 1, sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
 			},
 		},
 		{
@@ -827,9 +873,24 @@ func TestProductsRequestPATCH(t *testing.T) {
 					WithArgs(1, "Updated product").
 					WillReturnError(sql.ErrNoRows)
 
+				// Mock the user making the change
+				mock.ExpectQuery("SELECT user_id FROM sessions WHERE \\(session_token = \\$1 AND expires_at > NOW\\(\\)\\)").
+					WithArgs("mockToken").
+					WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(1))
+
+				mock.ExpectBegin()
 				mock.ExpectQuery("UPDATE products SET name = \\$1, version = \\$2 WHERE id = \\$3 AND version = \\$4 RETURNING version").
 					WithArgs("Updated product", sqlmock.AnyArg(), 1, time.Time{}).
 					WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(time.Now()))
+
+				// Record the changed name in the history of the product
+				mock.ExpectQuery("SELECT COALESCE\\(MAX\\(revision\\), 0\\) \\+ 1 FROM product_history WHERE product_id = \\$1;").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"revision"}).AddRow(1))
+				mock.ExpectExec("INSERT INTO product_history").
+					WithArgs(1, 1, "name", `"Product1"`, `"Updated product"`, 1, sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
 			},
 			expectedCode: http.StatusOK,
 			expectedBody: "",
@@ -871,10 +932,44 @@ func TestProductsRequestPATCH(t *testing.T) {
 					WillReturnRows(sqlmock.NewRows(productColumns).
 						AddRow(1, "Product1", "Brand1", "1234567890123", "", "Comment1", false,
 							"Type1", 1.0, 1.0, 1, time.Time{}, "Status1"))
+
+				mock.ExpectQuery("SELECT user_id FROM sessions WHERE \\(session_token = \\$1 AND expires_at > NOW\\(\\)\\)").
+					WithArgs("mockToken").
+					WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(1))
+
+				mock.ExpectBegin()
+				mock.ExpectQuery("UPDATE products SET name = \\$1, version = \\$2 WHERE id = \\$3 AND version = \\$4 RETURNING version").
+					WithArgs("Updated product", sqlmock.AnyArg(), 1, time.Time{}).
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
 			},
 			expectedCode: http.StatusConflict,
 			expectedBody: "Could not update the product because of a conflict, please refresh",
 		},
+		{
+			name: "Could not update the product",
+			path: "/products/1",
+			body: `{"updates":{"name":"Updated product"}}`,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+
+				mock.ExpectQuery("SELECT \\* FROM products WHERE id = \\$1").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows(productColumns).
+						AddRow(1, "Product1", "Brand1", "1234567890123", "", "Comment1", false,
+							"Type1", 1.0, 1.0, 1, time.Time{}, "Status1"))
+
+				UserIDMock(mock)
+
+				mock.ExpectBegin()
+				mock.ExpectQuery("UPDATE products SET name = \\$1, version = \\$2 WHERE id = \\$3 AND version = \\$4 RETURNING version").
+					WithArgs("Updated product", sqlmock.AnyArg(), 1, time.Time{}).
+					WillReturnError(sql.ErrConnDone)
+				mock.ExpectRollback()
+			},
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: "Could not update the product.",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -911,7 +1006,7 @@ func TestProductsRequestPOST(t *testing.T) {
 	}{
 		{
 			name: "Create new product",
-			body: `{"name":"Product1","brand":"Brand1","ean_code":"1234567890123","image_url":"","comment":"Comment1","is_public":false,"type":"bundle","high_temperature":1.0,"low_temperature":-1.0,"testing_team":1,"status":"active"}`,
+			body: `{"name":"Product1","brand":"Brand1","ean_code":"1234567890128","image_url":"","comment":"Comment1","is_public":false,"type":"bundle","high_temperature":1.0,"low_temperature":-1.0,"testing_team":1,"status":"active"}`,
 			setupMocks: func() {
 				AuthenticationMock(mock)
 
@@ -919,11 +1014,11 @@ func TestProductsRequestPOST(t *testing.T) {
 					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
 
 				mock.ExpectQuery("SELECT \\* FROM products WHERE testing_team = \\$1 AND ean_code = \\$2;").
-					WithArgs(1, "1234567890123").
+					WithArgs(1, "1234567890128").
 					WillReturnError(sql.ErrNoRows)
 
 				mock.ExpectExec("INSERT INTO products").
-					WithArgs("Product1", "Brand1", "1234567890123", "", "Comment1", false,
+					WithArgs("Product1", "Brand1", "1234567890128", "", "Comment1", false,
 						"bundle", 1.0, -1.0, 1, sqlmock.AnyArg(), "active").
 					WillReturnResult(sqlmock.NewResult(1, 1))
 			},
@@ -941,7 +1036,7 @@ func TestProductsRequestPOST(t *testing.T) {
 		},
 		{
 			name: "Unable to add new product",
-			body: `{"name":"Product1","brand":"Brand1","ean_code":"1234567890123","image_url":"","comment":"Comment1","is_public":false,"type":"bundle","high_temperature":1.0,"low_temperature":-1.0,"testing_team":1,"status":"active"}`,
+			body: `{"name":"Product1","brand":"Brand1","ean_code":"1234567890128","image_url":"","comment":"Comment1","is_public":false,"type":"bundle","high_temperature":1.0,"low_temperature":-1.0,"testing_team":1,"status":"active"}`,
 			setupMocks: func() {
 				AuthenticationMock(mock)
 
@@ -949,11 +1044,11 @@ func TestProductsRequestPOST(t *testing.T) {
 					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
 
 				mock.ExpectQuery("SELECT \\* FROM products WHERE testing_team = \\$1 AND ean_code = \\$2;").
-					WithArgs(1, "1234567890123").
+					WithArgs(1, "1234567890128").
 					WillReturnError(sql.ErrNoRows)
 
 				mock.ExpectExec("INSERT INTO products").
-					WithArgs("Product1", "Brand1", "1234567890123", "", "Comment1", false,
+					WithArgs("Product1", "Brand1", "1234567890128", "", "Comment1", false,
 						"bundle", 1.0, -1.0, 1, sqlmock.AnyArg(), "active").
 					WillReturnError(sql.ErrNoRows)
 			},
@@ -962,7 +1057,7 @@ func TestProductsRequestPOST(t *testing.T) {
 		},
 		{
 			name: "A product with this EAN code already exists",
-			body: `{"name":"Product1","brand":"Brand1","ean_code":"1234567890123","image_url":"","comment":"Comment1","is_public":false,"type":"bundle","high_temperature":1.0,"low_temperature":-1.0,"testing_team":1,"status":"active"}`,
+			body: `{"name":"Product1","brand":"Brand1","ean_code":"1234567890128","image_url":"","comment":"Comment1","is_public":false,"type":"bundle","high_temperature":1.0,"low_temperature":-1.0,"testing_team":1,"status":"active"}`,
 			setupMocks: func() {
 				AuthenticationMock(mock)
 
@@ -970,9 +1065,9 @@ func TestProductsRequestPOST(t *testing.T) {
 					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
 
 				mock.ExpectQuery("SELECT \\* FROM products WHERE testing_team = \\$1 AND ean_code = \\$2;").
-					WithArgs(1, "1234567890123").
+					WithArgs(1, "1234567890128").
 					WillReturnRows(sqlmock.NewRows(productColumns).
-						AddRow(1, "Product1", "Brand1", "1234567890123", "", "Comment1", false,
+						AddRow(1, "Product1", "Brand1", "1234567890128", "", "Comment1", false,
 							"bundle", 1.0, -1.0, 1, time.Time{}, "active"))
 			},
 			expectedCode: http.StatusConflict,
@@ -980,7 +1075,7 @@ func TestProductsRequestPOST(t *testing.T) {
 		},
 		{
 			name: "Product creation failed (product count failed)",
-			body: `{"name":"Product1","brand":"Brand1","ean_code":"1234567890123","image_url":"","comment":"Comment1","is_public":true,"type":"bundle","high_temperature":1.0,"low_temperature":-1.0,"testing_team":1,"status":"active"}`,
+			body: `{"name":"Product1","brand":"Brand1","ean_code":"1234567890128","image_url":"","comment":"Comment1","is_public":true,"type":"bundle","high_temperature":1.0,"low_temperature":-1.0,"testing_team":1,"status":"active"}`,
 			setupMocks: func() {
 				mock.ExpectQuery("SELECT user_id FROM sessions WHERE \\(session_token = \\$1 AND expires_at > NOW\\(\\)\\)").
 					WithArgs("mockToken").
@@ -999,7 +1094,7 @@ func TestProductsRequestPOST(t *testing.T) {
 		},
 		{
 			name: "Researcher cannot create public products",
-			body: `{"name":"Product1","brand":"Brand1","ean_code":"1234567890123","image_url":"","comment":"Comment1","is_public":true,"type":"bundle","high_temperature":1.0,"low_temperature":-1.0,"testing_team":1,"status":"active"}`,
+			body: `{"name":"Product1","brand":"Brand1","ean_code":"1234567890128","image_url":"","comment":"Comment1","is_public":true,"type":"bundle","high_temperature":1.0,"low_temperature":-1.0,"testing_team":1,"status":"active"}`,
 			setupMocks: func() {
 				mock.ExpectQuery("SELECT user_id FROM sessions WHERE \\(session_token = \\$1 AND expires_at > NOW\\(\\)\\)").
 					WithArgs("mockToken").
@@ -1038,6 +1133,15 @@ func TestProductsRequestPOST(t *testing.T) {
 			expectedCode: http.StatusConflict,
 			expectedBody: "This Product already exists, try another name if the EAN code is blank\n",
 		},
+		{
+			name: "Invalid EAN code",
+			body: `{"name":"Product1","brand":"Brand1","ean_code":"1234567890123","image_url":"","comment":"Comment1","is_public":false,"type":"solid","high_temperature":1.0,"low_temperature":-1.0,"testing_team":1,"status":"active"}`,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+			},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Invalid EAN code: the check digit of 1234567890123 should be 8\n",
+		},
 	}
 
 	for _, tt := range tests {
@@ -1182,6 +1286,19 @@ func TestValidateProductPATCHRequestBody(t *testing.T) {
 				http.StatusConflict),
 			code: http.StatusConflict,
 		},
+		{
+			name: "Invalid EAN code",
+			db:   mockDB,
+			productUpdateRequest: ProductPATCHRequest{
+				Updates: map[string]interface{}{"ean_code": "12345678"},
+			},
+			existingProduct: testProduct,
+			team:            1,
+			path:            "/products/1",
+			setupMocks:      func() {},
+			want:            fmt.Errorf("invalid EAN code, %w", errors.New("the check digit of 12345678 should be 0")),
+			code:            http.StatusBadRequest,
+		},
 		{
 			name: "Invalid PATCH request body keys",
 			db:   mockDB,
@@ -1225,8 +1342,8 @@ func TestValidateProductPATCHRequestBody(t *testing.T) {
 				Comment:         "Comment1",
 				IsPublic:        false,
 				Type:            "Type1",
-				HighTemperature: 1.0,
-				LowTemperature:  1.0,
+				HighTemperature: float(1.0),
+				LowTemperature:  float(1.0),
 				TestingTeam:     2,
 				Version:         time.Time{},
 				Status:          "Status1",
@@ -1254,8 +1371,8 @@ func TestValidateProductPATCHRequestBody(t *testing.T) {
 				Comment:         "Comment1",
 				IsPublic:        true,
 				Type:            "Type1",
-				HighTemperature: 1.0,
-				LowTemperature:  1.0,
+				HighTemperature: float(1.0),
+				LowTemperature:  float(1.0),
 				TestingTeam:     2,
 				Version:         time.Time{},
 				Status:          "Status1",
@@ -1321,8 +1438,8 @@ func Test_queryAndScanProduct(t *testing.T) {
 				Comment:         "Comment1",
 				IsPublic:        false,
 				Type:            "Type1",
-				HighTemperature: 1.0,
-				LowTemperature:  1.0,
+				HighTemperature: float(1.0),
+				LowTemperature:  float(1.0),
 				TestingTeam:     1,
 				Version:         time.Time{},
 				Status:          "Status1",
@@ -1,26 +1,39 @@
 package rankingsHandler
 
-//coverage:ignore file
 import (
 	"backend/internal/domain"
+	"backend/internal/handler/testsHandler"
+	"backend/internal/services/access"
+	"backend/internal/utils"
 	"database/sql"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
 	"log"
 	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
 )
 
+const rankingColumns = "r.test_id, r.product_id, r.rank, r.distance_behind, r.is_rank_public, r.version, r.ski_id"
+
+var rankingPath = regexp.MustCompile(`^/rankings/(\d+)/(\d+)/?$`)
+
 // FetchRankings is a function that fetches all rankings from the database.
 func FetchRankings(w http.ResponseWriter, testRanks []domain.TestRank, rows *sql.Rows, err error) {
+	if err != nil {
+		http.Error(w, "Could not retrieve all testRanks.", http.StatusInternalServerError)
+		log.Println("Could not retrieve all testRanks: " + err.Error())
+		return
+	}
+	defer rows.Close()
+
 	for rows.Next() {
-		var rank domain.TestRank
-
-		if err = rows.Scan(
-			&rank.TestID,
-			&rank.ProductID,
-			&rank.Rank,
-			&rank.DistanceBehind,
-			&rank.Version,
-			&rank.IsPublic); err != nil {
+		rank, err := scanRanking(rows)
+		if err != nil {
 			http.Error(w, "Could not retrieve all testRanks.", http.StatusInternalServerError)
 			log.Println("Could not retrieve all testRanks: " + err.Error())
 			return
@@ -43,49 +56,199 @@ func FetchRankings(w http.ResponseWriter, testRanks []domain.TestRank, rows *sql
 		log.Println("Could not encode testRanks: " + err.Error())
 		return
 	}
+}
 
-	return
+func scanRanking(row interface{ Scan(...any) error }) (domain.TestRank, error) {
+	var rank domain.TestRank
+	var position, distanceBehind sql.NullInt64
+	var skiID sql.NullInt64
+	err := row.Scan(
+		&rank.TestID,
+		&rank.ProductID,
+		&position,
+		&distanceBehind,
+		&rank.IsPublic,
+		&rank.Version,
+		&skiID)
+	rank.Rank = int(position.Int64)
+	rank.DistanceBehind = int(distanceBehind.Int64)
+	if skiID.Valid {
+		id := int(skiID.Int64)
+		rank.SkiID = &id
+	}
+	return rank, err
 }
 
-// Todo: Sjekk om denne metoden faktisk skal brukes eller ikke. Har bare supresset errors fra den enn så lenge
-func IsProductPartOfTest(db *sql.DB, ranking RankingsPOSTRequest) (bool, error) {
-	// Check if the product is part of the test.
-	var counter int
-	err := db.QueryRow("SELECT COUNT(*) FROM rankings WHERE product_id = $1 AND test_id = $2;",
-		// ranking.ProductID,
-		ranking.TestID).Scan(&counter)
+// rankingFilter builds the conditions of a rankings query from the test_id, product_id and team_id query parameters.
+// The rankings of the tests of the team are always visible, while the rankings of other teams are only visible when
+// both the test and the rank are public. With public=true only those public rankings are listed, and without any
+// filter the rankings of the team are listed.
+func rankingFilter(w http.ResponseWriter, r *http.Request, team int) (string, []interface{}, error) {
+	conditions := "(t.testing_team = $1 OR (t.is_public AND r.is_rank_public))"
+	args := []interface{}{team}
+
+	filtered := false
+	if r.URL.Query().Get("public") == "true" {
+		conditions += " AND t.is_public AND r.is_rank_public"
+		filtered = true
+	}
+
+	for _, param := range []struct {
+		name   string
+		column string
+	}{{"test_id", "r.test_id"}, {"product_id", "r.product_id"}, {"team_id", "t.testing_team"}} {
+		value := r.URL.Query().Get(param.name)
+		if value == "" {
+			continue
+		}
+
+		id, err := strconv.Atoi(value)
+		if err != nil || id <= 0 {
+			http.Error(w, "Invalid "+param.name+" parameter.", http.StatusBadRequest)
+			return "", nil, fmt.Errorf("invalid %s parameter %s", param.name, value)
+		}
+		args = append(args, id)
+		conditions += fmt.Sprintf(" AND %s = $%d", param.column, len(args))
+		filtered = true
+	}
+
+	if !filtered {
+		conditions += " AND t.testing_team = $1"
+	}
+	return conditions, args, nil
+}
+
+// getRankings retrieves the rankings matching the conditions, ordered by test and rank.
+func getRankings(db *sql.DB, conditions string, args []interface{}) (*sql.Rows, error) {
+	return db.Query(`SELECT `+rankingColumns+` FROM test_ranks r JOIN tests t ON t.id = r.test_id
+							WHERE `+conditions+` ORDER BY r.test_id, r.rank NULLS LAST, r.product_id;`, args...)
+}
+
+// getRanking retrieves the ranking of a product in a test, if it is visible to the team.
+func getRanking(db *sql.DB, testID int, productID int, team int) (domain.TestRank, error) {
+	return scanRanking(db.QueryRow(`SELECT `+rankingColumns+` FROM test_ranks r JOIN tests t ON t.id = r.test_id
+							WHERE r.test_id = $1 AND r.product_id = $2
+							  AND (t.testing_team = $3 OR (t.is_public AND r.is_rank_public));`,
+		testID, productID, team))
+}
+
+// getRankingIDs gets the test and product ID of a ranking from the URL path.
+func getRankingIDs(w http.ResponseWriter, r *http.Request) (int, int, bool) {
+	matches := rankingPath.FindStringSubmatch(r.URL.Path)
+	if matches == nil {
+		http.Error(w, "Invalid request URL, use '/rankings/{test_id}/{product_id}'.", http.StatusBadRequest)
+		log.Println("Invalid request URL: " + r.URL.Path)
+		return 0, 0, false
+	}
+
+	testID, err := utils.GetIDFromURLQuery(w, matches[1])
 	if err != nil {
-		return false, err
+		return 0, 0, false
+	}
+	productID, err := utils.GetIDFromURLQuery(w, matches[2])
+	if err != nil {
+		return 0, 0, false
 	}
+	return testID, productID, true
+}
 
-	// If the product is part of the test, return true.
-	if counter != 0 {
-		return true, nil
+// checkTestWriteAccess checks that the test exists and that the team can change its rankings, writing the error
+// response if not.
+func checkTestWriteAccess(w http.ResponseWriter, db *sql.DB, testID int, team int) bool {
+	testingTeam, isPublic, state, err := access.GetTestAccess(db, testID)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Test not found.", http.StatusNotFound)
+		log.Printf("Test %d not found", testID)
+		return false
+	} else if err != nil {
+		http.Error(w, "Could not retrieve the test.", http.StatusInternalServerError)
+		log.Println("Could not retrieve the test: " + err.Error())
+		return false
 	}
 
-	return false, nil
+	if err, code := access.ValidateWritePermissions("test", testingTeam, isPublic, state, team); err != nil {
+		http.Error(w, "Validation error: "+err.Error(), code)
+		log.Println("Validation error: " + err.Error())
+		return false
+	}
+	return true
 }
 
-/*
-// UpdateAmountOfTests is a function that updates the amount of tests for a product when a ranking is made.
-func UpdateAmountOfTests(w http.ResponseWriter, db *sql.DB, ranking RankingsPOSTRequest) {
-	// Get the amount of tests for the product.
-	var amountOfTests int
-	err := db.QueryRow("SELECT amount_of_tests FROM products WHERE id = $1;", ranking.ProductID).Scan(&amountOfTests)
+// IsProductPartOfTest checks if a product is already ranked in a test.
+func IsProductPartOfTest(db *sql.DB, testID int, productID int) (bool, error) {
+	var counter int
+	err := db.QueryRow("SELECT COUNT(*) FROM test_ranks WHERE test_id = $1 AND product_id = $2;",
+		testID, productID).Scan(&counter)
 	if err != nil {
-		http.Error(w, "Unable to create ranking for this product, please try another one.", http.StatusNotFound)
-		log.Println("Could not retrieve the amount of tests for the product: " + err.Error())
-		return
+		return false, err
 	}
+	return counter != 0, nil
+}
+
+// isUniqueViolation reports whether an insert failed because the row already exists.
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
 
-	// Update the product's amount of tests.
-	_, err = db.Exec("UPDATE products SET amount_of_tests = $1 WHERE id = $2;",
-		amountOfTests+1, ranking.ProductID)
+// insertRanking inserts the ranking of a product in a test and returns its version.
+func insertRanking(tx *sql.Tx, ranking RankingsPOSTRequest, isPublic bool) (time.Time, error) {
+	var version time.Time
+	err := tx.QueryRow(`INSERT INTO test_ranks (
+                      	test_id, product_id, rank, distance_behind, version, is_rank_public, ski_id)
+						VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING version;`,
+		ranking.TestID,
+		ranking.ProductID,
+		ranking.Rank,
+		ranking.DistanceBehind,
+		time.Now(),
+		isPublic,
+		ranking.SkiID).Scan(&version)
+	return version, err
+}
+
+// getTemperatureWarnings updates the temperature range flags of the results of a test, and returns the warning of
+// the product if it is ranked outside its rated temperature range.
+func getTemperatureWarnings(tx *sql.Tx, testID int, productID int) ([]testsHandler.TemperatureWarning, error) {
+	testWarnings, err := testsHandler.UpdateTemperatureRangeFlags(tx, testID)
 	if err != nil {
-		http.Error(w, "Unable to create the new ranking. Please try again.", http.StatusInternalServerError)
-		log.Println("Could not update the amount of tests for the product: " + err.Error())
-		return
+		return nil, err
+	}
+
+	warnings := []testsHandler.TemperatureWarning{}
+	for _, warning := range testWarnings {
+		if warning.ProductID == productID {
+			warnings = append(warnings, warning)
+		}
 	}
+	return warnings, nil
 }
 
-*/
+// updateRanking updates the fields of a ranking if it has not been changed since the version, and returns the new
+// version. sql.ErrNoRows is returned when the ranking does not exist or has been changed.
+func updateRanking(db *sql.DB, testID int, productID int, update RankingsPATCHRequest) (time.Time, error) {
+	var fields []string
+	var args []interface{}
+	if update.Rank != nil {
+		args = append(args, *update.Rank)
+		fields = append(fields, fmt.Sprintf("rank = $%d", len(args)))
+	}
+	if update.DistanceBehind != nil {
+		args = append(args, *update.DistanceBehind)
+		fields = append(fields, fmt.Sprintf("distance_behind = $%d", len(args)))
+	}
+	if update.IsPublic != nil {
+		args = append(args, *update.IsPublic)
+		fields = append(fields, fmt.Sprintf("is_rank_public = $%d", len(args)))
+	}
+	args = append(args, time.Now())
+	fields = append(fields, fmt.Sprintf("version = $%d", len(args)))
+
+	args = append(args, testID, productID, update.Version)
+	query := fmt.Sprintf(`UPDATE test_ranks SET %s WHERE test_id = $%d AND product_id = $%d AND version = $%d
+							RETURNING version;`, strings.Join(fields, ", "), len(args)-2, len(args)-1, len(args))
+
+	var version time.Time
+	err := db.QueryRow(query, args...).Scan(&version)
+	return version, err
+}
@@ -1,23 +1,29 @@
 package rankingsHandler
 
-//coverage:ignore file
 import (
 	"backend/internal/domain"
+	"backend/internal/handler/testsHandler"
 	"backend/internal/middleware"
 	"backend/internal/resources"
+	"backend/internal/services/access"
 	"backend/internal/utils"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
-	"strconv"
-	"time"
 )
 
 // RankingsHandler routes HTTP requests for rankings to the appropriate handler function.
 //
 // It supports the following methods:
-// - GET: Retrieves a list of rankings based on filters.
+// - GET: Retrieves a list of rankings based on filters, or the ranking of a product in a test.
 // - POST: Creates a new ranking.
+// - PATCH: Updates the ranking of a product in a test.
+// - DELETE: Deletes the ranking of a product in a test.
+//
+// A ranking is the result of a product in a test, stored in the test_ranks table, and is identified by the test
+// and product ID: /rankings/{test_id}/{product_id}.
 func RankingsHandler(db *sql.DB) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("content-type", "application/json")
@@ -28,6 +34,8 @@ func RankingsHandler(db *sql.DB) http.HandlerFunc {
 			RankingsRequestPOST(w, r, db)
 		case http.MethodPatch:
 			RankingsRequestPATCH(w, r, db)
+		case http.MethodDelete:
+			RankingsRequestDELETE(w, r, db)
 		default:
 			http.Error(w, resources.MethodNotAllowed, http.StatusNotImplemented)
 			return
@@ -38,85 +46,88 @@ func RankingsHandler(db *sql.DB) http.HandlerFunc {
 // RankingsRequestGET handles GET requests for rankings.
 //
 //	@Summary		Get a list of rankings
-//	@Description	Retrieves a list of rankings based on query parameters.
+//	@Description	Retrieves a list of rankings based on query parameters, or the ranking of a product in a test.
+//	@Description	The rankings of the tests of the team are always visible, while the rankings of other teams are
+//	@Description	only visible when both the test and the rank are public. Without any filter the rankings of the
+//	@Description	team are listed.
 //	@Tags			Rankings
 //	@Accept			json
 //	@Produce		json
 //	@Security		BearerAuth
-//	@Param			public	query		string			false	"Public rankings"
-//	@Param			test_id	query		int				false	"Test ID"
-//	@Param			rank	query		int				false	"Rank"
-//	@Success		200		{array}		domain.TestRank	"Successful response with a list of rankings"
-//	@Failure		400		{string}	string			"Invalid test_id parameter"
-//	@Failure		400		{string}	string			"Invalid rank parameter"
-//	@Failure		500		{string}	string			"Could not retrieve all rankings"
+//	@Param			public		query		string			false	"Only public rankings"
+//	@Param			test_id		query		int				false	"Test ID"
+//	@Param			product_id	query		int				false	"Product ID"
+//	@Param			team_id		query		int				false	"Team ID"
+//	@Success		200			{array}		domain.TestRank	"Successful response with a list of rankings"
+//	@Failure		400			{string}	string			"Invalid test_id parameter."
+//	@Failure		404			{string}	string			"Ranking not found."
+//	@Failure		500			{string}	string			"Could not retrieve all testRanks."
 //	@Router			/rankings [get]
-//	@Router			/rankings/{rank_id} [get]
+//	@Router			/rankings/{test_id}/{product_id} [get]
 func RankingsRequestGET(w http.ResponseWriter, r *http.Request, db *sql.DB) {
-	public := r.URL.Query().Get("public")
-	testIDParam := r.URL.Query().Get("test_id")
-	rankParam := r.URL.Query().Get("rank")
-
-	var testRanks []domain.TestRank
-
-	// Get all public testRanks.
-	if public == "true" {
-		rows, err := db.Query("SELECT * FROM testRanks WHERE is_rank_public = $1;",
-			true)
-		FetchRankings(w, testRanks, rows, err)
-		return
-	}
-
-	if testIDParam != "" && rankParam != "" {
-		// Convert the testID and rank parameters to integers.
-		testID, err1 := strconv.Atoi(testIDParam)
-		rank, err2 := strconv.Atoi(rankParam)
+	// Get the user's team membership.
+	team := middleware.GetUserTeamRole(w, r, db)
 
-		// Check if the testID parameter is invalid.
-		if err1 != nil {
-			http.Error(w, "Invalid test_id parameter)!", http.StatusBadRequest)
-			log.Println("Invalid test_id parameter: " + err1.Error())
+	if rankingPath.MatchString(r.URL.Path) {
+		testID, productID, ok := getRankingIDs(w, r)
+		if !ok {
 			return
 		}
 
-		// Check if the rank parameter is invalid.
-		if err2 != nil {
-			http.Error(w, "Invalid rank parameter!", http.StatusBadRequest)
-			log.Println("Invalid rank parameter: " + err2.Error())
+		rank, err := getRanking(db, testID, productID, team)
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "Ranking not found.", http.StatusNotFound)
+			log.Printf("Ranking of product %d in test %d not found", productID, testID)
+			return
+		} else if err != nil {
+			http.Error(w, "Could not retrieve the ranking.", http.StatusInternalServerError)
+			log.Println("Could not retrieve the ranking: " + err.Error())
 			return
 		}
 
-		// Fetch the testRanks with the given testID and rank from the database.
-		rows, err := db.Query("SELECT * FROM testRanks WHERE test_id = $1 AND rank = $2;", testID, rank)
-		FetchRankings(w, testRanks, rows, err)
+		err = json.NewEncoder(w).Encode(rank)
+		if err != nil {
+			http.Error(w, "Could not encode the ranking.", http.StatusInternalServerError)
+			log.Println("Could not encode the ranking: " + err.Error())
+		}
 		return
 	}
 
-	// Get the user's team membership.
-	team := middleware.GetUserTeamRole(w, r, db)
+	conditions, args, err := rankingFilter(w, r, team)
+	if err != nil {
+		log.Println(err.Error())
+		return
+	}
 
-	// Fetch the product with the given name from the database based on the user's team membership.
-	rows, err := db.Query("SELECT * FROM testRanks WHERE testing_team = $1;", team)
+	var testRanks []domain.TestRank
+	rows, err := getRankings(db, conditions, args)
 	FetchRankings(w, testRanks, rows, err)
-	return
 }
 
 // RankingsRequestPOST handles POST requests for rankings.
 //
 //	@Summary		Create a new ranking
-//	@Description	Creates a new ranking
+//	@Description	Adds the result of a product to a test of the team. The ranking is public if the product is,
+//	@Description	unless is_public is given. A product ranked outside its rated temperature range is flagged and
+//	@Description	returned as a warning, or refused when the team has a strict temperature range.
 //	@Tags			Rankings
 //	@Accept			json
 //	@Produce		json
 //	@Security		BearerAuth
-//	@Param			ranking	body	RankingsPOSTRequest	true	"New ranking information"
-//	@Success		201		"Ranking created successfully"
-//	@Failure		400		{string}	string	"Invalid request body"
-//	@Failure		409		{string}	string	"Product is already part of the test"
-//	@Failure		500		{string}	string	"Could not create ranking"
+//	@Param			ranking	body		RankingsPOSTRequest	true	"New ranking information"
+//	@Success		201		{string}	string				"Ranking created successfully"
+//	@Failure		400		{string}	string				"Invalid POST request body"
+//	@Failure		400		{object}	testsHandler.TestTemperatureResponse	"Products were tested outside their rated temperature range"
+//	@Failure		401		{string}	string				"User cannot update this test"
+//	@Failure		404		{string}	string				"Test not found."
+//	@Failure		409		{string}	string				"The product is already part of this test."
+//	@Failure		409		{string}	string				"Test is completed and cannot be changed"
+//	@Failure		500		{string}	string				"Could not create ranking."
 //	@Router			/rankings [post]
 //	@Router			/rankings/ [post]
 func RankingsRequestPOST(w http.ResponseWriter, r *http.Request, db *sql.DB) {
+	// Get the user's team membership.
+	team := middleware.GetUserTeamRole(w, r, db)
 
 	ranking, err := utils.ParseAndValidateRequest[RankingsPOSTRequest](r)
 	if err != nil {
@@ -125,58 +136,237 @@ func RankingsRequestPOST(w http.ResponseWriter, r *http.Request, db *sql.DB) {
 		return
 	}
 
-	var id int
-	err = db.QueryRow("SELECT COUNT(*) FROM rankings;").Scan(&id)
-	if err != nil {
-		http.Error(w, "Ranking creation failed", http.StatusBadRequest)
-		log.Println("Unable to get the total ranking count: " + err.Error())
+	// Check if the new ranking is set to public and the user is a researcher.
+	if ranking.IsPublic != nil && *ranking.IsPublic && domain.TeamRole(team) == domain.Researcher {
+		http.Error(w, "Researcher cannot create public rankings", http.StatusBadRequest)
+		log.Println("Researcher cannot create public rankings")
+		return
+	}
+
+	if !checkTestWriteAccess(w, db, ranking.TestID, team) {
 		return
 	}
 
+	isPublic, err := access.GetProductAvailability(db, ranking.ProductID, team)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "The product does not exist.", http.StatusBadRequest)
+		log.Printf("Product %d does not exist", ranking.ProductID)
+		return
+	} else if err != nil {
+		http.Error(w, "Could not create ranking.", http.StatusInternalServerError)
+		log.Println("Could not retrieve the product: " + err.Error())
+		return
+	}
+	if ranking.IsPublic != nil {
+		isPublic = *ranking.IsPublic
+	}
+
+	if ranking.SkiID != nil {
+		found, err := access.IsTeamSki(db, *ranking.SkiID, team)
+		if err != nil {
+			http.Error(w, "Could not create ranking.", http.StatusInternalServerError)
+			log.Println("Could not retrieve the ski: " + err.Error())
+			return
+		}
+		if !found {
+			http.Error(w, "The ski does not exist.", http.StatusBadRequest)
+			log.Printf("Ski %d does not exist", *ranking.SkiID)
+			return
+		}
+	}
+
 	// Check if the product is already part of a test.
-	partOfTest, checkErr := IsProductPartOfTest(db, ranking)
-	if checkErr != nil {
+	partOfTest, err := IsProductPartOfTest(db, ranking.TestID, ranking.ProductID)
+	if err != nil {
 		http.Error(w, "Could not create the new ranking. Please try again.", http.StatusInternalServerError)
-		log.Println("Could not check if the product is part of the test: " + checkErr.Error())
+		log.Println("Could not check if the product is part of the test: " + err.Error())
+		return
+	}
+	if partOfTest {
+		http.Error(w, "The product is already part of this test.", http.StatusConflict)
+		log.Println("The product is already part of the test.")
 		return
 	}
 
-	if !partOfTest {
-		// Check if the new ranking is set to public and the user is a researcher.
-		if ranking.IsPublic == true {
-			http.Error(w, "Researcher cannot create public rankings", http.StatusBadRequest)
-			log.Println("Researcher cannot create public rankings")
-			return
-		} else {
-			_, err = db.Exec(
-				`INSERT INTO rankings (
-                      test_id, 
-                      rank,
-                      wins,
-                      is_public,
-                      version) 
-				VALUES ($1, $2, $3, $4, $5);`,
-				ranking.TestID,
-				ranking.Rank,
-				ranking.Wins,
-				ranking.IsPublic, time.Now())
-			if err != nil {
-				http.Error(w, "Could not create ranking.", http.StatusInternalServerError)
-				log.Println("Could not create ranking: " + err.Error())
-				return
+	tx, err := db.Begin()
+	if err != nil {
+		http.Error(w, resources.TransactionStartFailed, http.StatusInternalServerError)
+		log.Println(resources.TransactionStartFailed + ": " + err.Error())
+		return
+	}
+	defer func() {
+		if err != nil {
+			rollbackErr := tx.Rollback()
+			if rollbackErr != nil {
+				log.Println(resources.RollbackFailed + rollbackErr.Error())
 			}
-
-			// Return a successful response.
-			w.WriteHeader(http.StatusCreated)
-			return
 		}
-	} else {
+	}()
+
+	// A ranking inserted concurrently since the check is a conflict as well.
+	version, err := insertRanking(tx, ranking, isPublic)
+	if isUniqueViolation(err) {
 		http.Error(w, "The product is already part of this test.", http.StatusConflict)
 		log.Println("The product is already part of the test.")
 		return
+	} else if err != nil {
+		http.Error(w, "Could not create ranking.", http.StatusInternalServerError)
+		log.Println("Could not create ranking: " + err.Error())
+		return
+	}
+
+	// Flag the product if it is ranked outside its rated temperature range, which a strict team refuses.
+	warnings, err := getTemperatureWarnings(tx, ranking.TestID, ranking.ProductID)
+	if err != nil {
+		http.Error(w, "Could not check the temperature ranges.", http.StatusInternalServerError)
+		log.Println("Could not check the temperature ranges: " + err.Error())
+		return
+	}
+	if err = testsHandler.RefuseOutOfTemperatureRange(w, tx, team, warnings); err != nil {
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		http.Error(w, resources.TransactionCommitFailed, http.StatusInternalServerError)
+		log.Println(resources.TransactionCommitFailed + ": " + err.Error())
+		return
+	}
+	testsHandler.NotifyTestChanged(ranking.TestID)
+
+	w.WriteHeader(http.StatusCreated)
+	err = json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":  "Ranking created successfully",
+		"version":  version,
+		"warnings": warnings,
+	})
+	if err != nil {
+		log.Println("Could not JSON encode the response: " + err.Error())
 	}
 }
 
+// RankingsRequestPATCH handles PATCH requests for rankings.
+//
+//	@Summary		Update a ranking
+//	@Description	Updates the rank, distance behind and visibility of a product in a test. The version must be the
+//	@Description	version of the ranking the update is based on, and the update is rejected with a conflict if the
+//	@Description	ranking has been changed since.
+//	@Tags			Rankings
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			test_id		path		int						true	"Test ID"
+//	@Param			product_id	path		int						true	"Product ID"
+//	@Param			ranking		body		RankingsPATCHRequest	true	"Ranking updates"
+//	@Success		200			{string}	string					"Ranking updated successfully"
+//	@Failure		400			{string}	string					"Invalid PATCH request body"
+//	@Failure		401			{string}	string					"User cannot update this test"
+//	@Failure		404			{string}	string					"Ranking not found."
+//	@Failure		409			{string}	string					"Detected a conflict for the current ranking, please refresh."
+//	@Failure		409			{string}	string					"Test is completed and cannot be changed"
+//	@Failure		500			{string}	string					"Could not update the ranking."
+//	@Router			/rankings/{test_id}/{product_id} [patch]
 func RankingsRequestPATCH(w http.ResponseWriter, r *http.Request, db *sql.DB) {
+	testID, productID, ok := getRankingIDs(w, r)
+	if !ok {
+		return
+	}
+
+	// Get the user's team membership.
+	team := middleware.GetUserTeamRole(w, r, db)
+
+	update, err := utils.ParseAndValidateRequest[RankingsPATCHRequest](r)
+	if err != nil {
+		http.Error(w, resources.InvalidPATCHRequest, http.StatusBadRequest)
+		log.Println(resources.InvalidPATCHRequest + ": " + err.Error())
+		return
+	}
+	if update.Rank == nil && update.DistanceBehind == nil && update.IsPublic == nil {
+		http.Error(w, resources.NoFieldsToUpdate, http.StatusBadRequest)
+		log.Println(resources.NoFieldsToUpdate)
+		return
+	}
+	if update.IsPublic != nil && *update.IsPublic && domain.TeamRole(team) == domain.Researcher {
+		http.Error(w, "Researcher cannot make rankings public", http.StatusBadRequest)
+		log.Println("Researcher cannot make rankings public")
+		return
+	}
+
+	if !checkTestWriteAccess(w, db, testID, team) {
+		return
+	}
+
+	newVersion, err := updateRanking(db, testID, productID, update)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Either the ranking does not exist, or it has been changed since the version of the update.
+		exists, existsErr := IsProductPartOfTest(db, testID, productID)
+		if existsErr == nil && !exists {
+			http.Error(w, "Ranking not found.", http.StatusNotFound)
+			log.Printf("Ranking of product %d in test %d not found", productID, testID)
+			return
+		}
+		http.Error(w, "Detected a conflict for the current ranking, please refresh.", http.StatusConflict)
+		log.Println("Detected a conflict for the current ranking, please refresh.")
+		return
+	} else if err != nil {
+		http.Error(w, "Could not update the ranking.", http.StatusInternalServerError)
+		log.Println("Could not update the ranking: " + err.Error())
+		return
+	}
+	testsHandler.NotifyTestChanged(testID)
+
+	err = json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Ranking updated successfully",
+		"version": newVersion,
+	})
+	if err != nil {
+		http.Error(w, "Could not JSON encode the response.", http.StatusInternalServerError)
+		log.Println("Could not JSON encode the response: " + err.Error())
+	}
+}
+
+// RankingsRequestDELETE handles DELETE requests for rankings.
+//
+//	@Summary		Delete a ranking
+//	@Description	Removes the result of a product from a test of the team.
+//	@Tags			Rankings
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			test_id		path	int	true	"Test ID"
+//	@Param			product_id	path	int	true	"Product ID"
+//	@Success		204			"Ranking deleted successfully"
+//	@Failure		401			{string}	string	"User cannot update this test"
+//	@Failure		404			{string}	string	"Ranking not found."
+//	@Failure		409			{string}	string	"Test is completed and cannot be changed"
+//	@Failure		500			{string}	string	"Could not delete the ranking."
+//	@Router			/rankings/{test_id}/{product_id} [delete]
+func RankingsRequestDELETE(w http.ResponseWriter, r *http.Request, db *sql.DB) {
+	testID, productID, ok := getRankingIDs(w, r)
+	if !ok {
+		return
+	}
+
+	// Get the user's team membership.
+	team := middleware.GetUserTeamRole(w, r, db)
+
+	if !checkTestWriteAccess(w, db, testID, team) {
+		return
+	}
+
+	result, err := db.Exec("DELETE FROM test_ranks WHERE test_id = $1 AND product_id = $2;", testID, productID)
+	if err != nil {
+		http.Error(w, "Could not delete the ranking.", http.StatusInternalServerError)
+		log.Println("Could not delete the ranking: " + err.Error())
+		return
+	}
+
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		http.Error(w, "Ranking not found.", http.StatusNotFound)
+		log.Printf("Ranking of product %d in test %d not found", productID, testID)
+		return
+	}
+	testsHandler.NotifyTestChanged(testID)
 
+	w.WriteHeader(http.StatusNoContent)
 }
@@ -0,0 +1,10 @@
+package rankingsHandler
+
+import "time"
+
+type RankingsPATCHRequest struct {
+	Rank           *int      `json:"rank" validate:"omitempty,gt=0"`
+	DistanceBehind *int      `json:"distance_behind" validate:"omitempty,gte=0"`
+	IsPublic       *bool     `json:"is_public"`
+	Version        time.Time `json:"version" validate:"required"` // The version of the ranking the update is based on.
+}
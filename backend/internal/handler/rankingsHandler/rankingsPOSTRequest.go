@@ -1,11 +1,10 @@
 package rankingsHandler
 
-import "time"
-
 type RankingsPOSTRequest struct {
-	Wins     int       `json:"wins" validate:"required"`
-	Rank     int       `json:"rank" validate:"required"`
-	TestID   int       `json:"test_id" validate:"required"`
-	IsPublic bool      `json:"is_public"`
-	Version  time.Time `json:"version"`
+	TestID         int   `json:"test_id" validate:"required,gt=0"`
+	ProductID      int   `json:"product_id" validate:"required,gt=0"`
+	Rank           *int  `json:"rank" validate:"omitempty,gt=0"` // Empty for a product that has not been ranked yet.
+	DistanceBehind *int  `json:"distance_behind" validate:"omitempty,gte=0"`
+	IsPublic       *bool `json:"is_public"` // Defaults to the availability of the product.
+	SkiID          *int  `json:"ski_id" validate:"omitempty,gt=0"`
 }
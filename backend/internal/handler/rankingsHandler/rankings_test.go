@@ -0,0 +1,404 @@
+package rankingsHandler
+
+import (
+	"backend/internal/handler/testsHandler"
+	"backend/internal/utils"
+	"database/sql"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func AuthenticationMock(mock sqlmock.Sqlmock) {
+	// Mock the user id query
+	mock.ExpectQuery("SELECT user_id FROM sessions WHERE \\(session_token = \\$1 AND expires_at > NOW\\(\\)\\)").
+		WithArgs("mockToken").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(1))
+
+	// Mock the user team id query
+	mock.ExpectQuery("SELECT team_id FROM users WHERE id = \\$1;").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"team_id"}).AddRow(1))
+
+	// Mock the user team role query
+	mock.ExpectQuery("SELECT team_role FROM team WHERE id = \\$1;").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"team_role"}).AddRow(1))
+}
+
+var rankingRowColumns = []string{"test_id", "product_id", "rank", "distance_behind", "is_rank_public", "version", "ski_id"}
+
+func TestRankingsHandler(t *testing.T) {
+	mockDB, mock := utils.InitMockDB(t)
+
+	version := time.Date(2025, 1, 12, 10, 0, 0, 0, time.UTC)
+	accessMock := func(testingTeam int, state string) {
+		mock.ExpectQuery("SELECT testing_team, is_public, state FROM tests WHERE id = \\$1;").
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"testing_team", "is_public", "state"}).
+				AddRow(testingTeam, false, state))
+	}
+	// The new ranking of product 3 is checked against a test at -5 °C snow and -8 °C air temperature.
+	temperatureRangeMock := func(low float64, high float64) {
+		mock.ExpectQuery("SELECT r.product_id, r.out_of_temperature_range, .* FROM test_ranks r").
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"product_id", "out_of_temperature_range", "type",
+				"low_temperature", "high_temperature", "temperature", "temperature"}).
+				AddRow(3, false, "liquid", low, high, -5.0, -8.0))
+	}
+
+	tests := []struct {
+		name         string
+		method       string
+		path         string
+		body         string
+		setupMocks   func()
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:         "Method = GET (Status OK - rankings of a product)",
+			method:       http.MethodGet,
+			path:         "/rankings?product_id=3",
+			expectedCode: http.StatusOK,
+			expectedBody: `[{"test_id":1,"product_id":3,"rank":1,"distance_behind":0,"is_public":true,` +
+				`"version":"2025-01-12T10:00:00Z","ski_id":4},{"test_id":2,"product_id":3,"rank":0`,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				mock.ExpectQuery("SELECT r.test_id, .* FROM test_ranks r JOIN tests t ON t.id = r.test_id "+
+					"WHERE \\(t.testing_team = \\$1 OR \\(t.is_public AND r.is_rank_public\\)\\) AND r.product_id = \\$2 ORDER BY").
+					WithArgs(1, 3).
+					WillReturnRows(sqlmock.NewRows(rankingRowColumns).
+						AddRow(1, 3, 1, 0, true, version, 4).
+						AddRow(2, 3, nil, nil, false, version, nil))
+			},
+		},
+		{
+			name:         "Method = GET (Status OK - rankings of the team)",
+			method:       http.MethodGet,
+			path:         "/rankings",
+			expectedCode: http.StatusOK,
+			expectedBody: "No testRanks found.",
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				mock.ExpectQuery("WHERE \\(t.testing_team = \\$1 OR \\(t.is_public AND r.is_rank_public\\)\\) " +
+					"AND t.testing_team = \\$1 ORDER BY").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows(rankingRowColumns))
+			},
+		},
+		{
+			name:         "Method = GET (Status bad request - invalid team_id)",
+			method:       http.MethodGet,
+			path:         "/rankings?team_id=blue",
+			expectedCode: http.StatusBadRequest,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+			},
+		},
+		{
+			name:         "Method = GET (Status not found - ranking of another team)",
+			method:       http.MethodGet,
+			path:         "/rankings/1/3",
+			expectedCode: http.StatusNotFound,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				mock.ExpectQuery("SELECT r.test_id, .* WHERE r.test_id = \\$1 AND r.product_id = \\$2").
+					WithArgs(1, 3, 1).
+					WillReturnError(sql.ErrNoRows)
+			},
+		},
+		{
+			name:         "Method = POST (Status created)",
+			method:       http.MethodPost,
+			path:         "/rankings",
+			body:         `{"test_id":1,"product_id":3,"rank":2,"distance_behind":15}`,
+			expectedCode: http.StatusCreated,
+			expectedBody: "Ranking created successfully",
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				accessMock(1, "in_progress")
+				mock.ExpectQuery("SELECT is_public FROM products WHERE id = \\$1 AND \\(is_public OR testing_team = \\$2\\);").
+					WithArgs(3, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"is_public"}).AddRow(true))
+				mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM test_ranks WHERE test_id = \\$1 AND product_id = \\$2;").
+					WithArgs(1, 3).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+				mock.ExpectBegin()
+				mock.ExpectQuery("INSERT INTO test_ranks").
+					WithArgs(1, 3, 2, 15, sqlmock.AnyArg(), true, nil).
+					WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(version))
+				temperatureRangeMock(-10, -2)
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:         "Method = POST (Status created - outside the temperature range)",
+			method:       http.MethodPost,
+			path:         "/rankings",
+			body:         `{"test_id":1,"product_id":3,"rank":2,"distance_behind":15}`,
+			expectedCode: http.StatusCreated,
+			expectedBody: `"warnings":[{"product_id":3,"low_temperature":0,"high_temperature":5`,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				accessMock(1, "in_progress")
+				mock.ExpectQuery("SELECT is_public FROM products").
+					WithArgs(3, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"is_public"}).AddRow(true))
+				mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM test_ranks").
+					WithArgs(1, 3).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+				mock.ExpectBegin()
+				mock.ExpectQuery("INSERT INTO test_ranks").
+					WithArgs(1, 3, 2, 15, sqlmock.AnyArg(), true, nil).
+					WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(version))
+				temperatureRangeMock(0, 5)
+				mock.ExpectExec("UPDATE test_ranks SET out_of_temperature_range = \\$1").
+					WithArgs(true, 1, 3).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("SELECT strict_temperature_range FROM team WHERE id = \\$1;").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"strict_temperature_range"}).AddRow(false))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:         "Method = POST (Status bad request - outside the strict temperature range)",
+			method:       http.MethodPost,
+			path:         "/rankings",
+			body:         `{"test_id":1,"product_id":3,"rank":2,"distance_behind":15}`,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "products were tested outside their rated temperature range",
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				accessMock(1, "in_progress")
+				mock.ExpectQuery("SELECT is_public FROM products").
+					WithArgs(3, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"is_public"}).AddRow(true))
+				mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM test_ranks").
+					WithArgs(1, 3).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+				mock.ExpectBegin()
+				mock.ExpectQuery("INSERT INTO test_ranks").
+					WithArgs(1, 3, 2, 15, sqlmock.AnyArg(), true, nil).
+					WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(version))
+				temperatureRangeMock(0, 5)
+				mock.ExpectExec("UPDATE test_ranks SET out_of_temperature_range = \\$1").
+					WithArgs(true, 1, 3).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("SELECT strict_temperature_range FROM team WHERE id = \\$1;").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"strict_temperature_range"}).AddRow(true))
+				mock.ExpectRollback()
+			},
+		},
+		{
+			name:         "Method = POST (Status conflict - inserted concurrently, unranked)",
+			method:       http.MethodPost,
+			path:         "/rankings",
+			body:         `{"test_id":1,"product_id":3}`,
+			expectedCode: http.StatusConflict,
+			expectedBody: "The product is already part of this test.",
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				accessMock(1, "in_progress")
+				mock.ExpectQuery("SELECT is_public FROM products").
+					WithArgs(3, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"is_public"}).AddRow(true))
+				mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM test_ranks").
+					WithArgs(1, 3).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+				mock.ExpectBegin()
+				mock.ExpectQuery("INSERT INTO test_ranks").
+					WithArgs(1, 3, nil, nil, sqlmock.AnyArg(), true, nil).
+					WillReturnError(&pq.Error{Code: "23505"})
+				mock.ExpectRollback()
+			},
+		},
+		{
+			name:         "Method = POST (Status conflict - product already in the test)",
+			method:       http.MethodPost,
+			path:         "/rankings",
+			body:         `{"test_id":1,"product_id":3,"is_public":false}`,
+			expectedCode: http.StatusConflict,
+			expectedBody: "The product is already part of this test.",
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				accessMock(1, "in_progress")
+				mock.ExpectQuery("SELECT is_public FROM products").
+					WithArgs(3, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"is_public"}).AddRow(true))
+				mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM test_ranks").
+					WithArgs(1, 3).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+			},
+		},
+		{
+			name:         "Method = POST (Status unauthorized - test of another team)",
+			method:       http.MethodPost,
+			path:         "/rankings",
+			body:         `{"test_id":1,"product_id":3}`,
+			expectedCode: http.StatusUnauthorized,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				accessMock(2, "in_progress")
+			},
+		},
+		{
+			name:         "Method = POST (Status bad request - missing product)",
+			method:       http.MethodPost,
+			path:         "/rankings",
+			body:         `{"test_id":1}`,
+			expectedCode: http.StatusBadRequest,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+			},
+		},
+		{
+			name:         "Method = PATCH (Status OK)",
+			method:       http.MethodPatch,
+			path:         "/rankings/1/3",
+			body:         `{"rank":1,"is_public":false,"version":"2025-01-12T10:00:00Z"}`,
+			expectedCode: http.StatusOK,
+			expectedBody: "Ranking updated successfully",
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				accessMock(1, "in_progress")
+				mock.ExpectQuery("UPDATE test_ranks SET rank = \\$1, is_rank_public = \\$2, version = \\$3 "+
+					"WHERE test_id = \\$4 AND product_id = \\$5 AND version = \\$6").
+					WithArgs(1, false, sqlmock.AnyArg(), 1, 3, version).
+					WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(version.Add(time.Minute)))
+			},
+		},
+		{
+			name:         "Method = PATCH (Status conflict - changed since the version)",
+			method:       http.MethodPatch,
+			path:         "/rankings/1/3",
+			body:         `{"distance_behind":5,"version":"2025-01-12T10:00:00Z"}`,
+			expectedCode: http.StatusConflict,
+			expectedBody: "Detected a conflict for the current ranking, please refresh.",
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				accessMock(1, "in_progress")
+				mock.ExpectQuery("UPDATE test_ranks SET distance_behind = \\$1, version = \\$2").
+					WithArgs(5, sqlmock.AnyArg(), 1, 3, version).
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM test_ranks").
+					WithArgs(1, 3).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+			},
+		},
+		{
+			name:         "Method = PATCH (Status conflict - completed test)",
+			method:       http.MethodPatch,
+			path:         "/rankings/1/3",
+			body:         `{"rank":1,"version":"2025-01-12T10:00:00Z"}`,
+			expectedCode: http.StatusConflict,
+			expectedBody: "test is completed and cannot be changed",
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				accessMock(1, "completed")
+			},
+		},
+		{
+			name:         "Method = PATCH (Status bad request - no fields)",
+			method:       http.MethodPatch,
+			path:         "/rankings/1/3",
+			body:         `{"version":"2025-01-12T10:00:00Z"}`,
+			expectedCode: http.StatusBadRequest,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+			},
+		},
+		{
+			name:         "Method = DELETE (Status no content)",
+			method:       http.MethodDelete,
+			path:         "/rankings/1/3",
+			expectedCode: http.StatusNoContent,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				accessMock(1, "in_progress")
+				mock.ExpectExec("DELETE FROM test_ranks WHERE test_id = \\$1 AND product_id = \\$2;").
+					WithArgs(1, 3).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name:         "Method = DELETE (Status not found)",
+			method:       http.MethodDelete,
+			path:         "/rankings/1/3",
+			expectedCode: http.StatusNotFound,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				accessMock(1, "in_progress")
+				mock.ExpectExec("DELETE FROM test_ranks").
+					WithArgs(1, 3).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+		{
+			name:         "Method = PUT (Status not implemented)",
+			method:       http.MethodPut,
+			path:         "/rankings",
+			expectedCode: http.StatusNotImplemented,
+			setupMocks:   func() {},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer mockToken")
+			rr := httptest.NewRecorder()
+
+			RankingsHandler(mockDB).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedCode, rr.Code)
+			if tt.expectedBody != "" {
+				assert.Contains(t, rr.Body.String(), tt.expectedBody)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestRankingsRequestDELETE_notifiesTestChanged(t *testing.T) {
+	mockDB, mock := utils.InitMockDB(t)
+
+	changed := make(chan int, 1)
+	testsHandler.OnTestChanged = func(testID int) { changed <- testID }
+	defer func() { testsHandler.OnTestChanged = nil }()
+
+	AuthenticationMock(mock)
+	mock.ExpectQuery("SELECT testing_team, is_public, state FROM tests WHERE id = \\$1;").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"testing_team", "is_public", "state"}).AddRow(1, false, "in_progress"))
+	mock.ExpectExec("DELETE FROM test_ranks").
+		WithArgs(1, 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	req := httptest.NewRequest(http.MethodDelete, "/rankings/1/3", nil)
+	req.Header.Set("Authorization", "Bearer mockToken")
+	rr := httptest.NewRecorder()
+
+	RankingsHandler(mockDB).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+	select {
+	case testID := <-changed:
+		assert.Equal(t, 1, testID)
+	case <-time.After(time.Second):
+		t.Error("the test change was not notified")
+	}
+}
@@ -0,0 +1,169 @@
+package ratingsHandler
+
+import (
+	"backend/internal/middleware"
+	"backend/internal/resources"
+	"database/sql"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+)
+
+// RatingsHandler routes HTTP requests for the product ratings to the appropriate handler function.
+//
+// It supports the following methods:
+// - GET: Retrieves the leaderboard of the products, or the ratings of a product.
+//
+// The ratings are fitted with a Bradley-Terry model, treating every completed or published test as a set of
+// pairwise comparisons between its products, and are kept up to date as the tests change.
+func RatingsHandler(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		switch r.Method {
+		case http.MethodGet:
+			if productRatingPath.MatchString(r.URL.Path) {
+				ProductRatingRequestGET(w, r, db)
+				return
+			}
+			LeaderboardRequestGET(w, r, db)
+		default:
+			http.Error(w, resources.MethodNotAllowed, http.StatusNotImplemented)
+			return
+		}
+	}
+}
+
+// LeaderboardRequestGET handles GET requests for the leaderboard of the products.
+//
+//	@Summary		Get the leaderboard of the products
+//	@Description	Retrieves the best rated products. The ratings are fitted with a Bradley-Terry model over the
+//	@Description	completed and published tests, where 1500 is an average product and a difference of 400 points
+//	@Description	means the better product wins 10 of 11 comparisons. The ratings are fitted over all the tests, or
+//	@Description	over the tests of a snow type or a 5 °C snow temperature band. The tests are those of the team
+//	@Description	together with the public tests, or only the public tests with public=true.
+//	@Tags			Ratings
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			snow_type	query		string				false	"Only tests of this snow type"	Enums(A1, A2, A3, A4, A5, FS, NS, IN, IT, TR)
+//	@Param			temperature	query		number				false	"Only tests in the temperature band of this snow temperature"
+//	@Param			public		query		string				false	"Only public tests"
+//	@Param			min_tests	query		int					false	"Only products rated in at least this many tests"
+//	@Param			limit		query		int					false	"Number of products, 50 by default and at most 500"
+//	@Success		200			{array}		LeaderboardEntry	"The best rated products"
+//	@Failure		400			{string}	string				"Invalid snow_type."
+//	@Failure		500			{string}	string				"Could not retrieve the leaderboard."
+//	@Router			/ratings/leaderboard [get]
+func LeaderboardRequestGET(w http.ResponseWriter, r *http.Request, db *sql.DB) {
+	if !leaderboardPath.MatchString(r.URL.Path) {
+		http.Error(w, "Invalid request URL, use '/ratings/leaderboard'.", http.StatusBadRequest)
+		log.Println("Invalid request URL: " + r.URL.Path)
+		return
+	}
+
+	// Get the user's team membership.
+	team := middleware.GetUserTeamRole(w, r, db)
+
+	scope, ok := scopeFromQuery(w, r)
+	if !ok {
+		return
+	}
+
+	limit := defaultLeaderboardLimit
+	minTests := 0
+	for _, param := range []struct {
+		name  string
+		value *int
+		max   int
+	}{{"limit", &limit, maxLeaderboardLimit}, {"min_tests", &minTests, 0}} {
+		value := r.URL.Query().Get(param.name)
+		if value == "" {
+			continue
+		}
+		number, err := strconv.Atoi(value)
+		if err != nil || number < 0 || (param.max > 0 && number > param.max) {
+			http.Error(w, "Invalid "+param.name+".", http.StatusBadRequest)
+			log.Println("Invalid " + param.name + ": " + value)
+			return
+		}
+		*param.value = number
+	}
+
+	leaderboard, err := getLeaderboard(db, poolFromQuery(r, team), scope, minTests, limit, team)
+	if err != nil {
+		http.Error(w, "Could not retrieve the leaderboard.", http.StatusInternalServerError)
+		log.Println("Could not retrieve the leaderboard: " + err.Error())
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(leaderboard)
+	if err != nil {
+		http.Error(w, "Could not encode the leaderboard.", http.StatusInternalServerError)
+		log.Println("Could not encode the leaderboard: " + err.Error())
+	}
+}
+
+// ProductRatingRequestGET handles GET requests for the ratings of a product.
+//
+//	@Summary		Get the ratings of a product
+//	@Description	Retrieves the ratings of a product over all the tests and for each snow type and temperature
+//	@Description	band it has been tested in, or only the rating of the scope given by snow_type or temperature.
+//	@Description	The tests are those of the team together with the public tests, or only the public tests with
+//	@Description	public=true.
+//	@Tags			Ratings
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id			path		int						true	"Product ID"
+//	@Param			snow_type	query		string					false	"Only the rating for this snow type"	Enums(A1, A2, A3, A4, A5, FS, NS, IN, IT, TR)
+//	@Param			temperature	query		number					false	"Only the rating for the temperature band of this snow temperature"
+//	@Param			public		query		string					false	"Only public tests"
+//	@Success		200			{array}		domain.ProductRating	"The ratings of the product"
+//	@Failure		400			{string}	string					"Invalid ID"
+//	@Failure		404			{string}	string					"Product not found."
+//	@Failure		500			{string}	string					"Could not retrieve the ratings."
+//	@Router			/products/{id}/rating [get]
+func ProductRatingRequestGET(w http.ResponseWriter, r *http.Request, db *sql.DB) {
+	productID, err := strconv.Atoi(productRatingPath.FindStringSubmatch(r.URL.Path)[1])
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		log.Println("Invalid ID: " + err.Error())
+		return
+	}
+
+	// Get the user's team membership.
+	team := middleware.GetUserTeamRole(w, r, db)
+
+	var scope *ratingScope
+	if r.URL.Query().Get("snow_type") != "" || r.URL.Query().Get("temperature") != "" {
+		queryScope, ok := scopeFromQuery(w, r)
+		if !ok {
+			return
+		}
+		scope = &queryScope
+	}
+
+	visible, err := isProductVisible(db, productID, team)
+	if err != nil {
+		http.Error(w, "Could not retrieve the ratings.", http.StatusInternalServerError)
+		log.Println("Could not retrieve the product: " + err.Error())
+		return
+	}
+	if !visible {
+		http.Error(w, "Product not found.", http.StatusNotFound)
+		log.Printf("Product %d not found", productID)
+		return
+	}
+
+	ratings, err := getProductRatings(db, poolFromQuery(r, team), productID, scope)
+	if err != nil {
+		http.Error(w, "Could not retrieve the ratings.", http.StatusInternalServerError)
+		log.Println("Could not retrieve the ratings: " + err.Error())
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(ratings)
+	if err != nil {
+		http.Error(w, "Could not encode the ratings.", http.StatusInternalServerError)
+		log.Println("Could not encode the ratings: " + err.Error())
+	}
+}
@@ -0,0 +1,410 @@
+package ratingsHandler
+
+import (
+	"backend/internal/domain"
+	"backend/internal/resources"
+	"backend/internal/services/rating"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var (
+	leaderboardPath   = regexp.MustCompile(`^/ratings/leaderboard/?$`)
+	productRatingPath = regexp.MustCompile(`^/products/(\d+)/rating/?$`)
+)
+
+// snowTypes are the snow types ratings can be fitted for.
+var snowTypes = map[string]bool{
+	"A1": true, "A2": true, "A3": true, "A4": true, "A5": true,
+	"FS": true, "NS": true, "IN": true, "IT": true, "TR": true,
+}
+
+const (
+	defaultLeaderboardLimit = 50
+	maxLeaderboardLimit     = 500
+)
+
+// ratingScope is a set of tests the ratings are fitted over.
+type ratingScope struct {
+	Type  string
+	Value string
+}
+
+// condition returns the condition selecting the tests of the scope, with the arguments numbered from next.
+func (s ratingScope) condition(next int) (string, []interface{}) {
+	switch s.Type {
+	case domain.RatingScopeSnowType:
+		return fmt.Sprintf(" AND sc.snow_type = $%d", next), []interface{}{s.Value}
+	case domain.RatingScopeTemperature:
+		band, _ := strconv.Atoi(s.Value)
+		return fmt.Sprintf(" AND sc.temperature >= $%d AND sc.temperature < $%d", next, next+1),
+			[]interface{}{band, band + rating.TemperatureBandWidth}
+	}
+	return "", nil
+}
+
+// scopeFromQuery gets the scope of the ratings from the snow_type or temperature query parameter. Without either,
+// the ratings over all the tests are used.
+func scopeFromQuery(w http.ResponseWriter, r *http.Request) (ratingScope, bool) {
+	snowType := r.URL.Query().Get("snow_type")
+	temperature := r.URL.Query().Get("temperature")
+
+	switch {
+	case snowType != "" && temperature != "":
+		http.Error(w, "Use either snow_type or temperature.", http.StatusBadRequest)
+		log.Println("Both snow_type and temperature given")
+		return ratingScope{}, false
+	case snowType != "":
+		if !snowTypes[snowType] {
+			http.Error(w, "Invalid snow_type.", http.StatusBadRequest)
+			log.Println("Invalid snow_type: " + snowType)
+			return ratingScope{}, false
+		}
+		return ratingScope{domain.RatingScopeSnowType, snowType}, true
+	case temperature != "":
+		value, err := strconv.ParseFloat(temperature, 64)
+		if err != nil {
+			http.Error(w, "Invalid temperature.", http.StatusBadRequest)
+			log.Println("Invalid temperature: " + temperature)
+			return ratingScope{}, false
+		}
+		return ratingScope{domain.RatingScopeTemperature, strconv.Itoa(rating.TemperatureBand(value))}, true
+	}
+	return ratingScope{domain.RatingScopeAll, ""}, true
+}
+
+// poolFromQuery gets the pool of the ratings: the public tests with public=true, and otherwise the tests of the
+// team together with the public tests.
+func poolFromQuery(r *http.Request, team int) int {
+	if r.URL.Query().Get("public") == "true" {
+		return 0
+	}
+	return team
+}
+
+// productRatingFields returns the destinations of the columns of a product rating.
+func productRatingFields(productRating *domain.ProductRating) []any {
+	return []any{
+		&productRating.ProductID,
+		&productRating.ScopeType,
+		&productRating.ScopeValue,
+		&productRating.Rating,
+		&productRating.Uncertainty,
+		&productRating.Tests,
+		&productRating.Comparisons,
+		&productRating.UpdatedAt,
+	}
+}
+
+// getLeaderboard retrieves the best rated products of a scope that are visible to the team.
+func getLeaderboard(db *sql.DB, pool int, scope ratingScope, minTests int, limit int, team int) (
+	[]LeaderboardEntry, error) {
+	rows, err := db.Query(`SELECT pr.product_id, pr.scope_type, pr.scope_value, pr.rating, pr.uncertainty, pr.tests,
+       							pr.comparisons, pr.updated_at, p.name, COALESCE(p.brand, '')
+							FROM product_ratings pr JOIN products p ON p.id = pr.product_id
+							WHERE pr.pool = $1 AND pr.scope_type = $2 AND pr.scope_value = $3 AND pr.tests >= $4
+							  AND (p.is_public OR p.testing_team = $5)
+							ORDER BY pr.rating DESC, pr.product_id LIMIT $6;`,
+		pool, scope.Type, scope.Value, minTests, team, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	leaderboard := []LeaderboardEntry{}
+	for rows.Next() {
+		var entry LeaderboardEntry
+		if err = rows.Scan(append(productRatingFields(&entry.ProductRating), &entry.Name, &entry.Brand)...); err != nil {
+			return nil, err
+		}
+		entry.Position = len(leaderboard) + 1
+		leaderboard = append(leaderboard, entry)
+	}
+	return leaderboard, rows.Err()
+}
+
+// isProductVisible checks that a product exists and is visible to the team.
+func isProductVisible(db *sql.DB, productID int, team int) (bool, error) {
+	var count int
+	err := db.QueryRow("SELECT COUNT(*) FROM products WHERE id = $1 AND (is_public OR testing_team = $2);",
+		productID, team).Scan(&count)
+	return count > 0, err
+}
+
+// getProductRatings retrieves the ratings of a product in every scope, or only in the scope if one is given.
+func getProductRatings(db *sql.DB, pool int, productID int, scope *ratingScope) ([]domain.ProductRating, error) {
+	query := `SELECT product_id, scope_type, scope_value, rating, uncertainty, tests, comparisons, updated_at
+				FROM product_ratings WHERE pool = $1 AND product_id = $2`
+	args := []interface{}{pool, productID}
+	if scope != nil {
+		query += " AND scope_type = $3 AND scope_value = $4"
+		args = append(args, scope.Type, scope.Value)
+	}
+
+	rows, err := db.Query(query+" ORDER BY scope_type, scope_value;", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ratings := []domain.ProductRating{}
+	for rows.Next() {
+		var productRating domain.ProductRating
+		if err = rows.Scan(productRatingFields(&productRating)...); err != nil {
+			return nil, err
+		}
+		ratings = append(ratings, productRating)
+	}
+	return ratings, rows.Err()
+}
+
+// recomputeMutex keeps the ratings from being fitted by more than one goroutine at a time, as the fits replace the
+// ratings of their scope.
+var recomputeMutex sync.Mutex
+
+// RecomputeForTest brings the ratings affected by a created or updated test up to date. Only the pools the test is
+// part of are fitted again, and only for the scopes of the test and the scopes its products are already rated in, so
+// a test that moved to another scope is also removed from the old one. The previous ratings are the starting point
+// of the fits.
+func RecomputeForTest(db *sql.DB, testID int) error {
+	recomputeMutex.Lock()
+	defer recomputeMutex.Unlock()
+
+	var team int
+	var isPublic bool
+	var snowType string
+	var temperature float64
+	err := db.QueryRow(`SELECT t.testing_team, t.is_public, sc.snow_type, sc.temperature
+							FROM tests t JOIN snow_conditions sc ON sc.id = t.sc_id WHERE t.id = $1;`, testID).
+		Scan(&team, &isPublic, &snowType, &temperature)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	// A public test is part of the public pool and the pools of all the teams.
+	pools := []int{team}
+	if isPublic {
+		if pools, err = getAllPools(db); err != nil {
+			return err
+		}
+	}
+
+	scopes := []ratingScope{
+		{domain.RatingScopeAll, ""},
+		{domain.RatingScopeSnowType, snowType},
+		{domain.RatingScopeTemperature, strconv.Itoa(rating.TemperatureBand(temperature))},
+	}
+	rows, err := db.Query(`SELECT DISTINCT scope_type, scope_value FROM product_ratings
+							WHERE product_id IN (SELECT product_id FROM test_ranks WHERE test_id = $1);`, testID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var scope ratingScope
+		if err = rows.Scan(&scope.Type, &scope.Value); err != nil {
+			return err
+		}
+		if !containsScope(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	for _, pool := range pools {
+		for _, scope := range scopes {
+			if err = recomputeScope(db, pool, scope); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// RecomputeAll fits all the ratings again, for every pool and scope.
+func RecomputeAll(db *sql.DB) error {
+	recomputeMutex.Lock()
+	defer recomputeMutex.Unlock()
+
+	pools, err := getAllPools(db)
+	if err != nil {
+		return err
+	}
+
+	scopes := []ratingScope{{domain.RatingScopeAll, ""}}
+	rows, err := db.Query(`SELECT DISTINCT sc.snow_type, sc.temperature FROM tests t
+							JOIN snow_conditions sc ON sc.id = t.sc_id
+							WHERE t.state IN ` + domain.ReportableStatesSQL + `;`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var snowType string
+		var temperature float64
+		if err = rows.Scan(&snowType, &temperature); err != nil {
+			return err
+		}
+		for _, scope := range []ratingScope{
+			{domain.RatingScopeSnowType, snowType},
+			{domain.RatingScopeTemperature, strconv.Itoa(rating.TemperatureBand(temperature))},
+		} {
+			if !containsScope(scopes, scope) {
+				scopes = append(scopes, scope)
+			}
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	for _, pool := range pools {
+		for _, scope := range scopes {
+			if err = recomputeScope(db, pool, scope); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// getAllPools returns the public pool and the pools of all the teams.
+func getAllPools(db *sql.DB) ([]int, error) {
+	rows, err := db.Query("SELECT id FROM team ORDER BY id;")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	pools := []int{0}
+	for rows.Next() {
+		var team int
+		if err = rows.Scan(&team); err != nil {
+			return nil, err
+		}
+		pools = append(pools, team)
+	}
+	return pools, rows.Err()
+}
+
+func containsScope(scopes []ratingScope, scope ratingScope) bool {
+	for _, s := range scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// getPlacings retrieves the ranked results of the completed and published tests of a pool and scope, grouped by
+// test. The public pool only has public results, while the pool of a team also has all the results of the team.
+func getPlacings(db *sql.DB, pool int, scope ratingScope) ([][]rating.Placing, error) {
+	condition := "t.is_public AND r.is_rank_public"
+	var args []interface{}
+	if pool != 0 {
+		condition = "(t.testing_team = $1 OR (t.is_public AND r.is_rank_public))"
+		args = append(args, pool)
+	}
+	scopeCondition, scopeArgs := scope.condition(len(args) + 1)
+	args = append(args, scopeArgs...)
+
+	rows, err := db.Query(`SELECT r.test_id, r.product_id, r.rank FROM test_ranks r
+							JOIN tests t ON t.id = r.test_id
+							JOIN snow_conditions sc ON sc.id = t.sc_id
+							WHERE t.state IN `+domain.ReportableStatesSQL+` AND r.rank > 0 AND `+condition+scopeCondition+`
+							ORDER BY r.test_id, r.rank;`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tests [][]rating.Placing
+	lastTest := 0
+	for rows.Next() {
+		var testID int
+		var placing rating.Placing
+		if err = rows.Scan(&testID, &placing.ProductID, &placing.Rank); err != nil {
+			return nil, err
+		}
+		if testID != lastTest || len(tests) == 0 {
+			tests = append(tests, nil)
+			lastTest = testID
+		}
+		tests[len(tests)-1] = append(tests[len(tests)-1], placing)
+	}
+	return tests, rows.Err()
+}
+
+// recomputeScope fits the ratings of a pool and scope, and replaces the stored ratings with them.
+func recomputeScope(db *sql.DB, pool int, scope ratingScope) error {
+	tests, err := getPlacings(db, pool, scope)
+	if err != nil {
+		return err
+	}
+
+	rows, err := db.Query(`SELECT product_id, rating FROM product_ratings
+							WHERE pool = $1 AND scope_type = $2 AND scope_value = $3;`, pool, scope.Type, scope.Value)
+	if err != nil {
+		return err
+	}
+	previous := map[int]float64{}
+	for rows.Next() {
+		var productID int
+		var productRating float64
+		if err = rows.Scan(&productID, &productRating); err != nil {
+			rows.Close()
+			return err
+		}
+		previous[productID] = productRating
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	results := rating.Fit(tests, previous)
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("%s: %w", resources.TransactionStartFailed, err)
+	}
+	defer func() {
+		if err != nil {
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				log.Println(resources.RollbackFailed + rollbackErr.Error())
+			}
+		}
+	}()
+
+	_, err = tx.Exec("DELETE FROM product_ratings WHERE pool = $1 AND scope_type = $2 AND scope_value = $3;",
+		pool, scope.Type, scope.Value)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for productID, result := range results {
+		_, err = tx.Exec(`INSERT INTO product_ratings (
+                        	pool, product_id, scope_type, scope_value, rating, uncertainty, tests, comparisons, updated_at)
+							VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9);`,
+			pool, productID, scope.Type, scope.Value, result.Rating, result.Uncertainty, result.Tests,
+			result.Comparisons, now)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = tx.Commit()
+	return err
+}
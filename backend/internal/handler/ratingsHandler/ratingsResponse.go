@@ -0,0 +1,11 @@
+package ratingsHandler
+
+import "backend/internal/domain"
+
+// LeaderboardEntry is a product on the leaderboard of a scope.
+type LeaderboardEntry struct {
+	Position int    `json:"position"`
+	Name     string `json:"name"`
+	Brand    string `json:"brand"`
+	domain.ProductRating
+}
@@ -0,0 +1,202 @@
+package ratingsHandler
+
+import (
+	"backend/internal/utils"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func AuthenticationMock(mock sqlmock.Sqlmock) {
+	// Mock the user id query
+	mock.ExpectQuery("SELECT user_id FROM sessions WHERE \\(session_token = \\$1 AND expires_at > NOW\\(\\)\\)").
+		WithArgs("mockToken").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(1))
+
+	// Mock the user team id query
+	mock.ExpectQuery("SELECT team_id FROM users WHERE id = \\$1;").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"team_id"}).AddRow(1))
+
+	// Mock the user team role query
+	mock.ExpectQuery("SELECT team_role FROM team WHERE id = \\$1;").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"team_role"}).AddRow(1))
+}
+
+var ratingColumns = []string{
+	"product_id", "scope_type", "scope_value", "rating", "uncertainty", "tests", "comparisons", "updated_at",
+}
+
+func Test_recomputeScope(t *testing.T) {
+	mockDB, mock := utils.InitMockDB(t)
+
+	mock.ExpectQuery("SELECT r.test_id, r.product_id, r.rank FROM test_ranks r .* WHERE t.state IN \\('completed', 'published'\\) "+
+		"AND r.rank > 0 AND \\(t.testing_team = \\$1 OR \\(t.is_public AND r.is_rank_public\\)\\) "+
+		"AND sc.temperature >= \\$2 AND sc.temperature < \\$3").
+		WithArgs(1, -10, -5).
+		WillReturnRows(sqlmock.NewRows([]string{"test_id", "product_id", "rank"}).
+			AddRow(1, 3, 1).AddRow(1, 4, 2))
+	mock.ExpectQuery("SELECT product_id, rating FROM product_ratings").
+		WithArgs(1, "temperature", "-10").
+		WillReturnRows(sqlmock.NewRows([]string{"product_id", "rating"}).AddRow(3, 1600.0).AddRow(5, 1450.0))
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM product_ratings WHERE pool = \\$1 AND scope_type = \\$2 AND scope_value = \\$3;").
+		WithArgs(1, "temperature", "-10").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("INSERT INTO product_ratings").
+		WithArgs(1, sqlmock.AnyArg(), "temperature", "-10", sqlmock.AnyArg(), sqlmock.AnyArg(), 1, 1, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO product_ratings").
+		WithArgs(1, sqlmock.AnyArg(), "temperature", "-10", sqlmock.AnyArg(), sqlmock.AnyArg(), 1, 1, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := recomputeScope(mockDB, 1, ratingScope{"temperature", "-10"})
+	assert.NoError(t, err)
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestRatingsHandler(t *testing.T) {
+	mockDB, mock := utils.InitMockDB(t)
+
+	updatedAt := time.Date(2025, 1, 12, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		method       string
+		path         string
+		setupMocks   func()
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:         "Method = GET (Status OK - leaderboard of a snow type)",
+			method:       http.MethodGet,
+			path:         "/ratings/leaderboard?snow_type=A2&limit=10&min_tests=2",
+			expectedCode: http.StatusOK,
+			expectedBody: `[{"position":1,"name":"Blue Wax","brand":"Swix","product_id":3,"scope_type":"snow_type",` +
+				`"scope_value":"A2","rating":1650.5,"uncertainty":80.2,"tests":4,"comparisons":12,` +
+				`"updated_at":"2025-01-12T10:00:00Z"},{"position":2,"name":"Red Wax"`,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				mock.ExpectQuery("SELECT pr.product_id, .* FROM product_ratings pr JOIN products p").
+					WithArgs(1, "snow_type", "A2", 2, 1, 10).
+					WillReturnRows(sqlmock.NewRows(append(ratingColumns, "name", "brand")).
+						AddRow(3, "snow_type", "A2", 1650.5, 80.2, 4, 12, updatedAt, "Blue Wax", "Swix").
+						AddRow(5, "snow_type", "A2", 1420.0, 95.0, 2, 6, updatedAt, "Red Wax", ""))
+			},
+		},
+		{
+			name:         "Method = GET (Status OK - public leaderboard of a temperature band)",
+			method:       http.MethodGet,
+			path:         "/ratings/leaderboard?temperature=-7.5&public=true",
+			expectedCode: http.StatusOK,
+			expectedBody: `[]`,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				mock.ExpectQuery("SELECT pr.product_id, .* FROM product_ratings pr JOIN products p").
+					WithArgs(0, "temperature", "-10", 0, 1, 50).
+					WillReturnRows(sqlmock.NewRows(append(ratingColumns, "name", "brand")))
+			},
+		},
+		{
+			name:         "Method = GET (Status bad request - invalid snow type)",
+			method:       http.MethodGet,
+			path:         "/ratings/leaderboard?snow_type=B9",
+			expectedCode: http.StatusBadRequest,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+			},
+		},
+		{
+			name:         "Method = GET (Status bad request - limit too high)",
+			method:       http.MethodGet,
+			path:         "/ratings/leaderboard?limit=1000",
+			expectedCode: http.StatusBadRequest,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+			},
+		},
+		{
+			name:         "Method = GET (Status OK - ratings of a product)",
+			method:       http.MethodGet,
+			path:         "/products/3/rating",
+			expectedCode: http.StatusOK,
+			expectedBody: `[{"product_id":3,"scope_type":"all","scope_value":"","rating":1580,"uncertainty":60`,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM products WHERE id = \\$1 AND \\(is_public OR testing_team = \\$2\\);").
+					WithArgs(3, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+				mock.ExpectQuery("SELECT product_id, .* FROM product_ratings WHERE pool = \\$1 AND product_id = \\$2 ORDER BY").
+					WithArgs(1, 3).
+					WillReturnRows(sqlmock.NewRows(ratingColumns).
+						AddRow(3, "all", "", 1580.0, 60.0, 6, 20, updatedAt).
+						AddRow(3, "snow_type", "A2", 1650.5, 80.2, 4, 12, updatedAt))
+			},
+		},
+		{
+			name:         "Method = GET (Status OK - rating of a product for a snow type)",
+			method:       http.MethodGet,
+			path:         "/products/3/rating?snow_type=A2",
+			expectedCode: http.StatusOK,
+			expectedBody: `"scope_value":"A2"`,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM products").
+					WithArgs(3, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+				mock.ExpectQuery("SELECT product_id, .* AND scope_type = \\$3 AND scope_value = \\$4").
+					WithArgs(1, 3, "snow_type", "A2").
+					WillReturnRows(sqlmock.NewRows(ratingColumns).
+						AddRow(3, "snow_type", "A2", 1650.5, 80.2, 4, 12, updatedAt))
+			},
+		},
+		{
+			name:         "Method = GET (Status not found - product of another team)",
+			method:       http.MethodGet,
+			path:         "/products/3/rating",
+			expectedCode: http.StatusNotFound,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM products").
+					WithArgs(3, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+			},
+		},
+		{
+			name:         "Method = POST (Status not implemented)",
+			method:       http.MethodPost,
+			path:         "/ratings/leaderboard",
+			expectedCode: http.StatusNotImplemented,
+			setupMocks:   func() {},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set("Authorization", "Bearer mockToken")
+			rr := httptest.NewRecorder()
+
+			RatingsHandler(mockDB).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedCode, rr.Code)
+			if tt.expectedBody != "" {
+				assert.Contains(t, rr.Body.String(), tt.expectedBody)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package recommendationsHandler
+
+import (
+	"backend/internal/middleware"
+	"backend/internal/resources"
+	"backend/internal/services/similarity"
+	"backend/internal/utils"
+	"database/sql"
+	"encoding/json"
+	"log"
+	"net/http"
+)
+
+// RecommendationsHandler routes HTTP requests for the wax recommendations to the appropriate handler function.
+//
+// It supports the following methods:
+// - POST: Recommends products and bundles for the given conditions.
+func RecommendationsHandler(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		switch r.Method {
+		case http.MethodPost:
+			RecommendationsRequestPOST(w, r, db)
+		default:
+			http.Error(w, resources.MethodNotAllowed, http.StatusNotImplemented)
+			return
+		}
+	}
+}
+
+// RecommendationsRequestPOST handles POST requests for recommendations.
+//
+//	@Summary		Recommend products for the conditions
+//	@Description	Ranks the products and bundles by how they placed in the completed and published tests with
+//	@Description	conditions similar to the given ones. Each test is weighted by the distance between its snow, air
+//	@Description	and track conditions and the given conditions, and tests that are too far away are left out. The
+//	@Description	score is the expected placing, from 0 for last to 1 for first, and the confidence grows with the
+//	@Description	number of close tests. The tests are those of the team together with the public tests.
+//	@Tags			Recommendations
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		RecommendationPOSTRequest	true	"The conditions"
+//	@Success		200		{object}	RecommendationResponse		"The recommended products, best first"
+//	@Failure		400		{string}	string						"Invalid POST request body"
+//	@Failure		500		{string}	string						"Could not retrieve the tests."
+//	@Router			/recommendations [post]
+func RecommendationsRequestPOST(w http.ResponseWriter, r *http.Request, db *sql.DB) {
+	// Get the user's team membership.
+	team := middleware.GetUserTeamRole(w, r, db)
+
+	request, err := utils.ParseAndValidateRequest[RecommendationPOSTRequest](r)
+	if err != nil {
+		http.Error(w, resources.InvalidPOSTRequest, http.StatusBadRequest)
+		log.Println(resources.InvalidPOSTRequest + ": " + err.Error())
+		return
+	}
+	if request.Limit == 0 {
+		request.Limit = defaultRecommendationLimit
+	}
+
+	tests, products, err := getTestResults(db, team)
+	if err != nil {
+		http.Error(w, "Could not retrieve the tests.", http.StatusInternalServerError)
+		log.Println("Could not retrieve the tests: " + err.Error())
+		return
+	}
+
+	recommendations, similarTests := recommend(tests, products, similarity.Conditions{
+		SnowTemperature: request.SnowTemperature,
+		SnowType:        request.SnowType,
+		SnowHumidity:    request.SnowHumidity,
+		AirHumidity:     request.AirHumidity,
+		TrackHardness:   request.TrackHardness,
+	}, request.Limit)
+
+	err = json.NewEncoder(w).Encode(RecommendationResponse{
+		SimilarTests:    similarTests,
+		Recommendations: recommendations,
+	})
+	if err != nil {
+		http.Error(w, "Could not encode the recommendations.", http.StatusInternalServerError)
+		log.Println("Could not encode the recommendations: " + err.Error())
+	}
+}
@@ -0,0 +1,165 @@
+package recommendationsHandler
+
+import (
+	"backend/internal/domain"
+	"backend/internal/services/similarity"
+	"database/sql"
+	"math"
+	"sort"
+)
+
+const defaultRecommendationLimit = 10
+
+// minSimilarity is the weight below which a test is too far from the conditions to count.
+const minSimilarity = 0.05
+
+// priorWeight is the weight of the virtual test every product placed in the middle of. It pulls the score of a
+// product with little evidence towards 0.5, and sets how quickly the confidence grows.
+const priorWeight = 1.0
+
+// placedProduct is a product ranked in a test.
+type placedProduct struct {
+	ProductID int
+	Rank      int
+}
+
+// productInfo is what is shown about a recommended product.
+type productInfo struct {
+	Name        string
+	Brand       string
+	Type        string
+	Status      string
+	Recommended bool // Visible to the team and still in use.
+}
+
+// testResult is a test with its conditions and its ranked products.
+type testResult struct {
+	TestID     int
+	Conditions similarity.Conditions
+	Products   []placedProduct
+}
+
+// getTestResults retrieves the ranked products of the completed and published tests of the team and the public
+// tests, together with the products ranked in them.
+func getTestResults(db *sql.DB, team int) ([]testResult, map[int]productInfo, error) {
+	rows, err := db.Query(`SELECT t.id, sc.temperature, sc.snow_type, sc.snow_humidity, ac.temperature, ac.humidity,
+       							ac.wind, ac.cloud, tc.track_hardness, tc.track_type, r.product_id, r.rank, p.name,
+       							COALESCE(p.brand, ''), p.type, p.status, (p.is_public OR p.testing_team = $1)
+							FROM tests t
+							JOIN snow_conditions sc ON sc.id = t.sc_id
+							JOIN air_conditions ac ON ac.id = t.ac_id
+							JOIN track_conditions tc ON tc.id = t.tc_id
+							JOIN test_ranks r ON r.test_id = t.id
+							JOIN products p ON p.id = r.product_id
+							WHERE t.state IN `+domain.ReportableStatesSQL+` AND r.rank > 0
+							  AND (t.testing_team = $1 OR (t.is_public AND r.is_rank_public))
+							ORDER BY t.id, r.rank;`, team)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	var tests []testResult
+	products := map[int]productInfo{}
+	for rows.Next() {
+		var (
+			testID                          int
+			snowTemperature, airTemperature float64
+			airHumidity                     float64
+			conditions                      similarity.Conditions
+			placed                          placedProduct
+			product                         productInfo
+			visible                         bool
+		)
+		if err = rows.Scan(&testID, &snowTemperature, &conditions.SnowType, &conditions.SnowHumidity,
+			&airTemperature, &airHumidity, &conditions.Wind, &conditions.Cloud, &conditions.TrackHardness,
+			&conditions.TrackType, &placed.ProductID, &placed.Rank, &product.Name, &product.Brand, &product.Type,
+			&product.Status, &visible); err != nil {
+			return nil, nil, err
+		}
+		conditions.SnowTemperature = &snowTemperature
+		conditions.AirTemperature = &airTemperature
+		conditions.AirHumidity = &airHumidity
+
+		if len(tests) == 0 || tests[len(tests)-1].TestID != testID {
+			tests = append(tests, testResult{TestID: testID, Conditions: conditions})
+		}
+		tests[len(tests)-1].Products = append(tests[len(tests)-1].Products, placed)
+
+		product.Recommended = visible && product.Status != "retired" && product.Status != "discontinued"
+		products[placed.ProductID] = product
+	}
+	if err = rows.Err(); err != nil {
+		return nil, nil, err
+	}
+	return tests, products, nil
+}
+
+// recommend ranks the products by how they placed in the tests, with each test weighted by how similar its
+// conditions are to the given conditions. It returns the recommendations and the number of similar tests.
+func recommend(tests []testResult, products map[int]productInfo, conditions similarity.Conditions, limit int) (
+	[]Recommendation, int) {
+	type evidence struct {
+		weight float64
+		score  float64
+		tests  int
+	}
+	evidences := map[int]*evidence{}
+
+	similarTests := 0
+	for _, test := range tests {
+		// A single product says nothing about how it compares with the others.
+		if len(test.Products) < 2 {
+			continue
+		}
+		weight := similarity.Similarity(similarity.Distance(conditions, test.Conditions, similarity.DefaultWeights))
+		if weight < minSimilarity {
+			continue
+		}
+		similarTests++
+
+		last := len(test.Products)
+		for _, product := range test.Products {
+			// The placing from 1 for the winner to 0 for the last product, with the ranks of ties shared.
+			placing := math.Max(0, float64(last-product.Rank)/float64(last-1))
+			if evidences[product.ProductID] == nil {
+				evidences[product.ProductID] = &evidence{}
+			}
+			evidences[product.ProductID].weight += weight
+			evidences[product.ProductID].score += weight * placing
+			evidences[product.ProductID].tests++
+		}
+	}
+
+	recommendations := []Recommendation{}
+	for productID, e := range evidences {
+		product := products[productID]
+		if !product.Recommended {
+			continue
+		}
+		recommendations = append(recommendations, Recommendation{
+			ProductID:  productID,
+			Name:       product.Name,
+			Brand:      product.Brand,
+			Type:       product.Type,
+			IsBundle:   product.Type == "bundle",
+			Score:      (e.score + priorWeight*0.5) / (e.weight + priorWeight),
+			Confidence: e.weight / (e.weight + priorWeight),
+			Tests:      e.tests,
+		})
+	}
+
+	sort.Slice(recommendations, func(i, j int) bool {
+		if recommendations[i].Score != recommendations[j].Score {
+			return recommendations[i].Score > recommendations[j].Score
+		}
+		return recommendations[i].ProductID < recommendations[j].ProductID
+	})
+	if len(recommendations) > limit {
+		recommendations = recommendations[:limit]
+	}
+	for i := range recommendations {
+		recommendations[i].Position = i + 1
+	}
+	return recommendations, similarTests
+}
@@ -0,0 +1,11 @@
+package recommendationsHandler
+
+// RecommendationPOSTRequest are the conditions to recommend products for.
+type RecommendationPOSTRequest struct {
+	SnowTemperature *float64 `json:"snow_temperature" validate:"required,lte=100,gte=-100"`
+	SnowType        string   `json:"snow_type" validate:"required,oneof=A1 A2 A3 A4 A5 FS NS IN IT TR"`
+	SnowHumidity    string   `json:"snow_humidity" validate:"required,oneof=DS W1 W2 W3 W4"`
+	AirHumidity     *float64 `json:"air_humidity" validate:"required,lte=100,gte=0"`
+	TrackHardness   string   `json:"track_hardness" validate:"required,oneof=H1 H2 H3 H4 H5 H6"`
+	Limit           int      `json:"limit" validate:"omitempty,gt=0,lte=100"` // Defaults to 10.
+}
@@ -0,0 +1,20 @@
+package recommendationsHandler
+
+// Recommendation is a product or bundle recommended for the conditions.
+type Recommendation struct {
+	Position   int     `json:"position"`
+	ProductID  int     `json:"product_id"`
+	Name       string  `json:"name"`
+	Brand      string  `json:"brand"`
+	Type       string  `json:"type"`
+	IsBundle   bool    `json:"is_bundle"`
+	Score      float64 `json:"score"`      // Expected placing, from 0 for last to 1 for first, in similar conditions.
+	Confidence float64 `json:"confidence"` // From 0 with no similar tests towards 1 with many close tests.
+	Tests      int     `json:"tests"`      // Number of similar tests the product was ranked in.
+}
+
+// RecommendationResponse is the ranked list of recommendations, with the number of similar tests they are based on.
+type RecommendationResponse struct {
+	SimilarTests    int              `json:"similar_tests"`
+	Recommendations []Recommendation `json:"recommendations"`
+}
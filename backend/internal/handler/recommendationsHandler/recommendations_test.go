@@ -0,0 +1,162 @@
+package recommendationsHandler
+
+import (
+	"backend/internal/services/similarity"
+	"backend/internal/utils"
+	"bytes"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func AuthenticationMock(mock sqlmock.Sqlmock) {
+	// Mock the user id query
+	mock.ExpectQuery("SELECT user_id FROM sessions WHERE \\(session_token = \\$1 AND expires_at > NOW\\(\\)\\)").
+		WithArgs("mockToken").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(1))
+
+	// Mock the user team id query
+	mock.ExpectQuery("SELECT team_id FROM users WHERE id = \\$1;").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"team_id"}).AddRow(1))
+
+	// Mock the user team role query
+	mock.ExpectQuery("SELECT team_role FROM team WHERE id = \\$1;").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"team_role"}).AddRow(1))
+}
+
+func float(value float64) *float64 {
+	return &value
+}
+
+func Test_recommend(t *testing.T) {
+	cold := similarity.Conditions{SnowTemperature: float(-12), SnowType: "A1", SnowHumidity: "DS",
+		AirHumidity: float(60), TrackHardness: "H4"}
+	warm := similarity.Conditions{SnowTemperature: float(0), SnowType: "A5", SnowHumidity: "W3",
+		AirHumidity: float(95), TrackHardness: "H1"}
+
+	tests := []testResult{
+		{TestID: 1, Conditions: cold, Products: []placedProduct{{1, 1}, {2, 2}, {3, 3}}},
+		{TestID: 2, Conditions: cold, Products: []placedProduct{{1, 1}, {3, 2}}},
+		{TestID: 3, Conditions: warm, Products: []placedProduct{{2, 1}, {1, 2}}},
+		{TestID: 4, Conditions: cold, Products: []placedProduct{{4, 1}}},
+		{TestID: 5, Conditions: cold, Products: []placedProduct{{5, 1}, {1, 2}}},
+	}
+	products := map[int]productInfo{
+		1: {Name: "Cold Wax", Type: "solid", Recommended: true},
+		2: {Name: "Warm Wax", Type: "liquid", Recommended: true},
+		3: {Name: "Cold Bundle", Type: "bundle", Recommended: true},
+		4: {Name: "Untested Wax", Type: "solid", Recommended: true},
+		5: {Name: "Retired Wax", Type: "solid", Status: "retired"},
+	}
+
+	recommendations, similarTests := recommend(tests, products, cold, 10)
+
+	// The warm test is too far away and the single product test says nothing.
+	assert.Equal(t, 3, similarTests)
+	assert.Len(t, recommendations, 3)
+
+	assert.Equal(t, 1, recommendations[0].ProductID)
+	assert.Equal(t, 1, recommendations[0].Position)
+	assert.Equal(t, 3, recommendations[0].Tests)
+	assert.InDelta(t, (2+0.5)/4.0, recommendations[0].Score, 1e-9)
+	assert.InDelta(t, 0.75, recommendations[0].Confidence, 1e-9)
+
+	assert.Equal(t, 2, recommendations[1].ProductID)
+	assert.InDelta(t, 0.5, recommendations[1].Score, 1e-9)
+
+	assert.Equal(t, 3, recommendations[2].ProductID)
+	assert.True(t, recommendations[2].IsBundle)
+	assert.InDelta(t, 0.5/3, recommendations[2].Score, 1e-9)
+
+	// In warm conditions the warm wax is the best.
+	recommendations, similarTests = recommend(tests, products, warm, 1)
+	assert.Equal(t, 1, similarTests)
+	assert.Len(t, recommendations, 1)
+	assert.Equal(t, 2, recommendations[0].ProductID)
+}
+
+func TestRecommendationsHandler(t *testing.T) {
+	mockDB, mock := utils.InitMockDB(t)
+
+	columns := []string{"id", "temperature", "snow_type", "snow_humidity", "temperature", "humidity", "wind",
+		"cloud", "track_hardness", "track_type", "product_id", "rank", "name", "brand", "type", "status", "visible"}
+
+	tests := []struct {
+		name         string
+		method       string
+		body         string
+		setupMocks   func()
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:   "Method = POST (Status OK)",
+			method: http.MethodPost,
+			body: `{"snow_temperature": -8, "snow_type": "A2", "snow_humidity": "DS", "air_humidity": 70,
+				"track_hardness": "H3"}`,
+			expectedCode: http.StatusOK,
+			expectedBody: `{"similar_tests":1,"recommendations":[{"position":1,"product_id":3,"name":"Blue Wax",` +
+				`"brand":"Swix","type":"solid","is_bundle":false,"score":0.75,"confidence":0.5,"tests":1},` +
+				`{"position":2,"product_id":4,"name":"SuperGo Bundle","brand":"","type":"bundle","is_bundle":true,` +
+				`"score":0.25,"confidence":0.5,"tests":1}]}`,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				mock.ExpectQuery("SELECT t.id, sc.temperature, .* FROM tests t .* WHERE t.state IN \\('completed', 'published'\\)").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow(1, -8.0, "A2", "DS", -10, 70, "L", "2", "H3", "T1", 3, 1, "Blue Wax", "Swix", "solid", "active", true).
+						AddRow(1, -8.0, "A2", "DS", -10, 70, "L", "2", "H3", "T1", 4, 2, "SuperGo Bundle", "", "bundle", "active", true))
+			},
+		},
+		{
+			name:         "Method = POST (Status bad request - missing conditions)",
+			method:       http.MethodPost,
+			body:         `{"snow_temperature": -8, "snow_type": "A2"}`,
+			expectedCode: http.StatusBadRequest,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+			},
+		},
+		{
+			name:   "Method = POST (Status bad request - invalid snow type)",
+			method: http.MethodPost,
+			body: `{"snow_temperature": -8, "snow_type": "B9", "snow_humidity": "DS", "air_humidity": 70,
+				"track_hardness": "H3"}`,
+			expectedCode: http.StatusBadRequest,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+			},
+		},
+		{
+			name:         "Method = GET (Status not implemented)",
+			method:       http.MethodGet,
+			expectedCode: http.StatusNotImplemented,
+			setupMocks:   func() {},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+
+			req := httptest.NewRequest(tt.method, "/recommendations", bytes.NewBufferString(tt.body))
+			req.Header.Set("Authorization", "Bearer mockToken")
+			rr := httptest.NewRecorder()
+
+			RecommendationsHandler(mockDB).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedCode, rr.Code)
+			if tt.expectedBody != "" {
+				assert.Contains(t, rr.Body.String(), tt.expectedBody)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,246 @@
+package runsHandler
+
+import (
+	"backend/internal/domain"
+	"backend/internal/handler/testsHandler"
+	"backend/internal/middleware"
+	"backend/internal/resources"
+	"backend/internal/services/access"
+	"backend/internal/utils"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"regexp"
+	"strconv"
+)
+
+var runsPath = regexp.MustCompile(`^/tests/(\d+)/runs/?$`)
+
+// RunsHandler routes HTTP requests for the raw runs of a test to the appropriate handler function.
+//
+// It supports the following methods:
+// - GET: Retrieves the runs of a test with the computed statistics.
+// - POST: Records new runs and recomputes the ranks of the test.
+//
+// Each method has its own dedicated request handler function, which is documented separately using Swagger annotations.
+func RunsHandler(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		switch r.Method {
+		case http.MethodGet:
+			RunsRequestGET(w, r, db)
+		case http.MethodPost:
+			RunsRequestPOST(w, r, db)
+		default:
+			http.Error(w, resources.MethodNotAllowed, http.StatusNotImplemented)
+			return
+		}
+	}
+}
+
+// RunsRequestGET retrieves the runs of a test.
+//
+//	@Summary		Get the runs of a test
+//	@Description	Retrieves the raw runs of a test grouped by product, with the mean, standard deviation, rank and
+//	@Description	distance behind computed from the runs.
+//	@Tags			Runs
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			test_id	path		int				true	"Test ID"
+//	@Success		200		{object}	domain.TestRuns	"Successful response with the runs"
+//	@Failure		400		{string}	string			"Invalid request URL"
+//	@Failure		401		{string}	string			"Unauthorized"
+//	@Failure		404		{string}	string			"Could not retrieve the test."
+//	@Failure		500		{string}	string			"Could not retrieve the runs."
+//	@Router			/tests/{test_id}/runs [get]
+func RunsRequestGET(w http.ResponseWriter, r *http.Request, db *sql.DB) {
+	matches := runsPath.FindStringSubmatch(r.URL.Path)
+	if len(matches) != 2 {
+		http.Error(w, "Invalid request URL, use '/tests/{test_id}/runs'.", http.StatusBadRequest)
+		log.Println("Invalid request URL: " + r.URL.Path)
+		return
+	}
+	testID, _ := strconv.Atoi(matches[1])
+
+	// Get the user's team membership.
+	team := middleware.GetUserTeamRole(w, r, db)
+
+	// Check that the test is visible for the user's team.
+	testingTeam, isPublic, _, err := access.GetTestAccess(db, testID)
+	if err != nil {
+		http.Error(w, "Could not retrieve the test.", http.StatusNotFound)
+		log.Println("Could not retrieve the test: " + err.Error())
+		return
+	}
+	if !isPublic && testingTeam != team {
+		http.Error(w, resources.AuthenticationError, http.StatusUnauthorized)
+		log.Println("User cannot view the runs of this test")
+		return
+	}
+
+	runs, err := getRunsForTest(db, testID)
+	if err != nil {
+		http.Error(w, "Could not retrieve the runs.", http.StatusInternalServerError)
+		log.Println("Could not retrieve the runs: " + err.Error())
+		return
+	}
+
+	metric := runMetric(runs)
+	err = json.NewEncoder(w).Encode(domain.TestRuns{
+		TestID:   testID,
+		Metric:   metric,
+		Products: summarizeRuns(metric, runs),
+	})
+	if err != nil {
+		http.Error(w, "Could not encode the runs.", http.StatusInternalServerError)
+		log.Println("Could not encode the runs: " + err.Error())
+		return
+	}
+}
+
+// RunsRequestPOST records new runs for a test.
+//
+//	@Summary		Record runs for a test
+//	@Description	Records raw glide runs for the products in a test, either glide-out distances in meters or gate
+//	@Description	times in seconds. All the runs of a test must use the same metric. The rank and distance behind
+//	@Description	of every product are recomputed from all the runs of the test and written to the test ranks.
+//	@Description	The ski pair of a run must belong to the team.
+//	@Tags			Runs
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			test_id	path		int				true	"Test ID"
+//	@Param			runs	body		RunsPOSTRequest	true	"New runs"
+//	@Success		201		{object}	domain.TestRuns	"Runs recorded successfully"
+//	@Failure		400		{string}	string			"Invalid POST request body"
+//	@Failure		401		{string}	string			"Unauthorized"
+//	@Failure		404		{string}	string			"Could not retrieve the test."
+//	@Failure		409		{string}	string			"The runs of a test must use the same metric."
+//	@Failure		500		{string}	string			"Could not record the runs."
+//	@Router			/tests/{test_id}/runs [post]
+func RunsRequestPOST(w http.ResponseWriter, r *http.Request, db *sql.DB) {
+	matches := runsPath.FindStringSubmatch(r.URL.Path)
+	if len(matches) != 2 {
+		http.Error(w, "Invalid request URL, use '/tests/{test_id}/runs'.", http.StatusBadRequest)
+		log.Println("Invalid request URL: " + r.URL.Path)
+		return
+	}
+	testID, _ := strconv.Atoi(matches[1])
+
+	// Get the user's team membership.
+	team := middleware.GetUserTeamRole(w, r, db)
+
+	request, err := utils.ParseAndValidateRequest[RunsPOSTRequest](r)
+	if err != nil {
+		http.Error(w, resources.InvalidPOSTRequest, http.StatusBadRequest)
+		log.Println(resources.InvalidPOSTRequest + ": " + err.Error())
+		return
+	}
+
+	testingTeam, isPublic, state, err := access.GetTestAccess(db, testID)
+	if err != nil {
+		http.Error(w, "Could not retrieve the test.", http.StatusNotFound)
+		log.Println("Could not retrieve the test: " + err.Error())
+		return
+	}
+
+	var code int
+	if err, code = access.ValidateWritePermissions("test", testingTeam, isPublic, state, team); err != nil {
+		http.Error(w, "Validation error: "+err.Error(), code)
+		log.Println("Validation error: " + err.Error())
+		return
+	}
+	if err, code = validateRunProducts(db, request, team); err != nil {
+		http.Error(w, "Validation error: "+err.Error(), code)
+		log.Println("Validation error: " + err.Error())
+		return
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		http.Error(w, resources.TransactionStartFailed, http.StatusInternalServerError)
+		return
+	}
+	defer func() {
+		if err != nil {
+			rollbackErr := tx.Rollback()
+			if rollbackErr != nil {
+				log.Println(resources.RollbackFailed + rollbackErr.Error())
+			}
+		}
+	}()
+
+	// Lock the test, so concurrent recordings compute the ranks from all the runs.
+	_, err = tx.Exec("SELECT id FROM tests WHERE id = $1 FOR UPDATE;", testID)
+	if err != nil {
+		http.Error(w, "Could not record the runs.", http.StatusInternalServerError)
+		log.Println("Could not lock the test: " + err.Error())
+		return
+	}
+
+	var existingMetric string
+	err = tx.QueryRow("SELECT metric FROM test_runs WHERE test_id = $1 LIMIT 1;", testID).Scan(&existingMetric)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Could not record the runs.", http.StatusInternalServerError)
+		log.Println("Could not retrieve the metric of the test: " + err.Error())
+		return
+	}
+	if existingMetric != "" && domain.RunMetric(existingMetric) != request.Metric {
+		err = errors.New("metric mismatch")
+		http.Error(w, "The runs of a test must use the same metric.", http.StatusConflict)
+		log.Println("The runs of a test must use the same metric: " + existingMetric)
+		return
+	}
+
+	err = insertRuns(tx, testID, request)
+	if err != nil {
+		http.Error(w, "Could not record the runs.", http.StatusInternalServerError)
+		log.Println("Could not record the runs: " + err.Error())
+		return
+	}
+
+	runs, err := getRunsForTest(tx, testID)
+	if err != nil {
+		http.Error(w, "Could not record the runs.", http.StatusInternalServerError)
+		log.Println("Could not retrieve the runs: " + err.Error())
+		return
+	}
+
+	summaries := summarizeRuns(request.Metric, runs)
+	err = writeRanks(tx, testID, summaries)
+	if err != nil {
+		http.Error(w, "Could not record the runs.", http.StatusInternalServerError)
+		log.Println("Could not write the ranks: " + err.Error())
+		return
+	}
+
+	// The products ranked by the runs are flagged when they were tested outside their rated temperature range.
+	_, err = testsHandler.UpdateTemperatureRangeFlags(tx, testID)
+	if err != nil {
+		http.Error(w, "Could not record the runs.", http.StatusInternalServerError)
+		log.Println("Could not update the temperature range flags: " + err.Error())
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		http.Error(w, resources.TransactionCommitFailed, http.StatusInternalServerError)
+		log.Println(resources.TransactionCommitFailed + ": " + err.Error())
+		return
+	}
+	testsHandler.NotifyTestChanged(testID)
+
+	w.WriteHeader(http.StatusCreated)
+	err = json.NewEncoder(w).Encode(domain.TestRuns{
+		TestID:   testID,
+		Metric:   request.Metric,
+		Products: summaries,
+	})
+	if err != nil {
+		log.Println("Could not encode the runs: " + err.Error())
+		return
+	}
+}
@@ -0,0 +1,221 @@
+package runsHandler
+
+import (
+	"backend/internal/domain"
+	"backend/internal/services/access"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"time"
+)
+
+// querier is implemented by both *sql.DB and *sql.Tx.
+type querier interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+}
+
+// validateRunProducts checks that the products of the runs exist and are visible to the team, and that the ski pairs
+// the runs were made on belong to the team.
+func validateRunProducts(db *sql.DB, request RunsPOSTRequest, team int) (error, int) {
+	checked := map[int]bool{}
+	checkedSkis := map[int]bool{}
+	for _, run := range request.Runs {
+		if !checked[run.ProductID] {
+			checked[run.ProductID] = true
+
+			_, err := access.GetProductAvailability(db, run.ProductID, team)
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("product %d does not exist, %d", run.ProductID, http.StatusBadRequest),
+					http.StatusBadRequest
+			} else if err != nil {
+				return fmt.Errorf("could not retrieve product %d, %d", run.ProductID, http.StatusInternalServerError),
+					http.StatusInternalServerError
+			}
+		}
+
+		if run.SkiID == nil || checkedSkis[*run.SkiID] {
+			continue
+		}
+		checkedSkis[*run.SkiID] = true
+
+		found, err := access.IsTeamSki(db, *run.SkiID, team)
+		if err != nil {
+			return fmt.Errorf("could not retrieve the ski, %d", http.StatusInternalServerError),
+				http.StatusInternalServerError
+		}
+		if !found {
+			return fmt.Errorf("ski %d does not exist, %d", *run.SkiID, http.StatusBadRequest), http.StatusBadRequest
+		}
+	}
+	return nil, 0
+}
+
+// getRunsForTest retrieves all the runs of a test in the order they were made.
+func getRunsForTest(q querier, testID int) ([]domain.TestRun, error) {
+	rows, err := q.Query(`SELECT id, test_id, product_id, ski_id, metric, value, recorded_at, version
+								FROM test_runs WHERE test_id = $1 ORDER BY recorded_at, id;`, testID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var runs []domain.TestRun
+	for rows.Next() {
+		var run domain.TestRun
+		var skiID sql.NullInt64
+		if err = rows.Scan(
+			&run.ID,
+			&run.TestID,
+			&run.ProductID,
+			&skiID,
+			&run.Metric,
+			&run.Value,
+			&run.RecordedAt,
+			&run.Version); err != nil {
+			return nil, err
+		}
+		if skiID.Valid {
+			id := int(skiID.Int64)
+			run.SkiID = &id
+		}
+		runs = append(runs, run)
+	}
+	return runs, rows.Err()
+}
+
+// insertRuns inserts the new runs of a test.
+func insertRuns(tx *sql.Tx, testID int, request RunsPOSTRequest) error {
+	now := time.Now()
+	for _, run := range request.Runs {
+		recordedAt := now
+		if run.RecordedAt != nil {
+			recordedAt = *run.RecordedAt
+		}
+
+		_, err := tx.Exec(`INSERT INTO test_runs (
+                       test_id, product_id, ski_id, metric, value, recorded_at, version)
+                       VALUES ($1, $2, $3, $4, $5, $6, $7);`,
+			testID,
+			run.ProductID,
+			run.SkiID,
+			request.Metric,
+			run.Value,
+			recordedAt,
+			now)
+		if err != nil {
+			return fmt.Errorf("failed to insert run for product %d: %w", run.ProductID, err)
+		}
+	}
+	return nil
+}
+
+// summarizeRuns groups the runs by product and computes the mean, the sample standard deviation, the rank and the
+// distance behind the best product. Products with the same mean share a rank.
+func summarizeRuns(metric domain.RunMetric, runs []domain.TestRun) []domain.RunSummary {
+	summaries := []domain.RunSummary{}
+	index := map[int]int{}
+	for _, run := range runs {
+		i, ok := index[run.ProductID]
+		if !ok {
+			i = len(summaries)
+			index[run.ProductID] = i
+			summaries = append(summaries, domain.RunSummary{ProductID: run.ProductID})
+		}
+		summaries[i].Runs = append(summaries[i].Runs, run)
+	}
+	if len(summaries) == 0 {
+		return summaries
+	}
+
+	for i := range summaries {
+		summaries[i].Count = len(summaries[i].Runs)
+		summaries[i].Mean, summaries[i].StdDev = meanAndStdDev(summaries[i].Runs)
+	}
+
+	// Longer glide-out distances and shorter gate times are better.
+	better := func(a, b float64) bool {
+		if metric == domain.RunTime {
+			return a < b
+		}
+		return a > b
+	}
+	sort.SliceStable(summaries, func(i, j int) bool {
+		if summaries[i].Mean != summaries[j].Mean {
+			return better(summaries[i].Mean, summaries[j].Mean)
+		}
+		return summaries[i].ProductID < summaries[j].ProductID
+	})
+
+	best := summaries[0].Mean
+	for i := range summaries {
+		if i > 0 && summaries[i].Mean == summaries[i-1].Mean {
+			summaries[i].Rank = summaries[i-1].Rank
+		} else {
+			summaries[i].Rank = i + 1
+		}
+		// Centimeters for distances in meters, hundredths for times in seconds.
+		summaries[i].DistanceBehind = int(math.Round(math.Abs(best-summaries[i].Mean) * 100))
+	}
+	return summaries
+}
+
+// meanAndStdDev computes the mean and the sample standard deviation of the runs.
+func meanAndStdDev(runs []domain.TestRun) (float64, float64) {
+	var sum float64
+	for _, run := range runs {
+		sum += run.Value
+	}
+	mean := sum / float64(len(runs))
+	if len(runs) < 2 {
+		return mean, 0
+	}
+
+	var squares float64
+	for _, run := range runs {
+		squares += (run.Value - mean) * (run.Value - mean)
+	}
+	return mean, math.Sqrt(squares / float64(len(runs)-1))
+}
+
+// lastSki returns the ski pair of the most recent run that has one.
+func lastSki(runs []domain.TestRun) *int {
+	for i := len(runs) - 1; i >= 0; i-- {
+		if runs[i].SkiID != nil {
+			return runs[i].SkiID
+		}
+	}
+	return nil
+}
+
+// writeRanks writes the computed rank and distance behind of every product to test_ranks.
+func writeRanks(tx *sql.Tx, testID int, summaries []domain.RunSummary) error {
+	for _, summary := range summaries {
+		_, err := tx.Exec(`INSERT INTO test_ranks (
+                      	test_id, product_id, rank, distance_behind, version, is_rank_public, ski_id)
+						VALUES ($1, $2, $3, $4, $5, (SELECT is_public FROM products WHERE id = $2), $6)
+						ON CONFLICT (test_id, product_id) DO UPDATE
+						SET rank = EXCLUDED.rank, distance_behind = EXCLUDED.distance_behind, version = EXCLUDED.version,
+						    ski_id = COALESCE(EXCLUDED.ski_id, test_ranks.ski_id);`,
+			testID,
+			summary.ProductID,
+			summary.Rank,
+			summary.DistanceBehind,
+			time.Now(),
+			lastSki(summary.Runs))
+		if err != nil {
+			return fmt.Errorf("failed to write rank for product %d: %w", summary.ProductID, err)
+		}
+	}
+	return nil
+}
+
+// runMetric returns the metric of the runs, or an empty metric when the test has no runs.
+func runMetric(runs []domain.TestRun) domain.RunMetric {
+	if len(runs) == 0 {
+		return ""
+	}
+	return runs[0].Metric
+}
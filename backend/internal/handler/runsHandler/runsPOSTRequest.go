@@ -0,0 +1,21 @@
+package runsHandler
+
+import (
+	"backend/internal/domain"
+	"time"
+)
+
+type RunsPOSTRequest struct {
+	// Metric: distance for glide-out distances in meters, time for gate times in seconds.
+	Metric domain.RunMetric `json:"metric" validate:"required,oneof=distance time"`
+	Runs   []RunPOST        `json:"runs" validate:"required,min=1,dive"`
+}
+
+type RunPOST struct {
+	ProductID int  `json:"product_id" validate:"required,gt=0"`
+	SkiID     *int `json:"ski_id" validate:"omitempty,gt=0"`
+	// Value: The measured distance or time of the run.
+	Value float64 `json:"value" validate:"required,gt=0"`
+	// RecordedAt: When the run was made. Defaults to the time of the request.
+	RecordedAt *time.Time `json:"recorded_at"`
+}
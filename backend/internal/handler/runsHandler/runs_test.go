@@ -0,0 +1,354 @@
+package runsHandler
+
+import (
+	"backend/internal/domain"
+	"backend/internal/utils"
+	"database/sql"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func AuthenticationMock(mock sqlmock.Sqlmock) {
+	// Mock the user id query
+	mock.ExpectQuery("SELECT user_id FROM sessions WHERE \\(session_token = \\$1 AND expires_at > NOW\\(\\)\\)").
+		WithArgs("mockToken").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(1))
+
+	// Mock the user team id query
+	mock.ExpectQuery("SELECT team_id FROM users WHERE id = \\$1;").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"team_id"}).AddRow(1))
+
+	// Mock the user team role query
+	mock.ExpectQuery("SELECT team_role FROM team WHERE id = \\$1;").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"team_role"}).AddRow(1))
+}
+
+var runColumns = []string{
+	"id", "test_id", "product_id", "ski_id", "metric", "value", "recorded_at", "version",
+}
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func newRuns(metric domain.RunMetric, values map[int][]float64) []domain.TestRun {
+	var runs []domain.TestRun
+	for _, productID := range []int{1, 2, 3} {
+		for _, value := range values[productID] {
+			runs = append(runs, domain.TestRun{ProductID: productID, Metric: metric, Value: value})
+		}
+	}
+	return runs
+}
+
+func Test_summarizeRuns(t *testing.T) {
+	t.Run("Distance runs, the longest mean wins", func(t *testing.T) {
+		summaries := summarizeRuns(domain.RunDistance, newRuns(domain.RunDistance, map[int][]float64{
+			1: {40.0, 42.0},
+			2: {45.0, 45.5, 44.5},
+			3: {41.0},
+		}))
+
+		assert.Len(t, summaries, 3)
+		assert.Equal(t, 2, summaries[0].ProductID)
+		assert.Equal(t, 1, summaries[0].Rank)
+		assert.Equal(t, 3, summaries[0].Count)
+		assert.InDelta(t, 45.0, summaries[0].Mean, 1e-9)
+		assert.InDelta(t, 0.5, summaries[0].StdDev, 1e-9)
+		assert.Equal(t, 0, summaries[0].DistanceBehind)
+
+		// Products 1 and 3 have the same mean, and share the rank.
+		assert.Equal(t, 1, summaries[1].ProductID)
+		assert.Equal(t, 2, summaries[1].Rank)
+		assert.InDelta(t, 1.4142, summaries[1].StdDev, 1e-4)
+		assert.Equal(t, 400, summaries[1].DistanceBehind)
+		assert.Equal(t, 3, summaries[2].ProductID)
+		assert.Equal(t, 2, summaries[2].Rank)
+		assert.Equal(t, 0.0, summaries[2].StdDev)
+	})
+
+	t.Run("Time runs, the shortest mean wins", func(t *testing.T) {
+		summaries := summarizeRuns(domain.RunTime, newRuns(domain.RunTime, map[int][]float64{
+			1: {7.52, 7.48},
+			2: {7.31, 7.35},
+		}))
+
+		assert.Equal(t, 2, summaries[0].ProductID)
+		assert.Equal(t, 1, summaries[0].Rank)
+		assert.Equal(t, 1, summaries[1].ProductID)
+		assert.Equal(t, 2, summaries[1].Rank)
+		assert.Equal(t, 17, summaries[1].DistanceBehind)
+	})
+
+	t.Run("No runs", func(t *testing.T) {
+		assert.Empty(t, summarizeRuns(domain.RunDistance, nil))
+	})
+}
+
+func Test_lastSki(t *testing.T) {
+	runs := []domain.TestRun{{SkiID: intPtr(4)}, {SkiID: intPtr(7)}, {}}
+	assert.Equal(t, intPtr(7), lastSki(runs))
+	assert.Nil(t, lastSki([]domain.TestRun{{}}))
+}
+
+func TestRunsHandler(t *testing.T) {
+	mockDB, mock := utils.InitMockDB(t)
+	recordedAt := time.Date(2025, 1, 12, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		method       string
+		path         string
+		body         string
+		setupMocks   func()
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:         "Method = GET (Status OK)",
+			method:       http.MethodGet,
+			path:         "/tests/1/runs",
+			expectedCode: http.StatusOK,
+			expectedBody: `"metric":"distance","products":[{"product_id":2,"count":1`,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+
+				mock.ExpectQuery("SELECT testing_team, is_public, state FROM tests WHERE id = \\$1;").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"testing_team", "is_public", "state"}).
+						AddRow(1, false, "in_progress"))
+
+				mock.ExpectQuery("SELECT id, test_id, product_id, ski_id, metric, value, recorded_at, version").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows(runColumns).
+						AddRow(1, 1, 1, nil, "distance", 40.0, recordedAt, recordedAt).
+						AddRow(2, 1, 2, 3, "distance", 42.5, recordedAt, recordedAt))
+			},
+		},
+		{
+			name:         "Method = GET (Status unauthorized - private test of another team)",
+			method:       http.MethodGet,
+			path:         "/tests/1/runs",
+			expectedCode: http.StatusUnauthorized,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+
+				mock.ExpectQuery("SELECT testing_team, is_public, state FROM tests WHERE id = \\$1;").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"testing_team", "is_public", "state"}).
+						AddRow(2, false, "in_progress"))
+			},
+		},
+		{
+			name:         "Method = POST (Status created)",
+			method:       http.MethodPost,
+			path:         "/tests/1/runs",
+			body:         `{"metric":"time","runs":[{"product_id":1,"value":7.5,"recorded_at":"2025-01-12T10:00:00Z"},{"product_id":2,"ski_id":3,"value":7.3,"recorded_at":"2025-01-12T10:01:00Z"}]}`,
+			expectedCode: http.StatusCreated,
+			expectedBody: `"distance_behind":20`,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+
+				mock.ExpectQuery("SELECT testing_team, is_public, state FROM tests WHERE id = \\$1;").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"testing_team", "is_public", "state"}).
+						AddRow(1, false, "in_progress"))
+				mock.ExpectQuery("SELECT is_public FROM products WHERE id = \\$1 AND \\(is_public OR testing_team = \\$2\\);").
+					WithArgs(1, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"is_public"}).AddRow(true))
+				mock.ExpectQuery("SELECT is_public FROM products WHERE id = \\$1 AND \\(is_public OR testing_team = \\$2\\);").
+					WithArgs(2, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"is_public"}).AddRow(true))
+				mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM skis WHERE id = \\$1 AND testing_team = \\$2;").
+					WithArgs(3, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+				mock.ExpectBegin()
+				mock.ExpectExec("SELECT id FROM tests WHERE id = \\$1 FOR UPDATE;").
+					WithArgs(1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("SELECT metric FROM test_runs WHERE test_id = \\$1 LIMIT 1;").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"metric"}).AddRow("time"))
+
+				mock.ExpectExec("INSERT INTO test_runs").
+					WithArgs(1, 1, nil, domain.RunTime, 7.5, recordedAt, sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO test_runs").
+					WithArgs(1, 2, 3, domain.RunTime, 7.3, recordedAt.Add(time.Minute), sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectQuery("SELECT id, test_id, product_id, ski_id, metric, value, recorded_at, version").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows(runColumns).
+						AddRow(1, 1, 1, nil, "time", 7.5, recordedAt, recordedAt).
+						AddRow(2, 1, 2, 3, "time", 7.3, recordedAt.Add(time.Minute), recordedAt))
+
+				mock.ExpectExec("INSERT INTO test_ranks").
+					WithArgs(1, 2, 1, 0, sqlmock.AnyArg(), 3).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO test_ranks").
+					WithArgs(1, 1, 2, 20, sqlmock.AnyArg(), nil).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				// Product 1 is rated for warmer snow than the test, and is flagged.
+				mock.ExpectQuery("SELECT r.product_id, r.out_of_temperature_range, .* FROM test_ranks r").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"product_id", "out_of_temperature_range", "type",
+						"low_temperature", "high_temperature", "temperature", "temperature"}).
+						AddRow(1, false, "liquid", 0.0, 5.0, -5.0, -8.0).
+						AddRow(2, false, "liquid", -10.0, -2.0, -5.0, -8.0))
+				mock.ExpectExec("UPDATE test_ranks SET out_of_temperature_range = \\$1").
+					WithArgs(true, 1, 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:         "Method = POST (Status conflict - different metric)",
+			method:       http.MethodPost,
+			path:         "/tests/1/runs",
+			body:         `{"metric":"distance","runs":[{"product_id":1,"value":41.2}]}`,
+			expectedCode: http.StatusConflict,
+			expectedBody: "The runs of a test must use the same metric.",
+			setupMocks: func() {
+				AuthenticationMock(mock)
+
+				mock.ExpectQuery("SELECT testing_team, is_public, state FROM tests WHERE id = \\$1;").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"testing_team", "is_public", "state"}).
+						AddRow(1, false, "in_progress"))
+				mock.ExpectQuery("SELECT is_public FROM products WHERE id = \\$1 AND \\(is_public OR testing_team = \\$2\\);").
+					WithArgs(1, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"is_public"}).AddRow(true))
+
+				mock.ExpectBegin()
+				mock.ExpectExec("SELECT id FROM tests WHERE id = \\$1 FOR UPDATE;").
+					WithArgs(1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("SELECT metric FROM test_runs WHERE test_id = \\$1 LIMIT 1;").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"metric"}).AddRow("time"))
+				mock.ExpectRollback()
+			},
+		},
+		{
+			name:         "Method = POST (Status bad request - unknown product)",
+			method:       http.MethodPost,
+			path:         "/tests/1/runs",
+			body:         `{"metric":"distance","runs":[{"product_id":9,"value":41.2}]}`,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "product 9 does not exist",
+			setupMocks: func() {
+				AuthenticationMock(mock)
+
+				mock.ExpectQuery("SELECT testing_team, is_public, state FROM tests WHERE id = \\$1;").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"testing_team", "is_public", "state"}).
+						AddRow(1, false, "in_progress"))
+				mock.ExpectQuery("SELECT is_public FROM products WHERE id = \\$1 AND \\(is_public OR testing_team = \\$2\\);").
+					WithArgs(9, 1).
+					WillReturnError(sql.ErrNoRows)
+			},
+		},
+		{
+			name:         "Method = POST (Status bad request - ski of another team)",
+			method:       http.MethodPost,
+			path:         "/tests/1/runs",
+			body:         `{"metric":"distance","runs":[{"product_id":1,"ski_id":4,"value":41.2}]}`,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "ski 4 does not exist",
+			setupMocks: func() {
+				AuthenticationMock(mock)
+
+				mock.ExpectQuery("SELECT testing_team, is_public, state FROM tests WHERE id = \\$1;").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"testing_team", "is_public", "state"}).
+						AddRow(1, false, "in_progress"))
+				mock.ExpectQuery("SELECT is_public FROM products WHERE id = \\$1 AND \\(is_public OR testing_team = \\$2\\);").
+					WithArgs(1, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"is_public"}).AddRow(true))
+				mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM skis WHERE id = \\$1 AND testing_team = \\$2;").
+					WithArgs(4, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+			},
+		},
+		{
+			name:         "Method = POST (Status unauthorized - test of another team)",
+			method:       http.MethodPost,
+			path:         "/tests/1/runs",
+			body:         `{"metric":"distance","runs":[{"product_id":1,"value":41.2}]}`,
+			expectedCode: http.StatusUnauthorized,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+
+				mock.ExpectQuery("SELECT testing_team, is_public, state FROM tests WHERE id = \\$1;").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"testing_team", "is_public", "state"}).
+						AddRow(2, false, "in_progress"))
+			},
+		},
+		{
+			name:         "Method = POST (Status conflict - published test)",
+			method:       http.MethodPost,
+			path:         "/tests/1/runs",
+			body:         `{"metric":"distance","runs":[{"product_id":1,"value":41.2}]}`,
+			expectedCode: http.StatusConflict,
+			expectedBody: "test is published and cannot be changed",
+			setupMocks: func() {
+				AuthenticationMock(mock)
+
+				mock.ExpectQuery("SELECT testing_team, is_public, state FROM tests WHERE id = \\$1;").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"testing_team", "is_public", "state"}).
+						AddRow(1, true, "published"))
+			},
+		},
+		{
+			name:         "Method = POST (Status bad request - invalid metric)",
+			method:       http.MethodPost,
+			path:         "/tests/1/runs",
+			body:         `{"metric":"speed","runs":[{"product_id":1,"value":41.2}]}`,
+			expectedCode: http.StatusBadRequest,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+			},
+		},
+		{
+			name:         "Method = DELETE (Status not implemented)",
+			method:       http.MethodDelete,
+			path:         "/tests/1/runs",
+			expectedCode: http.StatusNotImplemented,
+			setupMocks:   func() {},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer mockToken")
+			rr := httptest.NewRecorder()
+
+			RunsHandler(mockDB).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedCode, rr.Code)
+			if tt.expectedBody != "" {
+				assert.Contains(t, rr.Body.String(), tt.expectedBody)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
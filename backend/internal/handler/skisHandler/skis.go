@@ -0,0 +1,331 @@
+package skisHandler
+
+import (
+	"backend/internal/middleware"
+	"backend/internal/resources"
+	"backend/internal/utils"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var skiPath = regexp.MustCompile(`^/skis/(\d+)$`)
+var skiResultsPath = regexp.MustCompile(`^/skis/(\d+)/results$`)
+
+// SkisHandler routes HTTP requests for the test skis to the appropriate handler function.
+//
+// It supports the following methods:
+// - GET: Retrieves the skis of a team, a single ski, or the results of a ski.
+// - POST: Creates a new ski.
+// - PATCH: Updates an existing ski.
+// - DELETE: Deletes a ski.
+//
+// Each method has its own dedicated request handler function, which is documented separately using Swagger annotations.
+func SkisHandler(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		switch r.Method {
+		case http.MethodGet:
+			SkisRequestGET(w, r, db)
+		case http.MethodPost:
+			SkisRequestPOST(w, r, db)
+		case http.MethodPatch:
+			SkisRequestPATCH(w, r, db)
+		case http.MethodDelete:
+			SkisRequestDELETE(w, r, db)
+		default:
+			http.Error(w, resources.MethodNotAllowed, http.StatusNotImplemented)
+			return
+		}
+	}
+}
+
+// SkisRequestGET retrieves the skis of the user's team.
+//
+//	@Summary		Get the test skis
+//	@Description	Retrieves all the skis of the user's team, a single ski, or the product results from the tests
+//	@Description	where the ski pair was used.
+//	@Tags			Skis
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			ski_id		path		int			false	"Ski ID"
+//	@Param			product_id	query		int			false	"Only results for this product"
+//	@Success		200			{array}		domain.Ski	"Successful response with a list of skis"
+//	@Failure		401			{string}	string		"Unauthorized"
+//	@Failure		404			{string}	string		"Could not find the ski."
+//	@Failure		500			{string}	string		"Could not retrieve the skis."
+//	@Router			/skis [get]
+//	@Router			/skis/{ski_id} [get]
+//	@Router			/skis/{ski_id}/results [get]
+func SkisRequestGET(w http.ResponseWriter, r *http.Request, db *sql.DB) {
+	// Get the user's team membership.
+	team := middleware.GetUserTeamRole(w, r, db)
+
+	if matches := skiResultsPath.FindStringSubmatch(r.URL.Path); len(matches) == 2 {
+		skiID, _ := strconv.Atoi(matches[1])
+		SkiResultsRequestGET(w, r, db, skiID, team)
+		return
+	}
+
+	idParam := strings.Trim(strings.TrimPrefix(r.URL.Path, "/skis"), "/")
+	if idParam == "" {
+		skis, err := getSkisForTeam(db, team)
+		if err != nil {
+			http.Error(w, "Could not retrieve the skis.", http.StatusInternalServerError)
+			log.Println("Could not retrieve the skis: " + err.Error())
+			return
+		}
+
+		err = json.NewEncoder(w).Encode(skis)
+		if err != nil {
+			http.Error(w, "Could not encode the skis.", http.StatusInternalServerError)
+			log.Println("Could not encode the skis: " + err.Error())
+		}
+		return
+	}
+
+	skiID, err := utils.GetIDFromURLQuery(w, idParam)
+	if err != nil {
+		return
+	}
+
+	ski, err := getSkiWithID(db, skiID)
+	if err != nil || ski.TestingTeam != team {
+		http.Error(w, "Could not find the ski.", http.StatusNotFound)
+		log.Println("Could not find the ski: " + idParam)
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(ski)
+	if err != nil {
+		http.Error(w, "Could not encode the ski.", http.StatusInternalServerError)
+		log.Println("Could not encode the ski: " + err.Error())
+		return
+	}
+}
+
+// SkiResultsRequestGET retrieves the product results from the tests where a ski pair was used.
+func SkiResultsRequestGET(w http.ResponseWriter, r *http.Request, db *sql.DB, skiID int, team int) {
+	ski, err := getSkiWithID(db, skiID)
+	if err != nil || ski.TestingTeam != team {
+		http.Error(w, "Could not find the ski.", http.StatusNotFound)
+		log.Println("Could not find the ski: " + strconv.Itoa(skiID))
+		return
+	}
+
+	var productID int
+	if param := r.URL.Query().Get("product_id"); param != "" {
+		productID, err = utils.GetIDFromURLQuery(w, param)
+		if err != nil {
+			return
+		}
+	}
+
+	results, err := getSkiResults(db, skiID, team, productID)
+	if err != nil {
+		http.Error(w, "Could not retrieve the results of the ski.", http.StatusInternalServerError)
+		log.Println("Could not retrieve the results of the ski: " + err.Error())
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(results)
+	if err != nil {
+		http.Error(w, "Could not encode the results.", http.StatusInternalServerError)
+		log.Println("Could not encode the results: " + err.Error())
+		return
+	}
+}
+
+// SkisRequestPOST creates a new ski for the user's team.
+//
+//	@Summary		Create a new ski
+//	@Description	Adds a new ski pair to the fleet of the user's team.
+//	@Tags			Skis
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			ski	body		SkiPOSTRequest	true	"New ski information"
+//	@Success		201	{string}	string			"Ski created successfully"
+//	@Failure		400	{string}	string			"Invalid POST request body"
+//	@Failure		409	{string}	string			"A ski with this ski code already exists"
+//	@Failure		500	{string}	string			"Could not create the ski."
+//	@Router			/skis [post]
+func SkisRequestPOST(w http.ResponseWriter, r *http.Request, db *sql.DB) {
+	// Get the user's team membership.
+	team := middleware.GetUserTeamRole(w, r, db)
+
+	ski, err := utils.ParseAndValidateRequest[SkiPOSTRequest](r)
+	if err != nil {
+		http.Error(w, resources.InvalidPOSTRequest, http.StatusBadRequest)
+		log.Println(resources.InvalidPOSTRequest + ": " + err.Error())
+		return
+	}
+
+	taken, err := isSkiCodeTaken(db, team, ski.SkiCode, 0)
+	if err != nil {
+		http.Error(w, "Could not create the ski.", http.StatusInternalServerError)
+		log.Println("Could not check the ski code: " + err.Error())
+		return
+	}
+	if taken {
+		http.Error(w, "A ski with this ski code already exists", http.StatusConflict)
+		log.Println("A ski with this ski code already exists")
+		return
+	}
+
+	skiID, err := insertNewSki(db, ski, team)
+	if err != nil {
+		http.Error(w, "Could not create the ski.", http.StatusInternalServerError)
+		log.Println("Could not create the ski: " + err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	err = json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Ski created successfully",
+		"id":      skiID,
+	})
+	if err != nil {
+		log.Println("Could not JSON encode the response: " + err.Error())
+		return
+	}
+}
+
+// SkisRequestPATCH updates an existing ski.
+//
+//	@Summary		Update a ski
+//	@Description	Updates fields of an existing ski of the user's team.
+//	@Tags			Skis
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			ski_id	path		int				true	"Ski ID"
+//	@Param			ski		body		SkiPATCHRequest	true	"Ski update fields"
+//	@Success		200		{string}	string			"Ski updated successfully"
+//	@Failure		400		{string}	string			"Invalid PATCH request body"
+//	@Failure		401		{string}	string			"Unauthorized"
+//	@Failure		404		{string}	string			"Could not find the ski."
+//	@Failure		409		{string}	string			"Detected a conflict for the current ski, please refresh."
+//	@Router			/skis/{ski_id} [patch]
+func SkisRequestPATCH(w http.ResponseWriter, r *http.Request, db *sql.DB) {
+	matches := skiPath.FindStringSubmatch(r.URL.Path)
+	if len(matches) != 2 {
+		http.Error(w, "Invalid request URL, use '/skis/{ski_id}'.", http.StatusBadRequest)
+		log.Println("Invalid request URL: " + r.URL.Path)
+		return
+	}
+	skiID, _ := strconv.Atoi(matches[1])
+
+	// Get the user's team membership.
+	team := middleware.GetUserTeamRole(w, r, db)
+
+	var skiUpdateRequest SkiPATCHRequest
+	err := utils.DecodeRequestBody(w, r, &skiUpdateRequest)
+	if err != nil {
+		return
+	}
+
+	existingSki, err := getSkiWithID(db, skiID)
+	if err != nil {
+		http.Error(w, "Could not find the ski.", http.StatusNotFound)
+		log.Println("Could not find the ski: " + err.Error())
+		return
+	}
+
+	var code int
+	err, code = ValidateSkiPATCHRequestBody(db, skiUpdateRequest, existingSki, team)
+	if err != nil {
+		http.Error(w, "Validation error: "+err.Error(), code)
+		return
+	}
+
+	// Solve the concurrency challenge by checking if the ski has been updated since the last sync.
+	if existingSki.Version.After(skiUpdateRequest.Version) {
+		http.Error(w, "Detected a conflict for the current ski, please refresh.", http.StatusConflict)
+		log.Println("Detected a conflict for the current ski, please refresh.")
+		return
+	}
+
+	var updatedFields []string
+	var newValues []interface{}
+	i := 1 // Index for the newValues array.
+	updatedFields, newValues, i = utils.CreateUpdateQuery(w, skiUpdateRequest.Updates, updatedFields, newValues, i)
+	if len(updatedFields) == 0 {
+		return
+	}
+
+	query := fmt.Sprintf("UPDATE skis SET %s WHERE id = $%d AND version = $%d RETURNING version",
+		strings.Join(updatedFields, ", "), i, i+1)
+	newValues = append(newValues, skiID, existingSki.Version)
+
+	var newVersion time.Time
+	err = db.QueryRow(query, newValues...).Scan(&newVersion)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Detected a conflict for the current ski, please refresh.", http.StatusConflict)
+		log.Println("Detected a conflict for the current ski, please refresh.")
+		return
+	} else if err != nil {
+		http.Error(w, "Could not update the ski.", http.StatusInternalServerError)
+		log.Println("Could not update the ski: " + err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Ski updated successfully",
+		"version": newVersion,
+	})
+	if err != nil {
+		log.Println("Could not JSON encode the response: " + err.Error())
+		return
+	}
+}
+
+// SkisRequestDELETE deletes a ski. The test results keep their ranks, but lose the reference to the ski.
+//
+//	@Summary		Delete a ski
+//	@Description	Deletes a ski of the user's team. Test ranks that used the ski are kept without the ski reference.
+//	@Tags			Skis
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			ski_id	path	int	true	"Ski ID"
+//	@Success		204		"Ski deleted successfully"
+//	@Failure		404		{string}	string	"Could not find the ski."
+//	@Failure		500		{string}	string	"Could not delete the ski."
+//	@Router			/skis/{ski_id} [delete]
+func SkisRequestDELETE(w http.ResponseWriter, r *http.Request, db *sql.DB) {
+	matches := skiPath.FindStringSubmatch(r.URL.Path)
+	if len(matches) != 2 {
+		http.Error(w, "Invalid request URL, use '/skis/{ski_id}'.", http.StatusBadRequest)
+		log.Println("Invalid request URL: " + r.URL.Path)
+		return
+	}
+	skiID, _ := strconv.Atoi(matches[1])
+
+	// Get the user's team membership.
+	team := middleware.GetUserTeamRole(w, r, db)
+
+	result, err := db.Exec("DELETE FROM skis WHERE id = $1 AND testing_team = $2;", skiID, team)
+	if err != nil {
+		http.Error(w, "Could not delete the ski.", http.StatusInternalServerError)
+		log.Println("Could not delete the ski: " + err.Error())
+		return
+	}
+
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		http.Error(w, "Could not find the ski.", http.StatusNotFound)
+		log.Println("Could not find the ski: " + matches[1])
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,172 @@
+package skisHandler
+
+import (
+	"backend/internal/domain"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"log"
+	"net/http"
+	"time"
+)
+
+const skiColumns = "id, ski_code, brand, base, grind, flex, length, notes, testing_team, version"
+
+// scanSki scans a skis row into a Ski struct.
+func scanSki(row interface{ Scan(...any) error }) (domain.Ski, error) {
+	var ski domain.Ski
+	err := row.Scan(
+		&ski.ID,
+		&ski.SkiCode,
+		&ski.Brand,
+		&ski.Base,
+		&ski.Grind,
+		&ski.Flex,
+		&ski.Length,
+		&ski.Notes,
+		&ski.TestingTeam,
+		&ski.Version)
+	return ski, err
+}
+
+// getSkisForTeam retrieves all the skis of a team, ordered by their ski code.
+func getSkisForTeam(db *sql.DB, team int) ([]domain.Ski, error) {
+	rows, err := db.Query("SELECT "+skiColumns+" FROM skis WHERE testing_team = $1 ORDER BY ski_code;", team)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	skis := []domain.Ski{}
+	for rows.Next() {
+		ski, err := scanSki(rows)
+		if err != nil {
+			return nil, err
+		}
+		skis = append(skis, ski)
+	}
+	return skis, rows.Err()
+}
+
+// getSkiWithID retrieves a ski with a specific ID from the database.
+func getSkiWithID(db *sql.DB, skiID int) (domain.Ski, error) {
+	return scanSki(db.QueryRow("SELECT "+skiColumns+" FROM skis WHERE id = $1;", skiID))
+}
+
+// isSkiCodeTaken checks if another ski of the team already uses the ski code.
+func isSkiCodeTaken(db *sql.DB, team int, skiCode string, skiID int) (bool, error) {
+	var count int
+	err := db.QueryRow("SELECT COUNT(*) FROM skis WHERE testing_team = $1 AND ski_code = $2 AND id <> $3;",
+		team, skiCode, skiID).Scan(&count)
+	return count > 0, err
+}
+
+// insertNewSki inserts a new ski into the database and returns its ID.
+func insertNewSki(db *sql.DB, ski SkiPOSTRequest, team int) (int, error) {
+	var skiID int
+	err := db.QueryRow(`INSERT INTO skis (
+                  ski_code, brand, base, grind, flex, length, notes, testing_team, version)
+                  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+                  RETURNING id;`,
+		ski.SkiCode,
+		ski.Brand,
+		ski.Base,
+		ski.Grind,
+		ski.Flex,
+		ski.Length,
+		ski.Notes,
+		team,
+		time.Now()).Scan(&skiID)
+	return skiID, err
+}
+
+// ValidateSkiPATCHRequestBody validates the keys and values of a ski update, and that the team owns the ski.
+func ValidateSkiPATCHRequestBody(db *sql.DB, skiUpdateRequest SkiPATCHRequest, existingSki domain.Ski,
+	team int) (error, int) {
+	var update SkiUpdateFields
+	b, _ := json.Marshal(skiUpdateRequest.Updates)
+	err := json.Unmarshal(b, &update)
+	if err != nil {
+		log.Println("Could not decode request body: " + err.Error())
+		return fmt.Errorf("could not decode request body, %d", http.StatusBadRequest), http.StatusBadRequest
+	}
+
+	validate := validator.New()
+
+	// Validate keys
+	err = validate.Struct(skiUpdateRequest)
+	if err != nil {
+		log.Println("Invalid PATCH request body keys: " + err.Error())
+		return fmt.Errorf("invalid PATCH request body keys, %d", http.StatusBadRequest), http.StatusBadRequest
+	}
+
+	// Validate values
+	err = validate.Struct(update)
+	if err != nil {
+		log.Println("Invalid PATCH request body values: " + err.Error())
+		return fmt.Errorf("invalid PATCH request body values, %d", http.StatusBadRequest), http.StatusBadRequest
+	}
+
+	// Check if the user is authorized to update the ski.
+	if existingSki.TestingTeam != team {
+		log.Println("User cannot update this ski")
+		return fmt.Errorf("user cannot update this ski, %d", http.StatusUnauthorized), http.StatusUnauthorized
+	}
+
+	// The ski code is the label of the ski pair, so it must be present and unique within the team.
+	if update.SkiCode != nil {
+		if *update.SkiCode == "" {
+			return fmt.Errorf("ski code cannot be blank, %d", http.StatusBadRequest), http.StatusBadRequest
+		}
+
+		taken, err := isSkiCodeTaken(db, team, *update.SkiCode, existingSki.ID)
+		if err != nil {
+			log.Println("Could not check the ski code: " + err.Error())
+			return fmt.Errorf("could not check the ski code, %d", http.StatusInternalServerError),
+				http.StatusInternalServerError
+		}
+		if taken {
+			log.Println("Update not allowed: a ski with this ski code already exists")
+			return fmt.Errorf("a ski with this ski code already exists, %d", http.StatusConflict), http.StatusConflict
+		}
+	}
+	return nil, 0
+}
+
+// getSkiResults retrieves the results of the products tested on a ski pair, from the tests visible to the team.
+func getSkiResults(db *sql.DB, skiID int, team int, productID int) ([]domain.SkiResult, error) {
+	query := `SELECT t.id, t.test_date, t.location, tr.product_id,
+                      COALESCE(tr.rank, 0), COALESCE(tr.distance_behind, 0)
+				FROM test_ranks tr
+				JOIN tests t ON t.id = tr.test_id
+				WHERE tr.ski_id = $1 AND (t.testing_team = $2 OR (t.is_public AND tr.is_rank_public))`
+	args := []interface{}{skiID, team}
+
+	if productID != 0 {
+		query += " AND tr.product_id = $3"
+		args = append(args, productID)
+	}
+
+	rows, err := db.Query(query+" ORDER BY t.test_date DESC, tr.rank;", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := []domain.SkiResult{}
+	for rows.Next() {
+		var result domain.SkiResult
+		if err = rows.Scan(
+			&result.TestID,
+			&result.TestDate,
+			&result.Location,
+			&result.ProductID,
+			&result.Rank,
+			&result.DistanceBehind); err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, rows.Err()
+}
@@ -0,0 +1,20 @@
+package skisHandler
+
+import (
+	"time"
+)
+
+type SkiPATCHRequest struct {
+	Updates map[string]interface{} `json:"updates" validate:"required,dive,keys,oneof=ski_code brand base grind flex length notes,endkeys"`
+	Version time.Time              `json:"version"`
+}
+
+type SkiUpdateFields struct {
+	SkiCode *string `json:"ski_code" validate:"omitempty,lte=32,ascii"`
+	Brand   *string `json:"brand" validate:"omitempty,lte=64,ascii"`
+	Base    *string `json:"base" validate:"omitempty,lte=64,ascii"`
+	Grind   *string `json:"grind" validate:"omitempty,lte=64,ascii"`
+	Flex    *string `json:"flex" validate:"omitempty,lte=32,ascii"`
+	Length  *int    `json:"length" validate:"omitempty,gte=100,lte=250"`
+	Notes   *string `json:"notes" validate:"omitempty,max=2040"`
+}
@@ -0,0 +1,24 @@
+package skisHandler
+
+type SkiPOSTRequest struct {
+	// SkiCode: The team's own label for the ski pair, only ASCII, non-blank, max 32 characters.
+	SkiCode string `json:"ski_code" validate:"required,lte=32,ascii"`
+
+	// Brand: Only ASCII, can be blank, max 64 characters.
+	Brand string `json:"brand" validate:"omitempty,lte=64,ascii"`
+
+	// Base: Only ASCII, can be blank, max 64 characters.
+	Base string `json:"base" validate:"omitempty,lte=64,ascii"`
+
+	// Grind: The grind or structure of the base, only ASCII, can be blank, max 64 characters.
+	Grind string `json:"grind" validate:"omitempty,lte=64,ascii"`
+
+	// Flex: Only ASCII, can be blank, max 32 characters.
+	Flex string `json:"flex" validate:"omitempty,lte=32,ascii"`
+
+	// Length: In centimeters, between 100 and 250.
+	Length int `json:"length" validate:"omitempty,gte=100,lte=250"`
+
+	// Notes: max 2040 bytes.
+	Notes string `json:"notes" validate:"omitempty,max=2040"`
+}
@@ -0,0 +1,328 @@
+package skisHandler
+
+import (
+	"backend/internal/domain"
+	"backend/internal/utils"
+	"database/sql"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func AuthenticationMock(mock sqlmock.Sqlmock) {
+	// Mock the user id query
+	mock.ExpectQuery("SELECT user_id FROM sessions WHERE \\(session_token = \\$1 AND expires_at > NOW\\(\\)\\)").
+		WithArgs("mockToken").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(1))
+
+	// Mock the user team id query
+	mock.ExpectQuery("SELECT team_id FROM users WHERE id = \\$1;").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"team_id"}).AddRow(1))
+
+	// Mock the user team role query
+	mock.ExpectQuery("SELECT team_role FROM team WHERE id = \\$1;").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"team_role"}).AddRow(1))
+}
+
+var skiColumnNames = []string{
+	"id", "ski_code", "brand", "base", "grind", "flex", "length", "notes", "testing_team", "version",
+}
+
+func TestValidateSkiPATCHRequestBody(t *testing.T) {
+	mockDB, mock := utils.InitMockDB(t)
+	existingSki := domain.Ski{ID: 1, SkiCode: "C-12", TestingTeam: 1}
+
+	tests := []struct {
+		name         string
+		request      SkiPATCHRequest
+		team         int
+		setupMocks   func()
+		expectedCode int
+	}{
+		{
+			name:         "Valid update",
+			request:      SkiPATCHRequest{Updates: map[string]interface{}{"grind": "LS-2", "length": 192}},
+			team:         1,
+			setupMocks:   func() {},
+			expectedCode: 0,
+		},
+		{
+			name:         "Invalid key",
+			request:      SkiPATCHRequest{Updates: map[string]interface{}{"testing_team": 2}},
+			team:         1,
+			setupMocks:   func() {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "Invalid length",
+			request:      SkiPATCHRequest{Updates: map[string]interface{}{"length": 20}},
+			team:         1,
+			setupMocks:   func() {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "Ski of another team",
+			request:      SkiPATCHRequest{Updates: map[string]interface{}{"notes": "Fast"}},
+			team:         2,
+			setupMocks:   func() {},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "Blank ski code",
+			request:      SkiPATCHRequest{Updates: map[string]interface{}{"ski_code": ""}},
+			team:         1,
+			setupMocks:   func() {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:    "Ski code already in use",
+			request: SkiPATCHRequest{Updates: map[string]interface{}{"ski_code": "C-13"}},
+			team:    1,
+			setupMocks: func() {
+				mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM skis WHERE testing_team = \\$1 AND ski_code = \\$2 AND id <> \\$3;").
+					WithArgs(1, "C-13", 1).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+			},
+			expectedCode: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+
+			err, code := ValidateSkiPATCHRequestBody(mockDB, tt.request, existingSki, tt.team)
+			assert.Equal(t, tt.expectedCode, code)
+			if tt.expectedCode == 0 {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestSkisHandler(t *testing.T) {
+	mockDB, mock := utils.InitMockDB(t)
+	version := time.Date(2025, 3, 30, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		method       string
+		path         string
+		body         string
+		setupMocks   func()
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:         "Method = GET (Status OK - team skis)",
+			method:       http.MethodGet,
+			path:         "/skis",
+			expectedCode: http.StatusOK,
+			expectedBody: `"ski_code":"C-12"`,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+
+				mock.ExpectQuery("SELECT id, ski_code, brand, base, grind, flex, length, notes, testing_team, version FROM skis WHERE testing_team = \\$1").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows(skiColumnNames).
+						AddRow(1, "C-12", "Fischer", "Plus", "LS-2", "Medium", 192, "", 1, version))
+			},
+		},
+		{
+			name:         "Method = GET (Status not found - ski of another team)",
+			method:       http.MethodGet,
+			path:         "/skis/2",
+			expectedCode: http.StatusNotFound,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+
+				mock.ExpectQuery("SELECT .* FROM skis WHERE id = \\$1;").
+					WithArgs(2).
+					WillReturnRows(sqlmock.NewRows(skiColumnNames).
+						AddRow(2, "A-1", "Madshus", "", "", "", 0, "", 2, version))
+			},
+		},
+		{
+			name:         "Method = GET (Status OK - results of a ski for one product)",
+			method:       http.MethodGet,
+			path:         "/skis/1/results?product_id=3",
+			expectedCode: http.StatusOK,
+			expectedBody: `"product_id":3`,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+
+				mock.ExpectQuery("SELECT .* FROM skis WHERE id = \\$1;").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows(skiColumnNames).
+						AddRow(1, "C-12", "Fischer", "Plus", "LS-2", "Medium", 192, "", 1, version))
+
+				mock.ExpectQuery("SELECT t.id, t.test_date, t.location, tr.product_id").
+					WithArgs(1, 1, 3).
+					WillReturnRows(sqlmock.NewRows([]string{
+						"id", "test_date", "location", "product_id", "rank", "distance_behind"}).
+						AddRow(4, version, "Holmenkollen", 3, 1, 0))
+			},
+		},
+		{
+			name:         "Method = POST (Status created)",
+			method:       http.MethodPost,
+			path:         "/skis",
+			body:         `{"ski_code":"C-12","brand":"Fischer","base":"Plus","grind":"LS-2","flex":"Medium","length":192}`,
+			expectedCode: http.StatusCreated,
+			expectedBody: "Ski created successfully",
+			setupMocks: func() {
+				AuthenticationMock(mock)
+
+				mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM skis").
+					WithArgs(1, "C-12", 0).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+				mock.ExpectQuery("INSERT INTO skis").
+					WithArgs("C-12", "Fischer", "Plus", "LS-2", "Medium", 192, "", 1, sqlmock.AnyArg()).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+			},
+		},
+		{
+			name:         "Method = POST (Status conflict - ski code in use)",
+			method:       http.MethodPost,
+			path:         "/skis",
+			body:         `{"ski_code":"C-12"}`,
+			expectedCode: http.StatusConflict,
+			expectedBody: "A ski with this ski code already exists",
+			setupMocks: func() {
+				AuthenticationMock(mock)
+
+				mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM skis").
+					WithArgs(1, "C-12", 0).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+			},
+		},
+		{
+			name:         "Method = POST (Status bad request - missing ski code)",
+			method:       http.MethodPost,
+			path:         "/skis",
+			body:         `{"brand":"Fischer"}`,
+			expectedCode: http.StatusBadRequest,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+			},
+		},
+		{
+			name:         "Method = PATCH (Status OK)",
+			method:       http.MethodPatch,
+			path:         "/skis/1",
+			body:         `{"updates":{"grind":"LS-3"},"version":"2025-03-30T10:30:00Z"}`,
+			expectedCode: http.StatusOK,
+			expectedBody: "Ski updated successfully",
+			setupMocks: func() {
+				AuthenticationMock(mock)
+
+				mock.ExpectQuery("SELECT .* FROM skis WHERE id = \\$1;").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows(skiColumnNames).
+						AddRow(1, "C-12", "Fischer", "Plus", "LS-2", "Medium", 192, "", 1, version))
+
+				mock.ExpectQuery("UPDATE skis SET grind = \\$1, version = \\$2 WHERE id = \\$3 AND version = \\$4 RETURNING version").
+					WithArgs("LS-3", sqlmock.AnyArg(), 1, version).
+					WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(time.Now()))
+			},
+		},
+		{
+			name:         "Method = PATCH (Status conflict - outdated version)",
+			method:       http.MethodPatch,
+			path:         "/skis/1",
+			body:         `{"updates":{"grind":"LS-3"},"version":"2025-03-29T10:30:00Z"}`,
+			expectedCode: http.StatusConflict,
+			expectedBody: "Detected a conflict for the current ski, please refresh.",
+			setupMocks: func() {
+				AuthenticationMock(mock)
+
+				mock.ExpectQuery("SELECT .* FROM skis WHERE id = \\$1;").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows(skiColumnNames).
+						AddRow(1, "C-12", "Fischer", "Plus", "LS-2", "Medium", 192, "", 1, version))
+			},
+		},
+		{
+			name:         "Method = PATCH (Status not found)",
+			method:       http.MethodPatch,
+			path:         "/skis/9",
+			body:         `{"updates":{"grind":"LS-3"},"version":"2025-03-30T10:30:00Z"}`,
+			expectedCode: http.StatusNotFound,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+
+				mock.ExpectQuery("SELECT .* FROM skis WHERE id = \\$1;").
+					WithArgs(9).
+					WillReturnError(sql.ErrNoRows)
+			},
+		},
+		{
+			name:         "Method = DELETE (Status no content)",
+			method:       http.MethodDelete,
+			path:         "/skis/1",
+			expectedCode: http.StatusNoContent,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+
+				mock.ExpectExec("DELETE FROM skis WHERE id = \\$1 AND testing_team = \\$2;").
+					WithArgs(1, 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name:         "Method = DELETE (Status not found)",
+			method:       http.MethodDelete,
+			path:         "/skis/2",
+			expectedCode: http.StatusNotFound,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+
+				mock.ExpectExec("DELETE FROM skis WHERE id = \\$1 AND testing_team = \\$2;").
+					WithArgs(2, 1).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+		{
+			name:         "Method = PUT (Status not implemented)",
+			method:       http.MethodPut,
+			path:         "/skis/1",
+			expectedCode: http.StatusNotImplemented,
+			setupMocks:   func() {},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer mockToken")
+			rr := httptest.NewRecorder()
+
+			SkisHandler(mockDB).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedCode, rr.Code)
+			if tt.expectedBody != "" {
+				assert.Contains(t, rr.Body.String(), tt.expectedBody)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
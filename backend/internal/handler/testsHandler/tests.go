@@ -15,21 +15,58 @@ import (
 	"time"
 )
 
+var stateChangesPath = regexp.MustCompile(`^/tests/(\d+)/states/?$`)
+
+// OnTestChanged is called in the background after a test or its results have changed, so data derived from the
+// tests, such as the product ratings, can be brought up to date. It is set by the server.
+var OnTestChanged func(testID int)
+
+// NotifyTestChanged runs OnTestChanged for a test that was created or updated, or whose results changed, if it is set.
+func NotifyTestChanged(testID int) {
+	if OnTestChanged != nil {
+		go OnTestChanged(testID)
+	}
+}
+
 // TestsHandler routes HTTP requests for tests to the appropriate handler function.
 //
 // It supports the following methods:
-// - GET: Retrieves a list of tests based on filters.
-// - POST: Creates a new test.
+// - GET: Retrieves a list of tests based on filters or the state changes of a test, or exports the tests.
+// - POST: Creates a new test, imports tests from a CSV file, or finds the tests with the closest conditions.
 // - PUT: Updates an existing test.
 //
+// Requests for the drafts of the tests are passed on to the DraftsHandler.
+//
 // Each method has its own dedicated request handler function, which is documented separately using Swagger annotations.
 func TestsHandler(db *sql.DB) http.HandlerFunc {
+	drafts := DraftsHandler(db)
 	return func(w http.ResponseWriter, r *http.Request) {
+		if isDraftsPath(r.URL.Path) {
+			drafts(w, r)
+			return
+		}
+
 		w.Header().Set("content-type", "application/json")
 		switch r.Method {
 		case http.MethodGet:
+			if stateChangesPath.MatchString(r.URL.Path) {
+				TestStateChangesRequestGET(w, r, db)
+				return
+			}
+			if exportPath.MatchString(r.URL.Path) {
+				TestsExportRequestGET(w, r, db)
+				return
+			}
 			TestsRequestGET(w, r, db)
 		case http.MethodPost:
+			if importPath.MatchString(r.URL.Path) {
+				TestsImportRequestPOST(w, r, db)
+				return
+			}
+			if similarPath.MatchString(r.URL.Path) {
+				TestsSimilarRequestPOST(w, r, db)
+				return
+			}
 			TestsRequestPOST(w, r, db)
 		case http.MethodPatch:
 			TestsRequestPATCH(w, r, db)
@@ -51,6 +88,9 @@ func TestsHandler(db *sql.DB) http.HandlerFunc {
 //	@Param			public		query		string		false	"All Public Tests"
 //	@Param			start_date	query		string		false	"Start Date in YYYY-MM-DD format"
 //	@Param			end_date	query		string		false	"End date in YYYY-MM-DD format"
+//	@Param			location_id	query		int			false	"Only tests at this location"
+//	@Param			track_id	query		int			false	"Only tests on this track"
+//	@Param			state		query		string		false	"Only tests in this state"	Enums(planned, in_progress, completed, published)
 //	@Success		200			{array}		domain.Test	"Successful response with a list of tests"
 //	@Failure		400			{string}	string		"Invalid start date format."
 //	@Failure		500			{string}	string		"Could not retrieve all tests."
@@ -64,8 +104,13 @@ func TestsRequestGET(w http.ResponseWriter, r *http.Request, db *sql.DB) {
 	var tests []domain.Test
 
 	if public == "true" {
+		filter, args, err := testFilter(w, r, []interface{}{true})
+		if err != nil {
+			return
+		}
+
 		// Fetch all the public tests from the database.
-		rows, err := db.Query("SELECT * FROM tests WHERE is_public = $1;", true)
+		rows, err := db.Query("SELECT "+testColumns+" FROM tests WHERE is_public = $1"+filter+";", args...)
 		FetchTests(w, tests, rows, err)
 		return
 	}
@@ -86,17 +131,27 @@ func TestsRequestGET(w http.ResponseWriter, r *http.Request, db *sql.DB) {
 			return
 		}
 
+		filter, args, err := testFilter(w, r, []interface{}{team, startDate, endDate})
+		if err != nil {
+			return
+		}
+
 		// Fetch all the tests from the database where the date is between the start and end date.
 		rows, err := db.Query(
-			`SELECT * FROM tests WHERE testing_team = $1 AND 
-                          test_date >= to_date($2, 'YYYY-MM-DD') AND test_date <= to_date($3, 'YYYY-MM-DD');`,
-			team, startDate, endDate)
+			`SELECT `+testColumns+` FROM tests WHERE testing_team = $1 AND 
+                          test_date >= to_date($2, 'YYYY-MM-DD') AND test_date <= to_date($3, 'YYYY-MM-DD')`+filter+`;`,
+			args...)
 		FetchTests(w, tests, rows, err)
 		return
 	}
 
+	filter, args, err := testFilter(w, r, []interface{}{team})
+	if err != nil {
+		return
+	}
+
 	// Fetch all the tests from the database (both private and public) for the different authenticated user's
-	rows, err := db.Query("SELECT * FROM tests WHERE testing_team = $1;", team)
+	rows, err := db.Query("SELECT "+testColumns+" FROM tests WHERE testing_team = $1"+filter+";", args...)
 	FetchTests(w, tests, rows, err)
 	return
 }
@@ -104,14 +159,17 @@ func TestsRequestGET(w http.ResponseWriter, r *http.Request, db *sql.DB) {
 // TestsRequestPOST is the request handler for creating a new test.
 //
 //	@Summary		Create a new test
-//	@Description	Adds a new test to the database.
+//	@Description	Adds a new test to the database. Every ranked product is checked against the snow and air
+//	@Description	temperatures of the test, and the results outside the rated temperature range of the product are
+//	@Description	flagged and returned as warnings. A team with a strict temperature range has such tests refused.
 //	@Tags			Tests
 //	@Accept			json
 //	@Produce		json
 //	@Security		BearerAuth
-//	@Param			test	body	TestPOSTRequest	true	"New test information"
-//	@Success		201		"Test created successfully"
-//	@Failure		500		{string}	string	"Could not create test."
+//	@Param			test	body		TestPOSTRequest			true	"New test information"
+//	@Success		201		{object}	TestTemperatureResponse	"Test created successfully"
+//	@Failure		400		{object}	TestTemperatureResponse	"Products tested outside their temperature range"
+//	@Failure		500		{string}	string					"Could not create test."
 //	@Router			/tests [post]
 //	@Router			/tests/ [post]
 func TestsRequestPOST(w http.ResponseWriter, r *http.Request, db *sql.DB) {
@@ -142,6 +200,24 @@ func TestsRequestPOST(w http.ResponseWriter, r *http.Request, db *sql.DB) {
 		return
 	}
 
+	// Validate the location and track of the test
+	var code int
+	if err, code = validateTestLocation(db, &test); err != nil {
+		http.Error(w, "Validation error: "+err.Error(), code)
+		log.Println("Validation error: " + err.Error())
+		return
+	}
+
+	// Validate the skis the products were tested on
+	if err, code = validateTestSkis(db, test.TestRanks, team); err != nil {
+		http.Error(w, "Validation error: "+err.Error(), code)
+		log.Println("Validation error: " + err.Error())
+		return
+	}
+
+	// The user is recorded as the author of the state of a test created as completed or published.
+	userID := middleware.GetUserID(w, r, db)
+
 	tx, err := db.Begin()
 	if err != nil {
 		http.Error(w, resources.TransactionStartFailed, http.StatusInternalServerError)
@@ -157,7 +233,7 @@ func TestsRequestPOST(w http.ResponseWriter, r *http.Request, db *sql.DB) {
 	}()
 
 	// Create test with all relates entities
-	testID, err := createTest(tx, test, team)
+	testID, err := createTest(tx, test, team, userID)
 	if err != nil {
 		http.Error(w, "Failed to create test: "+err.Error(), http.StatusInternalServerError)
 		log.Println("Failed to create test: " + err.Error())
@@ -165,13 +241,18 @@ func TestsRequestPOST(w http.ResponseWriter, r *http.Request, db *sql.DB) {
 	}
 
 	// Create rankings
-	err = createTestRankings(tx, testID, test.TestRanks)
+	warnings, err := createTestRankings(tx, testID, temperaturesOf(test), test.TestRanks)
 	if err != nil {
 		http.Error(w, "Failed to create rankings: "+err.Error(), http.StatusInternalServerError)
 		log.Println("Failed to create rankings: " + err.Error())
 		return
 	}
 
+	// Refuse products outside their temperature range if the team does not allow them.
+	if err = RefuseOutOfTemperatureRange(w, tx, team, warnings); err != nil {
+		return
+	}
+
 	//Commit transaction
 	err = tx.Commit()
 	if err != nil {
@@ -180,13 +261,23 @@ func TestsRequestPOST(w http.ResponseWriter, r *http.Request, db *sql.DB) {
 		return
 	}
 
-	w.WriteHeader(http.StatusCreated)
+	NotifyTestChanged(testID)
+	writeTemperatureResponse(w, http.StatusCreated, TestTemperatureResponse{
+		Message:  "Test created successfully",
+		TestID:   testID,
+		Warnings: warnings,
+	})
 }
 
 // TestsRequestPATCH is the request handler for updating an existing test.
 //
 //	@Summary		Update an existing test's information, ranks, ac, tc and/or sc.
-//	@Description	Updates an existing test in the database.
+//	@Description	Updates an existing test in the database. The state of a test moves from planned to in_progress,
+//	@Description	between in_progress and completed, and from completed to published, which also makes the test
+//	@Description	public. Completed and published tests are read-only, so only their state can be changed. When the
+//	@Description	snow or air temperature changes, the ranked products are checked against the new temperatures and
+//	@Description	the results outside their rated temperature range are flagged and returned as warnings, or the
+//	@Description	update is refused for a team with a strict temperature range.
 //	@Tags			Tests
 //	@Accept			json
 //	@Produce		json
@@ -199,6 +290,8 @@ func TestsRequestPOST(w http.ResponseWriter, r *http.Request, db *sql.DB) {
 //	@Failure		400			{string}	string				"Invalid test ID"
 //	@Failure		400			{string}	string				"Invalid patch request."
 //	@Failure		409			{string}	string				"Detected a conflict for the current test, please refresh."
+//	@Failure		409			{string}	string				"Test is completed and cannot be changed"
+//	@Failure		400			{object}	TestTemperatureResponse	"Products tested outside their temperature range"
 //	@Failure		500			{string}	string				"Could not JSON encode the response."
 //	@Router			/tests/ [patch]
 //	@Router			/tests/{test_id}/products/{product_id} [patch]
@@ -264,15 +357,24 @@ func TestsRequestPATCH(w http.ResponseWriter, r *http.Request, db *sql.DB) {
 		log.Println("Detected a conflict for the current test, please refresh.")
 		return
 	}
-	newVersion := createTestUpdateQueries(w, r, db, testUpdateRequest,
-		existingTestVersion, testID, productID)
+
+	// Get the user making a change of the state, so the transition can be recorded.
+	var stateChange *domain.TestStateChange
+	if _, ok := testUpdateRequest.Updates["state"]; ok {
+		stateChange = newStateChange(testUpdateRequest.Updates, existingTest, middleware.GetUserID(w, r, db))
+	}
+
+	newVersion, warnings := createTestUpdateQueries(w, r, db, testUpdateRequest,
+		existingTestVersion, testID, productID, stateChange, existingTest.TestingTeam)
 
 	// Send a response if the test update was successful.
 	if !newVersion.IsZero() {
+		NotifyTestChanged(testID)
 		w.WriteHeader(http.StatusOK)
 		err = json.NewEncoder(w).Encode(map[string]interface{}{
-			"message": "Test updated successfully",
-			"version": newVersion,
+			"message":  "Test updated successfully",
+			"version":  newVersion,
+			"warnings": warnings,
 		})
 		if err != nil {
 			http.Error(w, "Could not JSON encode the response.", http.StatusInternalServerError)
@@ -281,3 +383,49 @@ func TestsRequestPATCH(w http.ResponseWriter, r *http.Request, db *sql.DB) {
 		}
 	}
 }
+
+// TestStateChangesRequestGET retrieves the state changes of a test.
+//
+//	@Summary		Get the state changes of a test
+//	@Description	Retrieves every change of the state of a test, with who made it and when, oldest first.
+//	@Tags			Tests
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			test_id	path		int						true	"Test ID"
+//	@Success		200		{array}		domain.TestStateChange	"Successful response with the state changes"
+//	@Failure		401		{string}	string					"Unauthorized"
+//	@Failure		404		{string}	string					"Could not retrieve the test."
+//	@Failure		500		{string}	string					"Could not retrieve the state changes."
+//	@Router			/tests/{test_id}/states [get]
+func TestStateChangesRequestGET(w http.ResponseWriter, r *http.Request, db *sql.DB) {
+	matches := stateChangesPath.FindStringSubmatch(r.URL.Path)
+	testID, _ := strconv.Atoi(matches[1])
+
+	// Get the user's team membership.
+	team := middleware.GetUserTeamRole(w, r, db)
+
+	test := GetTestWithID(w, db, testID)
+	if test.ID == 0 {
+		return
+	}
+	if !test.IsPublic && test.TestingTeam != team {
+		http.Error(w, resources.AuthenticationError, http.StatusUnauthorized)
+		log.Println("User cannot view the state changes of this test")
+		return
+	}
+
+	changes, err := getStateChanges(db, testID)
+	if err != nil {
+		http.Error(w, "Could not retrieve the state changes.", http.StatusInternalServerError)
+		log.Println("Could not retrieve the state changes: " + err.Error())
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(changes)
+	if err != nil {
+		http.Error(w, "Could not encode the state changes.", http.StatusInternalServerError)
+		log.Println("Could not encode the state changes: " + err.Error())
+		return
+	}
+}
@@ -0,0 +1,241 @@
+package testsHandler
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"time"
+)
+
+// mergePatch applies a JSON merge patch (RFC 7386) to the target and returns the result.
+func mergePatch(target map[string]interface{}, patch map[string]interface{}) map[string]interface{} {
+	if target == nil {
+		target = map[string]interface{}{}
+	}
+
+	for key, value := range patch {
+		if value == nil {
+			delete(target, key)
+			continue
+		}
+
+		patchObject, isObject := value.(map[string]interface{})
+		if !isObject {
+			target[key] = value
+			continue
+		}
+
+		targetObject, _ := target[key].(map[string]interface{})
+		target[key] = mergePatch(targetObject, patchObject)
+	}
+	return target
+}
+
+// decodeDraft decodes and validates a draft payload. Unknown fields are rejected so that they are never stored.
+func decodeDraft(payload []byte) (TestDraftRequest, error) {
+	var draft TestDraftRequest
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&draft); err != nil {
+		return TestDraftRequest{}, err
+	}
+
+	if err := validator.New().Struct(draft); err != nil {
+		return TestDraftRequest{}, err
+	}
+	return draft, nil
+}
+
+// missingDraftFields lists the fields that must be filled in before the draft can be promoted to a test.
+func missingDraftFields(draft TestDraftRequest) []string {
+	var missing []string
+
+	sc := draft.SnowConditions
+	if sc == nil {
+		sc = &SnowConditionsDraft{}
+	}
+	if sc.Temperature == nil {
+		missing = append(missing, "sc.temperature")
+	}
+	if sc.SnowType == nil {
+		missing = append(missing, "sc.snow_type")
+	}
+	if sc.SnowHumidity == nil {
+		missing = append(missing, "sc.snow_humidity")
+	}
+
+	ac := draft.AirConditions
+	if ac == nil {
+		ac = &AirConditionsDraft{}
+	}
+	if ac.Temperature == nil {
+		missing = append(missing, "ac.temperature")
+	}
+	if ac.Humidity == nil {
+		missing = append(missing, "ac.humidity")
+	}
+	if ac.Wind == nil {
+		missing = append(missing, "ac.wind")
+	}
+	if ac.Cloud == nil {
+		missing = append(missing, "ac.cloud")
+	}
+
+	tc := draft.TrackConditions
+	if tc == nil {
+		tc = &TrackConditionsDraft{}
+	}
+	if tc.TrackHardness == nil {
+		missing = append(missing, "tc.track_hardness")
+	}
+	if tc.TrackType == nil {
+		missing = append(missing, "tc.track_type")
+	}
+
+	if (draft.Location == nil || *draft.Location == "") && draft.LocationID == nil {
+		missing = append(missing, "location")
+	}
+	if draft.Date == nil {
+		missing = append(missing, "test_date")
+	}
+	if draft.Comment == nil || *draft.Comment == "" {
+		missing = append(missing, "comment")
+	}
+	if len(draft.TestRanks) == 0 {
+		missing = append(missing, "test_ranks")
+	}
+	return missing
+}
+
+func valueOrZero[T any](value *T) T {
+	var zero T
+	if value == nil {
+		return zero
+	}
+	return *value
+}
+
+// draftToTestPOSTRequest converts a complete draft into the request used to create a test.
+func draftToTestPOSTRequest(draft TestDraftRequest) TestPOSTRequest {
+	var test TestPOSTRequest
+
+	if sc := draft.SnowConditions; sc != nil {
+		test.SnowConditions = SnowConditionsPOST{
+			Temperature:  valueOrZero(sc.Temperature),
+			SnowType:     valueOrZero(sc.SnowType),
+			SnowHumidity: valueOrZero(sc.SnowHumidity),
+		}
+	}
+	if ac := draft.AirConditions; ac != nil {
+		test.AirConditions = AirConditionsPOST{
+			Temperature: valueOrZero(ac.Temperature),
+			Humidity:    valueOrZero(ac.Humidity),
+			Wind:        valueOrZero(ac.Wind),
+			Cloud:       valueOrZero(ac.Cloud),
+		}
+	}
+	if tc := draft.TrackConditions; tc != nil {
+		test.TrackConditions = TrackConditionsPOST{
+			TrackHardness: valueOrZero(tc.TrackHardness),
+			TrackType:     valueOrZero(tc.TrackType),
+		}
+	}
+
+	test.Location = valueOrZero(draft.Location)
+	test.LocationID = draft.LocationID
+	test.TrackID = draft.TrackID
+	test.Date = valueOrZero(draft.Date)
+	test.Comment = valueOrZero(draft.Comment)
+	test.IsPublic = valueOrZero(draft.IsPublic)
+	test.TestRanks = draft.TestRanks
+	return test
+}
+
+// scanDraft scans a test_drafts row into the draft response.
+func scanDraft(row interface{ Scan(...any) error }) (TestDraftResponse, error) {
+	var response TestDraftResponse
+	var createdBy sql.NullInt64
+	var payload []byte
+
+	err := row.Scan(
+		&response.ID,
+		&response.TestingTeam,
+		&createdBy,
+		&payload,
+		&response.CreatedAt,
+		&response.Version)
+	if err != nil {
+		return TestDraftResponse{}, err
+	}
+
+	if createdBy.Valid {
+		userID := int(createdBy.Int64)
+		response.CreatedBy = &userID
+	}
+
+	if err = json.Unmarshal(payload, &response.Draft); err != nil {
+		return TestDraftResponse{}, fmt.Errorf("could not decode the draft payload: %w", err)
+	}
+	return response, nil
+}
+
+// getDraftWithID retrieves a draft with a specific ID from the database.
+func getDraftWithID(db *sql.DB, draftID int) (TestDraftResponse, error) {
+	return scanDraft(db.QueryRow(`SELECT id, testing_team, created_by, payload, created_at, version
+										FROM test_drafts WHERE id = $1;`, draftID))
+}
+
+// getDraftsForTeam retrieves all the drafts of a team, with the most recently edited draft first.
+func getDraftsForTeam(db *sql.DB, team int) ([]TestDraftResponse, error) {
+	rows, err := db.Query(`SELECT id, testing_team, created_by, payload, created_at, version
+								FROM test_drafts WHERE testing_team = $1 ORDER BY version DESC;`, team)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	drafts := []TestDraftResponse{}
+	for rows.Next() {
+		draft, err := scanDraft(rows)
+		if err != nil {
+			return nil, err
+		}
+		drafts = append(drafts, draft)
+	}
+	return drafts, rows.Err()
+}
+
+// insertDraft inserts a new draft and returns it as stored.
+func insertDraft(db *sql.DB, draft TestDraftRequest, team int, userID int) (TestDraftResponse, error) {
+	payload, err := json.Marshal(draft)
+	if err != nil {
+		return TestDraftResponse{}, err
+	}
+
+	var createdBy interface{}
+	if userID != 0 {
+		createdBy = userID
+	}
+
+	now := time.Now()
+	return scanDraft(db.QueryRow(`INSERT INTO test_drafts (testing_team, created_by, payload, created_at, version)
+										VALUES ($1, $2, $3, $4, $5)
+										RETURNING id, testing_team, created_by, payload, created_at, version;`,
+		team, createdBy, payload, now, now))
+}
+
+// updateDraftPayload stores the new payload of a draft, if the draft has not been changed since the given version.
+func updateDraftPayload(db *sql.DB, draftID int, draft TestDraftRequest, version time.Time) (time.Time, error) {
+	payload, err := json.Marshal(draft)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	var newVersion time.Time
+	err = db.QueryRow(`UPDATE test_drafts SET payload = $1, version = $2
+							WHERE id = $3 AND version = $4 RETURNING version;`,
+		payload, time.Now(), draftID, version).Scan(&newVersion)
+	return newVersion, err
+}
@@ -0,0 +1,51 @@
+package testsHandler
+
+import "time"
+
+// TestDraftRequest is a partially filled test. Every field is optional until the draft is promoted to a test.
+type TestDraftRequest struct {
+	SnowConditions  *SnowConditionsDraft  `json:"sc,omitempty"`
+	AirConditions   *AirConditionsDraft   `json:"ac,omitempty"`
+	TrackConditions *TrackConditionsDraft `json:"tc,omitempty"`
+	Location        *string               `json:"location,omitempty" validate:"omitempty,lte=256,ascii"`
+	LocationID      *int                  `json:"location_id,omitempty" validate:"omitempty,gt=0"`
+	TrackID         *int                  `json:"track_id,omitempty" validate:"omitempty,gt=0"`
+	Date            *time.Time            `json:"test_date,omitempty" validate:"omitempty"`
+	Comment         *string               `json:"comment,omitempty" validate:"omitempty,max=2040"`
+	IsPublic        *bool                 `json:"is_public,omitempty" validate:"omitempty"`
+	TestRanks       []TestRanksPOST       `json:"test_ranks,omitempty" validate:"omitempty,dive"`
+}
+
+type SnowConditionsDraft struct {
+	Temperature  *float32 `json:"temperature,omitempty" validate:"omitempty,lte=100,gte=-100"`
+	SnowType     *string  `json:"snow_type,omitempty" validate:"omitempty,oneof=A1 A2 A3 A4 A5 FS NS IN IT TR"`
+	SnowHumidity *string  `json:"snow_humidity,omitempty" validate:"omitempty,oneof=DS W1 W2 W3 W4"`
+}
+
+type AirConditionsDraft struct {
+	Temperature *float32 `json:"temperature,omitempty" validate:"omitempty,lte=100,gte=-100"`
+	Humidity    *int     `json:"humidity,omitempty" validate:"omitempty,lte=100,gte=0"`
+	Wind        *string  `json:"wind,omitempty" validate:"omitempty,oneof=S L M ST"`
+	Cloud       *string  `json:"cloud,omitempty" validate:"omitempty,oneof=1 2 3 4"`
+}
+
+type TrackConditionsDraft struct {
+	TrackHardness *string `json:"track_hardness,omitempty" validate:"omitempty,oneof=H1 H2 H3 H4 H5 H6"`
+	TrackType     *string `json:"track_type,omitempty" validate:"omitempty,oneof=T1 T2 D1 D2"`
+}
+
+// TestDraftPATCHRequest is a JSON merge patch (RFC 7386) for a draft. Fields set to null are removed from the draft,
+// nested objects are merged and arrays such as the test ranks are replaced.
+type TestDraftPATCHRequest struct {
+	Updates map[string]interface{} `json:"updates" validate:"required"`
+	Version time.Time              `json:"version"`
+}
+
+type TestDraftResponse struct {
+	ID          int              `json:"id"`
+	TestingTeam int              `json:"testing_team"`
+	CreatedBy   *int             `json:"created_by"`
+	Draft       TestDraftRequest `json:"draft"`
+	CreatedAt   time.Time        `json:"created_at"`
+	Version     time.Time        `json:"version"`
+}
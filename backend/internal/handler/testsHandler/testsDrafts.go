@@ -0,0 +1,468 @@
+package testsHandler
+
+import (
+	"backend/internal/middleware"
+	"backend/internal/resources"
+	"backend/internal/utils"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"github.com/go-playground/validator/v10"
+	"log"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var draftsPath = regexp.MustCompile(`^/tests/drafts/?$`)
+var draftPath = regexp.MustCompile(`^/tests/drafts/(\d+)$`)
+var promoteDraftPath = regexp.MustCompile(`^/tests/drafts/(\d+)/promote$`)
+
+// isDraftsPath reports whether the request is for the drafts of the tests.
+func isDraftsPath(path string) bool {
+	return path == "/tests/drafts" || strings.HasPrefix(path, "/tests/drafts/")
+}
+
+// DraftsHandler routes HTTP requests for test drafts to the appropriate handler function.
+//
+// It supports the following methods:
+// - GET: Retrieves a draft, or all the drafts of the user's team.
+// - POST: Creates a new draft, or promotes a draft to a test.
+// - PATCH: Updates a draft with a JSON merge patch.
+// - DELETE: Deletes a draft.
+//
+// Each method has its own dedicated request handler function, which is documented separately using Swagger annotations.
+func DraftsHandler(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		switch r.Method {
+		case http.MethodGet:
+			DraftsRequestGET(w, r, db)
+		case http.MethodPost:
+			if promoteDraftPath.MatchString(r.URL.Path) {
+				DraftsRequestPromote(w, r, db)
+				return
+			}
+			DraftsRequestPOST(w, r, db)
+		case http.MethodPatch:
+			DraftsRequestPATCH(w, r, db)
+		case http.MethodDelete:
+			DraftsRequestDELETE(w, r, db)
+		default:
+			http.Error(w, resources.MethodNotAllowed, http.StatusNotImplemented)
+			return
+		}
+	}
+}
+
+// getDraftIDFromPath retrieves the draft ID from a '/tests/drafts/{draft_id}' URL.
+func getDraftIDFromPath(w http.ResponseWriter, r *http.Request) (int, bool) {
+	matches := draftPath.FindStringSubmatch(r.URL.Path)
+	if len(matches) != 2 {
+		http.Error(w, "Invalid request URL, use '/tests/drafts/{draft_id}'.", http.StatusBadRequest)
+		log.Println("Invalid request URL: " + r.URL.Path)
+		return 0, false
+	}
+	draftID, _ := strconv.Atoi(matches[1])
+	return draftID, true
+}
+
+// getTeamDraft retrieves a draft and checks that it belongs to the team, writing an error response if it does not.
+func getTeamDraft(w http.ResponseWriter, db *sql.DB, draftID int, team int) (TestDraftResponse, bool) {
+	draft, err := getDraftWithID(db, draftID)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Could not find the draft.", http.StatusNotFound)
+		log.Println("Could not find the draft: " + strconv.Itoa(draftID))
+		return TestDraftResponse{}, false
+	} else if err != nil {
+		http.Error(w, "Could not retrieve the draft.", http.StatusInternalServerError)
+		log.Println("Could not retrieve the draft: " + err.Error())
+		return TestDraftResponse{}, false
+	}
+
+	if draft.TestingTeam != team {
+		http.Error(w, resources.AuthenticationError, http.StatusUnauthorized)
+		log.Println("User cannot access this draft")
+		return TestDraftResponse{}, false
+	}
+	return draft, true
+}
+
+// DraftsRequestGET handles GET requests for test drafts.
+//
+//	@Summary		Get test drafts
+//	@Description	Retrieves a draft, or all the drafts of the user's team with the most recently edited first.
+//	@Tags			Tests
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			draft_id	path		int					false	"Draft ID"
+//	@Success		200			{array}		TestDraftResponse	"Successful response with the drafts"
+//	@Failure		401			{string}	string				"Unauthorized"
+//	@Failure		404			{string}	string				"Could not find the draft."
+//	@Failure		500			{string}	string				"Could not retrieve the drafts."
+//	@Router			/tests/drafts [get]
+//	@Router			/tests/drafts/{draft_id} [get]
+func DraftsRequestGET(w http.ResponseWriter, r *http.Request, db *sql.DB) {
+	// Get the user's team membership.
+	team := middleware.GetUserTeamRole(w, r, db)
+
+	if draftsPath.MatchString(r.URL.Path) {
+		drafts, err := getDraftsForTeam(db, team)
+		if err != nil {
+			http.Error(w, "Could not retrieve the drafts.", http.StatusInternalServerError)
+			log.Println("Could not retrieve the drafts: " + err.Error())
+			return
+		}
+
+		err = json.NewEncoder(w).Encode(drafts)
+		if err != nil {
+			http.Error(w, "Could not encode the drafts.", http.StatusInternalServerError)
+			log.Println("Could not encode the drafts: " + err.Error())
+		}
+		return
+	}
+
+	draftID, ok := getDraftIDFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	draft, ok := getTeamDraft(w, db, draftID, team)
+	if !ok {
+		return
+	}
+
+	err := json.NewEncoder(w).Encode(draft)
+	if err != nil {
+		http.Error(w, "Could not encode the draft.", http.StatusInternalServerError)
+		log.Println("Could not encode the draft: " + err.Error())
+		return
+	}
+}
+
+// DraftsRequestPOST is the request handler for creating a new test draft.
+//
+//	@Summary		Create a new test draft
+//	@Description	Stores a partially filled test for the user's team. All fields are optional.
+//	@Tags			Tests
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			draft	body		TestDraftRequest	true	"Draft information"
+//	@Success		201		{object}	TestDraftResponse	"Draft created successfully"
+//	@Failure		400		{string}	string				"Invalid POST request body"
+//	@Failure		500		{string}	string				"Could not create the draft."
+//	@Router			/tests/drafts [post]
+func DraftsRequestPOST(w http.ResponseWriter, r *http.Request, db *sql.DB) {
+	if !draftsPath.MatchString(r.URL.Path) {
+		http.Error(w, "Invalid request URL, use '/tests/drafts'.", http.StatusBadRequest)
+		log.Println("Invalid request URL: " + r.URL.Path)
+		return
+	}
+
+	// Get the user and the user's team membership.
+	userID := middleware.GetUserID(w, r, db)
+	team := middleware.GetUserTeamRole(w, r, db)
+
+	var body json.RawMessage
+	err := utils.DecodeRequestBody(w, r, &body)
+	if err != nil {
+		return
+	}
+
+	draft, err := decodeDraft(body)
+	if err != nil {
+		http.Error(w, resources.InvalidPOSTRequest, http.StatusBadRequest)
+		log.Println(resources.InvalidPOSTRequest + ": " + err.Error())
+		return
+	}
+
+	created, err := insertDraft(db, draft, team, userID)
+	if err != nil {
+		http.Error(w, "Could not create the draft.", http.StatusInternalServerError)
+		log.Println("Could not create the draft: " + err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	err = json.NewEncoder(w).Encode(created)
+	if err != nil {
+		log.Println("Could not encode the draft: " + err.Error())
+		return
+	}
+}
+
+// DraftsRequestPATCH is the request handler for updating a test draft.
+//
+//	@Summary		Update a test draft
+//	@Description	Applies a JSON merge patch to a draft. Fields set to null are removed from the draft,
+//	@Description	nested objects are merged and arrays are replaced.
+//	@Tags			Tests
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			draft_id	path		int						true	"Draft ID"
+//	@Param			draft		body		TestDraftPATCHRequest	true	"Draft updates"
+//	@Success		200			{string}	string					"Draft updated successfully"
+//	@Failure		400			{string}	string					"Invalid PATCH request body"
+//	@Failure		401			{string}	string					"Unauthorized"
+//	@Failure		404			{string}	string					"Could not find the draft."
+//	@Failure		409			{string}	string					"Detected a conflict for the current draft, please refresh."
+//	@Failure		500			{string}	string					"Could not update the draft."
+//	@Router			/tests/drafts/{draft_id} [patch]
+func DraftsRequestPATCH(w http.ResponseWriter, r *http.Request, db *sql.DB) {
+	draftID, ok := getDraftIDFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	// Get the user's team membership.
+	team := middleware.GetUserTeamRole(w, r, db)
+
+	request, err := utils.ParseAndValidateRequest[TestDraftPATCHRequest](r)
+	if err != nil {
+		http.Error(w, resources.InvalidPATCHRequest, http.StatusBadRequest)
+		log.Println(resources.InvalidPATCHRequest + ": " + err.Error())
+		return
+	}
+
+	existing, ok := getTeamDraft(w, db, draftID, team)
+	if !ok {
+		return
+	}
+
+	// Merge the updates into the stored draft, and validate the result as a whole.
+	var target map[string]interface{}
+	payload, _ := json.Marshal(existing.Draft)
+	_ = json.Unmarshal(payload, &target)
+
+	payload, err = json.Marshal(mergePatch(target, request.Updates))
+	if err != nil {
+		http.Error(w, resources.InvalidPATCHRequest, http.StatusBadRequest)
+		log.Println(resources.InvalidPATCHRequest + ": " + err.Error())
+		return
+	}
+
+	draft, err := decodeDraft(payload)
+	if err != nil {
+		http.Error(w, resources.InvalidPATCHRequest, http.StatusBadRequest)
+		log.Println(resources.InvalidPATCHRequest + ": " + err.Error())
+		return
+	}
+
+	newVersion, err := updateDraftPayload(db, draftID, draft, request.Version)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Detected a conflict for the current draft, please refresh.", http.StatusConflict)
+		log.Println("Detected a conflict for the current draft, please refresh.")
+		return
+	} else if err != nil {
+		http.Error(w, "Could not update the draft.", http.StatusInternalServerError)
+		log.Println("Could not update the draft: " + err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Draft updated successfully",
+		"version": newVersion,
+	})
+	if err != nil {
+		log.Println("Could not JSON encode the response: " + err.Error())
+		return
+	}
+}
+
+// DraftsRequestDELETE is the request handler for deleting a test draft.
+//
+//	@Summary		Delete a test draft
+//	@Description	Deletes a draft of the user's team.
+//	@Tags			Tests
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			draft_id	path		int		true	"Draft ID"
+//	@Success		204			"Draft deleted successfully"
+//	@Failure		401			{string}	string	"Unauthorized"
+//	@Failure		404			{string}	string	"Could not find the draft."
+//	@Failure		500			{string}	string	"Could not delete the draft."
+//	@Router			/tests/drafts/{draft_id} [delete]
+func DraftsRequestDELETE(w http.ResponseWriter, r *http.Request, db *sql.DB) {
+	draftID, ok := getDraftIDFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	// Get the user's team membership.
+	team := middleware.GetUserTeamRole(w, r, db)
+
+	result, err := db.Exec("DELETE FROM test_drafts WHERE id = $1 AND testing_team = $2;", draftID, team)
+	if err != nil {
+		http.Error(w, "Could not delete the draft.", http.StatusInternalServerError)
+		log.Println("Could not delete the draft: " + err.Error())
+		return
+	}
+
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		http.Error(w, "Could not find the draft.", http.StatusNotFound)
+		log.Println("Could not find the draft: " + strconv.Itoa(draftID))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DraftsRequestPromote is the request handler for turning a draft into a test.
+//
+//	@Summary		Promote a test draft
+//	@Description	Validates a draft as a new test, creates the test with its conditions and ranks,
+//	@Description	and deletes the draft, all in one transaction.
+//	@Tags			Tests
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			draft_id	path		int		true	"Draft ID"
+//	@Success		201			{string}	string	"Draft promoted successfully"
+//	@Failure		400			{string}	string	"The draft is incomplete."
+//	@Failure		400			{object}	TestTemperatureResponse	"Products tested outside their temperature range"
+//	@Failure		401			{string}	string	"Unauthorized"
+//	@Failure		404			{string}	string	"Could not find the draft."
+//	@Failure		500			{string}	string	"Failed to create test."
+//	@Router			/tests/drafts/{draft_id}/promote [post]
+func DraftsRequestPromote(w http.ResponseWriter, r *http.Request, db *sql.DB) {
+	matches := promoteDraftPath.FindStringSubmatch(r.URL.Path)
+	if len(matches) != 2 {
+		http.Error(w, "Invalid request URL, use '/tests/drafts/{draft_id}/promote'.", http.StatusBadRequest)
+		log.Println("Invalid request URL: " + r.URL.Path)
+		return
+	}
+	draftID, _ := strconv.Atoi(matches[1])
+
+	// Get the user's team membership.
+	team := middleware.GetUserTeamRole(w, r, db)
+	userID := middleware.GetUserID(w, r, db)
+
+	tx, err := db.Begin()
+	if err != nil {
+		http.Error(w, resources.TransactionStartFailed, http.StatusInternalServerError)
+		return
+	}
+	defer func() {
+		if err != nil {
+			rollbackErr := tx.Rollback()
+			if rollbackErr != nil {
+				log.Println(resources.RollbackFailed + rollbackErr.Error())
+			}
+		}
+	}()
+
+	// Lock the draft, so it can only be promoted once.
+	var payload []byte
+	var testingTeam int
+	err = tx.QueryRow("SELECT payload, testing_team FROM test_drafts WHERE id = $1 FOR UPDATE;", draftID).
+		Scan(&payload, &testingTeam)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Could not find the draft.", http.StatusNotFound)
+		log.Println("Could not find the draft: " + matches[1])
+		return
+	} else if err != nil {
+		http.Error(w, "Could not retrieve the draft.", http.StatusInternalServerError)
+		log.Println("Could not retrieve the draft: " + err.Error())
+		return
+	}
+
+	if testingTeam != team {
+		err = errors.New("draft belongs to another team")
+		http.Error(w, resources.AuthenticationError, http.StatusUnauthorized)
+		log.Println("User cannot promote this draft")
+		return
+	}
+
+	draft, err := decodeDraft(payload)
+	if err != nil {
+		http.Error(w, "Could not decode the draft.", http.StatusInternalServerError)
+		log.Println("Could not decode the draft: " + err.Error())
+		return
+	}
+
+	if missing := missingDraftFields(draft); len(missing) > 0 {
+		err = errors.New("draft is incomplete")
+		http.Error(w, "The draft is incomplete, missing: "+strings.Join(missing, ", "), http.StatusBadRequest)
+		log.Println("The draft is incomplete, missing: " + strings.Join(missing, ", "))
+		return
+	}
+
+	// Validate the draft with the same rules as a new test.
+	test := draftToTestPOSTRequest(draft)
+	if err = validator.New().Struct(test); err != nil {
+		http.Error(w, resources.InvalidPOSTRequest, http.StatusBadRequest)
+		log.Println(resources.InvalidPOSTRequest + ": " + err.Error())
+		return
+	}
+
+	if err = validatePermissions(test, team); err != nil {
+		http.Error(w, "Researcher cannot create public tests", http.StatusBadRequest)
+		log.Println("Researcher cannot create public tests")
+		return
+	}
+
+	var code int
+	if err, code = validateTestLocation(tx, &test); err != nil {
+		http.Error(w, "Validation error: "+err.Error(), code)
+		log.Println("Validation error: " + err.Error())
+		return
+	}
+	if err, code = validateTestSkis(tx, test.TestRanks, team); err != nil {
+		http.Error(w, "Validation error: "+err.Error(), code)
+		log.Println("Validation error: " + err.Error())
+		return
+	}
+
+	// Create test with all relates entities
+	testID, err := createTest(tx, test, team, userID)
+	if err != nil {
+		http.Error(w, "Failed to create test: "+err.Error(), http.StatusInternalServerError)
+		log.Println("Failed to create test: " + err.Error())
+		return
+	}
+
+	// Create rankings
+	warnings, err := createTestRankings(tx, testID, temperaturesOf(test), test.TestRanks)
+	if err != nil {
+		http.Error(w, "Failed to create rankings: "+err.Error(), http.StatusInternalServerError)
+		log.Println("Failed to create rankings: " + err.Error())
+		return
+	}
+
+	// Refuse products outside their temperature range if the team does not allow them.
+	if err = RefuseOutOfTemperatureRange(w, tx, team, warnings); err != nil {
+		return
+	}
+
+	_, err = tx.Exec("DELETE FROM test_drafts WHERE id = $1;", draftID)
+	if err != nil {
+		http.Error(w, "Could not delete the draft.", http.StatusInternalServerError)
+		log.Println("Could not delete the draft: " + err.Error())
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		http.Error(w, resources.TransactionCommitFailed, http.StatusInternalServerError)
+		log.Println(resources.TransactionCommitFailed + ": " + err.Error())
+		return
+	}
+
+	NotifyTestChanged(testID)
+	w.WriteHeader(http.StatusCreated)
+	err = json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":  "Draft promoted successfully",
+		"test_id":  testID,
+		"warnings": warnings,
+	})
+	if err != nil {
+		log.Println("Could not JSON encode the response: " + err.Error())
+		return
+	}
+}
@@ -0,0 +1,328 @@
+package testsHandler
+
+import (
+	"backend/internal/utils"
+	"database/sql"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+var draftColumns = []string{"id", "testing_team", "created_by", "payload", "created_at", "version"}
+
+const completeDraft = `{"sc":{"temperature":-10,"snow_type":"FS","snow_humidity":"W2"},"ac":{"temperature":-15,"humidity":30,"wind":"L","cloud":"1"},"tc":{"track_hardness":"H1","track_type":"D1"},"location":"Holmenkollen, Oslo","test_date":"2025-03-30T10:30:00Z","comment":"Excellent glide.","is_public":false,"test_ranks":[{"product_id":1,"rank":1,"distance_behind":0}]}`
+
+func Test_mergePatch(t *testing.T) {
+	target := map[string]interface{}{
+		"location": "Holmenkollen",
+		"comment":  "Windy",
+		"sc":       map[string]interface{}{"temperature": -10.0, "snow_type": "FS"},
+		"test_ranks": []interface{}{
+			map[string]interface{}{"product_id": 1.0},
+		},
+	}
+	patch := map[string]interface{}{
+		"comment":    nil,
+		"sc":         map[string]interface{}{"snow_type": "NS", "snow_humidity": "W1"},
+		"tc":         map[string]interface{}{"track_type": "T1"},
+		"test_ranks": []interface{}{},
+	}
+
+	assert.Equal(t, map[string]interface{}{
+		"location":   "Holmenkollen",
+		"sc":         map[string]interface{}{"temperature": -10.0, "snow_type": "NS", "snow_humidity": "W1"},
+		"tc":         map[string]interface{}{"track_type": "T1"},
+		"test_ranks": []interface{}{},
+	}, mergePatch(target, patch))
+}
+
+func Test_decodeDraft(t *testing.T) {
+	_, err := decodeDraft([]byte(`{"location":"Holmenkollen"}`))
+	assert.NoError(t, err)
+
+	_, err = decodeDraft([]byte(`{"sc":{"snow_type":"XX"}}`))
+	assert.Error(t, err, "invalid snow type")
+
+	_, err = decodeDraft([]byte(`{"unknown":true}`))
+	assert.Error(t, err, "unknown field")
+}
+
+func Test_missingDraftFields(t *testing.T) {
+	draft, err := decodeDraft([]byte(`{"sc":{"temperature":0,"snow_type":"FS"},"location":"Holmenkollen"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"sc.snow_humidity", "ac.temperature", "ac.humidity", "ac.wind", "ac.cloud",
+		"tc.track_hardness", "tc.track_type", "test_date", "comment", "test_ranks",
+	}, missingDraftFields(draft))
+
+	draft, err = decodeDraft([]byte(completeDraft))
+	assert.NoError(t, err)
+	assert.Empty(t, missingDraftFields(draft))
+
+	test := draftToTestPOSTRequest(draft)
+	assert.Equal(t, float32(-10), test.SnowConditions.Temperature)
+	assert.Equal(t, "D1", test.TrackConditions.TrackType)
+	assert.Equal(t, "Holmenkollen, Oslo", test.Location)
+	assert.Equal(t, time.Date(2025, 3, 30, 10, 30, 0, 0, time.UTC), test.Date)
+	assert.Len(t, test.TestRanks, 1)
+}
+
+func TestDraftsHandler(t *testing.T) {
+	mockDB, mock := utils.InitMockDB(t)
+	version := time.Date(2025, 3, 30, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		method       string
+		path         string
+		body         string
+		setupMocks   func()
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:         "Method = GET (Status OK - team drafts)",
+			method:       http.MethodGet,
+			path:         "/tests/drafts",
+			expectedCode: http.StatusOK,
+			expectedBody: `"location":"Holmenkollen"`,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+
+				mock.ExpectQuery("SELECT id, testing_team, created_by, payload, created_at, version").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows(draftColumns).
+						AddRow(1, 1, 1, []byte(`{"location":"Holmenkollen"}`), version, version))
+			},
+		},
+		{
+			name:         "Method = GET (Status unauthorized - draft of another team)",
+			method:       http.MethodGet,
+			path:         "/tests/drafts/1",
+			expectedCode: http.StatusUnauthorized,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+
+				mock.ExpectQuery("SELECT id, testing_team, created_by, payload, created_at, version").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows(draftColumns).
+						AddRow(1, 2, nil, []byte(`{}`), version, version))
+			},
+		},
+		{
+			name:         "Method = POST (Status created)",
+			method:       http.MethodPost,
+			path:         "/tests/drafts",
+			body:         `{"sc":{"temperature":-10},"location":"Holmenkollen"}`,
+			expectedCode: http.StatusCreated,
+			expectedBody: `"sc":{"temperature":-10}`,
+			setupMocks: func() {
+				mock.ExpectQuery("SELECT user_id FROM sessions").
+					WithArgs("mockToken").
+					WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(1))
+				AuthenticationMock(mock)
+
+				mock.ExpectQuery("INSERT INTO test_drafts").
+					WithArgs(1, 1, []byte(`{"sc":{"temperature":-10},"location":"Holmenkollen"}`),
+						sqlmock.AnyArg(), sqlmock.AnyArg()).
+					WillReturnRows(sqlmock.NewRows(draftColumns).
+						AddRow(1, 1, 1, []byte(`{"sc":{"temperature":-10},"location":"Holmenkollen"}`),
+							version, version))
+			},
+		},
+		{
+			name:         "Method = POST (Status bad request - invalid value)",
+			method:       http.MethodPost,
+			path:         "/tests/drafts",
+			body:         `{"sc":{"snow_type":"XX"}}`,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Invalid POST request body",
+			setupMocks: func() {
+				mock.ExpectQuery("SELECT user_id FROM sessions").
+					WithArgs("mockToken").
+					WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(1))
+				AuthenticationMock(mock)
+			},
+		},
+		{
+			name:         "Method = PATCH (Status OK - merged with the stored draft)",
+			method:       http.MethodPatch,
+			path:         "/tests/drafts/1",
+			body:         `{"updates":{"sc":{"snow_type":"FS"},"comment":null},"version":"2025-03-30T10:30:00Z"}`,
+			expectedCode: http.StatusOK,
+			expectedBody: "Draft updated successfully",
+			setupMocks: func() {
+				AuthenticationMock(mock)
+
+				mock.ExpectQuery("SELECT id, testing_team, created_by, payload, created_at, version").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows(draftColumns).
+						AddRow(1, 1, 1, []byte(`{"sc":{"temperature":-10},"comment":"Windy"}`), version, version))
+
+				mock.ExpectQuery("UPDATE test_drafts SET payload = \\$1, version = \\$2").
+					WithArgs([]byte(`{"sc":{"temperature":-10,"snow_type":"FS"}}`), sqlmock.AnyArg(), 1, version).
+					WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(time.Now()))
+			},
+		},
+		{
+			name:         "Method = PATCH (Status conflict - draft has changed)",
+			method:       http.MethodPatch,
+			path:         "/tests/drafts/1",
+			body:         `{"updates":{"location":"Lillehammer"},"version":"2025-03-30T10:30:00Z"}`,
+			expectedCode: http.StatusConflict,
+			expectedBody: "Detected a conflict for the current draft, please refresh.",
+			setupMocks: func() {
+				AuthenticationMock(mock)
+
+				mock.ExpectQuery("SELECT id, testing_team, created_by, payload, created_at, version").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows(draftColumns).
+						AddRow(1, 1, 1, []byte(`{}`), version, version))
+
+				mock.ExpectQuery("UPDATE test_drafts SET payload = \\$1, version = \\$2").
+					WillReturnError(sql.ErrNoRows)
+			},
+		},
+		{
+			name:         "Method = PATCH (Status bad request - invalid merged draft)",
+			method:       http.MethodPatch,
+			path:         "/tests/drafts/1",
+			body:         `{"updates":{"tc":{"track_type":"XX"}},"version":"2025-03-30T10:30:00Z"}`,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Invalid PATCH request body",
+			setupMocks: func() {
+				AuthenticationMock(mock)
+
+				mock.ExpectQuery("SELECT id, testing_team, created_by, payload, created_at, version").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows(draftColumns).
+						AddRow(1, 1, 1, []byte(`{}`), version, version))
+			},
+		},
+		{
+			name:         "Method = DELETE (Status no content)",
+			method:       http.MethodDelete,
+			path:         "/tests/drafts/1",
+			expectedCode: http.StatusNoContent,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+
+				mock.ExpectExec("DELETE FROM test_drafts WHERE id = \\$1 AND testing_team = \\$2;").
+					WithArgs(1, 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name:         "Method = DELETE (Status not found)",
+			method:       http.MethodDelete,
+			path:         "/tests/drafts/2",
+			expectedCode: http.StatusNotFound,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+
+				mock.ExpectExec("DELETE FROM test_drafts WHERE id = \\$1 AND testing_team = \\$2;").
+					WithArgs(2, 1).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+		{
+			name:         "Method = POST promote (Status bad request - incomplete draft)",
+			method:       http.MethodPost,
+			path:         "/tests/drafts/1/promote",
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "The draft is incomplete, missing: sc.temperature",
+			setupMocks: func() {
+				AuthenticationMock(mock)
+
+				UserIDMock(mock)
+				mock.ExpectBegin()
+
+				mock.ExpectQuery("SELECT payload, testing_team FROM test_drafts WHERE id = \\$1 FOR UPDATE;").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"payload", "testing_team"}).
+						AddRow([]byte(`{"location":"Holmenkollen"}`), 1))
+
+				mock.ExpectRollback()
+			},
+		},
+		{
+			name:         "Method = POST promote (Status created)",
+			method:       http.MethodPost,
+			path:         "/tests/drafts/1/promote",
+			expectedCode: http.StatusCreated,
+			expectedBody: `"test_id":5`,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+
+				UserIDMock(mock)
+				mock.ExpectBegin()
+
+				mock.ExpectQuery("SELECT payload, testing_team FROM test_drafts WHERE id = \\$1 FOR UPDATE;").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"payload", "testing_team"}).
+						AddRow([]byte(completeDraft), 1))
+
+				mock.ExpectQuery("INSERT INTO snow_conditions").
+					WithArgs(-10.0, "FS", "W2").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+				mock.ExpectQuery("INSERT INTO air_conditions").
+					WithArgs(-15.0, 30, "L", "1").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+
+				mock.ExpectQuery("INSERT INTO track_conditions").
+					WithArgs("H1", "D1").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+
+				mock.ExpectQuery("INSERT INTO tests").
+					WithArgs(version, "Holmenkollen, Oslo", "Excellent glide.", 1, 3, 2,
+						sqlmock.AnyArg(), false, 1, nil, nil, "in_progress").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+
+				mock.ExpectQuery("SELECT type, low_temperature, high_temperature FROM products WHERE id = \\$1;").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"type", "low_temperature", "high_temperature"}).AddRow("solid", nil, nil))
+
+				mock.ExpectQuery("SELECT is_public FROM products WHERE id = \\$1").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"is_public"}).AddRow(true))
+
+				mock.ExpectExec("INSERT INTO test_ranks").
+					WithArgs(5, 1, 1, 0, sqlmock.AnyArg(), true, nil, false).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectExec("DELETE FROM test_drafts WHERE id = \\$1;").
+					WithArgs(1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectCommit()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer mockToken")
+			rr := httptest.NewRecorder()
+
+			TestsHandler(mockDB).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedCode, rr.Code)
+			if tt.expectedBody != "" {
+				assert.Contains(t, rr.Body.String(), tt.expectedBody)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package testsHandler
+
+import (
+	"backend/internal/middleware"
+	"backend/internal/services/xlsx"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"regexp"
+)
+
+var exportPath = regexp.MustCompile(`^/tests/export/?$`)
+
+// exportWriter writes the rows of an export in one of the export formats.
+type exportWriter interface {
+	Write(row TestExportRow) error
+	Close() error
+}
+
+type csvExport struct{ writer *csv.Writer }
+
+func (e csvExport) Write(row TestExportRow) error {
+	values := row.SpreadsheetValues()
+	record := make([]string, len(values))
+	for i, value := range values {
+		record[i] = formatCSVValue(value)
+	}
+	return e.writer.Write(record)
+}
+
+func (e csvExport) Close() error {
+	e.writer.Flush()
+	return e.writer.Error()
+}
+
+type jsonlExport struct{ encoder *json.Encoder }
+
+func (e jsonlExport) Write(row TestExportRow) error {
+	return e.encoder.Encode(row)
+}
+
+func (e jsonlExport) Close() error {
+	return nil
+}
+
+type xlsxExport struct{ writer *xlsx.Writer }
+
+func (e xlsxExport) Write(row TestExportRow) error {
+	return e.writer.WriteRow(row.SpreadsheetValues()...)
+}
+
+func (e xlsxExport) Close() error {
+	return e.writer.Close()
+}
+
+// newExportWriter starts an export in the format, writing the header row for CSV and XLSX.
+func newExportWriter(format string, w io.Writer) (exportWriter, error) {
+	header := make([]any, len(exportColumns))
+	for i, column := range exportColumns {
+		header[i] = column
+	}
+
+	switch format {
+	case "jsonl":
+		return jsonlExport{json.NewEncoder(w)}, nil
+	case "xlsx":
+		writer, err := xlsx.NewWriter(w, "Tests")
+		if err != nil {
+			return nil, err
+		}
+		return xlsxExport{writer}, writer.WriteRow(header...)
+	default:
+		writer := csv.NewWriter(w)
+		return csvExport{writer}, writer.Write(exportColumns)
+	}
+}
+
+// TestsExportRequestGET handles GET requests for exporting tests.
+//
+//	@Summary		Export tests with their conditions and results
+//	@Description	Exports the tests with one row for each product result, together with the test and its snow, air
+//	@Description	and track conditions. A test without results is exported as a single row without a product. Only
+//	@Description	completed and published tests are exported, otherwise the tests are the same as in the list of
+//	@Description	tests: the public tests with public=true, and otherwise the tests of the team. The results of the
+//	@Description	tests of other teams are only included when the rank is public, without their skis. In CSV and
+//	@Description	XLSX, text starting with =, +, - or @ is prefixed with a quote so it is not read as a formula.
+//	@Tags			Tests
+//	@Produce		text/csv
+//	@Produce		application/x-ndjson
+//	@Produce		application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+//	@Security		BearerAuth
+//	@Param			format		query		string			false	"Export format, defaults to csv"	Enums(csv, jsonl, xlsx)
+//	@Param			public		query		string			false	"All Public Tests"
+//	@Param			start_date	query		string			false	"Start Date in YYYY-MM-DD format"
+//	@Param			end_date	query		string			false	"End date in YYYY-MM-DD format"
+//	@Param			location_id	query		int				false	"Only tests at this location"
+//	@Param			track_id	query		int				false	"Only tests on this track"
+//	@Param			state		query		string			false	"Only tests in this state"	Enums(completed, published)
+//	@Success		200			{array}		TestExportRow	"The exported rows"
+//	@Failure		400			{string}	string			"Invalid format, use csv, jsonl or xlsx."
+//	@Failure		500			{string}	string			"Could not export the tests."
+//	@Router			/tests/export [get]
+func TestsExportRequestGET(w http.ResponseWriter, r *http.Request, db *sql.DB) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "csv"
+	}
+	exportFormat, ok := exportFormats[format]
+	if !ok {
+		http.Error(w, "Invalid format, use csv, jsonl or xlsx.", http.StatusBadRequest)
+		log.Println("Invalid export format: " + format)
+		return
+	}
+
+	// Get the user's team membership.
+	team := middleware.GetUserTeamRole(w, r, db)
+
+	conditions, args, err := exportFilter(w, r, team)
+	if err != nil {
+		log.Println("Invalid export filter: " + err.Error())
+		return
+	}
+
+	rows, err := getExportRows(db, conditions, args)
+	if err != nil {
+		http.Error(w, "Could not export the tests.", http.StatusInternalServerError)
+		log.Println("Could not export the tests: " + err.Error())
+		return
+	}
+	defer rows.Close()
+
+	// The rows are streamed, so errors after this point can only be logged.
+	w.Header().Set("content-type", exportFormat.contentType)
+	w.Header().Set("content-disposition", `attachment; filename="tests.`+exportFormat.extension+`"`)
+	writer, err := newExportWriter(format, w)
+	if err != nil {
+		log.Println("Could not start the export: " + err.Error())
+		return
+	}
+
+	for rows.Next() {
+		row, err := scanExportRow(rows)
+		if err != nil {
+			log.Println("Could not retrieve the exported tests: " + err.Error())
+			return
+		}
+		if err = writer.Write(row); err != nil {
+			log.Println("Could not write the export: " + err.Error())
+			return
+		}
+	}
+	if err = rows.Err(); err != nil {
+		log.Println("Could not retrieve the exported tests: " + err.Error())
+		return
+	}
+
+	if err = writer.Close(); err != nil {
+		log.Println("Could not finish the export: " + err.Error())
+	}
+}
@@ -0,0 +1,198 @@
+package testsHandler
+
+import (
+	"backend/internal/domain"
+	"backend/internal/services/xlsx"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// TestExportRow is the result of a product in a test, together with the test and its conditions. A test without
+// results is exported as a single row without a product.
+type TestExportRow struct {
+	TestID          int              `json:"test_id"`
+	Date            time.Time        `json:"test_date"`
+	Location        string           `json:"location"`
+	LocationID      *int             `json:"location_id"`
+	TrackID         *int             `json:"track_id"`
+	Comment         string           `json:"comment"`
+	State           domain.TestState `json:"state"`
+	IsPublic        bool             `json:"is_public"`
+	TestingTeam     int              `json:"testing_team"`
+	SnowTemperature float64          `json:"sc_temperature"`
+	SnowType        string           `json:"snow_type"`
+	SnowHumidity    string           `json:"snow_humidity"`
+	AirTemperature  float64          `json:"ac_temperature"`
+	AirHumidity     int              `json:"air_humidity"`
+	Wind            string           `json:"wind"`
+	Cloud           string           `json:"cloud"`
+	TrackHardness   string           `json:"track_hardness"`
+	TrackType       string           `json:"track_type"`
+	ProductID       *int             `json:"product_id"`
+	ProductName     *string          `json:"product_name"`
+	ProductBrand    *string          `json:"product_brand"`
+	ProductType     *string          `json:"product_type"`
+	Rank            *int             `json:"rank"`
+	DistanceBehind  *int             `json:"distance_behind"`
+	SkiID           *int             `json:"ski_id"`
+	SkiCode         *string          `json:"ski_code"`
+	OutOfRange      *bool            `json:"out_of_temperature_range"`
+}
+
+// exportColumns are the columns of the CSV and XLSX exports, in the order of TestExportRow.Values.
+var exportColumns = []string{
+	"test_id", "test_date", "location", "location_id", "track_id", "comment", "state", "is_public", "testing_team",
+	"sc_temperature", "snow_type", "snow_humidity",
+	"ac_temperature", "air_humidity", "wind", "cloud",
+	"track_hardness", "track_type",
+	"product_id", "product_name", "product_brand", "product_type", "rank", "distance_behind", "ski_id", "ski_code",
+	"out_of_temperature_range",
+}
+
+// exportFormats are the content types and file extensions of the export formats.
+var exportFormats = map[string]struct {
+	contentType string
+	extension   string
+}{
+	"csv":   {"text/csv; charset=utf-8", "csv"},
+	"jsonl": {"application/x-ndjson", "jsonl"},
+	"xlsx":  {xlsx.ContentType, "xlsx"},
+}
+
+// Values returns the values of the row in the order of exportColumns, with nil for missing values.
+func (row TestExportRow) Values() []any {
+	return []any{
+		row.TestID, row.Date.Format(time.DateOnly), row.Location, valueOrNil(row.LocationID),
+		valueOrNil(row.TrackID), row.Comment, string(row.State), row.IsPublic, row.TestingTeam,
+		row.SnowTemperature, row.SnowType, row.SnowHumidity,
+		row.AirTemperature, row.AirHumidity, row.Wind, row.Cloud,
+		row.TrackHardness, row.TrackType,
+		valueOrNil(row.ProductID), valueOrNil(row.ProductName), valueOrNil(row.ProductBrand),
+		valueOrNil(row.ProductType), valueOrNil(row.Rank), valueOrNil(row.DistanceBehind), valueOrNil(row.SkiID),
+		valueOrNil(row.SkiCode), valueOrNil(row.OutOfRange),
+	}
+}
+
+// SpreadsheetValues returns the values of the row like Values, with the text that a spreadsheet would evaluate as a
+// formula escaped.
+func (row TestExportRow) SpreadsheetValues() []any {
+	values := row.Values()
+	for i, value := range values {
+		values[i] = escapeFormula(value)
+	}
+	return values
+}
+
+// escapeFormula prefixes text starting with =, +, - or @ with a quote, so a spreadsheet shows it as text instead of
+// evaluating it as a formula.
+func escapeFormula(value any) any {
+	if text, ok := value.(string); ok && text != "" && strings.ContainsRune("=+-@\t\r", rune(text[0])) {
+		return "'" + text
+	}
+	return value
+}
+
+func valueOrNil[T any](value *T) any {
+	if value == nil {
+		return nil
+	}
+	return *value
+}
+
+// formatCSVValue formats a value for a CSV file, leaving missing values empty.
+func formatCSVValue(value any) string {
+	if value == nil {
+		return ""
+	}
+	return fmt.Sprint(value)
+}
+
+// exportFilter builds the conditions of the tests to export, with the same visibility as the list of tests: the
+// public tests with public=true, and otherwise the tests of the team. Only the completed and published tests are
+// exported, as the results of the other tests can still change. The team is the first argument.
+func exportFilter(w http.ResponseWriter, r *http.Request, team int) (string, []interface{}, error) {
+	conditions := "testing_team = $1"
+	if r.URL.Query().Get("public") == "true" {
+		conditions = "is_public"
+	}
+	conditions += " AND state IN " + domain.ReportableStatesSQL
+
+	args := []interface{}{team}
+	for _, param := range []struct {
+		name     string
+		operator string
+	}{{"start_date", ">="}, {"end_date", "<="}} {
+		value := r.URL.Query().Get(param.name)
+		if value == "" {
+			continue
+		}
+		if _, err := time.Parse(time.DateOnly, value); err != nil {
+			http.Error(w, "Invalid "+param.name+" format, use YYYY-MM-DD.", http.StatusBadRequest)
+			return "", nil, err
+		}
+		args = append(args, value)
+		conditions += fmt.Sprintf(" AND test_date %s to_date($%d, 'YYYY-MM-DD')", param.operator, len(args))
+	}
+
+	filter, args, err := testFilter(w, r, args)
+	if err != nil {
+		return "", nil, err
+	}
+	return conditions + filter, args, nil
+}
+
+// getExportRows queries the results of the tests matching the conditions. The results of the tests of other teams
+// are only included when the rank is public, and their skis are left out.
+func getExportRows(db *sql.DB, conditions string, args []interface{}) (*sql.Rows, error) {
+	return db.Query(`SELECT t.id, t.test_date, t.location, t.location_id, t.track_id, t.comment, t.state,
+       			t.is_public, t.testing_team,
+       			sc.temperature, sc.snow_type, sc.snow_humidity,
+       			ac.temperature, ac.humidity, ac.wind, ac.cloud,
+       			tc.track_hardness, tc.track_type,
+       			r.product_id, p.name, p.brand, p.type, r.rank, r.distance_behind, s.id, s.ski_code,
+       			r.out_of_temperature_range
+			FROM (SELECT * FROM tests WHERE `+conditions+`) t
+			JOIN snow_conditions sc ON sc.id = t.sc_id
+			JOIN air_conditions ac ON ac.id = t.ac_id
+			JOIN track_conditions tc ON tc.id = t.tc_id
+			LEFT JOIN test_ranks r ON r.test_id = t.id AND (t.testing_team = $1 OR r.is_rank_public)
+			LEFT JOIN products p ON p.id = r.product_id
+			LEFT JOIN skis s ON s.id = r.ski_id AND s.testing_team = $1
+			ORDER BY t.test_date, t.id, r.rank NULLS LAST, r.product_id;`, args...)
+}
+
+func scanExportRow(rows *sql.Rows) (TestExportRow, error) {
+	var row TestExportRow
+	err := rows.Scan(
+		&row.TestID,
+		&row.Date,
+		&row.Location,
+		&row.LocationID,
+		&row.TrackID,
+		&row.Comment,
+		&row.State,
+		&row.IsPublic,
+		&row.TestingTeam,
+		&row.SnowTemperature,
+		&row.SnowType,
+		&row.SnowHumidity,
+		&row.AirTemperature,
+		&row.AirHumidity,
+		&row.Wind,
+		&row.Cloud,
+		&row.TrackHardness,
+		&row.TrackType,
+		&row.ProductID,
+		&row.ProductName,
+		&row.ProductBrand,
+		&row.ProductType,
+		&row.Rank,
+		&row.DistanceBehind,
+		&row.SkiID,
+		&row.SkiCode,
+		&row.OutOfRange)
+	return row, err
+}
@@ -0,0 +1,129 @@
+package testsHandler
+
+import (
+	"backend/internal/utils"
+	"database/sql/driver"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+var exportRowColumns = []string{
+	"id", "test_date", "location", "location_id", "track_id", "comment", "state", "is_public", "testing_team",
+	"temperature", "snow_type", "snow_humidity", "temperature", "humidity", "wind", "cloud",
+	"track_hardness", "track_type",
+	"product_id", "name", "brand", "type", "rank", "distance_behind", "ski_id", "ski_code",
+	"out_of_temperature_range",
+}
+
+func TestTestsExportRequestGET(t *testing.T) {
+	mockDB, mock := utils.InitMockDB(t)
+
+	date := time.Date(2025, 1, 12, 0, 0, 0, 0, time.UTC)
+	exportMock := func(conditions string, args ...driver.Value) {
+		mock.ExpectQuery("SELECT t.id, t.test_date, .* FROM \\(SELECT \\* FROM tests WHERE " + conditions + "\\) t").
+			WithArgs(args...).
+			WillReturnRows(sqlmock.NewRows(exportRowColumns).
+				AddRow(1, date, "Holmenkollen, Oslo", 2, nil, "=HYPERLINK(\"x\")", "completed", false, 1,
+					-8.5, "A2", "DS", -5, 80, "L", "1", "H1", "D1", 3, "Blue Wax", "Swix", "glider", 1, 0, 4, "S1", false).
+				AddRow(2, date, "Sjusjøen", nil, nil, "No results", "published", false, 1,
+					-2, "FS", "W1", 0, 90, "S", "4", "H2", "T1", nil, nil, nil, nil, nil, nil, nil, nil, nil))
+	}
+
+	tests := []struct {
+		name                string
+		path                string
+		setupMocks          func()
+		expectedCode        int
+		expectedContentType string
+		expectedBody        string
+	}{
+		{
+			name:                "Status OK - CSV",
+			path:                "/tests/export?start_date=2025-01-01&end_date=2025-01-31",
+			expectedCode:        http.StatusOK,
+			expectedContentType: "text/csv; charset=utf-8",
+			expectedBody: "test_id,test_date,location,location_id,track_id,comment,state,is_public,testing_team," +
+				"sc_temperature,snow_type,snow_humidity,ac_temperature,air_humidity,wind,cloud,track_hardness," +
+				"track_type,product_id,product_name,product_brand,product_type,rank,distance_behind,ski_id,ski_code," +
+				"out_of_temperature_range\n" +
+				"1,2025-01-12,\"Holmenkollen, Oslo\",2,,\"'=HYPERLINK(\"\"x\"\")\",completed,false,1,-8.5,A2,DS,-5,80,L,1,H1,D1," +
+				"3,Blue Wax,Swix,glider,1,0,4,S1,false\n" +
+				"2,2025-01-12,Sjusjøen,,,No results,published,false,1,-2,FS,W1,0,90,S,4,H2,T1,,,,,,,,,\n",
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				exportMock("testing_team = \\$1 AND state IN \\('completed', 'published'\\) "+
+					"AND test_date >= to_date\\(\\$2, 'YYYY-MM-DD'\\) AND test_date <= to_date\\(\\$3, 'YYYY-MM-DD'\\)",
+					1, "2025-01-01", "2025-01-31")
+			},
+		},
+		{
+			name:                "Status OK - JSON Lines of public tests",
+			path:                "/tests/export?format=jsonl&public=true&state=completed",
+			expectedCode:        http.StatusOK,
+			expectedContentType: "application/x-ndjson",
+			expectedBody: `{"test_id":2,"test_date":"2025-01-12T00:00:00Z","location":"Sjusjøen","location_id":null,` +
+				`"track_id":null,"comment":"No results","state":"published","is_public":false,"testing_team":1,` +
+				`"sc_temperature":-2,"snow_type":"FS","snow_humidity":"W1","ac_temperature":0,"air_humidity":90,` +
+				`"wind":"S","cloud":"4","track_hardness":"H2","track_type":"T1","product_id":null,"product_name":null,` +
+				`"product_brand":null,"product_type":null,"rank":null,"distance_behind":null,"ski_id":null,"ski_code":null,` +
+				`"out_of_temperature_range":null}`,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				exportMock("is_public AND state IN \\('completed', 'published'\\) AND state = \\$2", 1, "completed")
+			},
+		},
+		{
+			name:                "Status OK - XLSX",
+			path:                "/tests/export?format=xlsx",
+			expectedCode:        http.StatusOK,
+			expectedContentType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+			expectedBody:        "PK",
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				exportMock("testing_team = \\$1 AND state IN \\('completed', 'published'\\)", 1)
+			},
+		},
+		{
+			name:         "Status bad request - invalid format",
+			path:         "/tests/export?format=pdf",
+			expectedCode: http.StatusBadRequest,
+			setupMocks:   func() {},
+		},
+		{
+			name:         "Status bad request - invalid start date",
+			path:         "/tests/export?start_date=12.01.2025",
+			expectedCode: http.StatusBadRequest,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.Header.Set("Authorization", "Bearer mockToken")
+			rr := httptest.NewRecorder()
+
+			TestsHandler(mockDB).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedCode, rr.Code)
+			if tt.expectedContentType != "" {
+				assert.Equal(t, tt.expectedContentType, rr.Header().Get("content-type"))
+			}
+			if tt.expectedBody != "" {
+				assert.Contains(t, rr.Body.String(), tt.expectedBody)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
@@ -4,8 +4,11 @@ import (
 	"backend/internal/domain"
 	"backend/internal/middleware"
 	"backend/internal/resources"
+	"backend/internal/services/access"
+	"backend/internal/utils"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/go-playground/validator/v10"
 	"log"
@@ -15,6 +18,9 @@ import (
 	"time"
 )
 
+const testColumns = "id, test_date, location, comment, sc_id, tc_id, ac_id, version, is_public, testing_team, " +
+	"location_id, track_id, state"
+
 func FetchTests(w http.ResponseWriter, tests []domain.Test, rows *sql.Rows, err error) {
 	for rows.Next() {
 		var test domain.Test
@@ -29,7 +35,10 @@ func FetchTests(w http.ResponseWriter, tests []domain.Test, rows *sql.Rows, err
 			&test.AirConditions,
 			&test.Version,
 			&test.IsPublic,
-			&test.TestingTeam); err != nil {
+			&test.TestingTeam,
+			&test.LocationID,
+			&test.TrackID,
+			&test.State); err != nil {
 			http.Error(w, "Could not retrieve all tests ", http.StatusInternalServerError)
 			log.Println("Could not retrieve all tests " + err.Error())
 			return
@@ -55,12 +64,43 @@ func FetchTests(w http.ResponseWriter, tests []domain.Test, rows *sql.Rows, err
 	return
 }
 
+// testFilter adds the optional location_id, track_id and state query parameters to the conditions of a tests query.
+func testFilter(w http.ResponseWriter, r *http.Request, args []interface{}) (string, []interface{}, error) {
+	var filter string
+	if state := r.URL.Query().Get("state"); state != "" {
+		switch domain.TestState(state) {
+		case domain.TestPlanned, domain.TestInProgress, domain.TestCompleted, domain.TestPublished:
+		default:
+			http.Error(w, "Invalid state, use planned, in_progress, completed or published.", http.StatusBadRequest)
+			log.Println("Invalid state: " + state)
+			return "", nil, fmt.Errorf("invalid state %s", state)
+		}
+		args = append(args, state)
+		filter += fmt.Sprintf(" AND state = $%d", len(args))
+	}
+
+	for _, column := range []string{"location_id", "track_id"} {
+		param := r.URL.Query().Get(column)
+		if param == "" {
+			continue
+		}
+
+		id, err := utils.GetIDFromURLQuery(w, param)
+		if err != nil {
+			return "", nil, err
+		}
+		args = append(args, id)
+		filter += fmt.Sprintf(" AND %s = $%d", column, len(args))
+	}
+	return filter, args, nil
+}
+
 // GetTestWithID retrieves a test with a specific ID from the database.
 func GetTestWithID(w http.ResponseWriter, db *sql.DB, testID int) domain.Test {
 	var test domain.Test
 
 	// Get the existing test from the database.
-	err := db.QueryRow("SELECT * FROM tests WHERE id = $1;", testID).Scan(
+	err := db.QueryRow("SELECT "+testColumns+" FROM tests WHERE id = $1;", testID).Scan(
 		&test.ID,
 		&test.Date,
 		&test.Location,
@@ -70,7 +110,10 @@ func GetTestWithID(w http.ResponseWriter, db *sql.DB, testID int) domain.Test {
 		&test.AirConditions,
 		&test.Version,
 		&test.IsPublic,
-		&test.TestingTeam)
+		&test.TestingTeam,
+		&test.LocationID,
+		&test.TrackID,
+		&test.State)
 	if err != nil {
 		http.Error(w, "Could not retrieve the test.", http.StatusNotFound)
 		log.Println("Could not retrieve the test: " + err.Error())
@@ -130,6 +173,219 @@ func ValidateTestPATCHRequestBody(w http.ResponseWriter, r *http.Request, db *sq
 		return fmt.Errorf("user cannot update this test, %d", http.StatusUnauthorized), http.StatusUnauthorized
 	}
 
+	if err, code := validateTestStateUpdate(testUpdateRequest.Updates, update, existingTest, team); err != nil {
+		return err, code
+	}
+
+	if update.SkiID != nil {
+		if err, code := validateTestSkis(db, []TestRanksPOST{{SkiID: update.SkiID}}, team); err != nil {
+			return err, code
+		}
+	}
+
+	if update.LocationID != nil || update.TrackID != nil {
+		return validateTestLocationUpdate(db, testUpdateRequest.Updates, update, existingTest)
+	}
+
+	return nil, 0
+}
+
+// validateTestStateUpdate checks that a new state of a test follows the lifecycle of the tests, and that a completed
+// or published test is not changed other than by moving it to another state. A published test is made public.
+func validateTestStateUpdate(updates map[string]interface{}, update TestUpdateFields, existingTest domain.Test,
+	team int) (error, int) {
+	if existingTest.State.IsReadOnly() {
+		for field := range updates {
+			if field != "state" {
+				log.Println("Test is " + string(existingTest.State) + " and cannot be changed")
+				return fmt.Errorf("test is %s and cannot be changed, %d", existingTest.State, http.StatusConflict),
+					http.StatusConflict
+			}
+		}
+	}
+
+	if update.State == nil || domain.TestState(*update.State) == existingTest.State {
+		return nil, 0
+	}
+
+	next := domain.TestState(*update.State)
+	if !existingTest.State.CanTransitionTo(next) {
+		log.Println("Test cannot move from " + string(existingTest.State) + " to " + string(next))
+		return fmt.Errorf("test cannot move from %s to %s, %d", existingTest.State, next, http.StatusConflict),
+			http.StatusConflict
+	}
+
+	if next == domain.TestPublished && !existingTest.IsPublic {
+		if domain.TeamRole(team) == domain.Researcher {
+			log.Println("Researcher cannot make tests public")
+			return fmt.Errorf("researcher cannot make tests public, %d", http.StatusUnauthorized),
+				http.StatusUnauthorized
+		}
+		updates["is_public"] = true
+	}
+	return nil, 0
+}
+
+// newStateChange returns the state change made by a test update, or nil when the state is unchanged.
+func newStateChange(updates map[string]interface{}, existingTest domain.Test, userID int) *domain.TestStateChange {
+	next, ok := updates["state"].(string)
+	if !ok || domain.TestState(next) == existingTest.State {
+		return nil
+	}
+
+	change := &domain.TestStateChange{
+		TestID:    existingTest.ID,
+		FromState: existingTest.State,
+		ToState:   domain.TestState(next),
+		ChangedAt: time.Now(),
+	}
+	if userID != 0 {
+		change.ChangedBy = &userID
+	}
+	return change
+}
+
+// insertStateChange records a change of the state of a test.
+func insertStateChange(tx *sql.Tx, change domain.TestStateChange) error {
+	_, err := tx.Exec(`INSERT INTO test_state_changes (test_id, from_state, to_state, changed_by, changed_at)
+							VALUES ($1, $2, $3, $4, $5);`,
+		change.TestID,
+		change.FromState,
+		change.ToState,
+		change.ChangedBy,
+		change.ChangedAt)
+	return err
+}
+
+// getStateChanges retrieves the state changes of a test, oldest first.
+func getStateChanges(db *sql.DB, testID int) ([]domain.TestStateChange, error) {
+	rows, err := db.Query(`SELECT id, test_id, from_state, to_state, changed_by, changed_at
+								FROM test_state_changes WHERE test_id = $1 ORDER BY changed_at, id;`, testID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	changes := []domain.TestStateChange{}
+	for rows.Next() {
+		var change domain.TestStateChange
+		if err = rows.Scan(
+			&change.ID,
+			&change.TestID,
+			&change.FromState,
+			&change.ToState,
+			&change.ChangedBy,
+			&change.ChangedAt); err != nil {
+			return nil, err
+		}
+		changes = append(changes, change)
+	}
+	return changes, rows.Err()
+}
+
+// validateTestLocationUpdate checks that a new location or track of a test exists, and that the track is at the
+// location of the test. The free-text location follows the new location, and a track at the old location is removed.
+func validateTestLocationUpdate(db *sql.DB, updates map[string]interface{}, update TestUpdateFields,
+	existingTest domain.Test) (error, int) {
+	locationID := existingTest.LocationID
+	if update.LocationID != nil {
+		var name string
+		err := db.QueryRow("SELECT name FROM locations WHERE id = $1;", *update.LocationID).Scan(&name)
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Println("Location does not exist")
+			return fmt.Errorf("location %d does not exist, %d", *update.LocationID, http.StatusBadRequest),
+				http.StatusBadRequest
+		} else if err != nil {
+			log.Println("Could not retrieve the location: " + err.Error())
+			return fmt.Errorf("could not retrieve the location, %d", http.StatusInternalServerError),
+				http.StatusInternalServerError
+		}
+
+		if update.Location == nil {
+			updates["location"] = name
+		}
+		if update.TrackID == nil && existingTest.TrackID != nil &&
+			(existingTest.LocationID == nil || *existingTest.LocationID != *update.LocationID) {
+			updates["track_id"] = nil
+		}
+		locationID = update.LocationID
+	}
+
+	if update.TrackID != nil {
+		var trackLocation int
+		err := db.QueryRow("SELECT location_id FROM tracks WHERE id = $1;", *update.TrackID).Scan(&trackLocation)
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Println("Track does not exist")
+			return fmt.Errorf("track %d does not exist, %d", *update.TrackID, http.StatusBadRequest),
+				http.StatusBadRequest
+		} else if err != nil {
+			log.Println("Could not retrieve the track: " + err.Error())
+			return fmt.Errorf("could not retrieve the track, %d", http.StatusInternalServerError),
+				http.StatusInternalServerError
+		}
+
+		if locationID == nil || *locationID != trackLocation {
+			log.Println("Track is not at the location of the test")
+			return fmt.Errorf("track is not at the location of the test, %d", http.StatusBadRequest),
+				http.StatusBadRequest
+		}
+	}
+	return nil, 0
+}
+
+// validateTestLocation checks that the location and track of a new test exist, and that the track is at the
+// location. A test with a track and no location gets the location of the track.
+func validateTestLocation(q interface {
+	QueryRow(query string, args ...any) *sql.Row
+}, test *TestPOSTRequest) (error, int) {
+	if test.TrackID != nil {
+		var trackLocation int
+		err := q.QueryRow("SELECT location_id FROM tracks WHERE id = $1;", *test.TrackID).Scan(&trackLocation)
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("track %d does not exist, %d", *test.TrackID, http.StatusBadRequest), http.StatusBadRequest
+		} else if err != nil {
+			return fmt.Errorf("could not retrieve the track, %d", http.StatusInternalServerError),
+				http.StatusInternalServerError
+		}
+
+		if test.LocationID != nil && *test.LocationID != trackLocation {
+			return fmt.Errorf("track %d is not at location %d, %d", *test.TrackID, *test.LocationID,
+				http.StatusBadRequest), http.StatusBadRequest
+		}
+		test.LocationID = &trackLocation
+		return nil, 0
+	}
+
+	if test.LocationID != nil {
+		var count int
+		err := q.QueryRow("SELECT COUNT(*) FROM locations WHERE id = $1;", *test.LocationID).Scan(&count)
+		if err != nil {
+			return fmt.Errorf("could not retrieve the location, %d", http.StatusInternalServerError),
+				http.StatusInternalServerError
+		}
+		if count == 0 {
+			return fmt.Errorf("location %d does not exist, %d", *test.LocationID, http.StatusBadRequest),
+				http.StatusBadRequest
+		}
+	}
+	return nil, 0
+}
+
+// validateTestSkis checks that the ski pairs the products of a new test were tested on belong to the team.
+func validateTestSkis(q access.Querier, testRanks []TestRanksPOST, team int) (error, int) {
+	for _, rank := range testRanks {
+		if rank.SkiID == nil {
+			continue
+		}
+		found, err := access.IsTeamSki(q, *rank.SkiID, team)
+		if err != nil {
+			return fmt.Errorf("could not retrieve the ski, %d", http.StatusInternalServerError),
+				http.StatusInternalServerError
+		}
+		if !found {
+			return fmt.Errorf("ski %d does not exist, %d", *rank.SkiID, http.StatusBadRequest), http.StatusBadRequest
+		}
+	}
 	return nil, 0
 }
 
@@ -176,7 +432,7 @@ func insertTrackConditions(tx *sql.Tx, conditions TrackConditionsPOST) (int, err
 	return trackConditionID, err
 }
 
-func createTest(tx *sql.Tx, test TestPOSTRequest, team int) (int, error) {
+func createTest(tx *sql.Tx, test TestPOSTRequest, team int, userID int) (int, error) {
 	// Insert snow conditions to database
 	snowConditionID, err := insertSnowConditions(tx, test.SnowConditions)
 	if err != nil {
@@ -196,12 +452,19 @@ func createTest(tx *sql.Tx, test TestPOSTRequest, team int) (int, error) {
 		return 0, fmt.Errorf("failed to insert track conditions:  %w", err)
 	}
 
+	state := test.State
+	if state == "" {
+		state = domain.TestInProgress
+	}
+
 	// Insert test to database
 	var testID int
+	// Without a free-text location, the name of the referenced location is used.
 	err = tx.QueryRow(`INSERT INTO tests (
                    			test_date, location, comment, sc_id, tc_id, ac_id, 
-                   			version, is_public, testing_team) 
-							VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+                   			version, is_public, testing_team, location_id, track_id, state) 
+							VALUES ($1, COALESCE(NULLIF($2, ''), (SELECT name FROM locations WHERE id = $10)),
+							        $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
 							RETURNING id;`,
 		test.Date,
 		test.Location,
@@ -211,10 +474,30 @@ func createTest(tx *sql.Tx, test TestPOSTRequest, team int) (int, error) {
 		airConditionID,
 		time.Now(),
 		test.IsPublic,
-		team).Scan(&testID)
+		team,
+		test.LocationID,
+		test.TrackID,
+		state).Scan(&testID)
 	if err != nil {
 		return 0, fmt.Errorf("failed to insert test:  %w", err)
 	}
+
+	// A test created as completed or published skipped the states a test is worked on in, which is recorded as a
+	// change from in progress, so the history shows who completed it.
+	if state != domain.TestPlanned && state != domain.TestInProgress {
+		change := domain.TestStateChange{
+			TestID:    testID,
+			FromState: domain.TestInProgress,
+			ToState:   state,
+			ChangedAt: time.Now(),
+		}
+		if userID != 0 {
+			change.ChangedBy = &userID
+		}
+		if err = insertStateChange(tx, change); err != nil {
+			return 0, fmt.Errorf("failed to record the state of the test: %w", err)
+		}
+	}
 	return testID, nil
 }
 
@@ -259,38 +542,63 @@ func getAvailabilityFromProduct(tx *sql.Tx, productID int) (bool, error) {
 	return availability, err
 }
 
-// Insert test rankings into the database
-func insertTestRanking(tx *sql.Tx, testID int, testRank TestRanksPOST) error {
+// Insert test rankings into the database. outOfTemperatureRange flags a product ranked outside its rated temperature
+// range.
+func insertTestRanking(tx *sql.Tx, testID int, testRank TestRanksPOST, outOfTemperatureRange bool) error {
 	var isRankPublic, err = getAvailabilityFromProduct(tx, testRank.ProductID)
 	if err != nil {
 		return fmt.Errorf("failed to get product availability: %w", err)
 	}
 	_, err = tx.Exec(`INSERT INTO test_ranks (
-                      	test_id, product_id, rank, distance_behind, version, is_rank_public)
-						VALUES ($1, $2, $3, $4, $5, $6)`,
+                      	test_id, product_id, rank, distance_behind, version, is_rank_public, ski_id,
+                      	out_of_temperature_range)
+						VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
 		testID,
 		testRank.ProductID,
 		testRank.Rank,
 		testRank.DistanceBehind,
 		time.Now(),
-		isRankPublic)
+		isRankPublic,
+		testRank.SkiID,
+		outOfTemperatureRange)
 	return err
 }
 
-// Create rankings and associated products
-func createTestRankings(tx *sql.Tx, testID int, testRankings []TestRanksPOST) error {
+// Create rankings and associated products. The products are checked against the temperatures of the test, and the
+// results outside their rated temperature range are flagged and returned as warnings.
+func createTestRankings(tx *sql.Tx, testID int, temperatures testTemperatures, testRankings []TestRanksPOST) (
+	[]TemperatureWarning, error) {
+	warnings := []TemperatureWarning{}
 	for _, rank := range testRankings {
+		productRange, err := getTemperatureRange(tx, rank.ProductID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the temperature range of product %d: %w", rank.ProductID, err)
+		}
+		warning := checkTemperatureRange(rank.ProductID, productRange, temperatures)
+		if warning != nil {
+			warnings = append(warnings, *warning)
+		}
+
 		// Insert winner ranking
-		err := insertTestRanking(tx, testID, rank)
+		err = insertTestRanking(tx, testID, rank, warning != nil)
 		if err != nil {
-			return fmt.Errorf("failed to insert winner ranking: %w", err)
+			return nil, fmt.Errorf("failed to insert winner ranking: %w", err)
 		}
 	}
-	return nil
+	return warnings, nil
+}
+
+// temperaturesOf returns the temperatures of a new test.
+func temperaturesOf(test TestPOSTRequest) testTemperatures {
+	return testTemperatures{
+		Snow: float64(test.SnowConditions.Temperature),
+		Air:  float64(test.AirConditions.Temperature),
+	}
 }
 
 func createTestUpdateQueries(w http.ResponseWriter, r *http.Request, db *sql.DB, testUpdateRequest TestPATCHRequest,
-	existingTestVersion time.Time, testID int, productID int) time.Time {
+	existingTestVersion time.Time, testID int, productID int, stateChange *domain.TestStateChange, team int) (
+	time.Time, []TemperatureWarning) {
 	// Create the updatedFields and newValues arrays for the query, and increment the index for the newValues array.
 	var updatedFields []string
 	var newValues []interface{}
@@ -319,7 +627,7 @@ func createTestUpdateQueries(w http.ResponseWriter, r *http.Request, db *sql.DB,
 	if err != nil {
 		http.Error(w, resources.TransactionStartFailed, http.StatusInternalServerError)
 		log.Println("Could not start transaction: " + err.Error())
-		return time.Time{}
+		return time.Time{}, nil
 	}
 
 	// Validate fields
@@ -339,7 +647,7 @@ func createTestUpdateQueries(w http.ResponseWriter, r *http.Request, db *sql.DB,
 		default:
 			http.Error(w, fmt.Sprintf("Invalid field: %s", field), http.StatusBadRequest)
 			log.Printf("Invalid field: %s", field)
-			return time.Time{}
+			return time.Time{}, nil
 		}
 	}
 
@@ -363,22 +671,51 @@ func createTestUpdateQueries(w http.ResponseWriter, r *http.Request, db *sql.DB,
 		http.Error(w, "Invalid request URL, use '/tests/{test_id}/product/{product_id}' to update test ranks.",
 			http.StatusBadRequest)
 		log.Println("Invalid request URL: " + r.URL.Path)
-		return time.Time{}
+		return time.Time{}, nil
 	}
 
 	if containsTestUpdateFields {
 		newVersion = updateTestFields(w, tx, newVersion, existingTestVersion, updatedFields, newValues, testID)
 	}
 
+	// Check the ranked products against the new temperatures of the test.
+	warnings := []TemperatureWarning{}
+	if (containsACUpdateFields || containsSCUpdateFields) && !newVersion.IsZero() {
+		warnings, err = UpdateTemperatureRangeFlags(tx, testID)
+		if err != nil {
+			_ = tx.Rollback()
+			http.Error(w, "Could not check the temperature ranges.", http.StatusInternalServerError)
+			log.Println("Could not check the temperature ranges: " + err.Error())
+			return time.Time{}, nil
+		}
+
+		// Refuse products outside their temperature range if the team does not allow them.
+		if err = RefuseOutOfTemperatureRange(w, tx, team, warnings); err != nil {
+			_ = tx.Rollback()
+			return time.Time{}, nil
+		}
+	}
+
+	// Record the change of the state together with the update, so every transition has its author.
+	if stateChange != nil && !newVersion.IsZero() {
+		err = insertStateChange(tx, *stateChange)
+		if err != nil {
+			_ = tx.Rollback()
+			http.Error(w, "Could not record the state change of the test.", http.StatusInternalServerError)
+			log.Println("Could not record the state change of the test: " + err.Error())
+			return time.Time{}, nil
+		}
+	}
+
 	// Commit the transaction.
 	err = tx.Commit()
 	if err != nil {
 		http.Error(w, resources.TransactionCommitFailed, http.StatusInternalServerError)
 		log.Println("Could not commit transaction: " + err.Error())
-		return time.Time{}
+		return time.Time{}, nil
 	}
 
-	return newVersion
+	return newVersion, warnings
 }
 
 // Update the air conditions in the database.
@@ -34,8 +34,16 @@ func AuthenticationMock(mock sqlmock.Sqlmock) {
 		WillReturnRows(sqlmock.NewRows([]string{"team_role"}).AddRow(1))
 }
 
+// UserIDMock mocks the lookup of the authenticated user.
+func UserIDMock(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery("SELECT user_id FROM sessions WHERE \\(session_token = \\$1 AND expires_at > NOW\\(\\)\\)").
+		WithArgs("mockToken").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(1))
+}
+
 var testsColumns = []string{
 	"id", "test_date", "location", "comment", "sc_id", "tc_id", "ac_id", "version", "is_public", "testing_team",
+	"location_id", "track_id", "state",
 }
 
 // Mock rows for the sql.Rows interface
@@ -92,8 +100,8 @@ func TestFetchTests(t *testing.T) {
 			name: "Valid data return",
 			setupMock: func(mock sqlmock.Sqlmock) {
 				rows := sqlmock.NewRows(testsColumns).
-					AddRow(1, time.Now(), "location 1", "Test Comment", 1, 1, 1, time.Now(), true, 1).
-					AddRow(2, time.Now(), "Location 2", "Test Comment 2", 2, 2, 2, time.Now(), true, 1)
+					AddRow(1, time.Now(), "location 1", "Test Comment", 1, 1, 1, time.Now(), true, 1, nil, nil, "in_progress").
+					AddRow(2, time.Now(), "Location 2", "Test Comment 2", 2, 2, 2, time.Now(), true, 1, nil, nil, "in_progress")
 
 				// Either return this directly or have your test function use it
 				mock.ExpectQuery("SELECT .* FROM tests").WillReturnRows(rows)
@@ -150,11 +158,11 @@ func TestGetTestWithID(t *testing.T) {
 			name:   "Test found",
 			testID: 2,
 			mockSetup: func() {
-				cols := []string{"id", "test_date", "location", "comment", "sc_id", "tc_id", "ac_id", "version", "publicly_available", "testing_team"}
-				mock.ExpectQuery("SELECT \\* FROM tests WHERE id = \\$1").
+				cols := []string{"id", "test_date", "location", "comment", "sc_id", "tc_id", "ac_id", "version", "publicly_available", "testing_team", "location_id", "track_id", "state"}
+				mock.ExpectQuery("SELECT .* FROM tests WHERE id = \\$1").
 					WithArgs(2).
 					WillReturnRows(sqlmock.NewRows(cols).
-						AddRow(2, time.Now(), "Location 2", "Test Comment 2", 2, 2, 2, time.Now(), true, 1))
+						AddRow(2, time.Now(), "Location 2", "Test Comment 2", 2, 2, 2, time.Now(), true, 1, nil, nil, "in_progress"))
 			},
 			expectedTest:   domain.Test{ID: 2},
 			expectedStatus: http.StatusOK,
@@ -163,7 +171,7 @@ func TestGetTestWithID(t *testing.T) {
 			name:   "Test not found",
 			testID: 999,
 			mockSetup: func() {
-				mock.ExpectQuery("SELECT \\* FROM tests WHERE id = \\$1").
+				mock.ExpectQuery("SELECT .* FROM tests WHERE id = \\$1").
 					WithArgs(999).
 					WillReturnError(sql.ErrNoRows)
 			},
@@ -496,11 +504,14 @@ func TestCreateTest(t *testing.T) {
 			sqlmock.AnyArg(), // Use AnyArg() for time.Now()
 			testData.IsPublic,
 			team,
+			nil,           // location_id
+			nil,           // track_id
+			"in_progress", // state
 		).
 		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
 
 	// Call the function
-	id, err := createTest(tx, testData, team)
+	id, err := createTest(tx, testData, team, 1)
 
 	// Assertions
 	assert.NoError(t, err)
@@ -532,7 +543,7 @@ func Test_TestsHandler(t *testing.T) {
 			setupMocks: func() {
 				AuthenticationMock(mock)
 
-				mock.ExpectQuery("SELECT \\* FROM tests").
+				mock.ExpectQuery("SELECT .* FROM tests").
 					WillReturnRows(sqlmock.NewRows(testsColumns))
 			},
 		},
@@ -546,6 +557,7 @@ func Test_TestsHandler(t *testing.T) {
 				AuthenticationMock(mock)
 
 				// Begin transaction
+				UserIDMock(mock)
 				mock.ExpectBegin()
 
 				// Mock snow conditions insertion
@@ -575,17 +587,24 @@ func Test_TestsHandler(t *testing.T) {
 						sqlmock.AnyArg(), // version
 						false,            // is_public
 						1,                // testing_team
+						nil,              // location_id
+						nil,              // track_id
+						"in_progress",    // state
 					).
 					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
 
 				// Get product availability
+				mock.ExpectQuery("SELECT type, low_temperature, high_temperature FROM products WHERE id = \\$1;").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"type", "low_temperature", "high_temperature"}).AddRow("solid", nil, nil))
+
 				mock.ExpectQuery("SELECT is_public FROM products WHERE id = \\$1").
 					WithArgs(1).
 					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
 
 				// Mock test ranks insertion
 				mock.ExpectExec("INSERT INTO test_ranks").
-					WithArgs(1, 1, 1, 0, sqlmock.AnyArg(), true).
+					WithArgs(1, 1, 1, 0, sqlmock.AnyArg(), true, nil, false).
 					WillReturnResult(sqlmock.NewResult(5, 1))
 
 				// Commit transaction
@@ -602,6 +621,7 @@ func Test_TestsHandler(t *testing.T) {
 				AuthenticationMock(mock)
 
 				// Begin transaction
+				UserIDMock(mock)
 				mock.ExpectBegin()
 
 				// Mock snow conditions insertion
@@ -631,6 +651,9 @@ func Test_TestsHandler(t *testing.T) {
 						sqlmock.AnyArg(), // version
 						false,            // is_public
 						1,                // testing_team
+						nil,              // location_id
+						nil,              // track_id
+						"in_progress",    // state
 					).
 					WillReturnError(errors.New("test error"))
 			},
@@ -645,6 +668,7 @@ func Test_TestsHandler(t *testing.T) {
 				AuthenticationMock(mock)
 
 				// Begin transaction
+				UserIDMock(mock)
 				mock.ExpectBegin()
 
 				// Mock snow conditions insertion
@@ -674,17 +698,24 @@ func Test_TestsHandler(t *testing.T) {
 						sqlmock.AnyArg(), // version
 						false,            // is_public
 						1,                // testing_team
+						nil,              // location_id
+						nil,              // track_id
+						"in_progress",    // state
 					).
 					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
 
 				// Get product availability
+				mock.ExpectQuery("SELECT type, low_temperature, high_temperature FROM products WHERE id = \\$1;").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"type", "low_temperature", "high_temperature"}).AddRow("solid", nil, nil))
+
 				mock.ExpectQuery("SELECT is_public FROM products WHERE id = \\$1").
 					WithArgs(1).
 					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
 
 				// Mock test ranks insertion
 				mock.ExpectExec("INSERT INTO test_ranks").
-					WithArgs(1, 1, 1, 0, sqlmock.AnyArg(), true).
+					WithArgs(1, 1, 1, 0, sqlmock.AnyArg(), true, nil, false).
 					WillReturnError(errors.New("mock error")) // Simulate an error
 			},
 		},
@@ -698,6 +729,7 @@ func Test_TestsHandler(t *testing.T) {
 				AuthenticationMock(mock)
 
 				// Begin transaction
+				UserIDMock(mock)
 				mock.ExpectBegin()
 
 				// Mock snow conditions insertion
@@ -727,17 +759,24 @@ func Test_TestsHandler(t *testing.T) {
 						sqlmock.AnyArg(), // version
 						false,            // is_public
 						1,                // testing_team
+						nil,              // location_id
+						nil,              // track_id
+						"in_progress",    // state
 					).
 					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
 
 				// Get product availability
+				mock.ExpectQuery("SELECT type, low_temperature, high_temperature FROM products WHERE id = \\$1;").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"type", "low_temperature", "high_temperature"}).AddRow("solid", nil, nil))
+
 				mock.ExpectQuery("SELECT is_public FROM products WHERE id = \\$1").
 					WithArgs(1).
 					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
 
 				// Mock test ranks insertion
 				mock.ExpectExec("INSERT INTO test_ranks").
-					WithArgs(1, 1, 1, 0, sqlmock.AnyArg(), true).
+					WithArgs(1, 1, 1, 0, sqlmock.AnyArg(), true, nil, false).
 					WillReturnResult(sqlmock.NewResult(1, 1))
 
 				// Commit transaction
@@ -752,10 +791,10 @@ func Test_TestsHandler(t *testing.T) {
 			expectedCode: http.StatusOK,
 			setupMocks: func() {
 				// Get existing test
-				mock.ExpectQuery("SELECT \\* FROM tests WHERE id = \\$1;").
+				mock.ExpectQuery("SELECT .* FROM tests WHERE id = \\$1;").
 					WithArgs(1).
 					WillReturnRows(sqlmock.NewRows(testsColumns).
-						AddRow(1, time.Now(), "Old Location", "Comment", 1, 1, 1, time.Time{}, false, 1))
+						AddRow(1, time.Now(), "Old Location", "Comment", 1, 1, 1, time.Time{}, false, 1, nil, nil, "in_progress"))
 
 				// Mock authentication
 				AuthenticationMock(mock)
@@ -772,6 +811,88 @@ func Test_TestsHandler(t *testing.T) {
 				mock.ExpectCommit()
 			},
 		},
+		{
+			name:         "Method = PATCH (Status OK - completed)",
+			method:       http.MethodPatch,
+			path:         "/tests/1",
+			body:         `{"updates":{"state":"completed"}, "version":"0001-01-01T00:00:00Z"}`,
+			expectedCode: http.StatusOK,
+			setupMocks: func() {
+				mock.ExpectQuery("SELECT .* FROM tests WHERE id = \\$1;").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows(testsColumns).
+						AddRow(1, time.Now(), "Old Location", "Comment", 1, 1, 1, time.Time{}, false, 1, nil, nil, "in_progress"))
+				AuthenticationMock(mock)
+
+				// Get the user making the change
+				mock.ExpectQuery("SELECT user_id FROM sessions").
+					WithArgs("mockToken").
+					WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(7))
+
+				mock.ExpectBegin()
+				mock.ExpectQuery("UPDATE tests SET state = \\$1, version = \\$2 WHERE id = \\$3 AND version = \\$4 RETURNING version").
+					WithArgs("completed", sqlmock.AnyArg(), 1, time.Time{}).
+					WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(time.Now()))
+				mock.ExpectExec("INSERT INTO test_state_changes").
+					WithArgs(1, "in_progress", "completed", 7, sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:         "Method = PATCH (Status conflict - completed test is read-only)",
+			method:       http.MethodPatch,
+			path:         "/tests/1",
+			body:         `{"updates":{"comment":"Changed"}, "version":"0001-01-01T00:00:00Z"}`,
+			expectedCode: http.StatusConflict,
+			setupMocks: func() {
+				mock.ExpectQuery("SELECT .* FROM tests WHERE id = \\$1;").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows(testsColumns).
+						AddRow(1, time.Now(), "Old Location", "Comment", 1, 1, 1, time.Time{}, false, 1, nil, nil, "completed"))
+				AuthenticationMock(mock)
+			},
+		},
+		{
+			name:         "Method = PATCH (Status conflict - invalid transition)",
+			method:       http.MethodPatch,
+			path:         "/tests/1",
+			body:         `{"updates":{"state":"planned"}, "version":"0001-01-01T00:00:00Z"}`,
+			expectedCode: http.StatusConflict,
+			setupMocks: func() {
+				mock.ExpectQuery("SELECT .* FROM tests WHERE id = \\$1;").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows(testsColumns).
+						AddRow(1, time.Now(), "Old Location", "Comment", 1, 1, 1, time.Time{}, true, 1, nil, nil, "published"))
+				AuthenticationMock(mock)
+			},
+		},
+		{
+			name:         "Method = GET (Status OK - state changes)",
+			method:       http.MethodGet,
+			path:         "/tests/1/states",
+			expectedCode: http.StatusOK,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				mock.ExpectQuery("SELECT .* FROM tests WHERE id = \\$1;").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows(testsColumns).
+						AddRow(1, time.Now(), "Location", "Comment", 1, 1, 1, time.Now(), false, 1, nil, nil, "completed"))
+				mock.ExpectQuery("SELECT .* FROM test_state_changes WHERE test_id = \\$1 ORDER BY changed_at, id;").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "test_id", "from_state", "to_state", "changed_by", "changed_at"}).
+						AddRow(1, 1, "in_progress", "completed", 7, time.Now()))
+			},
+		},
+		{
+			name:         "Method = GET (Status bad request - invalid state)",
+			method:       http.MethodGet,
+			path:         "/tests?state=finished",
+			expectedCode: http.StatusBadRequest,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+			},
+		},
 		{
 			name:         "Method = PUT (Status method not allowed)",
 			method:       http.MethodPut,
@@ -816,10 +937,10 @@ func Test_TestsHandler(t *testing.T) {
 			expectedCode: http.StatusBadRequest,
 			setupMocks: func() {
 				// Get existing test
-				mock.ExpectQuery(`SELECT \* FROM tests WHERE id = \$1;`).
+				mock.ExpectQuery(`SELECT .* FROM tests WHERE id = \$1;`).
 					WithArgs(1).
 					WillReturnRows(sqlmock.NewRows(testsColumns).
-						AddRow(1, time.Now(), "Old Location", "Comment", 1, 1, 1, time.Time{}, false, 1))
+						AddRow(1, time.Now(), "Old Location", "Comment", 1, 1, 1, time.Time{}, false, 1, nil, nil, "in_progress"))
 			},
 		},
 		{
@@ -833,12 +954,12 @@ func Test_TestsHandler(t *testing.T) {
 
 				// Setup query result rows
 				rows := sqlmock.NewRows(testsColumns).
-					AddRow(1, time.Now(), "Location 1", "Test Comment", 1, 1, 1, time.Now(), true, 1).
-					AddRow(2, time.Now(), "Location 2", "Test Comment 2", 1, 1, 1, time.Now(), true, 1)
+					AddRow(1, time.Now(), "Location 1", "Test Comment", 1, 1, 1, time.Now(), true, 1, nil, nil, "in_progress").
+					AddRow(2, time.Now(), "Location 2", "Test Comment 2", 1, 1, 1, time.Now(), true, 1, nil, nil, "in_progress")
 
 				// The expected query should match what the handler actually executes
 				// Use the correct date range parameters
-				mock.ExpectQuery("SELECT \\* FROM tests WHERE testing_team = \\$1 AND test_date >= to_date\\(\\$2, 'YYYY-MM-DD'\\) AND test_date <= to_date\\(\\$3, 'YYYY-MM-DD'\\)").
+				mock.ExpectQuery("SELECT .* FROM tests WHERE testing_team = \\$1 AND test_date >= to_date\\(\\$2, 'YYYY-MM-DD'\\) AND test_date <= to_date\\(\\$3, 'YYYY-MM-DD'\\)").
 					WithArgs(1, "2022-02-05", "2030-07-19").
 					WillReturnRows(rows)
 			},
@@ -852,11 +973,11 @@ func Test_TestsHandler(t *testing.T) {
 			setupMocks: func() {
 				// Setup query result rows
 				rows := sqlmock.NewRows(testsColumns).
-					AddRow(1, time.Now(), "Location 1", "Test Comment", 1, 1, 1, time.Now(), true, 1).
-					AddRow(2, time.Now(), "Location 2", "Test Comment 2", 2, 2, 2, time.Now(), true, 1)
+					AddRow(1, time.Now(), "Location 1", "Test Comment", 1, 1, 1, time.Now(), true, 1, nil, nil, "in_progress").
+					AddRow(2, time.Now(), "Location 2", "Test Comment 2", 2, 2, 2, time.Now(), true, 1, nil, nil, "in_progress")
 
 				// Expect the query with the correct parameter name (is_public not publicly_available)
-				mock.ExpectQuery("SELECT \\* FROM tests WHERE is_public = \\$1").
+				mock.ExpectQuery("SELECT .* FROM tests WHERE is_public = \\$1").
 					WithArgs(true).
 					WillReturnRows(rows)
 			},
@@ -871,9 +992,106 @@ func Test_TestsHandler(t *testing.T) {
 				AuthenticationMock(mock)
 
 				// Begin transaction
+				UserIDMock(mock)
 				mock.ExpectBegin().WillReturnError(sql.ErrNoRows)
 			},
 		},
+		{
+			name:         "Method = GET (Status OK - public tests at a location)",
+			method:       http.MethodGet,
+			path:         "/tests?public=true&location_id=3",
+			body:         ``,
+			expectedCode: http.StatusOK,
+			setupMocks: func() {
+				rows := sqlmock.NewRows(testsColumns).
+					AddRow(1, time.Now(), "Beito", "Test Comment", 1, 1, 1, time.Now(), true, 1, 3, nil, "in_progress")
+
+				mock.ExpectQuery("SELECT .* FROM tests WHERE is_public = \\$1 AND location_id = \\$2;").
+					WithArgs(true, 3).
+					WillReturnRows(rows)
+			},
+		},
+		{
+			name:         "Method = GET (Status OK - team tests on a track)",
+			method:       http.MethodGet,
+			path:         "/tests?location_id=3&track_id=4",
+			body:         ``,
+			expectedCode: http.StatusOK,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+
+				rows := sqlmock.NewRows(testsColumns).
+					AddRow(1, time.Now(), "Beito", "Test Comment", 1, 1, 1, time.Now(), true, 1, 3, 4, "in_progress")
+
+				mock.ExpectQuery("SELECT .* FROM tests WHERE testing_team = \\$1 AND location_id = \\$2 AND track_id = \\$3;").
+					WithArgs(1, 3, 4).
+					WillReturnRows(rows)
+			},
+		},
+		{
+			name:         "Method = GET (Status bad request - invalid location_id)",
+			method:       http.MethodGet,
+			path:         "/tests?location_id=beito",
+			body:         ``,
+			expectedCode: http.StatusBadRequest,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+			},
+		},
+		{
+			name:         "Method = POST (Status bad request - track at another location)",
+			method:       http.MethodPost,
+			path:         "/tests",
+			body:         `{"sc":{"temperature":-10,"snow_type":"FS","snow_humidity":"W2"},"ac":{"temperature":-15,"humidity":30,"wind":"L","cloud":"1"},"tc":{"track_hardness":"H1","track_type":"D1"},"location_id":3,"track_id":4,"comment":"Excellent glide.","test_ranks":[{"product_id":1,"rank":1,"distance_behind":0}]}`,
+			expectedCode: http.StatusBadRequest,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+
+				mock.ExpectQuery("SELECT location_id FROM tracks WHERE id = \\$1;").
+					WithArgs(4).
+					WillReturnRows(sqlmock.NewRows([]string{"location_id"}).AddRow(5))
+			},
+		},
+		{
+			name:         "Method = POST (Status bad request - ski of another team)",
+			method:       http.MethodPost,
+			path:         "/tests",
+			body:         `{"sc":{"temperature":-10,"snow_type":"FS","snow_humidity":"W2"},"ac":{"temperature":-15,"humidity":30,"wind":"L","cloud":"1"},"tc":{"track_hardness":"H1","track_type":"D1"},"location":"Oslo","comment":"Excellent glide.","test_ranks":[{"product_id":1,"rank":1,"distance_behind":0,"ski_id":7}]}`,
+			expectedCode: http.StatusBadRequest,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+
+				mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM skis WHERE id = \\$1 AND testing_team = \\$2;").
+					WithArgs(7, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+			},
+		},
+		{
+			name:         "Method = PATCH (Status OK - new location)",
+			method:       http.MethodPatch,
+			path:         "/tests/1",
+			body:         `{"updates":{"location_id":3}, "version":"0001-01-01T00:00:00Z"}`,
+			expectedCode: http.StatusOK,
+			setupMocks: func() {
+				mock.ExpectQuery("SELECT .* FROM tests WHERE id = \\$1;").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows(testsColumns).
+						AddRow(1, time.Now(), "Old Location", "Comment", 1, 1, 1, time.Time{}, false, 1, 2, 7, "in_progress"))
+
+				AuthenticationMock(mock)
+
+				// The free-text location follows the location, and the track at the old location is removed.
+				mock.ExpectQuery("SELECT name FROM locations WHERE id = \\$1;").
+					WithArgs(3).
+					WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("Beitostølen"))
+
+				mock.ExpectBegin()
+				mock.ExpectQuery("UPDATE tests SET .* WHERE id = \\$5 AND version = \\$6 RETURNING version").
+					WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), 1, time.Time{}).
+					WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(time.Now()))
+				mock.ExpectCommit()
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -907,6 +1125,7 @@ func Test_createTestRankings(t *testing.T) {
 		testID       int
 		setupMocks   func(mock *sqlmock.Sqlmock)
 		testRankings []TestRanksPOST
+		wantWarnings int
 		wantErr      bool
 	}{
 		{
@@ -916,21 +1135,29 @@ func Test_createTestRankings(t *testing.T) {
 				(*mock).ExpectBegin()
 
 				// Expectations for the first product (ID 1)
+				(*mock).ExpectQuery("SELECT type, low_temperature, high_temperature FROM products WHERE id = \\$1;").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"type", "low_temperature", "high_temperature"}).AddRow("solid", nil, nil))
+
 				(*mock).ExpectQuery("SELECT is_public FROM products WHERE id = \\$1").
 					WithArgs(1).
 					WillReturnRows(sqlmock.NewRows([]string{"is_public"}).AddRow(true))
 
 				(*mock).ExpectExec("INSERT INTO test_ranks").
-					WithArgs(1, 1, 1, 0, sqlmock.AnyArg(), true).
+					WithArgs(1, 1, 1, 0, sqlmock.AnyArg(), true, nil, false).
 					WillReturnResult(sqlmock.NewResult(1, 1))
 
 				// Expectations for the second product (ID 2)
+				(*mock).ExpectQuery("SELECT type, low_temperature, high_temperature FROM products WHERE id = \\$1;").
+					WithArgs(2).
+					WillReturnRows(sqlmock.NewRows([]string{"type", "low_temperature", "high_temperature"}).AddRow("solid", nil, nil))
+
 				(*mock).ExpectQuery("SELECT is_public FROM products WHERE id = \\$1").
 					WithArgs(2).
 					WillReturnRows(sqlmock.NewRows([]string{"is_public"}).AddRow(true))
 
 				(*mock).ExpectExec("INSERT INTO test_ranks").
-					WithArgs(1, 2, 2, 5, sqlmock.AnyArg(), true).
+					WithArgs(1, 2, 2, 5, sqlmock.AnyArg(), true, nil, false).
 					WillReturnResult(sqlmock.NewResult(2, 1))
 
 				(*mock).ExpectCommit()
@@ -951,6 +1178,34 @@ func Test_createTestRankings(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name:   "Product tested outside its temperature range",
+			testID: 1,
+			setupMocks: func(mock *sqlmock.Sqlmock) {
+				(*mock).ExpectBegin()
+
+				(*mock).ExpectQuery("SELECT type, low_temperature, high_temperature FROM products WHERE id = \\$1;").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"type", "low_temperature", "high_temperature"}).AddRow("solid", -4.0, 0.0))
+
+				(*mock).ExpectQuery("SELECT is_public FROM products WHERE id = \\$1").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"is_public"}).AddRow(true))
+
+				(*mock).ExpectExec("INSERT INTO test_ranks").
+					WithArgs(1, 1, 1, 0, sqlmock.AnyArg(), true, nil, true).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+			testRankings: []TestRanksPOST{
+				{
+					ProductID:    1,
+					Rank:         1,
+					IsRankPublic: true,
+				},
+			},
+			wantWarnings: 1,
+			wantErr:      false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -965,9 +1220,12 @@ func Test_createTestRankings(t *testing.T) {
 				t.Errorf("failed to begin transaction: %v", err)
 			}
 
-			if err = createTestRankings(mockTX, tt.testID, tt.testRankings); (err != nil) != tt.wantErr {
+			temperatures := testTemperatures{Snow: -8, Air: -5}
+			warnings, err := createTestRankings(mockTX, tt.testID, temperatures, tt.testRankings)
+			if (err != nil) != tt.wantErr {
 				t.Errorf("createTestsRankings() error = %v, wantErr %v", err, tt.wantErr)
 			}
+			assert.Len(t, warnings, tt.wantWarnings)
 		})
 	}
 }
@@ -992,7 +1250,7 @@ func Test_insertTestRanking(t *testing.T) {
 					WillReturnRows(sqlmock.NewRows([]string{"is_public"}).AddRow(true))
 
 				(*mock).ExpectExec("INSERT INTO test_ranks").
-					WithArgs(1, 1, 1, 0, sqlmock.AnyArg(), true).
+					WithArgs(1, 1, 1, 0, sqlmock.AnyArg(), true, nil, false).
 					WillReturnResult(sqlmock.NewResult(1, 1))
 
 				(*mock).ExpectCommit()
@@ -1012,7 +1270,7 @@ func Test_insertTestRanking(t *testing.T) {
 				(*mock).ExpectBegin()
 
 				(*mock).ExpectExec("INSERT INTO test_ranks").
-					WithArgs(1, 1, 1, 0, sqlmock.AnyArg(), false).
+					WithArgs(1, 1, 1, 0, sqlmock.AnyArg(), false, nil, false).
 					WillReturnResult(sqlmock.NewResult(1, 1))
 
 				(*mock).ExpectCommit().WillReturnError(sql.ErrNoRows)
@@ -1039,7 +1297,7 @@ func Test_insertTestRanking(t *testing.T) {
 				t.Errorf("failed to begin transaction: %v", err)
 			}
 
-			if err = insertTestRanking(mockTX, tt.testID, tt.testRank); (err != nil) != tt.wantErr {
+			if err = insertTestRanking(mockTX, tt.testID, tt.testRank, false); (err != nil) != tt.wantErr {
 				t.Errorf("insertTestRanking() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
@@ -1081,6 +1339,13 @@ func Test_createTestUpdateQueries(t *testing.T) {
 					WithArgs(10, 1).
 					WillReturnResult(sqlmock.NewResult(1, 1))
 
+				// Check the ranked products against the new temperatures
+				(*mock).ExpectQuery("SELECT r.product_id, r.out_of_temperature_range, .* FROM test_ranks r").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"product_id", "out_of_temperature_range", "type",
+						"low_temperature", "high_temperature", "temperature", "temperature"}).
+						AddRow(1, false, "solid", nil, nil, 10.0, 10.0))
+
 				// Commit transaction
 				(*mock).ExpectCommit()
 			},
@@ -1139,6 +1404,13 @@ func Test_createTestUpdateQueries(t *testing.T) {
 					WithArgs(10, 1).
 					WillReturnResult(sqlmock.NewResult(1, 1))
 
+				// Check the ranked products against the new temperatures
+				(*mock).ExpectQuery("SELECT r.product_id, r.out_of_temperature_range, .* FROM test_ranks r").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"product_id", "out_of_temperature_range", "type",
+						"low_temperature", "high_temperature", "temperature", "temperature"}).
+						AddRow(1, false, "solid", nil, nil, 10.0, 10.0))
+
 				// Commit transaction
 				(*mock).ExpectCommit()
 			},
@@ -1234,6 +1506,13 @@ func Test_createTestUpdateQueries(t *testing.T) {
 					WithArgs(10, sqlmock.AnyArg(), 1, 1).
 					WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(time.Now()))
 
+				// Check the ranked products against the new temperatures
+				(*mock).ExpectQuery("SELECT r.product_id, r.out_of_temperature_range, .* FROM test_ranks r").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"product_id", "out_of_temperature_range", "type",
+						"low_temperature", "high_temperature", "temperature", "temperature"}).
+						AddRow(1, false, "solid", nil, nil, 10.0, 10.0))
+
 				// Commit transaction
 				(*mock).ExpectCommit()
 			},
@@ -1451,8 +1730,8 @@ func Test_createTestUpdateQueries(t *testing.T) {
 
 			formatedWant := time.Date(tt.want.Year(), tt.want.Month(), tt.want.Day(), tt.want.Hour(), tt.want.Minute(), tt.want.Second(), tt.want.Nanosecond(), tt.want.Location())
 
-			returnedTime := createTestUpdateQueries(rr, req, mockDB,
-				tt.testUpdateRequest, tt.existingTestVersion, tt.testID, tt.productID)
+			returnedTime, _ := createTestUpdateQueries(rr, req, mockDB,
+				tt.testUpdateRequest, tt.existingTestVersion, tt.testID, tt.productID, nil, 1)
 			// Truncate the time to seconds precision for reliable comparison
 			formatedReturnedTime := time.Date(returnedTime.Year(), returnedTime.Month(), returnedTime.Day(), returnedTime.Hour(), returnedTime.Minute(), returnedTime.Second(), tt.want.Nanosecond(), returnedTime.Location())
 			assert.Equalf(t, formatedWant, formatedReturnedTime,
@@ -1697,6 +1976,27 @@ func TestValidateTestPATCHRequestBody(t *testing.T) {
 			want: fmt.Errorf("researcher cannot update public tests, %d", http.StatusUnauthorized),
 			code: http.StatusUnauthorized,
 		},
+		{
+			name: "Ski of another team",
+			db:   mockDB,
+			testUpdateRequest: TestPATCHRequest{
+				Updates: map[string]interface{}{"ski_id": 7},
+			},
+			existingTest: domain.Test{
+				ID:          1,
+				TestingTeam: 1,
+				IsPublic:    false,
+			},
+			setupMocks: func() {
+				AuthenticationMock(mock)
+
+				mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM skis WHERE id = \\$1 AND testing_team = \\$2;").
+					WithArgs(7, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+			},
+			want: fmt.Errorf("ski 7 does not exist, %d", http.StatusBadRequest),
+			code: http.StatusBadRequest,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -1721,3 +2021,43 @@ func TestValidateTestPATCHRequestBody(t *testing.T) {
 		})
 	}
 }
+
+func Test_validateTestStateUpdate(t *testing.T) {
+	inProgress := domain.Test{ID: 1, State: domain.TestInProgress, TestingTeam: 1}
+	completed := domain.Test{ID: 1, State: domain.TestCompleted, TestingTeam: 1}
+	state := func(s string) TestUpdateFields { return TestUpdateFields{State: &s} }
+
+	updates := map[string]interface{}{"state": "completed", "comment": "Done"}
+	err, _ := validateTestStateUpdate(updates, state("completed"), inProgress, 1)
+	assert.NoError(t, err)
+
+	err, code := validateTestStateUpdate(map[string]interface{}{"comment": "Changed"}, TestUpdateFields{}, completed, 1)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusConflict, code)
+
+	err, code = validateTestStateUpdate(map[string]interface{}{"state": "planned"}, state("planned"), completed, 1)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusConflict, code)
+
+	// Publishing a private test makes it public.
+	updates = map[string]interface{}{"state": "published"}
+	err, _ = validateTestStateUpdate(updates, state("published"), completed, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, true, updates["is_public"])
+
+	err, code = validateTestStateUpdate(map[string]interface{}{"state": "published"}, state("published"), completed,
+		int(domain.Researcher))
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusUnauthorized, code)
+}
+
+func Test_newStateChange(t *testing.T) {
+	test := domain.Test{ID: 4, State: domain.TestInProgress}
+	assert.Nil(t, newStateChange(map[string]interface{}{"state": "in_progress"}, test, 2))
+
+	change := newStateChange(map[string]interface{}{"state": "completed"}, test, 2)
+	assert.Equal(t, 4, change.TestID)
+	assert.Equal(t, domain.TestInProgress, change.FromState)
+	assert.Equal(t, domain.TestCompleted, change.ToState)
+	assert.Equal(t, 2, *change.ChangedBy)
+}
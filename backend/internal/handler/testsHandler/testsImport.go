@@ -0,0 +1,203 @@
+package testsHandler
+
+import (
+	"backend/internal/middleware"
+	"backend/internal/resources"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"regexp"
+	"strconv"
+)
+
+var importPath = regexp.MustCompile(`^/tests/import/?$`)
+
+// TestsImportRequestPOST is the request handler for importing tests from a CSV file.
+//
+//	@Summary		Import tests from a CSV file
+//	@Description	Creates tests from a CSV file with one row for each product result. The columns are test_key,
+//	@Description	test_date, location, location_id, track_id, comment, is_public, state, sc_temperature, snow_type,
+//	@Description	snow_humidity, ac_temperature, air_humidity, wind, cloud, track_hardness, track_type, product_id,
+//	@Description	rank, distance_behind and ski_id, and only product_id is required. Rows with the same test_key, or
+//	@Description	without one the same date and venue, make up a test, and the test columns only have to be filled
+//	@Description	in on the first row of a test. The rows are validated with the same rules as a new test, and all
+//	@Description	the tests are created in one transaction, so nothing is created if a row is invalid. Imported
+//	@Description	tests are completed unless a state is given. Products ranked outside their rated temperature range
+//	@Description	are flagged and listed as warnings, or refused for a team with a strict temperature range. A dry
+//	@Description	run only validates the file, without the temperature ranges.
+//	@Tags			Tests
+//	@Accept			text/csv
+//	@Accept			multipart/form-data
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			file	formData	file				false	"CSV file, unless the file is the request body"
+//	@Param			dry_run	query		bool				false	"Only validate the file"
+//	@Success		200		{object}	TestImportReport	"Validation report of a dry run"
+//	@Success		201		{object}	TestImportReport	"Tests imported successfully"
+//	@Failure		400		{object}	TestImportReport	"Validation report of an invalid file"
+//	@Failure		413		{string}	string				"The file is larger than 5 MB."
+//	@Failure		500		{string}	string				"Could not import the tests."
+//	@Router			/tests/import [post]
+func TestsImportRequestPOST(w http.ResponseWriter, r *http.Request, db *sql.DB) {
+	dryRun := false
+	if value := r.URL.Query().Get("dry_run"); value != "" {
+		var err error
+		if dryRun, err = strconv.ParseBool(value); err != nil {
+			http.Error(w, "Invalid dry_run, use true or false.", http.StatusBadRequest)
+			log.Println("Invalid dry_run: " + value)
+			return
+		}
+	}
+
+	// Get the user's team membership.
+	team := middleware.GetUserTeamRole(w, r, db)
+
+	content, ok := readImportFile(w, r)
+	if !ok {
+		return
+	}
+
+	report := TestImportReport{DryRun: dryRun, TestIDs: []int{}, Errors: []ImportRowError{},
+		Warnings: []ImportRowError{}}
+	rows, rowErrors := readImportRows(content)
+	report.Rows = len(rows)
+	report.Errors = append(report.Errors, rowErrors...)
+
+	tests := groupImportRows(rows)
+	report.Tests = len(tests)
+	for _, test := range tests {
+		report.Errors = append(report.Errors, buildImportedTest(test)...)
+		report.Errors = append(report.Errors, validateImportedTest(test, team)...)
+	}
+
+	// The references are only looked up for a file without other errors.
+	if len(report.Errors) == 0 {
+		referenceErrors, err := validateImportReferences(db, tests, team)
+		if err != nil {
+			http.Error(w, "Could not import the tests.", http.StatusInternalServerError)
+			log.Println("Could not validate the imported tests: " + err.Error())
+			return
+		}
+		report.Errors = append(report.Errors, referenceErrors...)
+	}
+	sortImportErrors(report.Errors)
+
+	if dryRun {
+		writeImportReport(w, report, http.StatusOK)
+		return
+	}
+	if len(report.Errors) > 0 {
+		writeImportReport(w, report, http.StatusBadRequest)
+		return
+	}
+
+	// The user is recorded as the author of the state of the imported tests.
+	userID := middleware.GetUserID(w, r, db)
+
+	tx, err := db.Begin()
+	if err != nil {
+		http.Error(w, resources.TransactionStartFailed, http.StatusInternalServerError)
+		return
+	}
+	defer func() {
+		if err != nil {
+			rollbackErr := tx.Rollback()
+			if rollbackErr != nil {
+				log.Println(resources.RollbackFailed + rollbackErr.Error())
+			}
+		}
+	}()
+
+	for _, test := range tests {
+		var testID int
+		testID, err = createTest(tx, test.Test, team, userID)
+		if err != nil {
+			http.Error(w, "Could not import the tests.", http.StatusInternalServerError)
+			log.Printf("Failed to create the test of row %d: %s", test.Rows[0].Line, err.Error())
+			return
+		}
+
+		var warnings []TemperatureWarning
+		warnings, err = createTestRankings(tx, testID, temperaturesOf(test.Test), test.Test.TestRanks)
+		if err != nil {
+			http.Error(w, "Could not import the tests.", http.StatusInternalServerError)
+			log.Printf("Failed to create the rankings of row %d: %s", test.Rows[0].Line, err.Error())
+			return
+		}
+		report.Warnings = append(report.Warnings, temperatureRowWarnings(test, warnings)...)
+		report.TestIDs = append(report.TestIDs, testID)
+	}
+
+	// Refuse products outside their temperature range if the team does not allow them.
+	err = checkStrictTemperatureRange(tx, team, len(report.Warnings))
+	if errors.Is(err, errOutOfTemperatureRange) {
+		report.TestIDs = []int{}
+		report.Errors, report.Warnings = report.Warnings, []ImportRowError{}
+		writeImportReport(w, report, http.StatusBadRequest)
+		log.Println("Validation error: " + err.Error())
+		return
+	} else if err != nil {
+		http.Error(w, "Could not import the tests.", http.StatusInternalServerError)
+		log.Println("Could not check the temperature ranges: " + err.Error())
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		http.Error(w, resources.TransactionCommitFailed, http.StatusInternalServerError)
+		log.Println(resources.TransactionCommitFailed + ": " + err.Error())
+		return
+	}
+
+	for _, testID := range report.TestIDs {
+		NotifyTestChanged(testID)
+	}
+	writeImportReport(w, report, http.StatusCreated)
+}
+
+// readImportFile reads the imported file, which is either the request body or the file field of a multipart form.
+func readImportFile(w http.ResponseWriter, r *http.Request) ([]byte, bool) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportSize)
+
+	var file io.Reader = r.Body
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "multipart/form-data" {
+		formFile, _, err := r.FormFile("file")
+		if err != nil && !isTooLarge(err) {
+			http.Error(w, "Missing file, use the 'file' field.", http.StatusBadRequest)
+			log.Println("Missing import file: " + err.Error())
+			return nil, false
+		} else if err == nil {
+			defer formFile.Close()
+			file = formFile
+		}
+	}
+
+	content, err := io.ReadAll(file)
+	if isTooLarge(err) {
+		http.Error(w, "The file is larger than 5 MB.", http.StatusRequestEntityTooLarge)
+		log.Println("Import file too large")
+		return nil, false
+	} else if err != nil {
+		http.Error(w, "Could not read the file.", http.StatusBadRequest)
+		log.Println("Could not read the import file: " + err.Error())
+		return nil, false
+	}
+	return content, true
+}
+
+func isTooLarge(err error) bool {
+	var maxBytesError *http.MaxBytesError
+	return errors.As(err, &maxBytesError)
+}
+
+func writeImportReport(w http.ResponseWriter, report TestImportReport, code int) {
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		log.Println("Could not encode the import report: " + err.Error())
+	}
+}
@@ -0,0 +1,422 @@
+package testsHandler
+
+import (
+	"backend/internal/domain"
+	"backend/internal/services/access"
+	"bytes"
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxImportSize is the largest file that can be imported, in bytes.
+const maxImportSize = 5 << 20
+
+// importTestColumns are the columns describing a test. They are shared by all the rows of the test, so they only
+// have to be filled in on the first row.
+var importTestColumns = []string{
+	"test_date", "location", "location_id", "track_id", "comment", "is_public", "state",
+	"sc_temperature", "snow_type", "snow_humidity",
+	"ac_temperature", "air_humidity", "wind", "cloud",
+	"track_hardness", "track_type",
+}
+
+// importResultColumns are the columns describing the result of a product in a test.
+var importResultColumns = []string{"product_id", "rank", "distance_behind", "ski_id"}
+
+// importFieldColumns maps the fields of a new test to the columns of an imported file.
+var importFieldColumns = map[string]string{
+	"Location":                      "location",
+	"LocationID":                    "location_id",
+	"TrackID":                       "track_id",
+	"Date":                          "test_date",
+	"Comment":                       "comment",
+	"IsPublic":                      "is_public",
+	"State":                         "state",
+	"SnowConditions.Temperature":    "sc_temperature",
+	"SnowConditions.SnowType":       "snow_type",
+	"SnowConditions.SnowHumidity":   "snow_humidity",
+	"AirConditions.Temperature":     "ac_temperature",
+	"AirConditions.Humidity":        "air_humidity",
+	"AirConditions.Wind":            "wind",
+	"AirConditions.Cloud":           "cloud",
+	"TrackConditions.TrackHardness": "track_hardness",
+	"TrackConditions.TrackType":     "track_type",
+	"TestRanks":                     "product_id",
+	"ProductID":                     "product_id",
+	"Rank":                          "rank",
+	"DistanceBehind":                "distance_behind",
+	"SkiID":                         "ski_id",
+}
+
+var rankNamespace = regexp.MustCompile(`^TestRanks\[(\d+)\]\.(\w+)$`)
+
+// importRow is a row of an imported file, with the values by column.
+type importRow struct {
+	Line   int
+	Values map[string]string
+}
+
+// importedTest is a new test made up of rows of an imported file.
+type importedTest struct {
+	Rows []importRow
+	Test TestPOSTRequest
+}
+
+// isImportColumn reports whether the column can be used in an imported file.
+func isImportColumn(column string) bool {
+	if column == "test_key" {
+		return true
+	}
+	for _, c := range append(importTestColumns, importResultColumns...) {
+		if c == column {
+			return true
+		}
+	}
+	return false
+}
+
+// readImportRows reads the rows of an imported CSV file. The first row is the header, and both comma and semicolon
+// separated files are accepted.
+func readImportRows(content []byte) ([]importRow, []ImportRowError) {
+	// Spreadsheets often start the file with a byte order mark.
+	content = bytes.TrimPrefix(content, []byte("\ufeff"))
+	reader := csv.NewReader(bytes.NewReader(content))
+	firstLine, _, _ := bytes.Cut(content, []byte("\n"))
+	if bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")) {
+		reader.Comma = ';'
+	}
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, []ImportRowError{{Row: 1, Message: "could not read the header: " + err.Error()}}
+	}
+
+	var rowErrors []ImportRowError
+	seen := map[string]bool{}
+	for i := range header {
+		header[i] = strings.ToLower(strings.TrimSpace(header[i]))
+		switch {
+		case !isImportColumn(header[i]):
+			rowErrors = append(rowErrors, ImportRowError{Row: 1, Column: header[i], Message: "unknown column"})
+		case seen[header[i]]:
+			rowErrors = append(rowErrors, ImportRowError{Row: 1, Column: header[i], Message: "duplicate column"})
+		}
+		seen[header[i]] = true
+	}
+	if !seen["product_id"] {
+		rowErrors = append(rowErrors, ImportRowError{Row: 1, Column: "product_id", Message: "missing column"})
+	}
+	if len(rowErrors) > 0 {
+		return nil, rowErrors
+	}
+
+	var rows []importRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			rowErrors = append(rowErrors, ImportRowError{Row: parseErr.StartLine, Message: parseErr.Err.Error()})
+			if errors.Is(parseErr.Err, csv.ErrFieldCount) {
+				continue
+			}
+			break
+		} else if err != nil {
+			rowErrors = append(rowErrors, ImportRowError{Message: err.Error()})
+			break
+		}
+
+		line, _ := reader.FieldPos(0)
+		row := importRow{Line: line, Values: map[string]string{}}
+		empty := true
+		for i, column := range header {
+			row.Values[column] = strings.TrimSpace(record[i])
+			empty = empty && row.Values[column] == ""
+		}
+		if !empty {
+			rows = append(rows, row)
+		}
+	}
+
+	if len(rows) == 0 && len(rowErrors) == 0 {
+		rowErrors = append(rowErrors, ImportRowError{Row: 1, Message: "the file has no rows"})
+	}
+	return rows, rowErrors
+}
+
+// groupImportRows groups the rows of an imported file into tests. The rows with the same test_key make up a test,
+// and without a test_key the rows with the same date and venue do.
+func groupImportRows(rows []importRow) []*importedTest {
+	var tests []*importedTest
+	index := map[string]*importedTest{}
+	for _, row := range rows {
+		key := "key:" + row.Values["test_key"]
+		if row.Values["test_key"] == "" {
+			key = strings.Join([]string{"venue:", row.Values["test_date"], strings.ToLower(row.Values["location"]),
+				row.Values["location_id"], row.Values["track_id"]}, "|")
+		}
+
+		test, ok := index[key]
+		if !ok {
+			test = &importedTest{}
+			index[key] = test
+			tests = append(tests, test)
+		}
+		test.Rows = append(test.Rows, row)
+	}
+	return tests
+}
+
+// importParser parses the values of a row, collecting the errors.
+type importParser struct {
+	line   int
+	errors []ImportRowError
+}
+
+func (p *importParser) fail(column string, message string) {
+	p.errors = append(p.errors, ImportRowError{Row: p.line, Column: column, Message: message})
+}
+
+func (p *importParser) float(values map[string]string, column string) float32 {
+	if values[column] == "" {
+		return 0
+	}
+	// Spreadsheets with a Norwegian locale use a decimal comma.
+	value, err := strconv.ParseFloat(strings.Replace(values[column], ",", ".", 1), 32)
+	if err != nil {
+		p.fail(column, "invalid number "+values[column])
+	}
+	return float32(value)
+}
+
+func (p *importParser) integer(values map[string]string, column string) int {
+	if values[column] == "" {
+		return 0
+	}
+	value, err := strconv.Atoi(values[column])
+	if err != nil {
+		p.fail(column, "invalid integer "+values[column])
+	}
+	return value
+}
+
+func (p *importParser) optionalInteger(values map[string]string, column string) *int {
+	if values[column] == "" {
+		return nil
+	}
+	value := p.integer(values, column)
+	return &value
+}
+
+func (p *importParser) boolean(values map[string]string, column string) bool {
+	if values[column] == "" {
+		return false
+	}
+	value, err := strconv.ParseBool(values[column])
+	if err != nil {
+		p.fail(column, "invalid boolean "+values[column]+", use true or false")
+	}
+	return value
+}
+
+func (p *importParser) date(values map[string]string, column string) time.Time {
+	if values[column] == "" {
+		return time.Time{}
+	}
+	for _, layout := range []string{time.DateOnly, time.RFC3339, "2006-01-02 15:04"} {
+		if value, err := time.Parse(layout, values[column]); err == nil {
+			return value
+		}
+	}
+	p.fail(column, "invalid date "+values[column]+", use YYYY-MM-DD")
+	return time.Time{}
+}
+
+// buildImportedTest builds the new test from its rows. The test columns of the first row are used, and they can
+// only be repeated with the same values in the following rows. Imported tests are completed unless a state is given,
+// as they are historical results.
+func buildImportedTest(test *importedTest) []ImportRowError {
+	first := test.Rows[0]
+	parser := importParser{line: first.Line}
+
+	shared := map[string]string{}
+	for _, column := range importTestColumns {
+		shared[column] = first.Values[column]
+	}
+	for _, row := range test.Rows[1:] {
+		for _, column := range importTestColumns {
+			value := row.Values[column]
+			if shared[column] == "" {
+				shared[column] = value
+			} else if value != "" && value != shared[column] {
+				parser.errors = append(parser.errors, ImportRowError{Row: row.Line, Column: column,
+					Message: fmt.Sprintf("differs from row %d of the same test", first.Line)})
+			}
+		}
+	}
+
+	test.Test = TestPOSTRequest{
+		SnowConditions: SnowConditionsPOST{
+			Temperature:  parser.float(shared, "sc_temperature"),
+			SnowType:     shared["snow_type"],
+			SnowHumidity: shared["snow_humidity"],
+		},
+		AirConditions: AirConditionsPOST{
+			Temperature: parser.float(shared, "ac_temperature"),
+			Humidity:    parser.integer(shared, "air_humidity"),
+			Wind:        shared["wind"],
+			Cloud:       shared["cloud"],
+		},
+		TrackConditions: TrackConditionsPOST{
+			TrackHardness: shared["track_hardness"],
+			TrackType:     shared["track_type"],
+		},
+		Location:   shared["location"],
+		LocationID: parser.optionalInteger(shared, "location_id"),
+		TrackID:    parser.optionalInteger(shared, "track_id"),
+		Date:       parser.date(shared, "test_date"),
+		Comment:    shared["comment"],
+		IsPublic:   parser.boolean(shared, "is_public"),
+		State:      domain.TestState(shared["state"]),
+	}
+	if test.Test.State == "" {
+		test.Test.State = domain.TestCompleted
+	}
+
+	products := map[int]int{}
+	for _, row := range test.Rows {
+		parser.line = row.Line
+		rank := TestRanksPOST{
+			ProductID:      parser.integer(row.Values, "product_id"),
+			Rank:           parser.integer(row.Values, "rank"),
+			DistanceBehind: parser.integer(row.Values, "distance_behind"),
+			SkiID:          parser.optionalInteger(row.Values, "ski_id"),
+		}
+		if rank.ProductID <= 0 {
+			parser.fail("product_id", "a product ID is required")
+		} else if line, ok := products[rank.ProductID]; ok {
+			parser.fail("product_id", fmt.Sprintf("product %d is already in the test on row %d", rank.ProductID, line))
+		}
+		products[rank.ProductID] = row.Line
+		test.Test.TestRanks = append(test.Test.TestRanks, rank)
+	}
+	return parser.errors
+}
+
+// validateImportedTest validates a new test with the same rules as a test created with a POST request, and reports
+// the errors on the rows and columns they come from.
+func validateImportedTest(test *importedTest, team int) []ImportRowError {
+	var rowErrors []ImportRowError
+	if err := validatePermissions(test.Test, team); err != nil {
+		rowErrors = append(rowErrors, ImportRowError{Row: test.Rows[0].Line, Column: "is_public",
+			Message: "researcher cannot create public tests"})
+	}
+
+	err := validator.New().Struct(test.Test)
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return rowErrors
+	}
+
+	for _, fieldError := range validationErrors {
+		row := test.Rows[0].Line
+		field := strings.TrimPrefix(fieldError.StructNamespace(), "TestPOSTRequest.")
+		if matches := rankNamespace.FindStringSubmatch(field); matches != nil {
+			i, _ := strconv.Atoi(matches[1])
+			row, field = test.Rows[i].Line, matches[2]
+		}
+
+		message := "failed the " + fieldError.Tag() + " rule"
+		if fieldError.Param() != "" {
+			message += " (" + fieldError.Param() + ")"
+		}
+		rowErrors = append(rowErrors, ImportRowError{Row: row, Column: importFieldColumns[field], Message: message})
+	}
+	return rowErrors
+}
+
+// validateImportReferences checks that the locations, tracks, products and skis of the new tests exist and can be
+// used by the team. A track without a location gives the test the location of the track.
+func validateImportReferences(db *sql.DB, tests []*importedTest, team int) ([]ImportRowError, error) {
+	var rowErrors []ImportRowError
+	products := map[int]bool{}
+	skis := map[int]bool{}
+
+	for _, test := range tests {
+		if err, _ := validateTestLocation(db, &test.Test); err != nil {
+			column := "location_id"
+			if test.Test.TrackID != nil {
+				column = "track_id"
+			}
+			rowErrors = append(rowErrors, ImportRowError{Row: test.Rows[0].Line, Column: column,
+				Message: strings.TrimSuffix(err.Error(), fmt.Sprintf(", %d", 400))})
+		}
+
+		for i, rank := range test.Test.TestRanks {
+			if _, checked := products[rank.ProductID]; !checked {
+				var count int
+				err := db.QueryRow("SELECT COUNT(*) FROM products WHERE id = $1 AND (is_public OR testing_team = $2);",
+					rank.ProductID, team).Scan(&count)
+				if err != nil {
+					return nil, err
+				}
+				products[rank.ProductID] = count > 0
+			}
+			if !products[rank.ProductID] {
+				rowErrors = append(rowErrors, ImportRowError{Row: test.Rows[i].Line, Column: "product_id",
+					Message: fmt.Sprintf("product %d does not exist", rank.ProductID)})
+			}
+
+			if rank.SkiID == nil {
+				continue
+			}
+			if _, checked := skis[*rank.SkiID]; !checked {
+				found, err := access.IsTeamSki(db, *rank.SkiID, team)
+				if err != nil {
+					return nil, err
+				}
+				skis[*rank.SkiID] = found
+			}
+			if !skis[*rank.SkiID] {
+				rowErrors = append(rowErrors, ImportRowError{Row: test.Rows[i].Line, Column: "ski_id",
+					Message: fmt.Sprintf("ski %d does not exist", *rank.SkiID)})
+			}
+		}
+	}
+	return rowErrors, nil
+}
+
+// sortImportErrors orders the errors of an import by row.
+func sortImportErrors(rowErrors []ImportRowError) {
+	sort.SliceStable(rowErrors, func(i, j int) bool {
+		return rowErrors[i].Row < rowErrors[j].Row
+	})
+}
+
+// temperatureRowWarnings returns the temperature warnings of an imported test on the rows of the products.
+func temperatureRowWarnings(test *importedTest, warnings []TemperatureWarning) []ImportRowError {
+	var rowWarnings []ImportRowError
+	for _, warning := range warnings {
+		row := test.Rows[0].Line
+		for _, r := range test.Rows {
+			if r.Values["product_id"] == strconv.Itoa(warning.ProductID) {
+				row = r.Line
+				break
+			}
+		}
+		rowWarnings = append(rowWarnings, ImportRowError{Row: row, Column: "product_id", Message: warning.Message})
+	}
+	return rowWarnings
+}
@@ -0,0 +1,20 @@
+package testsHandler
+
+// TestImportReport is the response of a CSV import of tests.
+type TestImportReport struct {
+	DryRun  bool             `json:"dry_run"`
+	Rows    int              `json:"rows"`     // Number of result rows in the file.
+	Tests   int              `json:"tests"`    // Number of tests the rows make up.
+	TestIDs []int            `json:"test_ids"` // IDs of the created tests, empty for a dry run or a failed import.
+	Errors  []ImportRowError `json:"errors"`
+
+	// Products ranked outside their rated temperature range, which are errors for a team with a strict range.
+	Warnings []ImportRowError `json:"warnings"`
+}
+
+// ImportRowError is a problem with a row of an imported file. Row 1 is the header.
+type ImportRowError struct {
+	Row     int    `json:"row"`
+	Column  string `json:"column,omitempty"`
+	Message string `json:"message"`
+}
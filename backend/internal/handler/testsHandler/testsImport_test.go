@@ -0,0 +1,184 @@
+package testsHandler
+
+import (
+	"backend/internal/domain"
+	"backend/internal/utils"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const importHeader = "test_key,test_date,location,comment,sc_temperature,snow_type,ac_temperature,air_humidity,product_id,rank,distance_behind\n"
+
+func Test_readImportRows(t *testing.T) {
+	rows, rowErrors := readImportRows([]byte("\ufeffProduct_ID;Rank;Comment\n1;1;\"Cold; dry\"\n\n2;2;\n;;\n"))
+	assert.Empty(t, rowErrors)
+	assert.Equal(t, []importRow{
+		{Line: 2, Values: map[string]string{"product_id": "1", "rank": "1", "comment": "Cold; dry"}},
+		{Line: 4, Values: map[string]string{"product_id": "2", "rank": "2", "comment": ""}},
+	}, rows)
+
+	_, rowErrors = readImportRows([]byte("rank,colour\n1,red\n"))
+	assert.Equal(t, []ImportRowError{
+		{Row: 1, Column: "colour", Message: "unknown column"},
+		{Row: 1, Column: "product_id", Message: "missing column"},
+	}, rowErrors)
+
+	rows, rowErrors = readImportRows([]byte("product_id,rank\n1,1\n2\n3,3\n"))
+	assert.Len(t, rows, 2)
+	assert.Equal(t, []ImportRowError{{Row: 3, Message: "wrong number of fields"}}, rowErrors)
+
+	_, rowErrors = readImportRows([]byte("product_id,rank\n"))
+	assert.Equal(t, []ImportRowError{{Row: 1, Message: "the file has no rows"}}, rowErrors)
+}
+
+func Test_buildImportedTest(t *testing.T) {
+	rows, _ := readImportRows([]byte(importHeader +
+		"a,2025-01-12,Holmenkollen,Cold,\"-8,5\",A2,-5,80,1,1,0\n" +
+		"b,2025-01-13,Holmenkollen,Wet,1,W9,2,101,1,1,0\n" +
+		"a,,,,,,,,2,2,10\n" +
+		"a,2025-01-14,,,,,,,3,x,0\n"))
+	tests := groupImportRows(rows)
+	assert.Len(t, tests, 2)
+
+	rowErrors := buildImportedTest(tests[0])
+	assert.Equal(t, []ImportRowError{
+		{Row: 5, Column: "test_date", Message: "differs from row 2 of the same test"},
+		{Row: 5, Column: "rank", Message: "invalid integer x"},
+	}, rowErrors)
+	assert.Equal(t, float32(-8.5), tests[0].Test.SnowConditions.Temperature)
+	assert.Equal(t, time.Date(2025, 1, 12, 0, 0, 0, 0, time.UTC), tests[0].Test.Date)
+	assert.Equal(t, domain.TestCompleted, tests[0].Test.State)
+	assert.Len(t, tests[0].Test.TestRanks, 3)
+
+	assert.Empty(t, buildImportedTest(tests[1]))
+	assert.Equal(t, []ImportRowError{
+		{Row: 3, Column: "snow_type", Message: "failed the oneof rule (A1 A2 A3 A4 A5 FS NS IN IT TR)"},
+		{Row: 3, Column: "air_humidity", Message: "failed the lte rule (100)"},
+	}, validateImportedTest(tests[1], 1))
+}
+
+func TestTestsImportRequestPOST(t *testing.T) {
+	mockDB, mock := utils.InitMockDB(t)
+
+	validFile := importHeader +
+		"a,2025-01-12,Holmenkollen,Cold,-8,A2,-5,80,1,1,0\n" +
+		"a,,,,,,,,2,2,10\n"
+
+	referencesMock := func() {
+		mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM products WHERE id = \\$1 AND \\(is_public OR testing_team = \\$2\\);").
+			WithArgs(1, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM products WHERE id = \\$1 AND \\(is_public OR testing_team = \\$2\\);").
+			WithArgs(2, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	}
+
+	tests := []struct {
+		name         string
+		path         string
+		body         string
+		setupMocks   func()
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:         "Status OK - dry run report",
+			path:         "/tests/import?dry_run=true",
+			body:         validFile,
+			expectedCode: http.StatusOK,
+			expectedBody: `{"dry_run":true,"rows":2,"tests":1,"test_ids":[],"errors":[{"row":3,"column":"product_id","message":"product 2 does not exist"}],"warnings":[]}`,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				referencesMock()
+			},
+		},
+		{
+			name:         "Status bad request - invalid rows",
+			path:         "/tests/import",
+			body:         importHeader + "a,2025-01-12,Holmenkollen,,-8,B9,-5,80,1,1,0\n",
+			expectedCode: http.StatusBadRequest,
+			expectedBody: `"errors":[{"row":2,"column":"snow_type","message":"failed the oneof rule (A1 A2 A3 A4 A5 FS NS IN IT TR)"},` +
+				`{"row":2,"column":"comment","message":"failed the required rule"}]`,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+			},
+		},
+		{
+			name:         "Status bad request - invalid dry_run",
+			path:         "/tests/import?dry_run=maybe",
+			body:         validFile,
+			expectedCode: http.StatusBadRequest,
+			setupMocks:   func() {},
+		},
+		{
+			name:         "Status created",
+			path:         "/tests/import",
+			body:         importHeader + "a,2025-01-12,Holmenkollen,Cold,-8,A2,-5,80,1,1,0\n",
+			expectedCode: http.StatusCreated,
+			expectedBody: `"test_ids":[5]`,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM products").
+					WithArgs(1, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+				UserIDMock(mock)
+				mock.ExpectBegin()
+				mock.ExpectQuery("INSERT INTO snow_conditions").
+					WithArgs(float32(-8), "A2", "").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				mock.ExpectQuery("INSERT INTO air_conditions").
+					WithArgs(float32(-5), 80, "", "").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+				mock.ExpectQuery("INSERT INTO track_conditions").
+					WithArgs("", "").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+				mock.ExpectQuery("INSERT INTO tests").
+					WithArgs(time.Date(2025, 1, 12, 0, 0, 0, 0, time.UTC), "Holmenkollen", "Cold", 1, 3, 2,
+						sqlmock.AnyArg(), false, 1, nil, nil, "completed").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+				mock.ExpectExec("INSERT INTO test_state_changes").
+					WithArgs(5, domain.TestInProgress, domain.TestCompleted, 1, sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("SELECT type, low_temperature, high_temperature FROM products WHERE id = \\$1;").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"type", "low_temperature", "high_temperature"}).AddRow("solid", nil, nil))
+
+				mock.ExpectQuery("SELECT is_public FROM products WHERE id = \\$1").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"is_public"}).AddRow(true))
+				mock.ExpectExec("INSERT INTO test_ranks").
+					WithArgs(5, 1, 1, 0, sqlmock.AnyArg(), true, nil, false).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "text/csv")
+			req.Header.Set("Authorization", "Bearer mockToken")
+			rr := httptest.NewRecorder()
+
+			TestsHandler(mockDB).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedCode, rr.Code)
+			if tt.expectedBody != "" {
+				assert.Contains(t, rr.Body.String(), tt.expectedBody)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
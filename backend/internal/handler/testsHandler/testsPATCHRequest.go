@@ -3,7 +3,7 @@ package testsHandler
 import "time"
 
 type TestPATCHRequest struct {
-	Updates map[string]interface{} `json:"updates" validate:"required,dive,keys,oneof=product_id rank distance_behind is_rank_public track_hardness track_type ac_temperature air_humidity wind cloud sc_temperature snow_type snow_humidity location test_date comment is_public,endkeys"`
+	Updates map[string]interface{} `json:"updates" validate:"required,dive,keys,oneof=product_id rank distance_behind is_rank_public ski_id track_hardness track_type ac_temperature air_humidity wind cloud sc_temperature snow_type snow_humidity location location_id track_id test_date comment is_public state,endkeys"`
 	Version time.Time              `json:"version"`
 }
 
@@ -12,6 +12,7 @@ type TestUpdateFields struct {
 	Rank           *int       `json:"rank" validate:"omitempty,gt=0"`
 	DistanceBehind *int       `json:"distance_behind" validate:"omitempty,gte=0"`
 	IsRankPublic   *bool      `json:"is_rank_public" validate:"omitempty,oneof=true false"`
+	SkiID          *int       `json:"ski_id" validate:"omitempty,gt=0"`
 	SCTemperature  *float32   `json:"sc_temperature" validate:"omitempty,lte=100,gte=-100"`
 	SnowType       *string    `json:"snow_type" validate:"omitempty,oneof=A1 A2 A3 A4 A5 FS NS IN IT TR"`
 	SnowHumidity   *string    `json:"snow_humidity" validate:"omitempty,oneof=DS W1 W2 W3 W4"`
@@ -22,22 +23,29 @@ type TestUpdateFields struct {
 	TrackHardness  *string    `json:"track_hardness" validate:"omitempty,oneof=H1 H2 H3 H4 H5 H6"`
 	TrackType      *string    `json:"track_type" validate:"omitempty,oneof=T1 T2 D1 D2"`
 	Location       *string    `json:"location" validate:"omitempty,lte=256,ascii"`
+	LocationID     *int       `json:"location_id" validate:"omitempty,gt=0"`
+	TrackID        *int       `json:"track_id" validate:"omitempty,gt=0"`
 	Date           *time.Time `json:"test_date" validate:"omitempty"` //TODO: Need validation for date format
 	Comment        *string    `json:"comment" validate:"omitempty,max=2040"`
 	IsPublic       *bool      `json:"is_public" validate:"omitempty,oneof=true false"`
+	State          *string    `json:"state" validate:"omitempty,oneof=planned in_progress completed published"`
 }
 
 var validTestFields = map[string]bool{
-	"location":  true,
-	"test_date": true,
-	"comment":   true,
-	"is_public": true,
+	"location":    true,
+	"location_id": true,
+	"track_id":    true,
+	"test_date":   true,
+	"comment":     true,
+	"is_public":   true,
+	"state":       true,
 }
 
 var validRankFields = map[string]bool{
 	"rank":            true,
 	"distance_behind": true,
 	"is_rank_public":  true,
+	"ski_id":          true,
 }
 
 var validACFields = map[string]bool{
@@ -1,6 +1,7 @@
 package testsHandler
 
 import (
+	"backend/internal/domain"
 	"time"
 )
 
@@ -8,10 +9,13 @@ type TestPOSTRequest struct {
 	SnowConditions  SnowConditionsPOST  `json:"sc" validate:"required"`
 	AirConditions   AirConditionsPOST   `json:"ac" validate:"required"`
 	TrackConditions TrackConditionsPOST `json:"tc" validate:"required"`
-	Location        string              `json:"location" validate:"required,lte=256,ascii"`
+	Location        string              `json:"location" validate:"required_without=LocationID,lte=256,ascii"`
+	LocationID      *int                `json:"location_id" validate:"omitempty,gt=0"`
+	TrackID         *int                `json:"track_id" validate:"omitempty,gt=0"`
 	Date            time.Time           `json:"test_date" validate:"omitempty"` //TODO: Need validation for date format
 	Comment         string              `json:"comment" validate:"required,max=2040"`
 	IsPublic        bool                `json:"is_public" validate:"omitempty,oneof=true false"`
+	State           domain.TestState    `json:"state" validate:"omitempty,oneof=planned in_progress completed"` // Defaults to in_progress.
 	TestingTeam     int                 `json:"testing_team"`
 	TestRanks       []TestRanksPOST     `json:"test_ranks" validate:"required,dive"`
 }
@@ -21,6 +25,7 @@ type TestRanksPOST struct {
 	Rank           int  `json:"rank" validate:"omitempty,gt=0"`
 	DistanceBehind int  `json:"distance_behind" validate:"omitempty,gte=0"`
 	IsRankPublic   bool `json:"is_rank_public" validate:"omitempty,oneof=true false"`
+	SkiID          *int `json:"ski_id" validate:"omitempty,gt=0"` // The ski pair the product was tested on.
 }
 
 type SnowConditionsPOST struct {
@@ -0,0 +1,85 @@
+package testsHandler
+
+import (
+	"backend/internal/middleware"
+	"backend/internal/resources"
+	"backend/internal/services/similarity"
+	"backend/internal/utils"
+	"database/sql"
+	"encoding/json"
+	"log"
+	"net/http"
+	"regexp"
+)
+
+var similarPath = regexp.MustCompile(`^/tests/similar/?$`)
+
+// TestsSimilarRequestPOST is the request handler for finding the tests with the closest conditions.
+//
+//	@Summary		Find the tests with the closest conditions
+//	@Description	Retrieves the completed and published tests whose conditions are closest to the given ones,
+//	@Description	closest first, with the winning products of each test. The distance is a weighted distance over
+//	@Description	the snow and air temperatures, the air humidity and the levels of the snow type, snow humidity,
+//	@Description	wind, cloud, track hardness and track type, where each level is placed evenly between the first
+//	@Description	and the last level. Conditions that are left out are not compared. The weights replace the
+//	@Description	default weights, which weigh the snow the most, and a weight of 0 ignores a condition. The tests
+//	@Description	are those of the team together with the public tests, or only the public tests.
+//	@Tags			Tests
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		SimilarTestsPOSTRequest	true	"The conditions"
+//	@Success		200		{array}		SimilarTest				"The closest tests"
+//	@Failure		400		{string}	string					"Invalid POST request body"
+//	@Failure		500		{string}	string					"Could not retrieve the tests."
+//	@Router			/tests/similar [post]
+func TestsSimilarRequestPOST(w http.ResponseWriter, r *http.Request, db *sql.DB) {
+	// Get the user's team membership.
+	team := middleware.GetUserTeamRole(w, r, db)
+
+	request, err := utils.ParseAndValidateRequest[SimilarTestsPOSTRequest](r)
+	if err != nil {
+		http.Error(w, resources.InvalidPOSTRequest, http.StatusBadRequest)
+		log.Println(resources.InvalidPOSTRequest + ": " + err.Error())
+		return
+	}
+	if request.conditions() == (similarity.Conditions{}) {
+		http.Error(w, "No conditions to compare with.", http.StatusBadRequest)
+		log.Println("No conditions to compare with")
+		return
+	}
+
+	weights := similarity.DefaultWeights
+	if request.Weights != nil {
+		weights = *request.Weights
+	}
+	if request.Limit == 0 {
+		request.Limit = defaultSimilarTestsLimit
+	}
+
+	tests, err := getSimilarTestCandidates(db, team, request.Public)
+	if err != nil {
+		http.Error(w, "Could not retrieve the tests.", http.StatusInternalServerError)
+		log.Println("Could not retrieve the tests: " + err.Error())
+		return
+	}
+
+	tests = closestTests(tests, request.conditions(), weights, request.Limit)
+	for i := range tests {
+		tests[i].Winners, err = getTestWinners(db, tests[i].ID, team)
+		if err != nil {
+			http.Error(w, "Could not retrieve the tests.", http.StatusInternalServerError)
+			log.Printf("Could not retrieve the winners of test %d: %s", tests[i].ID, err.Error())
+			return
+		}
+	}
+
+	if tests == nil {
+		tests = []SimilarTest{}
+	}
+	err = json.NewEncoder(w).Encode(tests)
+	if err != nil {
+		http.Error(w, "Could not encode the tests.", http.StatusInternalServerError)
+		log.Println("Could not encode the tests: " + err.Error())
+	}
+}
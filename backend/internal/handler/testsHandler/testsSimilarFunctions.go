@@ -0,0 +1,115 @@
+package testsHandler
+
+import (
+	"backend/internal/domain"
+	"backend/internal/services/similarity"
+	"database/sql"
+	"sort"
+)
+
+const defaultSimilarTestsLimit = 20
+
+// getSimilarTestCandidates retrieves the completed and published tests of the team and the public tests, or only
+// the public tests, with their conditions.
+func getSimilarTestCandidates(db *sql.DB, team int, public bool) ([]SimilarTest, error) {
+	rows, err := db.Query(`SELECT t.id, t.test_date, t.location, t.is_public, t.testing_team, t.state,
+       							sc.id, sc.temperature, sc.snow_type, sc.snow_humidity,
+       							ac.id, ac.temperature, ac.humidity, ac.wind, ac.cloud,
+       							tc.id, tc.track_hardness, tc.track_type
+							FROM tests t
+							JOIN snow_conditions sc ON sc.id = t.sc_id
+							JOIN air_conditions ac ON ac.id = t.ac_id
+							JOIN track_conditions tc ON tc.id = t.tc_id
+							WHERE t.state IN `+domain.ReportableStatesSQL+`
+							  AND ((t.testing_team = $1 AND NOT $2) OR t.is_public);`, team, public)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tests []SimilarTest
+	for rows.Next() {
+		var test SimilarTest
+		if err = rows.Scan(&test.ID, &test.Date, &test.Location, &test.IsPublic, &test.TestingTeam, &test.State,
+			&test.SnowConditions.ID, &test.SnowConditions.Temperature, &test.SnowConditions.SnowType,
+			&test.SnowConditions.SnowHumidity, &test.AirConditions.ID, &test.AirConditions.Temperature,
+			&test.AirConditions.Humidity, &test.AirConditions.Wind, &test.AirConditions.Cloud,
+			&test.TrackConditions.ID, &test.TrackConditions.TrackHardness, &test.TrackConditions.TrackType,
+		); err != nil {
+			return nil, err
+		}
+		test.Winners = []SimilarTestWinner{}
+		tests = append(tests, test)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return tests, nil
+}
+
+// testConditions returns the conditions of a test to compare with.
+func testConditions(test SimilarTest) similarity.Conditions {
+	snowTemperature := float64(test.SnowConditions.Temperature)
+	airTemperature := float64(test.AirConditions.Temperature)
+	airHumidity := float64(test.AirConditions.Humidity)
+	return similarity.Conditions{
+		SnowTemperature: &snowTemperature,
+		SnowType:        test.SnowConditions.SnowType,
+		SnowHumidity:    test.SnowConditions.SnowHumidity,
+		AirTemperature:  &airTemperature,
+		AirHumidity:     &airHumidity,
+		Wind:            test.AirConditions.Wind,
+		Cloud:           test.AirConditions.Cloud,
+		TrackHardness:   test.TrackConditions.TrackHardness,
+		TrackType:       test.TrackConditions.TrackType,
+	}
+}
+
+// closestTests sets the distance of the tests to the conditions and returns the closest tests, closest first and
+// the most recent first at the same distance.
+func closestTests(tests []SimilarTest, conditions similarity.Conditions, weights similarity.Weights,
+	limit int) []SimilarTest {
+	for i := range tests {
+		tests[i].Distance = similarity.Distance(conditions, testConditions(tests[i]), weights)
+	}
+
+	sort.SliceStable(tests, func(i, j int) bool {
+		if tests[i].Distance != tests[j].Distance {
+			return tests[i].Distance < tests[j].Distance
+		}
+		if !tests[i].Date.Equal(tests[j].Date) {
+			return tests[i].Date.After(tests[j].Date)
+		}
+		return tests[i].ID < tests[j].ID
+	})
+	if len(tests) > limit {
+		tests = tests[:limit]
+	}
+	return tests
+}
+
+// getTestWinners retrieves the products ranked first in a test. The rankings of another team are only included when
+// they are public, and the private products of another team are left out.
+func getTestWinners(db *sql.DB, testID int, team int) ([]SimilarTestWinner, error) {
+	rows, err := db.Query(`SELECT r.product_id, p.name, COALESCE(p.brand, '')
+							FROM test_ranks r
+							JOIN tests t ON t.id = r.test_id
+							JOIN products p ON p.id = r.product_id
+							WHERE r.test_id = $1 AND r.rank = 1 AND (r.is_rank_public OR t.testing_team = $2)
+							  AND (p.is_public OR p.testing_team = $2)
+							ORDER BY r.product_id;`, testID, team)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	winners := []SimilarTestWinner{}
+	for rows.Next() {
+		var winner SimilarTestWinner
+		if err = rows.Scan(&winner.ProductID, &winner.Name, &winner.Brand); err != nil {
+			return nil, err
+		}
+		winners = append(winners, winner)
+	}
+	return winners, rows.Err()
+}
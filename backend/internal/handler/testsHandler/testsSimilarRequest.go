@@ -0,0 +1,35 @@
+package testsHandler
+
+import "backend/internal/services/similarity"
+
+// SimilarTestsPOSTRequest are the conditions to find the closest tests to. Conditions that are left out are not
+// compared.
+type SimilarTestsPOSTRequest struct {
+	SnowTemperature *float64            `json:"snow_temperature" validate:"omitempty,lte=100,gte=-100"`
+	SnowType        string              `json:"snow_type" validate:"omitempty,oneof=A1 A2 A3 A4 A5 FS NS IN IT TR"`
+	SnowHumidity    string              `json:"snow_humidity" validate:"omitempty,oneof=DS W1 W2 W3 W4"`
+	AirTemperature  *float64            `json:"air_temperature" validate:"omitempty,lte=100,gte=-100"`
+	AirHumidity     *float64            `json:"air_humidity" validate:"omitempty,lte=100,gte=0"`
+	Wind            string              `json:"wind" validate:"omitempty,oneof=S L M ST"`
+	Cloud           string              `json:"cloud" validate:"omitempty,oneof=1 2 3 4"`
+	TrackHardness   string              `json:"track_hardness" validate:"omitempty,oneof=H1 H2 H3 H4 H5 H6"`
+	TrackType       string              `json:"track_type" validate:"omitempty,oneof=T1 T2 D1 D2"`
+	Weights         *similarity.Weights `json:"weights" validate:"omitempty"`            // Replaces the default weights.
+	Public          bool                `json:"public"`                                  // Only public tests.
+	Limit           int                 `json:"limit" validate:"omitempty,gt=0,lte=100"` // Defaults to 20.
+}
+
+// conditions returns the conditions of the request.
+func (request SimilarTestsPOSTRequest) conditions() similarity.Conditions {
+	return similarity.Conditions{
+		SnowTemperature: request.SnowTemperature,
+		SnowType:        request.SnowType,
+		SnowHumidity:    request.SnowHumidity,
+		AirTemperature:  request.AirTemperature,
+		AirHumidity:     request.AirHumidity,
+		Wind:            request.Wind,
+		Cloud:           request.Cloud,
+		TrackHardness:   request.TrackHardness,
+		TrackType:       request.TrackType,
+	}
+}
@@ -0,0 +1,28 @@
+package testsHandler
+
+import (
+	"backend/internal/domain"
+	"time"
+)
+
+// SimilarTest is a test with conditions close to the given conditions.
+type SimilarTest struct {
+	ID              int                    `json:"id"`
+	Date            time.Time              `json:"test_date"`
+	Location        string                 `json:"location"`
+	IsPublic        bool                   `json:"is_public"`
+	TestingTeam     int                    `json:"testing_team"`
+	State           domain.TestState       `json:"state"`
+	Distance        float64                `json:"distance"` // 0 for the same conditions, about 1 for opposite ones.
+	SnowConditions  domain.SnowConditions  `json:"sc"`
+	AirConditions   domain.AirConditions   `json:"ac"`
+	TrackConditions domain.TrackConditions `json:"tc"`
+	Winners         []SimilarTestWinner    `json:"winners"`
+}
+
+// SimilarTestWinner is a product that won a test, together with any products it tied with.
+type SimilarTestWinner struct {
+	ProductID int    `json:"product_id"`
+	Name      string `json:"name"`
+	Brand     string `json:"brand"`
+}
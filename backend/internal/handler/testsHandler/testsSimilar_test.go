@@ -0,0 +1,152 @@
+package testsHandler
+
+import (
+	"backend/internal/domain"
+	"backend/internal/services/similarity"
+	"backend/internal/utils"
+	"bytes"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+var similarTestColumns = []string{
+	"id", "test_date", "location", "is_public", "testing_team", "state",
+	"id", "temperature", "snow_type", "snow_humidity",
+	"id", "temperature", "humidity", "wind", "cloud",
+	"id", "track_hardness", "track_type",
+}
+
+func Test_closestTests(t *testing.T) {
+	older := time.Date(2024, 1, 12, 0, 0, 0, 0, time.UTC)
+	newer := time.Date(2025, 1, 12, 0, 0, 0, 0, time.UTC)
+	test := func(id int, date time.Time, temperature float32, snowType string) SimilarTest {
+		return SimilarTest{
+			ID:              id,
+			Date:            date,
+			SnowConditions:  domain.SnowConditions{Temperature: temperature, SnowType: snowType, SnowHumidity: "DS"},
+			AirConditions:   domain.AirConditions{Temperature: -5, Humidity: 80, Wind: "L", Cloud: "2"},
+			TrackConditions: domain.TrackConditions{TrackHardness: "H3", TrackType: "T1"},
+		}
+	}
+	tests := []SimilarTest{
+		test(1, older, -7, "A2"),
+		test(2, older, -8, "A2"),
+		test(3, newer, -8, "A2"),
+		test(4, newer, -8, "A5"),
+	}
+	temperature := -8.0
+	conditions := similarity.Conditions{SnowTemperature: &temperature, SnowType: "A2"}
+
+	closest := closestTests(tests, conditions, similarity.DefaultWeights, 3)
+	assert.Len(t, closest, 3)
+	assert.Equal(t, []int{3, 2, 1}, []int{closest[0].ID, closest[1].ID, closest[2].ID})
+	assert.Equal(t, 0.0, closest[0].Distance)
+	assert.Greater(t, closest[2].Distance, 0.0)
+
+	// Ignoring the snow type makes the test on other snow as close as the others.
+	closest = closestTests(tests, conditions, similarity.Weights{SnowTemperature: 1}, 10)
+	assert.Equal(t, []int{3, 4, 2, 1}, []int{closest[0].ID, closest[1].ID, closest[2].ID, closest[3].ID})
+	assert.InDelta(t, 0.1, closest[3].Distance, 1e-6)
+}
+
+func TestTestsSimilarRequestPOST(t *testing.T) {
+	mockDB, mock := utils.InitMockDB(t)
+
+	date := time.Date(2025, 1, 12, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		body         string
+		setupMocks   func()
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:         "Status OK",
+			body:         `{"snow_temperature": -8, "snow_type": "A2", "weights": {"snow_temperature": 1}, "limit": 1}`,
+			expectedCode: http.StatusOK,
+			expectedBody: `[{"id":2,"test_date":"2025-01-12T00:00:00Z","location":"Holmenkollen","is_public":true,` +
+				`"testing_team":2,"state":"published","distance":0.1,` +
+				`"sc":{"id":5,"temperature":-7,"snow_type":"FS","snow_humidity":"W1"},` +
+				`"ac":{"id":6,"temperature":-3,"humidity":85,"wind":"S","cloud":"4"},` +
+				`"tc":{"id":7,"track_hardness":"H2","track_type":"D1"},` +
+				`"winners":[{"product_id":3,"name":"Blue Wax","brand":"Swix"}]}]`,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				mock.ExpectQuery("SELECT t.id, t.test_date, .* FROM tests t .* WHERE t.state IN \\('completed', 'published'\\) "+
+					"AND \\(\\(t.testing_team = \\$1 AND NOT \\$2\\) OR t.is_public\\);").
+					WithArgs(1, false).
+					WillReturnRows(sqlmock.NewRows(similarTestColumns).
+						AddRow(1, date, "Sjusjøen", false, 1, "completed", 1, -2.0, "A2", "DS", 2, -5, 80, "L", "2", 3, "H3", "T1").
+						AddRow(2, date, "Holmenkollen", true, 2, "published", 5, -7.0, "FS", "W1", 6, -3, 85, "S", "4", 7, "H2", "D1"))
+				mock.ExpectQuery("SELECT r.product_id, p.name, COALESCE\\(p.brand, ''\\) FROM test_ranks r .* "+
+					"WHERE r.test_id = \\$1 AND r.rank = 1 AND \\(r.is_rank_public OR t.testing_team = \\$2\\) "+
+					"AND \\(p.is_public OR p.testing_team = \\$2\\)").
+					WithArgs(2, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"product_id", "name", "brand"}).AddRow(3, "Blue Wax", "Swix"))
+			},
+		},
+		{
+			name:         "Status OK - no tests",
+			body:         `{"track_hardness": "H4", "public": true}`,
+			expectedCode: http.StatusOK,
+			expectedBody: `[]`,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+				mock.ExpectQuery("SELECT t.id, t.test_date, .* FROM tests t").
+					WithArgs(1, true).
+					WillReturnRows(sqlmock.NewRows(similarTestColumns))
+			},
+		},
+		{
+			name:         "Status bad request - no conditions",
+			body:         `{"limit": 5}`,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "No conditions to compare with.",
+			setupMocks: func() {
+				AuthenticationMock(mock)
+			},
+		},
+		{
+			name:         "Status bad request - negative weight",
+			body:         `{"snow_type": "A2", "weights": {"snow_type": -1}}`,
+			expectedCode: http.StatusBadRequest,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+			},
+		},
+		{
+			name:         "Status bad request - invalid wind",
+			body:         `{"wind": "X"}`,
+			expectedCode: http.StatusBadRequest,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+
+			req := httptest.NewRequest(http.MethodPost, "/tests/similar", bytes.NewBufferString(tt.body))
+			req.Header.Set("Authorization", "Bearer mockToken")
+			rr := httptest.NewRecorder()
+
+			TestsHandler(mockDB).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedCode, rr.Code)
+			if tt.expectedBody != "" {
+				assert.Contains(t, rr.Body.String(), tt.expectedBody)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
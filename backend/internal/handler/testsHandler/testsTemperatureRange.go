@@ -0,0 +1,201 @@
+package testsHandler
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// errOutOfTemperatureRange is returned when a team with a strict temperature range ranks a product outside its range.
+var errOutOfTemperatureRange = errors.New("products were tested outside their rated temperature range")
+
+// TemperatureWarning is a ranked product whose rated temperature range does not cover the temperatures of the test.
+type TemperatureWarning struct {
+	ProductID       int      `json:"product_id"`
+	LowTemperature  *float64 `json:"low_temperature"`
+	HighTemperature *float64 `json:"high_temperature"`
+	SnowTemperature float64  `json:"snow_temperature"`
+	AirTemperature  float64  `json:"air_temperature"`
+	Message         string   `json:"message"`
+}
+
+// TestTemperatureResponse is the response to a test that was created, or refused, with products tested outside
+// their rated temperature range.
+type TestTemperatureResponse struct {
+	Message  string               `json:"message"`
+	TestID   int                  `json:"test_id,omitempty"`
+	Warnings []TemperatureWarning `json:"warnings"`
+}
+
+// testTemperatures are the temperatures of a test the temperature ranges of the products are compared with.
+type testTemperatures struct {
+	Snow float64
+	Air  float64
+}
+
+// temperatureRange is the rated temperature range of a product. A missing bound leaves that side open.
+type temperatureRange struct {
+	Type string
+	Low  *float64
+	High *float64
+}
+
+// covers reports whether the range covers a temperature.
+func (r temperatureRange) covers(temperature float64) bool {
+	return (r.Low == nil || temperature >= *r.Low) && (r.High == nil || temperature <= *r.High)
+}
+
+// checkTemperatureRange returns a warning when the range of a product does not cover the snow or air temperature of
+// a test, or nil when it does. The range of a bundle is the window all of its layers are rated for.
+func checkTemperatureRange(productID int, productRange temperatureRange, temperatures testTemperatures,
+) *TemperatureWarning {
+	var outside []string
+	if !productRange.covers(temperatures.Snow) {
+		outside = append(outside, fmt.Sprintf("snow temperature %g °C", temperatures.Snow))
+	}
+	if !productRange.covers(temperatures.Air) {
+		outside = append(outside, fmt.Sprintf("air temperature %g °C", temperatures.Air))
+	}
+	if len(outside) == 0 {
+		return nil
+	}
+
+	return &TemperatureWarning{
+		ProductID:       productID,
+		LowTemperature:  productRange.Low,
+		HighTemperature: productRange.High,
+		SnowTemperature: temperatures.Snow,
+		AirTemperature:  temperatures.Air,
+		Message: fmt.Sprintf("product %d is rated for %s, but was tested at %s", productID,
+			formatTemperatureRange(productRange), strings.Join(outside, " and ")),
+	}
+}
+
+// formatTemperatureRange formats a range such as "-10 °C to -2 °C".
+func formatTemperatureRange(productRange temperatureRange) string {
+	switch {
+	case productRange.Low == nil:
+		return fmt.Sprintf("up to %g °C", *productRange.High)
+	case productRange.High == nil:
+		return fmt.Sprintf("%g °C and above", *productRange.Low)
+	}
+	return fmt.Sprintf("%g °C to %g °C", *productRange.Low, *productRange.High)
+}
+
+// getTemperatureRange retrieves the rated temperature range of a product.
+func getTemperatureRange(tx *sql.Tx, productID int) (temperatureRange, error) {
+	var productRange temperatureRange
+	err := tx.QueryRow("SELECT type, low_temperature, high_temperature FROM products WHERE id = $1;", productID).
+		Scan(&productRange.Type, &productRange.Low, &productRange.High)
+	return productRange, err
+}
+
+// isStrictTemperatureRange reports whether a team refuses results outside the rated temperature range of a product.
+func isStrictTemperatureRange(tx *sql.Tx, team int) (bool, error) {
+	var strict bool
+	err := tx.QueryRow("SELECT strict_temperature_range FROM team WHERE id = $1;", team).Scan(&strict)
+	return strict, err
+}
+
+// checkStrictTemperatureRange returns errOutOfTemperatureRange when there are warnings and the team refuses them.
+func checkStrictTemperatureRange(tx *sql.Tx, team int, warnings int) error {
+	if warnings == 0 {
+		return nil
+	}
+	strict, err := isStrictTemperatureRange(tx, team)
+	if err != nil {
+		return fmt.Errorf("failed to get the temperature range setting of the team: %w", err)
+	}
+	if strict {
+		return errOutOfTemperatureRange
+	}
+	return nil
+}
+
+// RefuseOutOfTemperatureRange responds with the warnings and returns an error when the team refuses results outside
+// the rated temperature range of the products, so the transaction is rolled back.
+func RefuseOutOfTemperatureRange(w http.ResponseWriter, tx *sql.Tx, team int, warnings []TemperatureWarning) error {
+	err := checkStrictTemperatureRange(tx, team, len(warnings))
+	if errors.Is(err, errOutOfTemperatureRange) {
+		writeTemperatureResponse(w, http.StatusBadRequest, TestTemperatureResponse{
+			Message:  "Validation error: " + err.Error(),
+			Warnings: warnings,
+		})
+		log.Println("Validation error: " + err.Error())
+	} else if err != nil {
+		http.Error(w, "Could not check the temperature ranges.", http.StatusInternalServerError)
+		log.Println("Could not check the temperature ranges: " + err.Error())
+	}
+	return err
+}
+
+// writeTemperatureResponse writes a response with the temperature warnings of a test.
+func writeTemperatureResponse(w http.ResponseWriter, code int, response TestTemperatureResponse) {
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Println("Could not JSON encode the response: " + err.Error())
+	}
+}
+
+// UpdateTemperatureRangeFlags checks the ranked products of a test against its current temperatures, and updates
+// the flags of the results that changed. It is called in the transaction of every change to the results of a test,
+// its temperatures or the range of a ranked product.
+func UpdateTemperatureRangeFlags(tx *sql.Tx, testID int) ([]TemperatureWarning, error) {
+	rows, err := tx.Query(`SELECT r.product_id, r.out_of_temperature_range, p.type, p.low_temperature,
+       							p.high_temperature, sc.temperature, ac.temperature
+							FROM test_ranks r
+							JOIN tests t ON t.id = r.test_id
+							JOIN snow_conditions sc ON sc.id = t.sc_id
+							JOIN air_conditions ac ON ac.id = t.ac_id
+							JOIN products p ON p.id = r.product_id
+							WHERE r.test_id = $1
+							ORDER BY r.product_id;`, testID)
+	if err != nil {
+		return nil, err
+	}
+
+	type flag struct {
+		productID int
+		value     bool
+	}
+	var changed []flag
+	warnings := []TemperatureWarning{}
+	for rows.Next() {
+		var (
+			productID    int
+			flagged      bool
+			productRange temperatureRange
+			temperatures testTemperatures
+		)
+		if err = rows.Scan(&productID, &flagged, &productRange.Type, &productRange.Low, &productRange.High,
+			&temperatures.Snow, &temperatures.Air); err != nil {
+			_ = rows.Close()
+			return nil, err
+		}
+
+		warning := checkTemperatureRange(productID, productRange, temperatures)
+		if warning != nil {
+			warnings = append(warnings, *warning)
+		}
+		if flagged != (warning != nil) {
+			changed = append(changed, flag{productID, warning != nil})
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	_ = rows.Close()
+
+	for _, f := range changed {
+		_, err = tx.Exec("UPDATE test_ranks SET out_of_temperature_range = $1 WHERE test_id = $2 AND product_id = $3;",
+			f.value, testID, f.productID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return warnings, nil
+}
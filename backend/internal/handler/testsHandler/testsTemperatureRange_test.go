@@ -0,0 +1,150 @@
+package testsHandler
+
+import (
+	"backend/internal/utils"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func temperature(value float64) *float64 {
+	return &value
+}
+
+func Test_checkTemperatureRange(t *testing.T) {
+	temperatures := testTemperatures{Snow: -8, Air: -5}
+
+	tests := []struct {
+		name         string
+		productRange temperatureRange
+		wantMessage  string
+	}{
+		{
+			name:         "In range",
+			productRange: temperatureRange{Type: "solid", Low: temperature(-10), High: temperature(-2)},
+		},
+		{
+			name:         "No range",
+			productRange: temperatureRange{Type: "solid"},
+		},
+		{
+			name:         "Bundle outside the window of its layers",
+			productRange: temperatureRange{Type: "bundle", Low: temperature(-12), High: temperature(-6)},
+			wantMessage:  "product 1 is rated for -12 °C to -6 °C, but was tested at air temperature -5 °C",
+		},
+		{
+			name:         "Bundle without a window",
+			productRange: temperatureRange{Type: "bundle"},
+		},
+		{
+			name:         "Snow outside the range",
+			productRange: temperatureRange{Type: "solid", Low: temperature(-6), High: temperature(0)},
+			wantMessage:  "product 1 is rated for -6 °C to 0 °C, but was tested at snow temperature -8 °C",
+		},
+		{
+			name:         "Snow and air outside an open range",
+			productRange: temperatureRange{Type: "liquid", Low: temperature(-4)},
+			wantMessage: "product 1 is rated for -4 °C and above, but was tested at snow temperature -8 °C and " +
+				"air temperature -5 °C",
+		},
+		{
+			name:         "Air outside an open range",
+			productRange: temperatureRange{Type: "solid", High: temperature(-6)},
+			wantMessage:  "product 1 is rated for up to -6 °C, but was tested at air temperature -5 °C",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warning := checkTemperatureRange(1, tt.productRange, temperatures)
+			if tt.wantMessage == "" {
+				assert.Nil(t, warning)
+				return
+			}
+			if assert.NotNil(t, warning) {
+				assert.Equal(t, tt.wantMessage, warning.Message)
+				assert.Equal(t, 1, warning.ProductID)
+			}
+		})
+	}
+}
+
+func TestRefuseOutOfTemperatureRange(t *testing.T) {
+	warnings := []TemperatureWarning{{ProductID: 1, SnowTemperature: -8, AirTemperature: -5, Message: "outside"}}
+
+	tests := []struct {
+		name         string
+		warnings     []TemperatureWarning
+		setupMocks   func(mock sqlmock.Sqlmock)
+		wantErr      bool
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:         "No warnings",
+			warnings:     []TemperatureWarning{},
+			setupMocks:   func(mock sqlmock.Sqlmock) {},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:     "Team allows warnings",
+			warnings: warnings,
+			setupMocks: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT strict_temperature_range FROM team WHERE id = \\$1;").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"strict_temperature_range"}).AddRow(false))
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:     "Team refuses warnings",
+			warnings: warnings,
+			setupMocks: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT strict_temperature_range FROM team WHERE id = \\$1;").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"strict_temperature_range"}).AddRow(true))
+			},
+			wantErr:      true,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: `"warnings":[{"product_id":1,"low_temperature":null,"high_temperature":null,` +
+				`"snow_temperature":-8,"air_temperature":-5,"message":"outside"}]`,
+		},
+		{
+			name:     "Team not found",
+			warnings: warnings,
+			setupMocks: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT strict_temperature_range FROM team WHERE id = \\$1;").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"strict_temperature_range"}))
+			},
+			wantErr:      true,
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: "Could not check the temperature ranges.",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB, mock := utils.InitMockDB(t)
+			mock.ExpectBegin()
+			tt.setupMocks(mock)
+
+			tx, err := mockDB.Begin()
+			if err != nil {
+				t.Fatalf("failed to begin transaction: %v", err)
+			}
+
+			rr := httptest.NewRecorder()
+			err = RefuseOutOfTemperatureRange(rr, tx, 1, tt.warnings)
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.expectedCode, rr.Code)
+			if tt.expectedBody != "" {
+				assert.Contains(t, rr.Body.String(), tt.expectedBody)
+			}
+
+			if err = mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
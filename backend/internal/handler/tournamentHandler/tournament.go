@@ -0,0 +1,392 @@
+package tournamentHandler
+
+import (
+	"backend/internal/domain"
+	"backend/internal/handler/testsHandler"
+	"backend/internal/middleware"
+	"backend/internal/resources"
+	"backend/internal/services/access"
+	"backend/internal/utils"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+var tournamentPath = regexp.MustCompile(`^/tests/(\d+)/tournament/?$`)
+var matchPath = regexp.MustCompile(`^/tests/(\d+)/tournament/matches/(\d+)$`)
+
+// TournamentHandler routes HTTP requests for head-to-head tournaments to the appropriate handler function.
+//
+// It supports the following methods:
+// - GET: Retrieves the bracket of a test.
+// - POST: Generates a new bracket from the products in a test.
+// - PATCH: Records both battles of a match and advances the winner.
+//
+// Each method has its own dedicated request handler function, which is documented separately using Swagger annotations.
+func TournamentHandler(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		switch r.Method {
+		case http.MethodGet:
+			TournamentRequestGET(w, r, db)
+		case http.MethodPost:
+			TournamentRequestPOST(w, r, db)
+		case http.MethodPatch:
+			TournamentRequestPATCH(w, r, db)
+		default:
+			http.Error(w, resources.MethodNotAllowed, http.StatusNotImplemented)
+			return
+		}
+	}
+}
+
+// TournamentRequestGET retrieves the tournament of a test.
+//
+//	@Summary		Get the tournament of a test
+//	@Description	Retrieves the head-to-head bracket of a test, grouped by round.
+//	@Tags			Tournaments
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			test_id	path		int					true	"Test ID"
+//	@Success		200		{object}	domain.Tournament	"Successful response with the tournament"
+//	@Failure		400		{string}	string				"Invalid request URL"
+//	@Failure		401		{string}	string				"Unauthorized"
+//	@Failure		404		{string}	string				"No tournament found for this test."
+//	@Failure		500		{string}	string				"Could not retrieve the tournament."
+//	@Router			/tests/{test_id}/tournament [get]
+func TournamentRequestGET(w http.ResponseWriter, r *http.Request, db *sql.DB) {
+	matches := tournamentPath.FindStringSubmatch(r.URL.Path)
+	if len(matches) != 2 {
+		http.Error(w, "Invalid request URL, use '/tests/{test_id}/tournament'.", http.StatusBadRequest)
+		log.Println("Invalid request URL: " + r.URL.Path)
+		return
+	}
+	testID, _ := strconv.Atoi(matches[1])
+
+	// Get the user's team membership.
+	team := middleware.GetUserTeamRole(w, r, db)
+
+	// Check that the test is visible for the user's team.
+	testingTeam, isPublic, _, err := access.GetTestAccess(db, testID)
+	if err != nil {
+		http.Error(w, "Could not retrieve the test.", http.StatusNotFound)
+		log.Println("Could not retrieve the test: " + err.Error())
+		return
+	}
+	if !isPublic && testingTeam != team {
+		http.Error(w, resources.AuthenticationError, http.StatusUnauthorized)
+		log.Println("User cannot view the tournament of this test")
+		return
+	}
+
+	tournament, err := getTournamentWithTestID(db, testID)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "No tournament found for this test.", http.StatusNotFound)
+		log.Println("No tournament found for test: " + matches[1])
+		return
+	} else if err != nil {
+		http.Error(w, "Could not retrieve the tournament.", http.StatusInternalServerError)
+		log.Println("Could not retrieve the tournament: " + err.Error())
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(tournament)
+	if err != nil {
+		http.Error(w, "Could not encode the tournament.", http.StatusInternalServerError)
+		log.Println("Could not encode the tournament: " + err.Error())
+		return
+	}
+}
+
+// TournamentRequestPOST generates a new tournament for a test.
+//
+//	@Summary		Create a tournament for a test
+//	@Description	Generates a single elimination bracket from the products in a test. The products are seeded in
+//	@Description	the given order, or by their current rank in the test. Only products ranked in the test can be
+//	@Description	seeded. Products without an opponent in the first round get a walkover.
+//	@Tags			Tournaments
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			test_id		path		int						true	"Test ID"
+//	@Param			tournament	body		TournamentPOSTRequest	false	"Seeded products"
+//	@Success		201			{object}	domain.Tournament		"Tournament created successfully"
+//	@Failure		400			{string}	string					"A tournament needs at least two products, or a product is not in the test."
+//	@Failure		401			{string}	string					"Unauthorized"
+//	@Failure		404			{string}	string					"Could not retrieve the test."
+//	@Failure		409			{string}	string					"This test already has a tournament."
+//	@Failure		500			{string}	string					"Could not create the tournament."
+//	@Router			/tests/{test_id}/tournament [post]
+func TournamentRequestPOST(w http.ResponseWriter, r *http.Request, db *sql.DB) {
+	matches := tournamentPath.FindStringSubmatch(r.URL.Path)
+	if len(matches) != 2 {
+		http.Error(w, "Invalid request URL, use '/tests/{test_id}/tournament'.", http.StatusBadRequest)
+		log.Println("Invalid request URL: " + r.URL.Path)
+		return
+	}
+	testID, _ := strconv.Atoi(matches[1])
+
+	// Get the user's team membership.
+	team := middleware.GetUserTeamRole(w, r, db)
+
+	// The request body is optional, without it the products of the test are used.
+	var request TournamentPOSTRequest
+	var err error
+	if r.ContentLength != 0 {
+		request, err = utils.ParseAndValidateRequest[TournamentPOSTRequest](r)
+		if err != nil {
+			http.Error(w, resources.InvalidPOSTRequest, http.StatusBadRequest)
+			log.Println(resources.InvalidPOSTRequest + ": " + err.Error())
+			return
+		}
+	}
+
+	testingTeam, isPublic, state, err := access.GetTestAccess(db, testID)
+	if err != nil {
+		http.Error(w, "Could not retrieve the test.", http.StatusNotFound)
+		log.Println("Could not retrieve the test: " + err.Error())
+		return
+	}
+
+	var code int
+	if err, code = access.ValidateWritePermissions("test", testingTeam, isPublic, state, team); err != nil {
+		http.Error(w, "Validation error: "+err.Error(), code)
+		log.Println("Validation error: " + err.Error())
+		return
+	}
+
+	productIDs, err := getTestProducts(db, testID)
+	if err != nil {
+		http.Error(w, "Could not retrieve the products of the test.", http.StatusInternalServerError)
+		log.Println("Could not retrieve the products of the test: " + err.Error())
+		return
+	}
+
+	// The seeding can only contain the products ranked in the test.
+	if len(request.ProductIDs) != 0 {
+		if err, code = validateSeeding(request.ProductIDs, productIDs); err != nil {
+			http.Error(w, "Validation error: "+err.Error(), code)
+			log.Println("Validation error: " + err.Error())
+			return
+		}
+		productIDs = request.ProductIDs
+	}
+
+	if len(productIDs) < 2 {
+		http.Error(w, "A tournament needs at least two products.", http.StatusBadRequest)
+		log.Println("A tournament needs at least two products.")
+		return
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		http.Error(w, resources.TransactionStartFailed, http.StatusInternalServerError)
+		return
+	}
+	defer func() {
+		if err != nil {
+			rollbackErr := tx.Rollback()
+			if rollbackErr != nil {
+				log.Println(resources.RollbackFailed + rollbackErr.Error())
+			}
+		}
+	}()
+
+	// Check if the test already has a tournament.
+	var existing int
+	err = tx.QueryRow("SELECT COUNT(*) FROM tournaments WHERE test_id = $1;", testID).Scan(&existing)
+	if err != nil {
+		http.Error(w, "Could not create the tournament.", http.StatusInternalServerError)
+		log.Println("Could not check for an existing tournament: " + err.Error())
+		return
+	}
+	if existing != 0 {
+		err = errors.New("tournament already exists")
+		http.Error(w, "This test already has a tournament.", http.StatusConflict)
+		log.Println("This test already has a tournament.")
+		return
+	}
+
+	tournament, err := insertTournament(tx, testID, buildBracket(productIDs))
+	if err != nil {
+		http.Error(w, "Could not create the tournament.", http.StatusInternalServerError)
+		log.Println("Could not create the tournament: " + err.Error())
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		http.Error(w, resources.TransactionCommitFailed, http.StatusInternalServerError)
+		log.Println(resources.TransactionCommitFailed + ": " + err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	err = json.NewEncoder(w).Encode(tournament)
+	if err != nil {
+		log.Println("Could not encode the tournament: " + err.Error())
+		return
+	}
+}
+
+// TournamentRequestPATCH records the result of a match.
+//
+//	@Summary		Record the result of a match
+//	@Description	Records both battles of a match, decides the winner by the summed battle score and advances it
+//	@Description	to the next round. When the final is decided, the rank and distance behind of every product is
+//	@Description	written to the test ranks.
+//	@Tags			Tournaments
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			test_id		path		int						true	"Test ID"
+//	@Param			match_id	path		int						true	"Match ID"
+//	@Param			match		body		MatchResultPATCHRequest	true	"Battle results"
+//	@Success		200			{string}	string					"Match updated successfully"
+//	@Failure		400			{string}	string					"Invalid PATCH request body"
+//	@Failure		401			{string}	string					"Unauthorized"
+//	@Failure		404			{string}	string					"No tournament found for this test."
+//	@Failure		409			{string}	string					"Detected a conflict for the current match, please refresh."
+//	@Failure		500			{string}	string					"Could not update the match."
+//	@Router			/tests/{test_id}/tournament/matches/{match_id} [patch]
+func TournamentRequestPATCH(w http.ResponseWriter, r *http.Request, db *sql.DB) {
+	matches := matchPath.FindStringSubmatch(r.URL.Path)
+	if len(matches) != 3 {
+		http.Error(w, "Invalid request URL, use '/tests/{test_id}/tournament/matches/{match_id}'.",
+			http.StatusBadRequest)
+		log.Println("Invalid request URL: " + r.URL.Path)
+		return
+	}
+	testID, _ := strconv.Atoi(matches[1])
+	matchID, _ := strconv.Atoi(matches[2])
+
+	// Get the user's team membership.
+	team := middleware.GetUserTeamRole(w, r, db)
+
+	request, err := utils.ParseAndValidateRequest[MatchResultPATCHRequest](r)
+	if err != nil {
+		http.Error(w, resources.InvalidPATCHRequest, http.StatusBadRequest)
+		log.Println(resources.InvalidPATCHRequest + ": " + err.Error())
+		return
+	}
+
+	testingTeam, isPublic, state, err := access.GetTestAccess(db, testID)
+	if err != nil {
+		http.Error(w, "Could not retrieve the test.", http.StatusNotFound)
+		log.Println("Could not retrieve the test: " + err.Error())
+		return
+	}
+
+	var code int
+	if err, code = access.ValidateWritePermissions("test", testingTeam, isPublic, state, team); err != nil {
+		http.Error(w, "Validation error: "+err.Error(), code)
+		log.Println("Validation error: " + err.Error())
+		return
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		http.Error(w, resources.TransactionStartFailed, http.StatusInternalServerError)
+		return
+	}
+	defer func() {
+		if err != nil {
+			rollbackErr := tx.Rollback()
+			if rollbackErr != nil {
+				log.Println(resources.RollbackFailed + rollbackErr.Error())
+			}
+		}
+	}()
+
+	tournamentID, status, tournamentMatches, err := lockTournamentMatches(tx, testID)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "No tournament found for this test.", http.StatusNotFound)
+		log.Println("No tournament found for test: " + matches[1])
+		return
+	} else if err != nil {
+		http.Error(w, "Could not update the match.", http.StatusInternalServerError)
+		log.Println("Could not retrieve the tournament: " + err.Error())
+		return
+	}
+
+	if status == string(domain.TournamentCompleted) {
+		err = errors.New("tournament is completed")
+		http.Error(w, "The tournament is completed and cannot be changed.", http.StatusConflict)
+		log.Println("The tournament is completed and cannot be changed.")
+		return
+	}
+
+	changed, err := recordMatchResult(tournamentMatches, matchID, *request.Battle1, *request.Battle2)
+	if errors.Is(err, errMatchNotFound) {
+		http.Error(w, "Could not find the match in this tournament.", http.StatusNotFound)
+		log.Println("Could not find the match in this tournament: " + matches[2])
+		return
+	} else if err != nil {
+		http.Error(w, "Validation error: "+err.Error(), http.StatusBadRequest)
+		log.Println("Validation error: " + err.Error())
+		return
+	}
+
+	// Only the recorded match is checked against the version of the request,
+	// the next match only gets its empty slot filled.
+	var newVersion time.Time
+	for i, idx := range changed {
+		var version *time.Time
+		if i == 0 {
+			version = &request.Version
+		}
+
+		var matchVersion time.Time
+		matchVersion, err = updateMatch(tx, tournamentMatches[idx], version)
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "Detected a conflict for the current match, please refresh.", http.StatusConflict)
+			log.Println("Detected a conflict for the current match, please refresh.")
+			return
+		} else if err != nil {
+			http.Error(w, "Could not update the match.", http.StatusInternalServerError)
+			log.Println("Could not update the match: " + err.Error())
+			return
+		}
+		if i == 0 {
+			newVersion = matchVersion
+		}
+	}
+
+	finished := isTournamentFinished(tournamentMatches)
+	if finished {
+		err = completeTournament(tx, tournamentID, testID, deriveRanks(tournamentMatches))
+		if err != nil {
+			http.Error(w, "Could not complete the tournament.", http.StatusInternalServerError)
+			log.Println("Could not complete the tournament: " + err.Error())
+			return
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		http.Error(w, resources.TransactionCommitFailed, http.StatusInternalServerError)
+		log.Println(resources.TransactionCommitFailed + ": " + err.Error())
+		return
+	}
+	if finished {
+		testsHandler.NotifyTestChanged(testID)
+	}
+
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":  "Match updated successfully",
+		"version":  newVersion,
+		"winner":   tournamentMatches[changed[0]].Winner,
+		"finished": finished,
+	})
+	if err != nil {
+		log.Println("Could not JSON encode the response: " + err.Error())
+		return
+	}
+}
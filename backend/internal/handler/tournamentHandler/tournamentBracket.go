@@ -0,0 +1,232 @@
+package tournamentHandler
+
+import (
+	"backend/internal/domain"
+	"errors"
+	"math/bits"
+	"sort"
+)
+
+var (
+	errMatchNotFound    = errors.New("match not found in this tournament")
+	errMatchNotReady    = errors.New("both products must be decided before the match can be run")
+	errMatchIsDraw      = errors.New("the match is a draw, re-run one of the battles")
+	errMatchHasAdvanced = errors.New("the winner has already played the next match and cannot be changed")
+)
+
+// TournamentRank is the final placement of a product in a finished tournament.
+type TournamentRank struct {
+	ProductID      int `json:"product_id"`
+	Rank           int `json:"rank"`
+	DistanceBehind int `json:"distance_behind"`
+}
+
+// seedPositions returns the seeds in bracket order, so that the top seeds can only meet in the last rounds.
+// For a bracket of 8 this gives 1, 8, 4, 5, 2, 7, 3, 6.
+func seedPositions(size int) []int {
+	positions := []int{1}
+	for len(positions) < size {
+		next := make([]int, 0, len(positions)*2)
+		for _, seed := range positions {
+			next = append(next, seed, 2*len(positions)+1-seed)
+		}
+		positions = next
+	}
+	return positions
+}
+
+// buildBracket creates every match of a single elimination bracket for the seeded products.
+// Products without an opponent in the first round get a walkover into the second round.
+func buildBracket(productIDs []int) []domain.TournamentMatch {
+	size := 2
+	for size < len(productIDs) {
+		size *= 2
+	}
+	rounds := bits.Len(uint(size)) - 1
+
+	var matches []domain.TournamentMatch
+	for round := 1; round <= rounds; round++ {
+		for matchNo := 1; matchNo <= size>>round; matchNo++ {
+			matches = append(matches, domain.TournamentMatch{Round: round, MatchNo: matchNo})
+		}
+	}
+
+	positions := seedPositions(size)
+	for i := 0; i < size/2; i++ {
+		matches[i].Product1 = productWithSeed(productIDs, positions[2*i])
+		matches[i].Product2 = productWithSeed(productIDs, positions[2*i+1])
+
+		// The lower seed is always the first product, so only the second slot can be empty.
+		if matches[i].Product2 == nil {
+			matches[i].Winner = matches[i].Product1
+			advanceWinner(matches, i)
+		}
+	}
+	return matches
+}
+
+// productWithSeed returns the product with the given seed, or nil if the seed is a walkover.
+func productWithSeed(productIDs []int, seed int) *int {
+	if seed > len(productIDs) {
+		return nil
+	}
+	productID := productIDs[seed-1]
+	return &productID
+}
+
+// findMatch returns the index of the match in the given round, or -1 if there is no such match.
+func findMatch(matches []domain.TournamentMatch, round int, matchNo int) int {
+	for i, match := range matches {
+		if match.Round == round && match.MatchNo == matchNo {
+			return i
+		}
+	}
+	return -1
+}
+
+// advanceWinner moves the winner of a match into its slot in the next round, and returns the index of that match.
+func advanceWinner(matches []domain.TournamentMatch, idx int) int {
+	next := findMatch(matches, matches[idx].Round+1, (matches[idx].MatchNo+1)/2)
+	if next < 0 {
+		return -1
+	}
+
+	winner := *matches[idx].Winner
+	if matches[idx].MatchNo%2 == 1 {
+		matches[next].Product1 = &winner
+	} else {
+		matches[next].Product2 = &winner
+	}
+	return next
+}
+
+// recordMatchResult stores both battles of a match, decides the winner and advances it to the next round.
+// It returns the indices of all the matches that were changed.
+func recordMatchResult(matches []domain.TournamentMatch, matchID int, battle1 int, battle2 int) ([]int, error) {
+	idx := -1
+	for i, match := range matches {
+		if match.ID == matchID {
+			idx = i
+		}
+	}
+	if idx < 0 {
+		return nil, errMatchNotFound
+	}
+
+	match := &matches[idx]
+	if match.Product1 == nil || match.Product2 == nil {
+		return nil, errMatchNotReady
+	}
+
+	// The battles are measured with reference to product 1, so the sign of the sum decides the match.
+	sum := battle1 + battle2
+	if sum == 0 {
+		return nil, errMatchIsDraw
+	}
+
+	next := findMatch(matches, match.Round+1, (match.MatchNo+1)/2)
+	if next >= 0 && matches[next].Winner != nil {
+		return nil, errMatchHasAdvanced
+	}
+
+	match.Battle1 = &battle1
+	match.Battle2 = &battle2
+	if sum > 0 {
+		match.Winner = match.Product1
+	} else {
+		match.Winner = match.Product2
+	}
+
+	changed := []int{idx}
+	if next >= 0 {
+		changed = append(changed, advanceWinner(matches, idx))
+	}
+	return changed, nil
+}
+
+// isTournamentFinished reports whether the final of the bracket has been decided.
+func isTournamentFinished(matches []domain.TournamentMatch) bool {
+	final := finalMatch(matches)
+	return final >= 0 && matches[final].Winner != nil
+}
+
+// finalMatch returns the index of the match in the last round.
+func finalMatch(matches []domain.TournamentMatch) int {
+	final := -1
+	for i, match := range matches {
+		if final < 0 || match.Round > matches[final].Round {
+			final = i
+		}
+	}
+	return final
+}
+
+// margin returns how far the winner of a match was ahead, summed over both battles.
+func margin(match domain.TournamentMatch) int {
+	sum := *match.Battle1 + *match.Battle2
+	if sum < 0 {
+		return -sum
+	}
+	return sum
+}
+
+// deriveRanks turns a finished bracket into ranks. Products knocked out in the same round share a rank,
+// e.g. both semifinal losers get rank 3. The distance behind a product is the distance behind the product
+// that knocked it out, plus the margin of that match.
+func deriveRanks(matches []domain.TournamentMatch) []TournamentRank {
+	final := finalMatch(matches)
+	if final < 0 || matches[final].Winner == nil {
+		return nil
+	}
+
+	champion := *matches[final].Winner
+	distances := map[int]int{champion: 0}
+	ranks := []TournamentRank{{ProductID: champion, Rank: 1, DistanceBehind: 0}}
+
+	// Walk the rounds from the final and down, so the distance of every winner is known before its losers.
+	for round := matches[final].Round; round >= 1; round-- {
+		matchesInRound := 0
+		for _, match := range matches {
+			if match.Round == round {
+				matchesInRound++
+			}
+		}
+
+		for _, match := range matches {
+			if match.Round != round || match.Winner == nil || match.Battle1 == nil || match.Battle2 == nil {
+				continue // Walkovers have no loser.
+			}
+
+			loser := *match.Product1
+			if loser == *match.Winner {
+				loser = *match.Product2
+			}
+			distances[loser] = distances[*match.Winner] + margin(match)
+			ranks = append(ranks, TournamentRank{
+				ProductID:      loser,
+				Rank:           matchesInRound + 1,
+				DistanceBehind: distances[loser],
+			})
+		}
+	}
+
+	sort.SliceStable(ranks, func(i, j int) bool {
+		if ranks[i].Rank != ranks[j].Rank {
+			return ranks[i].Rank < ranks[j].Rank
+		}
+		return ranks[i].DistanceBehind < ranks[j].DistanceBehind
+	})
+	return ranks
+}
+
+// groupRounds groups the matches of a tournament by round for the response.
+func groupRounds(matches []domain.TournamentMatch) []domain.TournamentRound {
+	var rounds []domain.TournamentRound
+	for _, match := range matches {
+		if len(rounds) == 0 || rounds[len(rounds)-1].Round != match.Round {
+			rounds = append(rounds, domain.TournamentRound{Round: match.Round})
+		}
+		rounds[len(rounds)-1].Matches = append(rounds[len(rounds)-1].Matches, match)
+	}
+	return rounds
+}
@@ -0,0 +1,214 @@
+package tournamentHandler
+
+import (
+	"backend/internal/domain"
+	"backend/internal/handler/testsHandler"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// getTestProducts retrieves the products of a test, ordered by their current rank, which is used as seeding.
+func getTestProducts(db *sql.DB, testID int) ([]int, error) {
+	rows, err := db.Query(`SELECT product_id FROM test_ranks WHERE test_id = $1
+                          ORDER BY rank NULLS LAST, product_id;`, testID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var productIDs []int
+	for rows.Next() {
+		var productID int
+		if err = rows.Scan(&productID); err != nil {
+			return nil, err
+		}
+		productIDs = append(productIDs, productID)
+	}
+	return productIDs, rows.Err()
+}
+
+// validateSeeding checks that every seeded product is ranked in the test, so a tournament can not rank products the
+// team can not see or that do not exist.
+func validateSeeding(seeding []int, testProducts []int) (error, int) {
+	inTest := map[int]bool{}
+	for _, productID := range testProducts {
+		inTest[productID] = true
+	}
+	for _, productID := range seeding {
+		if !inTest[productID] {
+			return fmt.Errorf("product %d is not ranked in the test, %d", productID, http.StatusBadRequest),
+				http.StatusBadRequest
+		}
+	}
+	return nil, 0
+}
+
+// scanMatches scans the rows of a tournament_matches query into a slice of matches.
+func scanMatches(rows *sql.Rows) ([]domain.TournamentMatch, error) {
+	defer rows.Close()
+
+	var matches []domain.TournamentMatch
+	for rows.Next() {
+		var match domain.TournamentMatch
+		var product1, product2, battle1, battle2, winner sql.NullInt64
+
+		if err := rows.Scan(
+			&match.ID,
+			&match.Round,
+			&match.MatchNo,
+			&product1,
+			&product2,
+			&battle1,
+			&battle2,
+			&winner,
+			&match.Version); err != nil {
+			return nil, err
+		}
+
+		match.Product1 = nullableInt(product1)
+		match.Product2 = nullableInt(product2)
+		match.Battle1 = nullableInt(battle1)
+		match.Battle2 = nullableInt(battle2)
+		match.Winner = nullableInt(winner)
+		matches = append(matches, match)
+	}
+	return matches, rows.Err()
+}
+
+func nullableInt(value sql.NullInt64) *int {
+	if !value.Valid {
+		return nil
+	}
+	i := int(value.Int64)
+	return &i
+}
+
+// getTournamentWithTestID retrieves the tournament of a test with all of its matches.
+func getTournamentWithTestID(db *sql.DB, testID int) (domain.Tournament, error) {
+	var tournament domain.Tournament
+	err := db.QueryRow("SELECT id, test_id, status, version FROM tournaments WHERE test_id = $1;", testID).
+		Scan(&tournament.ID, &tournament.TestID, &tournament.Status, &tournament.Version)
+	if err != nil {
+		return domain.Tournament{}, err
+	}
+
+	rows, err := db.Query(`SELECT id, round, match_no, product1_id, product2_id, battle1, battle2, winner_id, version
+								FROM tournament_matches WHERE tournament_id = $1 ORDER BY round, match_no;`,
+		tournament.ID)
+	if err != nil {
+		return domain.Tournament{}, err
+	}
+
+	matches, err := scanMatches(rows)
+	if err != nil {
+		return domain.Tournament{}, err
+	}
+	tournament.Rounds = groupRounds(matches)
+	return tournament, nil
+}
+
+// lockTournamentMatches locks the tournament of a test for the rest of the transaction and retrieves its matches.
+func lockTournamentMatches(tx *sql.Tx, testID int) (int, string, []domain.TournamentMatch, error) {
+	var tournamentID int
+	var status string
+	err := tx.QueryRow("SELECT id, status FROM tournaments WHERE test_id = $1 FOR UPDATE;", testID).
+		Scan(&tournamentID, &status)
+	if err != nil {
+		return 0, "", nil, err
+	}
+
+	rows, err := tx.Query(`SELECT id, round, match_no, product1_id, product2_id, battle1, battle2, winner_id, version
+								FROM tournament_matches WHERE tournament_id = $1 ORDER BY round, match_no;`,
+		tournamentID)
+	if err != nil {
+		return 0, "", nil, err
+	}
+
+	matches, err := scanMatches(rows)
+	return tournamentID, status, matches, err
+}
+
+// insertTournament inserts a new tournament and all of its matches.
+func insertTournament(tx *sql.Tx, testID int, matches []domain.TournamentMatch) (domain.Tournament, error) {
+	tournament := domain.Tournament{TestID: testID, Status: string(domain.TournamentInProgress)}
+
+	err := tx.QueryRow(`INSERT INTO tournaments (test_id, status, version)
+							VALUES ($1, $2, $3)
+							RETURNING id, version;`,
+		testID, tournament.Status, time.Now()).Scan(&tournament.ID, &tournament.Version)
+	if err != nil {
+		return domain.Tournament{}, fmt.Errorf("failed to insert tournament: %w", err)
+	}
+
+	for i := range matches {
+		err = tx.QueryRow(`INSERT INTO tournament_matches (
+                                tournament_id, round, match_no, product1_id, product2_id, winner_id, version)
+								VALUES ($1, $2, $3, $4, $5, $6, $7)
+								RETURNING id, version;`,
+			tournament.ID,
+			matches[i].Round,
+			matches[i].MatchNo,
+			matches[i].Product1,
+			matches[i].Product2,
+			matches[i].Winner,
+			time.Now()).Scan(&matches[i].ID, &matches[i].Version)
+		if err != nil {
+			return domain.Tournament{}, fmt.Errorf("failed to insert match %d in round %d: %w",
+				matches[i].MatchNo, matches[i].Round, err)
+		}
+	}
+
+	tournament.Rounds = groupRounds(matches)
+	return tournament, nil
+}
+
+// updateMatch writes the products, battles and winner of a match. If a version is given, the update only
+// succeeds when the match has not been changed since that version.
+func updateMatch(tx *sql.Tx, match domain.TournamentMatch, version *time.Time) (time.Time, error) {
+	query := `UPDATE tournament_matches SET product1_id = $1, product2_id = $2, battle1 = $3, battle2 = $4,
+                              winner_id = $5, version = $6 WHERE id = $7`
+	args := []interface{}{match.Product1, match.Product2, match.Battle1, match.Battle2, match.Winner,
+		time.Now(), match.ID}
+	if version != nil {
+		query += " AND version = $8"
+		args = append(args, *version)
+	}
+
+	var newVersion time.Time
+	err := tx.QueryRow(query+" RETURNING version;", args...).Scan(&newVersion)
+	return newVersion, err
+}
+
+// completeTournament marks the tournament as completed and writes the derived ranks to test_ranks, flagging the
+// products ranked outside their rated temperature range.
+func completeTournament(tx *sql.Tx, tournamentID int, testID int, ranks []TournamentRank) error {
+	_, err := tx.Exec("UPDATE tournaments SET status = $1, version = $2 WHERE id = $3;",
+		domain.TournamentCompleted, time.Now(), tournamentID)
+	if err != nil {
+		return fmt.Errorf("failed to complete tournament: %w", err)
+	}
+
+	for _, rank := range ranks {
+		_, err = tx.Exec(`INSERT INTO test_ranks (
+                      	test_id, product_id, rank, distance_behind, version, is_rank_public)
+						VALUES ($1, $2, $3, $4, $5, (SELECT is_public FROM products WHERE id = $2))
+						ON CONFLICT (test_id, product_id) DO UPDATE
+						SET rank = EXCLUDED.rank, distance_behind = EXCLUDED.distance_behind, version = EXCLUDED.version;`,
+			testID,
+			rank.ProductID,
+			rank.Rank,
+			rank.DistanceBehind,
+			time.Now())
+		if err != nil {
+			return fmt.Errorf("failed to write rank for product %d: %w", rank.ProductID, err)
+		}
+	}
+
+	_, err = testsHandler.UpdateTemperatureRangeFlags(tx, testID)
+	if err != nil {
+		return fmt.Errorf("failed to update the temperature range flags: %w", err)
+	}
+	return nil
+}
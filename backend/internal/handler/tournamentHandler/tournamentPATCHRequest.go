@@ -0,0 +1,10 @@
+package tournamentHandler
+
+import "time"
+
+type MatchResultPATCHRequest struct {
+	// Battle1 and Battle2 are measured with reference to product 1, positive if product 1 won the battle.
+	Battle1 *int      `json:"battle1" validate:"required,gte=-100000,lte=100000"`
+	Battle2 *int      `json:"battle2" validate:"required,gte=-100000,lte=100000"`
+	Version time.Time `json:"version"`
+}
@@ -0,0 +1,6 @@
+package tournamentHandler
+
+type TournamentPOSTRequest struct {
+	// ProductIDs: The products in seeding order. Defaults to the products already ranked in the test.
+	ProductIDs []int `json:"product_ids" validate:"omitempty,min=2,unique,dive,gt=0"`
+}
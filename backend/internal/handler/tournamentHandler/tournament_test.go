@@ -0,0 +1,528 @@
+package tournamentHandler
+
+import (
+	"backend/internal/domain"
+	"backend/internal/utils"
+	"database/sql"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func AuthenticationMock(mock sqlmock.Sqlmock) {
+	// Mock the user id query
+	mock.ExpectQuery("SELECT user_id FROM sessions WHERE \\(session_token = \\$1 AND expires_at > NOW\\(\\)\\)").
+		WithArgs("mockToken").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(1))
+
+	// Mock the user team id query
+	mock.ExpectQuery("SELECT team_id FROM users WHERE id = \\$1;").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"team_id"}).AddRow(1))
+
+	// Mock the user team role query
+	mock.ExpectQuery("SELECT team_role FROM team WHERE id = \\$1;").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"team_role"}).AddRow(1))
+}
+
+var matchColumns = []string{
+	"id", "round", "match_no", "product1_id", "product2_id", "battle1", "battle2", "winner_id", "version",
+}
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func Test_seedPositions(t *testing.T) {
+	assert.Equal(t, []int{1, 2}, seedPositions(2))
+	assert.Equal(t, []int{1, 4, 2, 3}, seedPositions(4))
+	assert.Equal(t, []int{1, 8, 4, 5, 2, 7, 3, 6}, seedPositions(8))
+}
+
+func Test_buildBracket(t *testing.T) {
+	matches := buildBracket([]int{10, 20, 30})
+
+	assert.Len(t, matches, 3)
+
+	// The top seed gets a walkover and is moved straight into the final.
+	assert.Equal(t, intPtr(10), matches[0].Product1)
+	assert.Nil(t, matches[0].Product2)
+	assert.Equal(t, intPtr(10), matches[0].Winner)
+
+	assert.Equal(t, intPtr(20), matches[1].Product1)
+	assert.Equal(t, intPtr(30), matches[1].Product2)
+	assert.Nil(t, matches[1].Winner)
+
+	assert.Equal(t, 2, matches[2].Round)
+	assert.Equal(t, intPtr(10), matches[2].Product1)
+	assert.Nil(t, matches[2].Product2)
+}
+
+func Test_recordMatchResult(t *testing.T) {
+	newBracket := func() []domain.TournamentMatch {
+		matches := buildBracket([]int{10, 20, 30, 40})
+		for i := range matches {
+			matches[i].ID = i + 1
+		}
+		return matches
+	}
+
+	tests := []struct {
+		name        string
+		setup       func(matches []domain.TournamentMatch)
+		matchID     int
+		battle1     int
+		battle2     int
+		wantErr     error
+		wantChanged []int
+		wantWinner  *int
+	}{
+		{
+			name:    "Match not found",
+			matchID: 99,
+			battle1: 1,
+			battle2: 1,
+			wantErr: errMatchNotFound,
+		},
+		{
+			name:    "Final is not ready",
+			matchID: 3,
+			battle1: 1,
+			battle2: 1,
+			wantErr: errMatchNotReady,
+		},
+		{
+			name:    "Draw",
+			matchID: 1,
+			battle1: 5,
+			battle2: -5,
+			wantErr: errMatchIsDraw,
+		},
+		{
+			name: "Winner has already played the next match",
+			setup: func(matches []domain.TournamentMatch) {
+				matches[2].Product1 = intPtr(10)
+				matches[2].Product2 = intPtr(20)
+				matches[2].Winner = intPtr(10)
+			},
+			matchID: 1,
+			battle1: 1,
+			battle2: 1,
+			wantErr: errMatchHasAdvanced,
+		},
+		{
+			name:        "Second product wins and advances",
+			matchID:     2,
+			battle1:     -3,
+			battle2:     1,
+			wantChanged: []int{1, 2},
+			wantWinner:  intPtr(30),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matches := newBracket()
+			if tt.setup != nil {
+				tt.setup(matches)
+			}
+
+			changed, err := recordMatchResult(matches, tt.matchID, tt.battle1, tt.battle2)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantChanged, changed)
+			assert.Equal(t, tt.wantWinner, matches[changed[0]].Winner)
+			assert.Equal(t, tt.wantWinner, matches[changed[1]].Product2)
+		})
+	}
+}
+
+func Test_deriveRanks(t *testing.T) {
+	matches := buildBracket([]int{10, 20, 30, 40})
+	for i := range matches {
+		matches[i].ID = i + 1
+	}
+
+	// 10 beats 40 by 4, 30 beats 20 by 2, and 10 beats 30 by 3 in the final.
+	_, err := recordMatchResult(matches, 1, 3, 1)
+	assert.NoError(t, err)
+	_, err = recordMatchResult(matches, 2, -1, -1)
+	assert.NoError(t, err)
+	assert.False(t, isTournamentFinished(matches))
+
+	_, err = recordMatchResult(matches, 3, 2, 1)
+	assert.NoError(t, err)
+	assert.True(t, isTournamentFinished(matches))
+
+	assert.Equal(t, []TournamentRank{
+		{ProductID: 10, Rank: 1, DistanceBehind: 0},
+		{ProductID: 30, Rank: 2, DistanceBehind: 3},
+		{ProductID: 40, Rank: 3, DistanceBehind: 4},
+		{ProductID: 20, Rank: 3, DistanceBehind: 5},
+	}, deriveRanks(matches))
+}
+
+func TestTournamentHandler(t *testing.T) {
+	mockDB, mock := utils.InitMockDB(t)
+	version := time.Date(2025, 3, 30, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		method       string
+		path         string
+		body         string
+		setupMocks   func()
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:         "Method = GET (Status OK)",
+			method:       http.MethodGet,
+			path:         "/tests/1/tournament",
+			expectedCode: http.StatusOK,
+			expectedBody: `"product1_id":10`,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+
+				mock.ExpectQuery("SELECT testing_team, is_public, state FROM tests WHERE id = \\$1;").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"testing_team", "is_public", "state"}).
+						AddRow(1, false, "in_progress"))
+
+				mock.ExpectQuery("SELECT id, test_id, status, version FROM tournaments WHERE test_id = \\$1;").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "test_id", "status", "version"}).
+						AddRow(1, 1, "in_progress", version))
+
+				mock.ExpectQuery("SELECT id, round, match_no, product1_id, product2_id, battle1, battle2, winner_id, version").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows(matchColumns).
+						AddRow(1, 1, 1, 10, 20, nil, nil, nil, version))
+			},
+		},
+		{
+			name:         "Method = GET (Status not found - no tournament)",
+			method:       http.MethodGet,
+			path:         "/tests/1/tournament",
+			expectedCode: http.StatusNotFound,
+			expectedBody: "No tournament found for this test.",
+			setupMocks: func() {
+				AuthenticationMock(mock)
+
+				mock.ExpectQuery("SELECT testing_team, is_public, state FROM tests WHERE id = \\$1;").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"testing_team", "is_public", "state"}).
+						AddRow(1, false, "in_progress"))
+
+				mock.ExpectQuery("SELECT id, test_id, status, version FROM tournaments WHERE test_id = \\$1;").
+					WithArgs(1).
+					WillReturnError(sql.ErrNoRows)
+			},
+		},
+		{
+			name:         "Method = GET (Status unauthorized - private test of another team)",
+			method:       http.MethodGet,
+			path:         "/tests/1/tournament",
+			expectedCode: http.StatusUnauthorized,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+
+				mock.ExpectQuery("SELECT testing_team, is_public, state FROM tests WHERE id = \\$1;").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"testing_team", "is_public", "state"}).
+						AddRow(2, false, "in_progress"))
+			},
+		},
+		{
+			name:         "Method = POST (Status created - seeded from test ranks)",
+			method:       http.MethodPost,
+			path:         "/tests/1/tournament",
+			expectedCode: http.StatusCreated,
+			expectedBody: `"status":"in_progress"`,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+
+				mock.ExpectQuery("SELECT testing_team, is_public, state FROM tests WHERE id = \\$1;").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"testing_team", "is_public", "state"}).
+						AddRow(1, false, "in_progress"))
+
+				mock.ExpectQuery("SELECT product_id FROM test_ranks WHERE test_id = \\$1").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"product_id"}).AddRow(10).AddRow(20))
+
+				mock.ExpectBegin()
+
+				mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM tournaments WHERE test_id = \\$1;").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+				mock.ExpectQuery("INSERT INTO tournaments").
+					WithArgs(1, "in_progress", sqlmock.AnyArg()).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "version"}).AddRow(1, version))
+
+				mock.ExpectQuery("INSERT INTO tournament_matches").
+					WithArgs(1, 1, 1, 10, 20, nil, sqlmock.AnyArg()).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "version"}).AddRow(1, version))
+
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:         "Method = POST (Status bad request - too few products)",
+			method:       http.MethodPost,
+			path:         "/tests/1/tournament",
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "A tournament needs at least two products.",
+			setupMocks: func() {
+				AuthenticationMock(mock)
+
+				mock.ExpectQuery("SELECT testing_team, is_public, state FROM tests WHERE id = \\$1;").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"testing_team", "is_public", "state"}).
+						AddRow(1, false, "in_progress"))
+
+				mock.ExpectQuery("SELECT product_id FROM test_ranks WHERE test_id = \\$1").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"product_id"}).AddRow(10))
+			},
+		},
+		{
+			name:         "Method = POST (Status bad request - product not in the test)",
+			method:       http.MethodPost,
+			path:         "/tests/1/tournament",
+			body:         `{"product_ids":[20,30]}`,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Validation error: product 30 is not ranked in the test",
+			setupMocks: func() {
+				AuthenticationMock(mock)
+
+				mock.ExpectQuery("SELECT testing_team, is_public, state FROM tests WHERE id = \\$1;").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"testing_team", "is_public", "state"}).
+						AddRow(1, false, "in_progress"))
+
+				mock.ExpectQuery("SELECT product_id FROM test_ranks WHERE test_id = \\$1").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"product_id"}).AddRow(10).AddRow(20))
+			},
+		},
+		{
+			name:         "Method = POST (Status conflict - tournament already exists)",
+			method:       http.MethodPost,
+			path:         "/tests/1/tournament",
+			body:         `{"product_ids":[20,10]}`,
+			expectedCode: http.StatusConflict,
+			expectedBody: "This test already has a tournament.",
+			setupMocks: func() {
+				AuthenticationMock(mock)
+
+				mock.ExpectQuery("SELECT testing_team, is_public, state FROM tests WHERE id = \\$1;").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"testing_team", "is_public", "state"}).
+						AddRow(1, false, "in_progress"))
+
+				mock.ExpectQuery("SELECT product_id FROM test_ranks WHERE test_id = \\$1").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"product_id"}).AddRow(10).AddRow(20))
+
+				mock.ExpectBegin()
+
+				mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM tournaments WHERE test_id = \\$1;").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+				mock.ExpectRollback()
+			},
+		},
+		{
+			name:         "Method = PATCH (Status OK - final decides the tournament)",
+			method:       http.MethodPatch,
+			path:         "/tests/1/tournament/matches/1",
+			body:         `{"battle1":5,"battle2":-2,"version":"2025-03-30T10:30:00Z"}`,
+			expectedCode: http.StatusOK,
+			expectedBody: `"finished":true`,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+
+				mock.ExpectQuery("SELECT testing_team, is_public, state FROM tests WHERE id = \\$1;").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"testing_team", "is_public", "state"}).
+						AddRow(1, false, "in_progress"))
+
+				mock.ExpectBegin()
+
+				mock.ExpectQuery("SELECT id, status FROM tournaments WHERE test_id = \\$1 FOR UPDATE;").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).AddRow(1, "in_progress"))
+
+				mock.ExpectQuery("SELECT id, round, match_no, product1_id, product2_id, battle1, battle2, winner_id, version").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows(matchColumns).
+						AddRow(1, 1, 1, 10, 20, nil, nil, nil, version))
+
+				mock.ExpectQuery("UPDATE tournament_matches SET .* AND version = \\$8 RETURNING version;").
+					WithArgs(10, 20, 5, -2, 10, sqlmock.AnyArg(), 1, version).
+					WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(time.Now()))
+
+				mock.ExpectExec("UPDATE tournaments SET status = \\$1, version = \\$2 WHERE id = \\$3;").
+					WithArgs(domain.TournamentCompleted, sqlmock.AnyArg(), 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectExec("INSERT INTO test_ranks").
+					WithArgs(1, 10, 1, 0, sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectExec("INSERT INTO test_ranks").
+					WithArgs(1, 20, 2, 3, sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectQuery("SELECT r.product_id, r.out_of_temperature_range, .* FROM test_ranks r").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"product_id", "out_of_temperature_range", "type",
+						"low_temperature", "high_temperature", "temperature", "temperature"}).
+						AddRow(10, false, "liquid", -10.0, -2.0, -5.0, -8.0).
+						AddRow(20, false, "liquid", -10.0, -2.0, -5.0, -8.0))
+
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:         "Method = PATCH (Status conflict - match has changed)",
+			method:       http.MethodPatch,
+			path:         "/tests/1/tournament/matches/1",
+			body:         `{"battle1":5,"battle2":-2,"version":"2025-03-30T10:30:00Z"}`,
+			expectedCode: http.StatusConflict,
+			expectedBody: "Detected a conflict for the current match, please refresh.",
+			setupMocks: func() {
+				AuthenticationMock(mock)
+
+				mock.ExpectQuery("SELECT testing_team, is_public, state FROM tests WHERE id = \\$1;").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"testing_team", "is_public", "state"}).
+						AddRow(1, false, "in_progress"))
+
+				mock.ExpectBegin()
+
+				mock.ExpectQuery("SELECT id, status FROM tournaments WHERE test_id = \\$1 FOR UPDATE;").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).AddRow(1, "in_progress"))
+
+				mock.ExpectQuery("SELECT id, round, match_no, product1_id, product2_id, battle1, battle2, winner_id, version").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows(matchColumns).
+						AddRow(1, 1, 1, 10, 20, nil, nil, nil, version))
+
+				mock.ExpectQuery("UPDATE tournament_matches SET").
+					WillReturnError(sql.ErrNoRows)
+
+				mock.ExpectRollback()
+			},
+		},
+		{
+			name:         "Method = PATCH (Status bad request - draw)",
+			method:       http.MethodPatch,
+			path:         "/tests/1/tournament/matches/1",
+			body:         `{"battle1":2,"battle2":-2,"version":"2025-03-30T10:30:00Z"}`,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: errMatchIsDraw.Error(),
+			setupMocks: func() {
+				AuthenticationMock(mock)
+
+				mock.ExpectQuery("SELECT testing_team, is_public, state FROM tests WHERE id = \\$1;").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"testing_team", "is_public", "state"}).
+						AddRow(1, false, "in_progress"))
+
+				mock.ExpectBegin()
+
+				mock.ExpectQuery("SELECT id, status FROM tournaments WHERE test_id = \\$1 FOR UPDATE;").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).AddRow(1, "in_progress"))
+
+				mock.ExpectQuery("SELECT id, round, match_no, product1_id, product2_id, battle1, battle2, winner_id, version").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows(matchColumns).
+						AddRow(1, 1, 1, 10, 20, nil, nil, nil, version))
+
+				mock.ExpectRollback()
+			},
+		},
+		{
+			name:         "Method = PATCH (Status conflict - tournament is completed)",
+			method:       http.MethodPatch,
+			path:         "/tests/1/tournament/matches/1",
+			body:         `{"battle1":5,"battle2":-2,"version":"2025-03-30T10:30:00Z"}`,
+			expectedCode: http.StatusConflict,
+			expectedBody: "The tournament is completed and cannot be changed.",
+			setupMocks: func() {
+				AuthenticationMock(mock)
+
+				mock.ExpectQuery("SELECT testing_team, is_public, state FROM tests WHERE id = \\$1;").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"testing_team", "is_public", "state"}).
+						AddRow(1, false, "in_progress"))
+
+				mock.ExpectBegin()
+
+				mock.ExpectQuery("SELECT id, status FROM tournaments WHERE test_id = \\$1 FOR UPDATE;").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).AddRow(1, "completed"))
+
+				mock.ExpectQuery("SELECT id, round, match_no, product1_id, product2_id, battle1, battle2, winner_id, version").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows(matchColumns))
+
+				mock.ExpectRollback()
+			},
+		},
+		{
+			name:         "Method = PATCH (Status bad request - invalid body)",
+			method:       http.MethodPatch,
+			path:         "/tests/1/tournament/matches/1",
+			body:         `{"battle1":5}`,
+			expectedCode: http.StatusBadRequest,
+			setupMocks: func() {
+				AuthenticationMock(mock)
+			},
+		},
+		{
+			name:         "Method = DELETE (Status not implemented)",
+			method:       http.MethodDelete,
+			path:         "/tests/1/tournament",
+			expectedCode: http.StatusNotImplemented,
+			setupMocks:   func() {},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer mockToken")
+			rr := httptest.NewRecorder()
+
+			TournamentHandler(mockDB).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedCode, rr.Code)
+			if tt.expectedBody != "" {
+				assert.Contains(t, rr.Body.String(), tt.expectedBody)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
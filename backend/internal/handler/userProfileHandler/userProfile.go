@@ -4,6 +4,7 @@ import (
 	"backend/internal/domain"
 	"backend/internal/middleware"
 	"backend/internal/resources"
+	"backend/internal/utils"
 	"database/sql"
 	"encoding/json"
 	"log"
@@ -15,12 +16,15 @@ import (
 //
 // It supports the following methods:
 // - GET: Retrieves the user profile for the authenticated user.
+// - PATCH: Updates the settings of the team of the authenticated admin user.
 func UserProfileHandler(db *sql.DB) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("content-type", "application/json")
 		switch r.Method {
 		case http.MethodGet:
 			UserProfileRequestGET(w, r, db)
+		case http.MethodPatch:
+			UserProfileRequestPATCH(w, r, db)
 		default:
 			http.Error(w, resources.MethodNotAllowed, http.StatusNotImplemented)
 			return
@@ -85,8 +89,9 @@ func UserProfileRequestGET(w http.ResponseWriter, r *http.Request, db *sql.DB) {
 			Email:    user.Email,
 			UserRole: user.UserRole,
 			Team: TeamResponse{
-				Name:     team.Name,
-				TeamRole: teamRole,
+				Name:                   team.Name,
+				TeamRole:               teamRole,
+				StrictTemperatureRange: team.StrictTemperatureRange,
 			},
 		}
 
@@ -104,3 +109,51 @@ func UserProfileRequestGET(w http.ResponseWriter, r *http.Request, db *sql.DB) {
 		return
 	}
 }
+
+// UserProfileRequestPATCH handles PATCH requests for user profiles.
+//
+//	@Summary		Update the team settings
+//	@Description	Updates the settings of the team of the authenticated user, which must be an admin. With a strict
+//	@Description	temperature range, results of products tested outside their rated temperature range are refused.
+//	@Tags			UserProfile
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			settings	body		UserProfilePATCHRequest	true	"Team settings"
+//	@Success		200			{string}	string					"Team settings updated successfully"
+//	@Failure		400			{string}	string					"Invalid PATCH request body"
+//	@Failure		401			{string}	string					"Unauthorized"
+//	@Failure		500			{string}	string					"Could not update the team settings."
+//	@Router			/user/profile [patch]
+func UserProfileRequestPATCH(w http.ResponseWriter, r *http.Request, db *sql.DB) {
+	// Check if the authenticated user is an admin of the team.
+	userRole := middleware.GetUserRole(w, r, db)
+	if domain.UserRole(userRole) != domain.Admin {
+		http.Error(w, resources.AuthenticationError, http.StatusUnauthorized)
+		log.Println(resources.AuthenticationError + ": " + "user is not an admin.")
+		return
+	}
+	teamID := middleware.GetUserTeamID(w, r, db)
+
+	request, err := utils.ParseAndValidateRequest[UserProfilePATCHRequest](r)
+	if err != nil {
+		http.Error(w, resources.InvalidPATCHRequest, http.StatusBadRequest)
+		log.Println(resources.InvalidPATCHRequest + ": " + err.Error())
+		return
+	}
+
+	err = updateStrictTemperatureRange(db, teamID, *request.StrictTemperatureRange)
+	if err != nil {
+		http.Error(w, "Could not update the team settings.", http.StatusInternalServerError)
+		log.Println("Could not update the team settings: " + err.Error())
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":                  "Team settings updated successfully",
+		"strict_temperature_range": *request.StrictTemperatureRange,
+	})
+	if err != nil {
+		log.Println("Could not JSON encode the response: " + err.Error())
+	}
+}
@@ -19,8 +19,8 @@ func GetUserAttributes(db *sql.DB, userID int, user *domain.User) error {
 
 // getTeamAttributes retrieves the team name and role for a user from the database.
 func getTeamAttributes(w http.ResponseWriter, db *sql.DB, user *domain.User, team *domain.Team, err error) {
-	err = db.QueryRow("SELECT name, team_role FROM team WHERE id = $1", user.Team).
-		Scan(&team.Name, &team.TeamRole)
+	err = db.QueryRow("SELECT name, team_role, strict_temperature_range FROM team WHERE id = $1", user.Team).
+		Scan(&team.Name, &team.TeamRole, &team.StrictTemperatureRange)
 	if err != nil {
 		http.Error(w, "Could not find the team name and role", http.StatusNotFound)
 		log.Printf("Could not find team name and role for user with id: %d Error: %v", user.ID, err.Error())
@@ -28,3 +28,9 @@ func getTeamAttributes(w http.ResponseWriter, db *sql.DB, user *domain.User, tea
 	}
 	return
 }
+
+// updateStrictTemperatureRange sets whether a team refuses results outside the rated temperature range of a product.
+func updateStrictTemperatureRange(db *sql.DB, teamID int, strict bool) error {
+	_, err := db.Exec("UPDATE team SET strict_temperature_range = $1 WHERE id = $2;", strict, teamID)
+	return err
+}
@@ -0,0 +1,6 @@
+package userProfileHandler
+
+type UserProfilePATCHRequest struct {
+	// Whether the team refuses results of products tested outside their rated temperature range.
+	StrictTemperatureRange *bool `json:"strict_temperature_range" validate:"required"`
+}
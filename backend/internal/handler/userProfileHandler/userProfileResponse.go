@@ -7,6 +7,7 @@ type UserProfileResponse struct {
 }
 
 type TeamResponse struct {
-	Name     string `json:"name"`
-	TeamRole string `json:"team_role"`
+	Name                   string `json:"name"`
+	TeamRole               string `json:"team_role"`
+	StrictTemperatureRange bool   `json:"strict_temperature_range"`
 }
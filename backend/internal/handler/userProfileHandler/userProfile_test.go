@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 )
 
@@ -161,10 +162,10 @@ func TestUserProfileRequestGET(t *testing.T) {
 					WillReturnRows(sqlmock.NewRows([]string{"email", "user_role", "team_id"}).
 						AddRow("test@example.com", "admin", 1))
 
-				(*mock).ExpectQuery("SELECT name, team_role FROM team WHERE id = \\$1").
+				(*mock).ExpectQuery("SELECT name, team_role, strict_temperature_range FROM team WHERE id = \\$1").
 					WithArgs(1).
-					WillReturnRows(sqlmock.NewRows([]string{"name", "team_role"}).
-						AddRow("Test Team", 999)) // Using invalid team role value
+					WillReturnRows(sqlmock.NewRows([]string{"name", "team_role", "strict_temperature_range"}).
+						AddRow("Test Team", 999, false)) // Using invalid team role value
 			},
 			expectedCode: http.StatusNotFound,
 			expectedBody: "Invalid team role",
@@ -181,13 +182,13 @@ func TestUserProfileRequestGET(t *testing.T) {
 					WillReturnRows(sqlmock.NewRows([]string{"email", "user_role", "team_id"}).
 						AddRow("test@example.com", "admin", 1))
 
-				(*mock).ExpectQuery("SELECT name, team_role FROM team WHERE id = \\$1").
+				(*mock).ExpectQuery("SELECT name, team_role, strict_temperature_range FROM team WHERE id = \\$1").
 					WithArgs(1).
-					WillReturnRows(sqlmock.NewRows([]string{"name", "team_role"}).
-						AddRow("Test Team", 1))
+					WillReturnRows(sqlmock.NewRows([]string{"name", "team_role", "strict_temperature_range"}).
+						AddRow("Test Team", 1, false))
 			},
 			expectedCode: http.StatusOK,
-			expectedBody: `{"email":"test@example.com","user_role":"admin","team":{"name":"Test Team","team_role":"Official"}}`,
+			expectedBody: `{"email":"test@example.com","user_role":"admin","team":{"name":"Test Team","team_role":"Official","strict_temperature_range":false}}`,
 		},
 		{
 			name: "Invalid team role",
@@ -201,10 +202,10 @@ func TestUserProfileRequestGET(t *testing.T) {
 					WillReturnRows(sqlmock.NewRows([]string{"email", "user_role", "team_id"}).
 						AddRow("test@example.com", "admin", 1))
 
-				(*mock).ExpectQuery("SELECT name, team_role FROM team WHERE id = \\$1").
+				(*mock).ExpectQuery("SELECT name, team_role, strict_temperature_range FROM team WHERE id = \\$1").
 					WithArgs(1).
-					WillReturnRows(sqlmock.NewRows([]string{"name", "team_role"}).
-						AddRow("Test Team", 999)) // Using invalid team role value
+					WillReturnRows(sqlmock.NewRows([]string{"name", "team_role", "strict_temperature_range"}).
+						AddRow("Test Team", 999, false)) // Using invalid team role value
 			},
 			expectedCode: http.StatusNotFound,
 			expectedBody: "Invalid team role\n",
@@ -236,6 +237,90 @@ func TestUserProfileRequestGET(t *testing.T) {
 	}
 }
 
+func TestUserProfileRequestPATCH(t *testing.T) {
+	userMock := func(mock *sqlmock.Sqlmock) {
+		(*mock).ExpectQuery(`SELECT user_id FROM sessions WHERE \(session_token = \$1 AND expires_at > NOW\(\)\)`).
+			WithArgs("mockToken").
+			WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(1))
+	}
+	roleMock := func(mock *sqlmock.Sqlmock, userRole string) {
+		userMock(mock)
+		(*mock).ExpectQuery("SELECT user_role FROM users WHERE id = \\$1;").
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"user_role"}).AddRow(userRole))
+	}
+	teamMock := func(mock *sqlmock.Sqlmock) {
+		userMock(mock)
+		(*mock).ExpectQuery("SELECT team_id FROM users WHERE id = \\$1;").
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"team_id"}).AddRow(2))
+	}
+
+	tests := []struct {
+		name         string
+		body         string
+		setupMocks   func(*sqlmock.Sqlmock)
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name: "Status OK",
+			body: `{"strict_temperature_range":true}`,
+			setupMocks: func(mock *sqlmock.Sqlmock) {
+				roleMock(mock, "admin")
+				teamMock(mock)
+				(*mock).ExpectExec("UPDATE team SET strict_temperature_range = \\$1 WHERE id = \\$2;").
+					WithArgs(true, 2).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			expectedCode: http.StatusOK,
+			expectedBody: `"strict_temperature_range":true`,
+		},
+		{
+			name: "Status unauthorized - user is not an admin",
+			body: `{"strict_temperature_range":true}`,
+			setupMocks: func(mock *sqlmock.Sqlmock) {
+				roleMock(mock, "member")
+			},
+			expectedCode: http.StatusUnauthorized,
+			expectedBody: resources.AuthenticationError,
+		},
+		{
+			name: "Status bad request - missing setting",
+			body: `{}`,
+			setupMocks: func(mock *sqlmock.Sqlmock) {
+				roleMock(mock, "admin")
+				teamMock(mock)
+			},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: resources.InvalidPATCHRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB, mock := utils.InitMockDB(t)
+
+			// Setup mocks for this test
+			tt.setupMocks(&mock)
+
+			req := httptest.NewRequest(http.MethodPatch, "/user/profile", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer mockToken")
+			rr := httptest.NewRecorder()
+
+			UserProfileHandler(mockDB).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedCode, rr.Code)
+			assert.Contains(t, rr.Body.String(), tt.expectedBody)
+
+			// Verify all mocks were called
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
 func Test_getTeamAttributes(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -249,7 +334,7 @@ func Test_getTeamAttributes(t *testing.T) {
 			user: &domain.User{ID: 1, Team: 1},
 			team: &domain.Team{},
 			setupMocks: func(mock *sqlmock.Sqlmock) {
-				(*mock).ExpectQuery("SELECT name, team_role FROM team WHERE id = \\$1").
+				(*mock).ExpectQuery("SELECT name, team_role, strict_temperature_range FROM team WHERE id = \\$1").
 					WithArgs(1).
 					WillReturnError(sql.ErrNoRows)
 			},
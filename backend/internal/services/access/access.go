@@ -0,0 +1,57 @@
+// Package access checks what a team is allowed to see and change.
+package access
+
+import (
+	"backend/internal/domain"
+	"database/sql"
+	"fmt"
+	"net/http"
+)
+
+// Querier is a database or a transaction to run the checks in.
+type Querier interface {
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// IsTeamSki reports whether a ski pair belongs to the team.
+func IsTeamSki(q Querier, skiID int, team int) (bool, error) {
+	var count int
+	err := q.QueryRow("SELECT COUNT(*) FROM skis WHERE id = $1 AND testing_team = $2;", skiID, team).Scan(&count)
+	return count > 0, err
+}
+
+// GetProductAvailability retrieves whether a product visible to the team is public. It returns sql.ErrNoRows when the
+// product does not exist or belongs to another team.
+func GetProductAvailability(q Querier, productID int, team int) (bool, error) {
+	var isPublic bool
+	err := q.QueryRow("SELECT is_public FROM products WHERE id = $1 AND (is_public OR testing_team = $2);",
+		productID, team).Scan(&isPublic)
+	return isPublic, err
+}
+
+// GetTestAccess retrieves the owning team, the visibility and the state of a test.
+func GetTestAccess(q Querier, testID int) (int, bool, domain.TestState, error) {
+	var testingTeam int
+	var isPublic bool
+	var state domain.TestState
+	err := q.QueryRow("SELECT testing_team, is_public, state FROM tests WHERE id = $1;", testID).
+		Scan(&testingTeam, &isPublic, &state)
+	return testingTeam, isPublic, state, err
+}
+
+// ValidateWritePermissions checks that the team is allowed to change a test or product. The entity names what is
+// changed in the error messages, and products pass an empty state since they have no lifecycle.
+func ValidateWritePermissions(entity string, testingTeam int, isPublic bool, state domain.TestState,
+	team int) (error, int) {
+	if testingTeam != team {
+		return fmt.Errorf("user cannot update this %s, %d", entity, http.StatusUnauthorized), http.StatusUnauthorized
+	}
+	if isPublic && domain.TeamRole(team) == domain.Researcher {
+		return fmt.Errorf("researcher cannot update public %ss, %d", entity, http.StatusUnauthorized),
+			http.StatusUnauthorized
+	}
+	if state.IsReadOnly() {
+		return fmt.Errorf("test is %s and cannot be changed, %d", state, http.StatusConflict), http.StatusConflict
+	}
+	return nil, 0
+}
@@ -0,0 +1,26 @@
+package access
+
+import (
+	"backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestValidateWritePermissions(t *testing.T) {
+	err, code := ValidateWritePermissions("test", 1, false, domain.TestInProgress, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, code)
+
+	err, code = ValidateWritePermissions("test", 2, false, domain.TestInProgress, 1)
+	assert.EqualError(t, err, "user cannot update this test, 401")
+	assert.Equal(t, http.StatusUnauthorized, code)
+
+	err, code = ValidateWritePermissions("product", int(domain.Researcher), true, "", int(domain.Researcher))
+	assert.EqualError(t, err, "researcher cannot update public products, 401")
+	assert.Equal(t, http.StatusUnauthorized, code)
+
+	err, code = ValidateWritePermissions("test", 1, false, domain.TestCompleted, 1)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusConflict, code)
+}
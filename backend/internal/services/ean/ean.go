@@ -0,0 +1,57 @@
+package ean
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrLength is returned for a code that is not an EAN-8, UPC-A or EAN-13 code.
+	ErrLength = errors.New("an EAN code has 8 (EAN-8), 12 (UPC-A) or 13 (EAN-13) digits")
+	// ErrDigits is returned for a code with other characters than digits.
+	ErrDigits = errors.New("an EAN code has only digits")
+)
+
+// Validate checks that a code is an EAN-8, UPC-A or EAN-13 code with a correct check digit.
+func Validate(code string) error {
+	if len(code) != 8 && len(code) != 12 && len(code) != 13 {
+		return ErrLength
+	}
+	for _, c := range code {
+		if c < '0' || c > '9' {
+			return ErrDigits
+		}
+	}
+
+	last := len(code) - 1
+	if check := CheckDigit(code[:last]); code[last] != check {
+		return fmt.Errorf("the check digit of %s should be %c", code, check)
+	}
+	return nil
+}
+
+// CheckDigit computes the check digit of the digits of an EAN or UPC code without its check digit. Counted from the
+// right, the digits are weighted 3 and 1 in turn, and the check digit brings the sum up to a multiple of 10.
+func CheckDigit(digits string) byte {
+	sum := 0
+	for i := range digits {
+		digit := int(digits[len(digits)-1-i] - '0')
+		if i%2 == 0 {
+			digit *= 3
+		}
+		sum += digit
+	}
+	return byte('0' + (10-sum%10)%10)
+}
+
+// Equivalent returns the codes that identify the same product as a code: a UPC-A code is the EAN-13 code with a
+// leading 0, so a product can be found by either. Other codes are only equivalent to themselves.
+func Equivalent(code string) []string {
+	switch {
+	case len(code) == 12:
+		return []string{code, "0" + code}
+	case len(code) == 13 && code[0] == '0':
+		return []string{code, code[1:]}
+	}
+	return []string{code}
+}
@@ -0,0 +1,46 @@
+package ean
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		code    string
+		wantErr string
+	}{
+		{name: "EAN-13", code: "7038010055720"},
+		{name: "EAN-13 with check digit 0", code: "4006381333931"},
+		{name: "EAN-8", code: "96385074"},
+		{name: "UPC-A", code: "036000291452"},
+		{name: "Wrong check digit", code: "1234567890123", wantErr: "the check digit of 1234567890123 should be 8"},
+		{name: "Wrong length", code: "123456789", wantErr: ErrLength.Error()},
+		{name: "Letters", code: "12345678901AB", wantErr: ErrDigits.Error()},
+		{name: "Empty", code: "", wantErr: ErrLength.Error()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.code)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestCheckDigit(t *testing.T) {
+	assert.Equal(t, byte('8'), CheckDigit("123456789012"))
+	assert.Equal(t, byte('4'), CheckDigit("9638507"))
+	assert.Equal(t, byte('2'), CheckDigit("03600029145"))
+}
+
+func TestEquivalent(t *testing.T) {
+	assert.Equal(t, []string{"036000291452", "0036000291452"}, Equivalent("036000291452"))
+	assert.Equal(t, []string{"0036000291452", "036000291452"}, Equivalent("0036000291452"))
+	assert.Equal(t, []string{"7038010055720"}, Equivalent("7038010055720"))
+	assert.Equal(t, []string{"96385074"}, Equivalent("96385074"))
+}
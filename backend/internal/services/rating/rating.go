@@ -0,0 +1,158 @@
+package rating
+
+import (
+	"math"
+	"sort"
+)
+
+// BaseRating is the rating of a product with no results, and the rating every product is pulled towards.
+const BaseRating = 1500
+
+// scale converts a log-strength to rating points, so a difference of 400 points means 10 to 1 odds.
+const scale = 400 / math.Ln10
+
+// priorGames is the number of virtual games every product wins and loses against a product with the base rating.
+// It keeps the ratings of products that never lost, or never won, finite, and anchors the scale when the products
+// were never compared with each other.
+const priorGames = 1.0
+
+const (
+	maxIterations = 1000
+	tolerance     = 1e-9
+)
+
+// Placing is the rank of a product in a test. Products with the same rank are tied.
+type Placing struct {
+	ProductID int
+	Rank      int
+}
+
+// Result is the fitted rating of a product.
+type Result struct {
+	Rating      float64 // Rating points, BaseRating for an average product.
+	Uncertainty float64 // Standard error of the rating, in rating points.
+	Tests       int     // Number of tests the product was compared in.
+	Comparisons int     // Number of pairwise comparisons with other products, over all the tests.
+}
+
+// pair is a product compared with another product, with the number of comparisons and the wins against it.
+type pair struct {
+	games float64
+	wins  float64
+}
+
+// Fit fits a Bradley-Terry model to the placings of the tests. Every test is a set of pairwise comparisons, where the
+// better ranked product wins and tied products share the win. The initial ratings, such as the previous fit, are
+// only a starting point and speed up the fit when few results have changed.
+func Fit(tests [][]Placing, initial map[int]float64) map[int]Result {
+	opponents := map[int]map[int]*pair{}
+	results := map[int]Result{}
+	for _, test := range tests {
+		for i, a := range test {
+			for _, b := range test[i+1:] {
+				if a.ProductID == b.ProductID {
+					continue
+				}
+				win := 0.5
+				if a.Rank < b.Rank {
+					win = 1
+				} else if a.Rank > b.Rank {
+					win = 0
+				}
+				addGame(opponents, a.ProductID, b.ProductID, win)
+				addGame(opponents, b.ProductID, a.ProductID, 1-win)
+			}
+		}
+
+		seen := map[int]bool{}
+		for _, placing := range test {
+			if len(test) < 2 || seen[placing.ProductID] {
+				continue
+			}
+			seen[placing.ProductID] = true
+			result := results[placing.ProductID]
+			result.Tests++
+			results[placing.ProductID] = result
+		}
+	}
+
+	products := make([]int, 0, len(opponents))
+	for product := range opponents {
+		products = append(products, product)
+	}
+	sort.Ints(products)
+
+	strength := map[int]float64{}
+	for _, product := range products {
+		strength[product] = 1
+		if rating, ok := initial[product]; ok {
+			strength[product] = math.Exp((rating - BaseRating) / scale)
+		}
+	}
+
+	// Minorization-maximization updates, with the prior as games against a product of strength 1. Each product is
+	// updated in place, which converges faster than updating all the products at once.
+	for iteration := 0; iteration < maxIterations; iteration++ {
+		change := 0.0
+		for _, product := range products {
+			wins := priorGames
+			denominator := 2 * priorGames / (strength[product] + 1)
+			for opponent, games := range opponents[product] {
+				wins += games.wins
+				denominator += games.games / (strength[product] + strength[opponent])
+			}
+			next := wins / denominator
+			change = math.Max(change, math.Abs(math.Log(next/strength[product])))
+			strength[product] = next
+		}
+		if change < tolerance {
+			break
+		}
+	}
+
+	for _, product := range products {
+		// The uncertainty is the inverse square root of the Fisher information of the log-strength.
+		p := strength[product] / (strength[product] + 1)
+		information := 2 * priorGames * p * (1 - p)
+		comparisons := 0
+		for opponent, games := range opponents[product] {
+			p = strength[product] / (strength[product] + strength[opponent])
+			information += games.games * p * (1 - p)
+			comparisons += int(games.games)
+		}
+
+		result := results[product]
+		result.Rating = BaseRating + scale*math.Log(strength[product])
+		result.Uncertainty = scale / math.Sqrt(information)
+		result.Comparisons = comparisons
+		results[product] = result
+	}
+
+	// Products that were never compared with another product have no rating.
+	for product := range results {
+		if _, ok := opponents[product]; !ok {
+			delete(results, product)
+		}
+	}
+	return results
+}
+
+func addGame(opponents map[int]map[int]*pair, product int, opponent int, win float64) {
+	if opponents[product] == nil {
+		opponents[product] = map[int]*pair{}
+	}
+	if opponents[product][opponent] == nil {
+		opponents[product][opponent] = &pair{}
+	}
+	opponents[product][opponent].games++
+	opponents[product][opponent].wins += win
+}
+
+// TemperatureBandWidth is the width of the temperature bands ratings are fitted for, in degrees Celsius.
+const TemperatureBandWidth = 5
+
+// TemperatureBand returns the lower bound of the temperature band of a snow temperature. The band of -7.5 °C is -10,
+// covering -10 °C up to but not including -5 °C.
+func TemperatureBand(temperature float64) int {
+	return int(math.Floor(temperature/TemperatureBandWidth)) * TemperatureBandWidth
+}
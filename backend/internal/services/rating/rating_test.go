@@ -0,0 +1,53 @@
+package rating
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFit(t *testing.T) {
+	// Products 1 and 3 never met, but 1 beat 2 and 2 beat 3.
+	tests := [][]Placing{
+		{{ProductID: 1, Rank: 1}, {ProductID: 2, Rank: 2}},
+		{{ProductID: 1, Rank: 1}, {ProductID: 2, Rank: 2}},
+		{{ProductID: 2, Rank: 1}, {ProductID: 3, Rank: 2}},
+		{{ProductID: 2, Rank: 1}, {ProductID: 3, Rank: 2}},
+		{{ProductID: 4, Rank: 1}},
+	}
+	results := Fit(tests, nil)
+
+	assert.Len(t, results, 3)
+	assert.Greater(t, results[1].Rating, results[2].Rating)
+	assert.Greater(t, results[2].Rating, results[3].Rating)
+	assert.InDelta(t, BaseRating, results[2].Rating, 0.01)
+	assert.InDelta(t, results[1].Rating-BaseRating, BaseRating-results[3].Rating, 0.01)
+	assert.Equal(t, Result{Rating: results[1].Rating, Uncertainty: results[1].Uncertainty, Tests: 2, Comparisons: 2},
+		results[1])
+	assert.Equal(t, 4, results[2].Comparisons)
+
+	// The product with more results is the most certain.
+	assert.Less(t, results[2].Uncertainty, results[1].Uncertainty)
+
+	// Starting from the previous fit gives the same ratings.
+	initial := map[int]float64{1: results[1].Rating, 2: results[2].Rating, 3: results[3].Rating}
+	refit := Fit(tests, initial)
+	assert.InDelta(t, results[1].Rating, refit[1].Rating, 0.01)
+}
+
+func TestFit_ties(t *testing.T) {
+	results := Fit([][]Placing{
+		{{ProductID: 1, Rank: 1}, {ProductID: 2, Rank: 1}, {ProductID: 3, Rank: 3}},
+	}, nil)
+
+	assert.InDelta(t, results[1].Rating, results[2].Rating, 0.01)
+	assert.Greater(t, results[1].Rating, float64(BaseRating))
+	assert.Less(t, results[3].Rating, float64(BaseRating))
+}
+
+func TestTemperatureBand(t *testing.T) {
+	assert.Equal(t, -10, TemperatureBand(-7.5))
+	assert.Equal(t, -5, TemperatureBand(-5))
+	assert.Equal(t, 0, TemperatureBand(0))
+	assert.Equal(t, 0, TemperatureBand(4.9))
+	assert.Equal(t, -5, TemperatureBand(-0.1))
+}
@@ -0,0 +1,129 @@
+package similarity
+
+import "math"
+
+// Conditions are the conditions of a test, or the conditions to compare the tests with. Numeric fields that are nil
+// and enum fields that are empty are unknown, and are left out of the distance.
+type Conditions struct {
+	SnowTemperature *float64
+	SnowType        string // 'A1', 'A2', 'A3', 'A4', 'A5', 'FS', 'NS', 'IN', 'IT', 'TR'
+	SnowHumidity    string // 'DS', 'W1', 'W2', 'W3', 'W4'
+	AirTemperature  *float64
+	AirHumidity     *float64
+	Wind            string // 'S', 'L', 'M', 'ST'
+	Cloud           string // '1', '2', '3', '4'
+	TrackHardness   string // 'H1', 'H2', 'H3', 'H4', 'H5', 'H6'
+	TrackType       string // 'T1', 'T2', 'D1', 'D2'
+}
+
+// Weights are the relative weights of the condition fields in the distance. A field with weight 0 is ignored.
+type Weights struct {
+	SnowTemperature float64 `json:"snow_temperature" validate:"gte=0"`
+	SnowType        float64 `json:"snow_type" validate:"gte=0"`
+	SnowHumidity    float64 `json:"snow_humidity" validate:"gte=0"`
+	AirTemperature  float64 `json:"air_temperature" validate:"gte=0"`
+	AirHumidity     float64 `json:"air_humidity" validate:"gte=0"`
+	Wind            float64 `json:"wind" validate:"gte=0"`
+	Cloud           float64 `json:"cloud" validate:"gte=0"`
+	TrackHardness   float64 `json:"track_hardness" validate:"gte=0"`
+	TrackType       float64 `json:"track_type" validate:"gte=0"`
+}
+
+// DefaultWeights weighs the snow the most, as it matters the most for the glide, followed by the track and the air.
+var DefaultWeights = Weights{
+	SnowTemperature: 3,
+	SnowType:        3,
+	SnowHumidity:    2,
+	AirTemperature:  1,
+	AirHumidity:     1,
+	Wind:            0.5,
+	Cloud:           0.5,
+	TrackHardness:   2,
+	TrackType:       1,
+}
+
+// The differences of the numeric fields that count as a distance of 1, as much as the two ends of an enum.
+const (
+	snowTemperatureScale = 10.0 // °C
+	airTemperatureScale  = 15.0 // °C
+	airHumidityScale     = 50.0 // %
+)
+
+// The levels of the enums, in order, as defined in the database.
+var (
+	snowTypeLevels      = []string{"A1", "A2", "A3", "A4", "A5", "FS", "NS", "IN", "IT", "TR"}
+	snowHumidityLevels  = []string{"DS", "W1", "W2", "W3", "W4"}
+	windLevels          = []string{"S", "L", "M", "ST"}
+	cloudLevels         = []string{"1", "2", "3", "4"}
+	trackHardnessLevels = []string{"H1", "H2", "H3", "H4", "H5", "H6"}
+	trackTypeLevels     = []string{"T1", "T2", "D1", "D2"}
+)
+
+// Ordinal encodes a level of an enum as its position between 0 for the first level and 1 for the last, or returns
+// false if the level is not one of the levels.
+func Ordinal(levels []string, level string) (float64, bool) {
+	for i, l := range levels {
+		if l == level {
+			return float64(i) / float64(len(levels)-1), true
+		}
+	}
+	return 0, false
+}
+
+// Distance returns the weighted distance between the conditions, from 0 for the same conditions to about 1 when
+// all the fields are as far apart as they can be. Only the fields known in both conditions are compared; the
+// distance is 0 when none of them are.
+func Distance(a Conditions, b Conditions, weights Weights) float64 {
+	sum, total := 0.0, 0.0
+	add := func(weight float64, difference float64) {
+		sum += weight * difference * difference
+		total += weight
+	}
+
+	for _, field := range []struct {
+		weight float64
+		a, b   *float64
+		scale  float64
+	}{
+		{weights.SnowTemperature, a.SnowTemperature, b.SnowTemperature, snowTemperatureScale},
+		{weights.AirTemperature, a.AirTemperature, b.AirTemperature, airTemperatureScale},
+		{weights.AirHumidity, a.AirHumidity, b.AirHumidity, airHumidityScale},
+	} {
+		if field.weight > 0 && field.a != nil && field.b != nil {
+			add(field.weight, (*field.a-*field.b)/field.scale)
+		}
+	}
+
+	for _, field := range []struct {
+		weight float64
+		a, b   string
+		levels []string
+	}{
+		{weights.SnowType, a.SnowType, b.SnowType, snowTypeLevels},
+		{weights.SnowHumidity, a.SnowHumidity, b.SnowHumidity, snowHumidityLevels},
+		{weights.Wind, a.Wind, b.Wind, windLevels},
+		{weights.Cloud, a.Cloud, b.Cloud, cloudLevels},
+		{weights.TrackHardness, a.TrackHardness, b.TrackHardness, trackHardnessLevels},
+		{weights.TrackType, a.TrackType, b.TrackType, trackTypeLevels},
+	} {
+		x, okA := Ordinal(field.levels, field.a)
+		y, okB := Ordinal(field.levels, field.b)
+		if field.weight > 0 && okA && okB {
+			add(field.weight, x-y)
+		}
+	}
+
+	if total == 0 {
+		return 0
+	}
+	return math.Sqrt(sum / total)
+}
+
+// Bandwidth is the distance at which a test counts for about a third of a test in the same conditions.
+const Bandwidth = 0.25
+
+// Similarity turns a distance into the weight of a test, from 1 for the same conditions towards 0 as the distance
+// grows past the bandwidth.
+func Similarity(distance float64) float64 {
+	return math.Exp(-(distance / Bandwidth) * (distance / Bandwidth))
+}
@@ -0,0 +1,63 @@
+package similarity
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+func float(value float64) *float64 {
+	return &value
+}
+
+func TestOrdinal(t *testing.T) {
+	value, ok := Ordinal(trackHardnessLevels, "H1")
+	assert.True(t, ok)
+	assert.Equal(t, 0.0, value)
+
+	value, ok = Ordinal(trackHardnessLevels, "H6")
+	assert.True(t, ok)
+	assert.Equal(t, 1.0, value)
+
+	value, ok = Ordinal(snowHumidityLevels, "W2")
+	assert.True(t, ok)
+	assert.Equal(t, 0.5, value)
+
+	_, ok = Ordinal(snowHumidityLevels, "W9")
+	assert.False(t, ok)
+}
+
+func TestDistance(t *testing.T) {
+	today := Conditions{SnowTemperature: float(-8), SnowType: "A2", SnowHumidity: "DS", TrackHardness: "H3"}
+
+	// The same conditions, with fields that are unknown today.
+	same := today
+	same.AirTemperature = float(-12)
+	same.Wind = "ST"
+	assert.Equal(t, 0.0, Distance(today, same, DefaultWeights))
+
+	// A colder test is further away than a slightly colder one.
+	colder := today
+	colder.SnowTemperature = float(-18)
+	slightlyColder := today
+	slightlyColder.SnowTemperature = float(-10)
+	assert.Greater(t, Distance(today, colder, DefaultWeights), Distance(today, slightlyColder, DefaultWeights))
+
+	// Only the snow temperature counts: 10 °C apart is a distance of 1.
+	assert.InDelta(t, 1.0, Distance(today, colder, Weights{SnowTemperature: 1}), 1e-9)
+
+	// Two of four equally weighted fields at opposite ends.
+	opposite := Conditions{SnowTemperature: float(-8), SnowType: "A2", SnowHumidity: "W4", TrackHardness: "H6"}
+	start := Conditions{SnowTemperature: float(-8), SnowType: "A2", SnowHumidity: "DS", TrackHardness: "H1"}
+	weights := Weights{SnowTemperature: 1, SnowType: 1, SnowHumidity: 1, TrackHardness: 1}
+	assert.InDelta(t, math.Sqrt(0.5), Distance(start, opposite, weights), 1e-9)
+
+	// Nothing to compare.
+	assert.Equal(t, 0.0, Distance(today, Conditions{}, DefaultWeights))
+}
+
+func TestSimilarity(t *testing.T) {
+	assert.Equal(t, 1.0, Similarity(0))
+	assert.InDelta(t, math.Exp(-1), Similarity(Bandwidth), 1e-9)
+	assert.Less(t, Similarity(1), 0.001)
+}
@@ -0,0 +1,104 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+// ErrNotFound is returned when no file is stored under a key.
+var ErrNotFound = errors.New("file not found")
+
+// validKey only allows the flat keys generated by the server, so a key can never point outside the storage.
+var validKey = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+// Storage is a backend for storing uploaded files under a key.
+type Storage interface {
+	// Save stores the content under the key, replacing any existing file.
+	Save(key string, content io.Reader) (int64, error)
+	// Open opens the file stored under the key. The caller must close the file.
+	Open(key string) (io.ReadCloser, error)
+	// Delete removes the file stored under the key. Deleting a missing file is not an error.
+	Delete(key string) error
+}
+
+// LocalStorage stores the files in a directory on the local filesystem.
+type LocalStorage struct {
+	Dir string
+}
+
+// NewLocalStorage creates the directory if needed and returns a storage backed by it.
+func NewLocalStorage(dir string) (*LocalStorage, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("could not create the storage directory: %w", err)
+	}
+	return &LocalStorage{Dir: dir}, nil
+}
+
+// NewLocalStorageFromEnv returns a storage in the ATTACHMENTS_DIR directory, or in ./attachments when it is not set.
+func NewLocalStorageFromEnv() (*LocalStorage, error) {
+	dir, exists := os.LookupEnv("ATTACHMENTS_DIR")
+	if !exists || dir == "" {
+		dir = "attachments"
+	}
+	return NewLocalStorage(dir)
+}
+
+func (s *LocalStorage) path(key string) (string, error) {
+	if !validKey.MatchString(key) {
+		return "", fmt.Errorf("invalid storage key: %q", key)
+	}
+	return filepath.Join(s.Dir, key), nil
+}
+
+func (s *LocalStorage) Save(key string, content io.Reader) (int64, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return 0, err
+	}
+
+	// Write to a temporary file first, so a failed upload never leaves a partial file under the key.
+	tmp, err := os.CreateTemp(s.Dir, ".upload-*")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(tmp.Name())
+
+	size, err := io.Copy(tmp, content)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return 0, err
+	}
+	return size, os.Rename(tmp.Name(), path)
+}
+
+func (s *LocalStorage) Open(key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return file, err
+}
+
+func (s *LocalStorage) Delete(key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
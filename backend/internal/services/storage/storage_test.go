@@ -0,0 +1,42 @@
+package storage
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestLocalStorage(t *testing.T) {
+	store, err := NewLocalStorage(t.TempDir())
+	assert.NoError(t, err)
+
+	size, err := store.Save("a1b2c3", strings.NewReader("snow crystals"))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(13), size)
+
+	file, err := store.Open("a1b2c3")
+	assert.NoError(t, err)
+	content, _ := io.ReadAll(file)
+	assert.NoError(t, file.Close())
+	assert.Equal(t, "snow crystals", string(content))
+
+	assert.NoError(t, store.Delete("a1b2c3"))
+	_, err = store.Open("a1b2c3")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	// Deleting a missing file is not an error.
+	assert.NoError(t, store.Delete("a1b2c3"))
+}
+
+func TestLocalStorage_invalidKey(t *testing.T) {
+	store, err := NewLocalStorage(t.TempDir())
+	assert.NoError(t, err)
+
+	for _, key := range []string{"", "../secret", "a/b", ".hidden"} {
+		_, err = store.Save(key, strings.NewReader("x"))
+		assert.Error(t, err, key)
+		_, err = store.Open(key)
+		assert.Error(t, err, key)
+	}
+}
@@ -0,0 +1,61 @@
+package thumbnail
+
+import (
+	"bytes"
+	"encoding/binary"
+	"github.com/stretchr/testify/assert"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func TestMake(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 1024, 512))
+	for y := 0; y < 512; y++ {
+		for x := 0; x < 1024; x++ {
+			src.Set(x, y, color.RGBA{R: 200, G: 20, B: 20, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	assert.NoError(t, png.Encode(&buf, src))
+
+	thumb, err := Make(&buf)
+	assert.NoError(t, err)
+
+	decoded, format, err := image.Decode(bytes.NewReader(thumb))
+	assert.NoError(t, err)
+	assert.Equal(t, "jpeg", format)
+	assert.Equal(t, image.Rect(0, 0, 256, 128), decoded.Bounds())
+
+	r, _, _, _ := decoded.At(100, 50).RGBA()
+	assert.InDelta(t, 200, r>>8, 10)
+}
+
+func TestMake_invalidImage(t *testing.T) {
+	_, err := Make(strings.NewReader("not an image"))
+	assert.Error(t, err)
+}
+
+func TestMake_tooLarge(t *testing.T) {
+	// A PNG header that declares a 10000x10000 image, with no pixel data behind it.
+	var buf bytes.Buffer
+	assert.NoError(t, png.Encode(&buf, image.NewGray(image.Rect(0, 0, 1, 1))))
+	header := buf.Bytes()[:33]
+	binary.BigEndian.PutUint32(header[16:], 10000)
+	binary.BigEndian.PutUint32(header[20:], 10000)
+	binary.BigEndian.PutUint32(header[29:], crc32.ChecksumIEEE(header[12:29]))
+
+	_, err := Make(bytes.NewReader(header))
+	assert.ErrorIs(t, err, ErrTooLarge)
+}
+
+func Test_scale(t *testing.T) {
+	small := image.NewRGBA(image.Rect(0, 0, 100, 40))
+	assert.Equal(t, small.Bounds(), scale(small, MaxSize).Bounds())
+
+	tall := image.NewRGBA(image.Rect(0, 0, 300, 3000))
+	assert.Equal(t, image.Rect(0, 0, 25, 256), scale(tall, MaxSize).Bounds())
+}
@@ -0,0 +1,48 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestWriter(t *testing.T) {
+	var buf bytes.Buffer
+	writer, err := NewWriter(&buf, "Tests & results")
+	assert.NoError(t, err)
+	assert.NoError(t, writer.WriteRow("test_id", "location", "rank"))
+	assert.NoError(t, writer.WriteRow(1, "Holmenkollen <Oslo>", nil, -8.5, true,
+		time.Date(2025, 1, 12, 0, 0, 0, 0, time.UTC)))
+	assert.NoError(t, writer.Close())
+
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.NoError(t, err)
+
+	files := map[string]string{}
+	for _, file := range archive.File {
+		reader, err := file.Open()
+		assert.NoError(t, err)
+		content, _ := io.ReadAll(reader)
+		files[file.Name] = string(content)
+	}
+
+	assert.Contains(t, files, "[Content_Types].xml")
+	assert.Contains(t, files["xl/workbook.xml"], `<sheet name="Tests &amp; results"`)
+	assert.Contains(t, files["xl/worksheets/sheet1.xml"],
+		`<row r="1"><c r="A1" t="inlineStr"><is><t xml:space="preserve">test_id</t></is></c>`)
+	assert.Contains(t, files["xl/worksheets/sheet1.xml"],
+		`<row r="2"><c r="A2"><v>1</v></c><c r="B2" t="inlineStr"><is><t xml:space="preserve">Holmenkollen &lt;Oslo&gt;</t></is></c>`+
+			`<c r="D2"><v>-8.5</v></c><c r="E2" t="b"><v>1</v></c>`+
+			`<c r="F2" t="inlineStr"><is><t>2025-01-12T00:00:00Z</t></is></c></row></sheetData></worksheet>`)
+}
+
+func Test_columnName(t *testing.T) {
+	assert.Equal(t, "A", columnName(0))
+	assert.Equal(t, "Z", columnName(25))
+	assert.Equal(t, "AA", columnName(26))
+	assert.Equal(t, "AZ", columnName(51))
+	assert.Equal(t, "BA", columnName(52))
+}
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS public.tournament_matches;
+DROP TABLE IF EXISTS public.tournaments;
+DROP TYPE IF EXISTS public.tournament_status;
@@ -0,0 +1,32 @@
+-- Head-to-head tournaments, where every match between two products consists of two battles.
+CREATE TYPE public.tournament_status AS ENUM (
+    'in_progress',
+    'completed'
+);
+
+CREATE TABLE public.tournaments (
+    id bigint GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    test_id bigint NOT NULL UNIQUE,
+    status public.tournament_status DEFAULT 'in_progress'::public.tournament_status NOT NULL,
+    version timestamp without time zone DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_tournaments_test FOREIGN KEY (test_id) REFERENCES public.tests(id) ON DELETE CASCADE
+);
+
+-- The battles are stored with reference to product 1, positive if product 1 won the battle.
+CREATE TABLE public.tournament_matches (
+    id bigint GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    tournament_id bigint NOT NULL,
+    round integer NOT NULL,
+    match_no integer NOT NULL,
+    product1_id bigint,
+    product2_id bigint,
+    battle1 integer,
+    battle2 integer,
+    winner_id bigint,
+    version timestamp without time zone DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT tournament_matches_round_match_key UNIQUE (tournament_id, round, match_no),
+    CONSTRAINT fk_tournament_matches_tournament FOREIGN KEY (tournament_id) REFERENCES public.tournaments(id) ON DELETE CASCADE,
+    CONSTRAINT fk_tournament_matches_product1 FOREIGN KEY (product1_id) REFERENCES public.products(id) ON DELETE CASCADE,
+    CONSTRAINT fk_tournament_matches_product2 FOREIGN KEY (product2_id) REFERENCES public.products(id) ON DELETE CASCADE,
+    CONSTRAINT fk_tournament_matches_winner FOREIGN KEY (winner_id) REFERENCES public.products(id) ON DELETE CASCADE
+);
@@ -0,0 +1,13 @@
+-- Partially filled tests, stored as JSON so the fields can be filled in over several sessions and devices.
+CREATE TABLE public.test_drafts (
+    id bigint GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    testing_team bigint NOT NULL,
+    created_by bigint,
+    payload jsonb DEFAULT '{}'::jsonb NOT NULL,
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    version timestamp without time zone DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_test_drafts_team FOREIGN KEY (testing_team) REFERENCES public.team(id) ON DELETE CASCADE,
+    CONSTRAINT fk_test_drafts_user FOREIGN KEY (created_by) REFERENCES public.users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX test_drafts_testing_team_idx ON public.test_drafts (testing_team);
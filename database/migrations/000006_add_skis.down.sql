@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS public.test_ranks_ski_id_idx;
+ALTER TABLE public.test_ranks DROP COLUMN IF EXISTS ski_id;
+DROP TABLE IF EXISTS public.skis;
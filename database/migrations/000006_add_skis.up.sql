@@ -0,0 +1,22 @@
+-- The test skis of each team. The ski code is the team's own label for the ski pair.
+CREATE TABLE public.skis (
+    id bigint GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    ski_code character varying(32) NOT NULL,
+    brand character varying(64) DEFAULT ''::character varying NOT NULL,
+    base character varying(64) DEFAULT ''::character varying NOT NULL,
+    grind character varying(64) DEFAULT ''::character varying NOT NULL,
+    flex character varying(32) DEFAULT ''::character varying NOT NULL,
+    length integer DEFAULT 0 NOT NULL,
+    notes character varying(2040) DEFAULT ''::character varying NOT NULL,
+    testing_team bigint NOT NULL,
+    version timestamp without time zone DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT skis_testing_team_ski_code_key UNIQUE (testing_team, ski_code),
+    CONSTRAINT fk_skis_team FOREIGN KEY (testing_team) REFERENCES public.team(id) ON DELETE CASCADE
+);
+
+-- The ski pair a product was tested on.
+ALTER TABLE public.test_ranks
+    ADD COLUMN ski_id bigint,
+    ADD CONSTRAINT fk_test_ranks_ski FOREIGN KEY (ski_id) REFERENCES public.skis(id) ON DELETE SET NULL;
+
+CREATE INDEX test_ranks_ski_id_idx ON public.test_ranks (ski_id);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS public.test_runs;
+DROP TYPE IF EXISTS public.run_metric;
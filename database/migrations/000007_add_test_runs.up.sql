@@ -0,0 +1,21 @@
+-- Raw glide runs of a test. Distance runs are glide-out distances in meters, time runs are gate times in seconds.
+CREATE TYPE public.run_metric AS ENUM (
+    'distance',
+    'time'
+);
+
+CREATE TABLE public.test_runs (
+    id bigint GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    test_id bigint NOT NULL,
+    product_id bigint NOT NULL,
+    ski_id bigint,
+    metric public.run_metric NOT NULL,
+    value double precision NOT NULL,
+    recorded_at timestamp without time zone NOT NULL,
+    version timestamp without time zone DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_test_runs_test FOREIGN KEY (test_id) REFERENCES public.tests(id) ON DELETE CASCADE,
+    CONSTRAINT fk_test_runs_product FOREIGN KEY (product_id) REFERENCES public.products(id) ON DELETE CASCADE,
+    CONSTRAINT fk_test_runs_ski FOREIGN KEY (ski_id) REFERENCES public.skis(id) ON DELETE SET NULL
+);
+
+CREATE INDEX test_runs_test_id_idx ON public.test_runs (test_id, product_id);
@@ -0,0 +1,17 @@
+-- Timestamped snow and air readings taken during a test, in addition to the single conditions of the test.
+CREATE TABLE public.condition_readings (
+    id bigint GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    test_id bigint NOT NULL,
+    recorded_at timestamp without time zone NOT NULL,
+    snow_temperature double precision NOT NULL,
+    snow_type public.snow_type_level,
+    snow_humidity public.humidity_levels,
+    air_temperature double precision NOT NULL,
+    air_humidity integer NOT NULL,
+    wind public.wind_levels,
+    cloud public.cloud_level,
+    version timestamp without time zone DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_condition_readings_test FOREIGN KEY (test_id) REFERENCES public.tests(id) ON DELETE CASCADE
+);
+
+CREATE INDEX condition_readings_test_id_idx ON public.condition_readings (test_id, recorded_at);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS public.tests_location_id_idx;
+ALTER TABLE public.tests DROP COLUMN IF EXISTS track_id;
+ALTER TABLE public.tests DROP COLUMN IF EXISTS location_id;
+DROP TABLE IF EXISTS public.tracks;
+DROP TABLE IF EXISTS public.locations;
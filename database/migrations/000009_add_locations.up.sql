@@ -0,0 +1,45 @@
+-- Named test locations with coordinates, and the tracks at every location.
+CREATE TABLE public.locations (
+    id bigint GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    name character varying(50) NOT NULL,
+    latitude double precision,
+    longitude double precision,
+    altitude integer,
+    version timestamp without time zone DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT locations_latitude_check CHECK (latitude BETWEEN -90 AND 90),
+    CONSTRAINT locations_longitude_check CHECK (longitude BETWEEN -180 AND 180)
+);
+
+CREATE UNIQUE INDEX locations_name_key ON public.locations (lower(name));
+
+CREATE TABLE public.tracks (
+    id bigint GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    location_id bigint NOT NULL,
+    name character varying(50) NOT NULL,
+    length integer,
+    version timestamp without time zone DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_tracks_location FOREIGN KEY (location_id) REFERENCES public.locations(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX tracks_location_name_key ON public.tracks (location_id, lower(name));
+
+ALTER TABLE public.tests
+    ADD COLUMN location_id bigint,
+    ADD COLUMN track_id bigint,
+    ADD CONSTRAINT fk_tests_location FOREIGN KEY (location_id) REFERENCES public.locations(id) ON DELETE RESTRICT,
+    ADD CONSTRAINT fk_tests_track FOREIGN KEY (track_id) REFERENCES public.tracks(id) ON DELETE RESTRICT;
+
+CREATE INDEX tests_location_id_idx ON public.tests (location_id);
+
+-- Migrate the free-text locations of the existing tests. Names that only differ in case or surrounding
+-- whitespace become the same location, other spellings of the same place must be merged by hand.
+INSERT INTO public.locations (name)
+SELECT DISTINCT ON (lower(trim(location))) trim(location)
+FROM public.tests
+WHERE trim(location) <> ''
+ORDER BY lower(trim(location)), trim(location);
+
+UPDATE public.tests t
+SET location_id = l.id
+FROM public.locations l
+WHERE lower(trim(t.location)) = lower(l.name);
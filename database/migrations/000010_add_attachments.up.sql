@@ -0,0 +1,22 @@
+-- Photos and files attached to either a test or a product. The files live in the storage backend under storage_key.
+CREATE TABLE public.attachments (
+    id bigint GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    test_id bigint,
+    product_id bigint,
+    file_name character varying(255) NOT NULL,
+    content_type character varying(100) NOT NULL,
+    size bigint NOT NULL,
+    storage_key character varying(64) NOT NULL UNIQUE,
+    thumbnail_key character varying(64),
+    uploaded_by bigint,
+    testing_team bigint NOT NULL,
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    CONSTRAINT attachments_single_parent CHECK ((test_id IS NULL) <> (product_id IS NULL)),
+    CONSTRAINT fk_attachments_test FOREIGN KEY (test_id) REFERENCES public.tests(id) ON DELETE CASCADE,
+    CONSTRAINT fk_attachments_product FOREIGN KEY (product_id) REFERENCES public.products(id) ON DELETE CASCADE,
+    CONSTRAINT fk_attachments_user FOREIGN KEY (uploaded_by) REFERENCES public.users(id) ON DELETE SET NULL,
+    CONSTRAINT fk_attachments_team FOREIGN KEY (testing_team) REFERENCES public.team(id)
+);
+
+CREATE INDEX attachments_test_id_idx ON public.attachments (test_id);
+CREATE INDEX attachments_product_id_idx ON public.attachments (product_id);
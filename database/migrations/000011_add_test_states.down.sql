@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS public.test_state_changes;
+DROP INDEX IF EXISTS public.tests_state_idx;
+ALTER TABLE public.tests DROP COLUMN IF EXISTS state;
+DROP TYPE IF EXISTS public.test_state;
@@ -0,0 +1,29 @@
+-- Lifecycle states of a test. Completed and published tests are read-only, and only they are included in reports.
+CREATE TYPE public.test_state AS ENUM (
+    'planned',
+    'in_progress',
+    'completed',
+    'published'
+);
+
+ALTER TABLE public.tests ADD COLUMN state public.test_state DEFAULT 'in_progress'::public.test_state NOT NULL;
+
+-- The existing tests already have their results, so they are completed, or published when they are public.
+UPDATE public.tests SET state = CASE WHEN is_public THEN 'published'::public.test_state
+                                     ELSE 'completed'::public.test_state END;
+
+CREATE INDEX tests_state_idx ON public.tests (state);
+
+-- Every change of the state of a test, with who made it and when.
+CREATE TABLE public.test_state_changes (
+    id bigint GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    test_id bigint NOT NULL,
+    from_state public.test_state NOT NULL,
+    to_state public.test_state NOT NULL,
+    changed_by bigint,
+    changed_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    CONSTRAINT fk_test_state_changes_test FOREIGN KEY (test_id) REFERENCES public.tests(id) ON DELETE CASCADE,
+    CONSTRAINT fk_test_state_changes_user FOREIGN KEY (changed_by) REFERENCES public.users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX test_state_changes_test_id_idx ON public.test_state_changes (test_id, changed_at);
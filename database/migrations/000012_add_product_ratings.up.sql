@@ -0,0 +1,20 @@
+-- Ratings of the products fitted with a Bradley-Terry model over the completed and published tests. The ratings are
+-- fitted for all tests and for each snow type and temperature band, and for each pool of tests: pool 0 is the public
+-- tests, while the pool of a team is its own tests together with the public tests.
+CREATE TABLE public.product_ratings (
+    pool bigint NOT NULL,
+    product_id bigint NOT NULL,
+    scope_type character varying(32) NOT NULL,
+    scope_value character varying(32) DEFAULT ''::character varying NOT NULL,
+    rating double precision NOT NULL,
+    uncertainty double precision NOT NULL,
+    tests integer NOT NULL,
+    comparisons integer NOT NULL,
+    updated_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    CONSTRAINT product_ratings_pkey PRIMARY KEY (pool, scope_type, scope_value, product_id),
+    CONSTRAINT product_ratings_scope_type_check CHECK (scope_type IN ('all', 'snow_type', 'temperature')),
+    CONSTRAINT fk_product_ratings_product FOREIGN KEY (product_id) REFERENCES public.products(id) ON DELETE CASCADE
+);
+
+CREATE INDEX product_ratings_leaderboard_idx ON public.product_ratings (pool, scope_type, scope_value, rating DESC);
+CREATE INDEX product_ratings_product_id_idx ON public.product_ratings (product_id);
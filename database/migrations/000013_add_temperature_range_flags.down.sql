@@ -0,0 +1,2 @@
+ALTER TABLE public.team DROP COLUMN IF EXISTS strict_temperature_range;
+ALTER TABLE public.test_ranks DROP COLUMN IF EXISTS out_of_temperature_range;
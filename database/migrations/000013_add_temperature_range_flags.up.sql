@@ -0,0 +1,12 @@
+-- Results of products tested outside their rated temperature range are flagged, so they can be left out of analyses.
+ALTER TABLE public.test_ranks ADD COLUMN out_of_temperature_range boolean DEFAULT false NOT NULL;
+
+-- A team can refuse such results instead of only being warned about them.
+ALTER TABLE public.team ADD COLUMN strict_temperature_range boolean DEFAULT false NOT NULL;
+
+-- Flag the existing results. A missing bound leaves that side of the range open, and bundles have no range yet.
+UPDATE public.test_ranks r SET out_of_temperature_range = true
+FROM public.tests t, public.snow_conditions sc, public.air_conditions ac, public.products p
+WHERE t.id = r.test_id AND sc.id = t.sc_id AND ac.id = t.ac_id AND p.id = r.product_id AND p.type <> 'bundle'
+  AND (sc.temperature < p.low_temperature OR sc.temperature > p.high_temperature
+    OR ac.temperature < p.low_temperature OR ac.temperature > p.high_temperature);
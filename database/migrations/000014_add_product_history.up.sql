@@ -0,0 +1,15 @@
+-- The history of the changes of each product. Every update of a product is a revision, with a row for each field
+-- it changed, who changed it and when, so the product can be reverted to an earlier revision.
+CREATE TABLE public.product_history (
+    id bigint GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    product_id bigint NOT NULL,
+    revision integer NOT NULL,
+    field character varying(32) NOT NULL,
+    old_value jsonb NOT NULL,
+    new_value jsonb NOT NULL,
+    changed_by bigint,
+    changed_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    CONSTRAINT product_history_revision_field_key UNIQUE (product_id, revision, field),
+    CONSTRAINT fk_product_history_product FOREIGN KEY (product_id) REFERENCES public.products(id) ON DELETE CASCADE,
+    CONSTRAINT fk_product_history_user FOREIGN KEY (changed_by) REFERENCES public.users(id) ON DELETE SET NULL
+);
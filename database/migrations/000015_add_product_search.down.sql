@@ -0,0 +1 @@
+DROP INDEX IF EXISTS public.products_search_idx;
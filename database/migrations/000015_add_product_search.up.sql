@@ -0,0 +1,5 @@
+-- Full-text search over the name, brand and comment of the products. The simple configuration does not stem the
+-- words, as the names of the waxes are in several languages. The search queries use the same expression as the index.
+CREATE INDEX products_search_idx ON public.products USING gin (
+    to_tsvector('simple'::regconfig, COALESCE(name, '') || ' ' || COALESCE(brand, '') || ' ' || COALESCE(comment, ''))
+);
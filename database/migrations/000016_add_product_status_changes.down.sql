@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS products_status_transition ON public.products;
+DROP FUNCTION IF EXISTS public.check_product_status_transition();
+DROP TABLE IF EXISTS public.product_status_changes;
@@ -0,0 +1,35 @@
+-- Every change of the status of a product, with the reason for it, who made it and when.
+CREATE TABLE public.product_status_changes (
+    id bigint GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    product_id bigint NOT NULL,
+    from_status public.statustype NOT NULL,
+    to_status public.statustype NOT NULL,
+    reason character varying(512) DEFAULT ''::character varying NOT NULL,
+    changed_by bigint,
+    changed_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    CONSTRAINT fk_product_status_changes_product FOREIGN KEY (product_id) REFERENCES public.products(id) ON DELETE CASCADE,
+    CONSTRAINT fk_product_status_changes_user FOREIGN KEY (changed_by) REFERENCES public.users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX product_status_changes_product_id_idx ON public.product_status_changes (product_id, changed_at);
+
+-- The statuses a product can move to from each status, the same as in domain.Status. A discontinued product can be
+-- produced again, while a retired product is final.
+CREATE FUNCTION public.check_product_status_transition() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+BEGIN
+    IF NEW.status = OLD.status OR (OLD.status, NEW.status) IN (
+        ('development', 'active'), ('development', 'retired'),
+        ('active', 'discontinued'), ('active', 'retired'),
+        ('discontinued', 'active'), ('discontinued', 'retired')) THEN
+        RETURN NEW;
+    END IF;
+    RAISE EXCEPTION 'product % cannot move from % to %', OLD.id, OLD.status, NEW.status
+        USING ERRCODE = 'check_violation';
+END;
+$$;
+
+CREATE TRIGGER products_status_transition
+    BEFORE UPDATE OF status ON public.products
+    FOR EACH ROW EXECUTE FUNCTION public.check_product_status_transition();
@@ -0,0 +1,5 @@
+ALTER TABLE public.product_bundles
+    DROP CONSTRAINT IF EXISTS fk_product_bundles_bundle,
+    DROP CONSTRAINT IF EXISTS product_bundles_layer_no_key;
+ALTER SEQUENCE public.bundle_id_seq OWNED BY NONE;
+DROP TABLE IF EXISTS public.bundles;
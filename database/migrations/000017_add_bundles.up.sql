@@ -0,0 +1,45 @@
+-- A bundle of products applied in layers. Its product row stands for it in tests and rankings, and the bundle links
+-- that product to its ordered layers in product_bundles. The IDs continue from bundle_id_seq, so the existing layers
+-- keep their bundle_id.
+CREATE TABLE public.bundles (
+    id bigint DEFAULT nextval('public.bundle_id_seq'::regclass) PRIMARY KEY,
+    product_id bigint NOT NULL,
+    name character varying(64) NOT NULL,
+    testing_team bigint NOT NULL,
+    version timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    CONSTRAINT bundles_product_id_key UNIQUE (product_id),
+    CONSTRAINT fk_bundles_product FOREIGN KEY (product_id) REFERENCES public.products(id) ON DELETE CASCADE,
+    CONSTRAINT fk_bundles_team FOREIGN KEY (testing_team) REFERENCES public.team(id) ON DELETE CASCADE
+);
+
+ALTER SEQUENCE public.bundle_id_seq OWNED BY public.bundles.id;
+
+-- Nothing recorded which product row belongs to which bundle_id. Both were created together, one bundle_id from
+-- the sequence for each product of type bundle, so they are matched in the order they were created. That only holds
+-- when every bundle product has layers and every set of layers has a bundle product. When the counts differ, the
+-- migration stops without changing anything, rather than link layers to the wrong products, and the data is fixed
+-- by hand before it is run again.
+DO $$
+DECLARE
+    layer_sets integer;
+    bundle_products integer;
+BEGIN
+    SELECT COUNT(DISTINCT bundle_id) INTO layer_sets FROM public.product_bundles;
+    SELECT COUNT(*) INTO bundle_products FROM public.products WHERE type = 'bundle';
+    IF layer_sets <> bundle_products THEN
+        RAISE EXCEPTION 'cannot match % sets of layers with % bundle products', layer_sets, bundle_products
+            USING HINT = 'Every bundle_id of product_bundles needs a product of type bundle, and the other way round.';
+    END IF;
+END;
+$$;
+
+INSERT INTO public.bundles (id, product_id, name, testing_team, version)
+SELECT b.bundle_id, p.id, p.name, p.testing_team, COALESCE(p.version, CURRENT_TIMESTAMP)
+FROM (SELECT bundle_id, row_number() OVER (ORDER BY bundle_id) AS n
+      FROM (SELECT DISTINCT bundle_id FROM public.product_bundles) ids) b
+JOIN (SELECT id, name, testing_team, version, row_number() OVER (ORDER BY id) AS n
+      FROM public.products WHERE type = 'bundle') p ON p.n = b.n;
+
+ALTER TABLE public.product_bundles
+    ADD CONSTRAINT fk_product_bundles_bundle FOREIGN KEY (bundle_id) REFERENCES public.bundles(id) ON DELETE CASCADE,
+    ADD CONSTRAINT product_bundles_layer_no_key UNIQUE (bundle_id, layer_no);
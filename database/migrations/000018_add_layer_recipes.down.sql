@@ -0,0 +1,12 @@
+ALTER TABLE public.product_bundles
+    DROP CONSTRAINT IF EXISTS product_bundles_method_check,
+    DROP CONSTRAINT IF EXISTS product_bundles_iron_temperature_check,
+    DROP CONSTRAINT IF EXISTS product_bundles_passes_check,
+    DROP CONSTRAINT IF EXISTS product_bundles_cooling_minutes_check,
+    DROP COLUMN IF EXISTS method,
+    DROP COLUMN IF EXISTS iron_temperature,
+    DROP COLUMN IF EXISTS passes,
+    DROP COLUMN IF EXISTS cooling_minutes,
+    DROP COLUMN IF EXISTS brushing,
+    DROP COLUMN IF EXISTS cork,
+    DROP COLUMN IF EXISTS fleece;
@@ -0,0 +1,15 @@
+-- How each layer of a bundle is applied. A layer without a method has no recipe recorded. The iron temperature is in
+-- degrees Celsius and only applies to ironed layers, and the brushing lists the brushes in the order they are used.
+ALTER TABLE public.product_bundles
+    ADD COLUMN method character varying(16),
+    ADD COLUMN iron_temperature smallint,
+    ADD COLUMN passes smallint,
+    ADD COLUMN cooling_minutes smallint,
+    ADD COLUMN brushing character varying(16)[] DEFAULT '{}'::character varying[] NOT NULL,
+    ADD COLUMN cork boolean DEFAULT false NOT NULL,
+    ADD COLUMN fleece boolean DEFAULT false NOT NULL,
+    ADD CONSTRAINT product_bundles_method_check CHECK (method IN ('iron', 'rub', 'spray', 'applicator')),
+    ADD CONSTRAINT product_bundles_iron_temperature_check
+        CHECK (iron_temperature IS NULL OR (method = 'iron' AND iron_temperature BETWEEN 90 AND 180)),
+    ADD CONSTRAINT product_bundles_passes_check CHECK (passes BETWEEN 1 AND 20),
+    ADD CONSTRAINT product_bundles_cooling_minutes_check CHECK (cooling_minutes BETWEEN 0 AND 240);
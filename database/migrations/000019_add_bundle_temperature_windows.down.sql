@@ -0,0 +1,5 @@
+UPDATE public.test_ranks r SET out_of_temperature_range = false
+FROM public.products p
+WHERE p.id = r.product_id AND p.type = 'bundle';
+
+UPDATE public.products SET low_temperature = 0, high_temperature = 0 WHERE type = 'bundle';
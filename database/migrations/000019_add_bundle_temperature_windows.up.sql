@@ -0,0 +1,21 @@
+-- The temperature range of a bundle is the window all of its layers are rated for, instead of 0 °C to 0 °C. A layer
+-- without a bound leaves that side open. Bundles without layers, or whose layers have no temperature in common, have
+-- no window.
+UPDATE public.products p
+SET low_temperature  = CASE WHEN w.low IS NULL OR w.high IS NULL OR w.low <= w.high THEN w.low END,
+    high_temperature = CASE WHEN w.low IS NULL OR w.high IS NULL OR w.low <= w.high THEN w.high END
+FROM (SELECT bp.id, MAX(lp.low_temperature) AS low, MIN(lp.high_temperature) AS high
+      FROM public.products bp
+      LEFT JOIN public.bundles b ON b.product_id = bp.id
+      LEFT JOIN public.product_bundles l ON l.bundle_id = b.id
+      LEFT JOIN public.products lp ON lp.id = l.product_id
+      WHERE bp.type = 'bundle'
+      GROUP BY bp.id) w
+WHERE p.id = w.id;
+
+-- Flag the results of bundles tested outside their window, as was done for the other products.
+UPDATE public.test_ranks r SET out_of_temperature_range = true
+FROM public.tests t, public.snow_conditions sc, public.air_conditions ac, public.products p
+WHERE t.id = r.test_id AND sc.id = t.sc_id AND ac.id = t.ac_id AND p.id = r.product_id AND p.type = 'bundle'
+  AND (sc.temperature < p.low_temperature OR sc.temperature > p.high_temperature
+    OR ac.temperature < p.low_temperature OR ac.temperature > p.high_temperature);
@@ -0,0 +1 @@
+ALTER TABLE public.locations DROP COLUMN created_by;
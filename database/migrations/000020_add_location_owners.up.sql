@@ -0,0 +1,5 @@
+-- The team that created a location may change or delete it, other teams may only use it. Locations created before
+-- they had an owner can only be changed by official teams.
+ALTER TABLE public.locations
+    ADD COLUMN created_by bigint,
+    ADD CONSTRAINT fk_locations_team FOREIGN KEY (created_by) REFERENCES public.team(id) ON DELETE SET NULL;